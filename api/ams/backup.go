@@ -0,0 +1,63 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package api
+
+// InstanceBackup describes a backup of an instance
+//
+// swagger:model
+type InstanceBackup struct {
+	// Name of the backup
+	// Example: backup0
+	Name string `json:"name" yaml:"name"`
+	// ID of the instance the backup was taken of
+	// Example: btavtegj1qm58qg7ru50
+	InstanceID string `json:"instance_id" yaml:"instance_id"`
+	// Creation UTC timestamp of the backup
+	// Example: 1532150640
+	CreatedAt int64 `json:"created_at" yaml:"created_at"`
+	// UTC timestamp when the backup expires and is deleted. Zero if the backup
+	// does not expire.
+	// Example: 1532237040
+	ExpiresAt int64 `json:"expires_at" yaml:"expires_at"`
+	// Size of the backup in bytes
+	// Example: 1073741824
+	Size int64 `json:"size" yaml:"size"`
+}
+
+// InstanceBackupsPost represents the fields used to create a backup of an instance
+//
+// swagger:model
+type InstanceBackupsPost struct {
+	// Name of the backup. AMS generates a name if empty.
+	// Example: backup0
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// UTC timestamp when the backup expires and is deleted
+	// Example: 1532237040
+	ExpiresAt int64 `json:"expires_at,omitempty" yaml:"expires_at,omitempty"`
+}
+
+// InstanceBackupRestorePost represents the fields used to restore an instance
+// from a backup
+//
+// swagger:model
+type InstanceBackupRestorePost struct {
+	// Whether or not to start the instance once it was restored
+	// Example: true
+	Start bool `json:"start" yaml:"start"`
+}
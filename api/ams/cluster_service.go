@@ -0,0 +1,74 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package api
+
+// ClusterServiceHealth describes the health of a service component of an AMS
+// cluster
+type ClusterServiceHealth string
+
+const (
+	// ClusterServiceHealthy is reported if the service operates normally
+	ClusterServiceHealthy ClusterServiceHealth = "healthy"
+	// ClusterServiceDegraded is reported if the service operates with
+	// reduced capacity or functionality
+	ClusterServiceDegraded ClusterServiceHealth = "degraded"
+	// ClusterServiceUnhealthy is reported if the service does not operate
+	ClusterServiceUnhealthy ClusterServiceHealth = "unhealthy"
+	// ClusterServiceUnknown is reported if AMS could not determine the
+	// health of the service, e.g. because it did not report in time
+	ClusterServiceUnknown ClusterServiceHealth = "unknown"
+)
+
+// ClusterService describes an internal service component of an AMS cluster
+// registered with the services registry
+//
+// swagger:model
+//
+// API extension: service_registry
+type ClusterService struct {
+	// Name uniquely identifies the service within the cluster
+	// Example: ams-node0
+	Name string `json:"name" yaml:"name"`
+	// Type of the service
+	// Example: ams
+	Type string `json:"type" yaml:"type"`
+	// Node the service runs on
+	// Example: lxd0
+	Node string `json:"node" yaml:"node"`
+	// Address the service is reachable at
+	// Example: 10.0.0.10:8444
+	Address string `json:"address" yaml:"address"`
+	// Version of the service
+	// Example: 1.18.0
+	Version string `json:"version" yaml:"version"`
+	// Health of the service
+	// Example: healthy
+	Health ClusterServiceHealth `json:"health" yaml:"health"`
+	// Message explaining the health of the service if it is not healthy
+	// Example: database connection lost
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+	// UTC timestamp of the last time the service reported to the registry
+	// Example: 1532150640
+	LastSeenAt int64 `json:"last_seen_at" yaml:"last_seen_at"`
+}
+
+// IsHealthy returns true if the service operates normally
+func (s *ClusterService) IsHealthy() bool {
+	return s.Health == ClusterServiceHealthy
+}
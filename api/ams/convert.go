@@ -0,0 +1,235 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"fmt"
+
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared"
+)
+
+// InstanceToContainer converts an instance API object to a container one
+func InstanceToContainer(inst *Instance) Container {
+	c := Container{
+		ID:            inst.ID,
+		Name:          inst.Name,
+		Type:          ContainerTypeRegular,
+		StatusCode:    ContainerStatus(inst.StatusCode),
+		Status:        inst.Status,
+		Node:          inst.Node,
+		AppID:         inst.AppID,
+		AppName:       inst.AppName,
+		AppVersion:    inst.AppVersion,
+		ImageID:       inst.ImageID,
+		ImageVersion:  inst.ImageVersion,
+		CreatedAt:     inst.CreatedAt,
+		Address:       inst.Address,
+		PublicAddress: inst.PublicAddress,
+		StoredLogs:    inst.StoredLogs,
+		ErrorMessage:  inst.ErrorMessage,
+		Architecture:  inst.Architecture,
+		Tags:          inst.Tags,
+	}
+	for _, service := range inst.Services {
+		c.Services = append(c.Services, ContainerService{
+			Port:        service.Port,
+			PortEnd:     service.PortEnd,
+			NodePort:    service.NodePort,
+			NodePortEnd: service.NodePortEnd,
+			Protocols:   service.Protocols,
+			Expose:      service.Expose,
+			Name:        service.Name,
+		})
+	}
+	c.Config.Platform = inst.Config.Platform
+	c.Config.BootPackage = inst.Config.BootPackage
+	c.Config.BootActivity = inst.Config.BootActivity
+	c.Config.MetricsServer = inst.Config.MetricsServer
+	c.Config.DisableWatchdog = inst.Config.DisableWatchdog
+	c.Config.DevMode = inst.Config.DevMode
+	c.Resources.CPUs = inst.Resources.CPUs
+
+	c.Resources.Memory = shared.GetByteSizeString(inst.Resources.Memory, 0)
+	c.Resources.DiskSize = shared.GetByteSizeString(inst.Resources.DiskSize, 0)
+	c.Resources.GPUSlots = inst.Resources.GPUSlots
+	c.Resources.VPUSlots = inst.Resources.VPUSlots
+	if inst.IsBase {
+		c.Type = ContainerTypeBase
+	}
+	return c
+}
+
+// ContainerToInstance converts a container API object to an instance one. It
+// fails if the resources of the container cannot be parsed.
+func ContainerToInstance(c *Container) (Instance, error) {
+	inst := Instance{
+		ID:            c.ID,
+		Name:          c.Name,
+		StatusCode:    InstanceStatus(c.StatusCode),
+		Status:        c.Status,
+		Node:          c.Node,
+		AppID:         c.AppID,
+		AppName:       c.AppName,
+		AppVersion:    c.AppVersion,
+		ImageID:       c.ImageID,
+		ImageVersion:  c.ImageVersion,
+		CreatedAt:     c.CreatedAt,
+		Address:       c.Address,
+		PublicAddress: c.PublicAddress,
+		StoredLogs:    c.StoredLogs,
+		ErrorMessage:  c.ErrorMessage,
+		Architecture:  c.Architecture,
+		Tags:          c.Tags,
+	}
+	for _, service := range c.Services {
+		inst.Services = append(inst.Services, InstanceService{
+			Port:        service.Port,
+			PortEnd:     service.PortEnd,
+			NodePort:    service.NodePort,
+			NodePortEnd: service.NodePortEnd,
+			Protocols:   service.Protocols,
+			Expose:      service.Expose,
+			Name:        service.Name,
+		})
+	}
+	inst.Config.Platform = c.Config.Platform
+	inst.Config.BootPackage = c.Config.BootPackage
+	inst.Config.BootActivity = c.Config.BootActivity
+	inst.Config.MetricsServer = c.Config.MetricsServer
+	inst.Config.DisableWatchdog = c.Config.DisableWatchdog
+	inst.Config.DevMode = c.Config.DevMode
+	inst.Resources.CPUs = c.Resources.CPUs
+	inst.Resources.GPUSlots = c.Resources.GPUSlots
+	inst.Resources.VPUSlots = c.Resources.VPUSlots
+	inst.IsBase = c.Type == ContainerTypeBase
+
+	memory, err := shared.ParseByteSizeString(c.Resources.Memory)
+	if err != nil {
+		return Instance{}, fmt.Errorf("failed to parse memory resource value: %w", err)
+	}
+	inst.Resources.Memory = memory
+
+	diskSize, err := shared.ParseByteSizeString(c.Resources.DiskSize)
+	if err != nil {
+		return Instance{}, fmt.Errorf("failed to parse disk size resource value: %w", err)
+	}
+	inst.Resources.DiskSize = diskSize
+
+	return inst, nil
+}
+
+// InstancesPostToContainersPost converts the request to launch an instance to
+// the corresponding request to launch a container. The instance type and name
+// have no equivalent for containers and are dropped.
+func InstancesPostToContainersPost(p *InstancesPost) ContainersPost {
+	d := ContainersPost{
+		ApplicationID:      p.ApplicationID,
+		ApplicationVersion: p.ApplicationVersion,
+		ImageID:            p.ImageID,
+		ImageVersion:       p.ImageVersion,
+		Node:               p.Node,
+		Userdata:           p.Userdata,
+		Addons:             p.Addons,
+		Services:           p.Services,
+		CPUs:               p.Resources.CPUs,
+		DiskSize:           p.Resources.DiskSize,
+		Memory:             p.Resources.Memory,
+		GPUSlots:           p.Resources.GPUSlots,
+		VPUSlots:           p.Resources.VPUSlots,
+		Tags:               p.Tags,
+		NoStart:            p.NoStart,
+	}
+	d.Config.Platform = p.Config.Platform
+	d.Config.BootPackage = p.Config.BootPackage
+	d.Config.BootActivity = p.Config.BootActivity
+	d.Config.MetricsServer = p.Config.MetricsServer
+	d.Config.DisableWatchdog = p.Config.DisableWatchdog
+	d.Config.Features = p.Config.Features
+	d.Config.DevMode = p.Config.DevMode
+	return d
+}
+
+// ContainersPostToInstancesPost converts the request to launch a container to
+// the corresponding request to launch an instance of type container. The
+// instance type preset of the container request has no equivalent for
+// instances and is dropped.
+func ContainersPostToInstancesPost(p *ContainersPost) InstancesPost {
+	d := InstancesPost{
+		Type:               InstanceTypeContainer,
+		ApplicationID:      p.ApplicationID,
+		ApplicationVersion: p.ApplicationVersion,
+		ImageID:            p.ImageID,
+		ImageVersion:       p.ImageVersion,
+		Node:               p.Node,
+		Userdata:           p.Userdata,
+		Addons:             p.Addons,
+		Services:           p.Services,
+		Tags:               p.Tags,
+		NoStart:            p.NoStart,
+	}
+	d.Resources.CPUs = p.CPUs
+	d.Resources.DiskSize = p.DiskSize
+	d.Resources.Memory = p.Memory
+	d.Resources.GPUSlots = p.GPUSlots
+	d.Resources.VPUSlots = p.VPUSlots
+	d.Config.Platform = p.Config.Platform
+	d.Config.BootPackage = p.Config.BootPackage
+	d.Config.BootActivity = p.Config.BootActivity
+	d.Config.MetricsServer = p.Config.MetricsServer
+	d.Config.DisableWatchdog = p.Config.DisableWatchdog
+	d.Config.Features = p.Config.Features
+	d.Config.DevMode = p.Config.DevMode
+	return d
+}
+
+// InstancePatchToContainerPatch converts an instance update request to a
+// container one
+func InstancePatchToContainerPatch(p *InstancePatch) ContainerPatch {
+	return ContainerPatch{DesiredStatus: p.DesiredStatus}
+}
+
+// ContainerPatchToInstancePatch converts a container update request to an
+// instance one
+func ContainerPatchToInstancePatch(p *ContainerPatch) InstancePatch {
+	return InstancePatch{DesiredStatus: p.DesiredStatus}
+}
+
+// InstanceExecPostToContainerExecPost converts an instance execution request
+// to a container one
+func InstanceExecPostToContainerExecPost(p *InstanceExecPost) ContainerExecPost {
+	return ContainerExecPost{
+		Command:     p.Command,
+		Environment: p.Environment,
+		Interactive: p.Interactive,
+		Width:       p.Width,
+		Height:      p.Height,
+	}
+}
+
+// ContainerExecPostToInstanceExecPost converts a container execution request
+// to an instance one
+func ContainerExecPostToInstanceExecPost(p *ContainerExecPost) InstanceExecPost {
+	return InstanceExecPost{
+		Command:     p.Command,
+		Environment: p.Environment,
+		Interactive: p.Interactive,
+		Width:       p.Width,
+		Height:      p.Height,
+	}
+}
@@ -0,0 +1,161 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+)
+
+// ExposeMode defines where a service of an instance is reachable
+type ExposeMode string
+
+const (
+	// ExposePrivate makes a service available on the private endpoint of the
+	// node only
+	ExposePrivate ExposeMode = "private"
+	// ExposePublic makes a service available on the public endpoint of the node
+	ExposePublic ExposeMode = "public"
+)
+
+// NewServiceSpec returns the definition of a service listening on the given
+// port. Without protocols the service is opened for TCP. Any mode other than
+// ExposePublic keeps the service private.
+func NewServiceSpec(name string, port int, mode ExposeMode, protocols ...NetworkProtocol) NetworkServiceSpec {
+	if len(protocols) == 0 {
+		protocols = []NetworkProtocol{NetworkProtocolTCP}
+	}
+	return NetworkServiceSpec{
+		Name:      name,
+		Port:      port,
+		Protocols: protocols,
+		Expose:    mode == ExposePublic,
+	}
+}
+
+// NewServiceRangeSpec returns the definition of a service listening on the
+// port range from port to portEnd. See NewServiceSpec for details.
+func NewServiceRangeSpec(name string, port, portEnd int, mode ExposeMode, protocols ...NetworkProtocol) NetworkServiceSpec {
+	spec := NewServiceSpec(name, port, mode, protocols...)
+	spec.PortEnd = portEnd
+	return spec
+}
+
+// ServiceEndpoint describes where a single port of a service of an instance
+// can be reached. A port range results in one endpoint per port and protocol.
+type ServiceEndpoint struct {
+	// Name of the service
+	Name string
+	// Protocol the endpoint is reachable with
+	Protocol NetworkProtocol
+	// Port the service listens on inside the instance
+	Port int
+	// Address of the instance on the network of the node
+	Address string
+	// Exposed is true if the service is reachable through the public
+	// endpoint of the node
+	Exposed bool
+	// PublicAddress is the address of the node the service is reachable on.
+	// Empty if the service is not exposed.
+	PublicAddress string
+	// NodePort is the port on the node mapped to Port. Zero if AMS did not
+	// report a node port.
+	NodePort int
+}
+
+// PublicEndpoint returns the host:port the endpoint is reachable on from
+// outside the node. Returns an empty string if the service is not exposed or
+// AMS did not report the node port.
+func (e ServiceEndpoint) PublicEndpoint() string {
+	if !e.Exposed || len(e.PublicAddress) == 0 || e.NodePort == 0 {
+		return ""
+	}
+	return net.JoinHostPort(e.PublicAddress, strconv.Itoa(e.NodePort))
+}
+
+// PrivateEndpoint returns the host:port the endpoint is reachable on from the
+// network of the node
+func (e ServiceEndpoint) PrivateEndpoint() string {
+	return net.JoinHostPort(e.Address, strconv.Itoa(e.Port))
+}
+
+// String returns a readable description of the endpoint
+func (e ServiceEndpoint) String() string {
+	target := e.PublicEndpoint()
+	if len(target) == 0 {
+		target = e.PrivateEndpoint()
+	}
+	return fmt.Sprintf("%s/%d/%s -> %s", e.Name, e.Port, e.Protocol, target)
+}
+
+// ContainerEndpoints returns the endpoints of all services the container has
+func ContainerEndpoints(c *Container) []ServiceEndpoint {
+	endpoints := []ServiceEndpoint{}
+	for _, s := range c.Services {
+		endpoints = appendEndpoints(endpoints, c.Address, c.PublicAddress, s.Name, s.Port, s.PortEnd, s.NodePort, s.Protocols, s.Expose)
+	}
+	return endpoints
+}
+
+// InstanceEndpoints returns the endpoints of all services the instance has
+func InstanceEndpoints(i *Instance) []ServiceEndpoint {
+	endpoints := []ServiceEndpoint{}
+	for _, s := range i.Services {
+		endpoints = appendEndpoints(endpoints, i.Address, i.PublicAddress, s.Name, s.Port, s.PortEnd, s.NodePort, s.Protocols, s.Expose)
+	}
+	return endpoints
+}
+
+// FindEndpoint returns the endpoint of the service with the given name, port
+// and protocol
+func FindEndpoint(endpoints []ServiceEndpoint, name string, port int, protocol NetworkProtocol) (ServiceEndpoint, bool) {
+	for _, e := range endpoints {
+		if e.Name == name && e.Port == port && e.Protocol == protocol {
+			return e, true
+		}
+	}
+	return ServiceEndpoint{}, false
+}
+
+func appendEndpoints(endpoints []ServiceEndpoint, address, publicAddress, name string, port, portEnd int, nodePort *int, protocols []NetworkProtocol, exposed bool) []ServiceEndpoint {
+	if portEnd < port {
+		portEnd = port
+	}
+	for p := port; p <= portEnd; p++ {
+		for _, protocol := range protocols {
+			e := ServiceEndpoint{
+				Name:     name,
+				Protocol: protocol,
+				Port:     p,
+				Address:  address,
+				Exposed:  exposed,
+			}
+			if exposed {
+				e.PublicAddress = publicAddress
+			}
+			// Node port ranges map one to one onto the service port range
+			if nodePort != nil && *nodePort > 0 {
+				e.NodePort = *nodePort + (p - port)
+			}
+			endpoints = append(endpoints, e)
+		}
+	}
+	return endpoints
+}
@@ -18,7 +18,10 @@
 
 package api
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // EventType is used to describe the type of an event
 //
@@ -86,4 +89,6 @@ const (
 type LifecycleEvent struct {
 	Action LifecycleEventAction `json:"action"`
 	Source string               `json:"source"`
+	// Extra holds the fields sent by AMS which are not known to the SDK
+	Extra map[string]json.RawMessage `json:"-"`
 }
@@ -0,0 +1,270 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/api"
+)
+
+// EventSchemaVersion is the version of the event schema the SDK understands.
+// AMS does not send a version with its events yet, so events without one are
+// assigned version 1.
+const EventSchemaVersion = 1
+
+// EventEnvelope is an event received from the events API with its payload
+// kept undecoded until the consumer asks for it in the typed form matching
+// the event type
+type EventEnvelope struct {
+	// Version of the event schema
+	Version int `json:"version"`
+	// Type of the event
+	Type EventType `json:"type"`
+	// Timestamp the event was sent at
+	Timestamp time.Time `json:"timestamp"`
+	// Metadata is the raw payload of the event
+	Metadata json.RawMessage `json:"metadata"`
+	// Extra holds the fields sent by AMS which are not known to the SDK
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// ParseEvent decodes an event as sent on the events API
+func ParseEvent(data []byte) (*EventEnvelope, error) {
+	e := &EventEnvelope{}
+	if err := json.Unmarshal(data, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// NewEventEnvelope converts an event as passed to the handlers of an event
+// listener into an envelope
+func NewEventEnvelope(data interface{}) (*EventEnvelope, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return ParseEvent(b)
+}
+
+// UnmarshalJSON decodes the event and retains unknown fields in Extra
+func (e *EventEnvelope) UnmarshalJSON(data []byte) error {
+	type plain EventEnvelope
+	extra, err := unmarshalRetaining(data, (*plain)(e))
+	if err != nil {
+		return err
+	}
+	e.Extra = extra
+	if e.Version == 0 {
+		e.Version = 1
+	}
+	return nil
+}
+
+// MarshalJSON encodes the event including the retained unknown fields
+func (e EventEnvelope) MarshalJSON() ([]byte, error) {
+	type plain EventEnvelope
+	return marshalRetaining(plain(e), e.Extra)
+}
+
+// Supported returns true if the schema version of the event is understood
+// by the SDK. Newer events can still be decoded, but fields added by later
+// versions are only available through Extra.
+func (e *EventEnvelope) Supported() bool {
+	return e.Version <= EventSchemaVersion
+}
+
+// Lifecycle decodes the payload of a lifecycle event
+func (e *EventEnvelope) Lifecycle() (*LifecycleEvent, error) {
+	if e.Type != EventTypeLifecycle {
+		return nil, fmt.Errorf("event of type %q is no lifecycle event", e.Type)
+	}
+	event := &LifecycleEvent{}
+	if err := json.Unmarshal(e.Metadata, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// Operation decodes the payload of an operation event
+func (e *EventEnvelope) Operation() (*OperationEvent, error) {
+	if e.Type != EventTypeOperation {
+		return nil, fmt.Errorf("event of type %q is no operation event", e.Type)
+	}
+	event := &OperationEvent{}
+	if err := json.Unmarshal(e.Metadata, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// Payload decodes the payload according to the event type and returns a
+// *LifecycleEvent or *OperationEvent. The raw payload is returned for
+// unknown event types.
+func (e *EventEnvelope) Payload() (interface{}, error) {
+	switch e.Type {
+	case EventTypeLifecycle:
+		return e.Lifecycle()
+	case EventTypeOperation:
+		return e.Operation()
+	default:
+		return e.Metadata, nil
+	}
+}
+
+// UnmarshalJSON decodes the event and retains unknown fields in Extra
+func (e *LifecycleEvent) UnmarshalJSON(data []byte) error {
+	type plain LifecycleEvent
+	extra, err := unmarshalRetaining(data, (*plain)(e))
+	if err != nil {
+		return err
+	}
+	e.Extra = extra
+	return nil
+}
+
+// MarshalJSON encodes the event including the retained unknown fields
+func (e LifecycleEvent) MarshalJSON() ([]byte, error) {
+	type plain LifecycleEvent
+	return marshalRetaining(plain(e), e.Extra)
+}
+
+// ResourceType returns the kind of resource the event was sent for, e.g.
+// "instances", "containers" or "nodes"
+func (e *LifecycleEvent) ResourceType() string {
+	parts := strings.Split(strings.Trim(e.Source, "/"), "/")
+	if len(parts) < 3 {
+		return ""
+	}
+	return parts[len(parts)-2]
+}
+
+// ResourceID returns the ID or name of the resource the event was sent for
+func (e *LifecycleEvent) ResourceID() string {
+	if len(e.Source) == 0 {
+		return ""
+	}
+	return path.Base(e.Source)
+}
+
+// IsInstanceEvent returns true if the event was sent for an instance or a
+// container
+func (e *LifecycleEvent) IsInstanceEvent() bool {
+	t := e.ResourceType()
+	return t == "instances" || t == "containers"
+}
+
+// IsNodeEvent returns true if the event was sent for a node
+func (e *LifecycleEvent) IsNodeEvent() bool {
+	return e.ResourceType() == "nodes"
+}
+
+// OperationEvent is the payload of an operation event. It carries the
+// current state of the operation.
+type OperationEvent struct {
+	api.Operation
+	// Extra holds the fields sent by AMS which are not known to the SDK
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the event and retains unknown fields in Extra
+func (e *OperationEvent) UnmarshalJSON(data []byte) error {
+	extra, err := unmarshalRetaining(data, &e.Operation)
+	if err != nil {
+		return err
+	}
+	e.Extra = extra
+	return nil
+}
+
+// MarshalJSON encodes the event including the retained unknown fields
+func (e OperationEvent) MarshalJSON() ([]byte, error) {
+	return marshalRetaining(e.Operation, e.Extra)
+}
+
+// unmarshalRetaining decodes data into v and returns the fields of data
+// which don't map to a field of v
+func unmarshalRetaining(data []byte, v interface{}) (map[string]json.RawMessage, error) {
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+	raw := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	known := jsonFieldNames(reflect.TypeOf(v).Elem())
+	for name := range raw {
+		if known[strings.ToLower(name)] {
+			delete(raw, name)
+		}
+	}
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	return raw, nil
+}
+
+// marshalRetaining encodes v and adds the given extra fields which are not
+// already set by v
+func marshalRetaining(v interface{}, extra map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for name, value := range extra {
+		if _, ok := fields[name]; !ok {
+			fields[name] = value
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// jsonFieldNames returns the lower cased JSON names of the fields of the
+// given struct type, including those of embedded structs
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := map[string]bool{}
+	for n := 0; n < t.NumField(); n++ {
+		f := t.Field(n)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && len(name) == 0 && f.Type.Kind() == reflect.Struct {
+			for embedded := range jsonFieldNames(f.Type) {
+				names[embedded] = true
+			}
+			continue
+		}
+		if len(name) == 0 {
+			name = f.Name
+		}
+		names[strings.ToLower(name)] = true
+	}
+	return names
+}
@@ -0,0 +1,73 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package api
+
+// API extensions the SDK knows about. Use these instead of plain strings when
+// checking for an extension so that typos are caught at compile time.
+const (
+	// ExtensionAddonRestoreHook adds the restore hook to addons
+	ExtensionAddonRestoreHook = "addon_restore_hook"
+	// ExtensionApplicationImageExport allows exporting applications as images
+	ExtensionApplicationImageExport = "application_image_export"
+	// ExtensionContainerExec allows executing commands in containers
+	ExtensionContainerExec = "container_exec"
+	// ExtensionContainerLogs allows retrieving the logs of containers
+	ExtensionContainerLogs = "container_logs"
+	// ExtensionImageNodeStatus exposes the synchronization status of images
+	// per node
+	ExtensionImageNodeStatus = "image_node_status"
+	// ExtensionInstanceConsole allows attaching to the console of instances
+	ExtensionInstanceConsole = "instance_console"
+	// ExtensionInstanceBackups adds the endpoints to back up and restore
+	// instances
+	ExtensionInstanceBackups = "instance_backups"
+	// ExtensionInstanceSupport adds the instance endpoints replacing the
+	// container endpoints
+	ExtensionInstanceSupport = "instance_support"
+	// ExtensionLogStreaming allows streaming logs as they are written
+	ExtensionLogStreaming = "log_streaming"
+	// ExtensionRegistry adds the application registry endpoints
+	ExtensionRegistry = "registry"
+	// ExtensionServiceRegistry exposes the registry of the service components
+	// of the cluster
+	ExtensionServiceRegistry = "service_registry"
+	// ExtensionVMSupport adds support for virtual machine instances
+	ExtensionVMSupport = "vm_support"
+	// ExtensionZipArchiveSupport allows uploading packages as zip archives
+	ExtensionZipArchiveSupport = "zip_archive_support"
+)
+
+// KnownExtensions returns all API extensions the SDK knows about
+func KnownExtensions() []string {
+	return []string{
+		ExtensionAddonRestoreHook,
+		ExtensionApplicationImageExport,
+		ExtensionContainerExec,
+		ExtensionContainerLogs,
+		ExtensionImageNodeStatus,
+		ExtensionInstanceConsole,
+		ExtensionInstanceBackups,
+		ExtensionInstanceSupport,
+		ExtensionLogStreaming,
+		ExtensionRegistry,
+		ExtensionServiceRegistry,
+		ExtensionVMSupport,
+		ExtensionZipArchiveSupport,
+	}
+}
@@ -183,3 +183,34 @@ type ImagesGet struct {
 type ImageDelete struct {
 	Force bool `json:"force"`
 }
+
+// ImageNodeStatus describes the synchronization status of an image version on
+// a single node of the cluster
+//
+// swagger:model
+//
+// API extension: image_node_status
+type ImageNodeStatus struct {
+	// Name of the node
+	// Example: lxd0
+	Node string `json:"node" yaml:"node"`
+	// Version of the image
+	// Example: 0
+	Version int `json:"version" yaml:"version"`
+	// Fingerprint of the image version
+	// Example: 0791cfc011f67c60b7bd0f852ddb686b79fa46083d9d43ef9845c9235c67b261
+	Fingerprint string `json:"fingerprint" yaml:"fingerprint"`
+	// Status of the image version on the node as an integer value
+	// Example: 3
+	StatusCode ImageStatus `json:"status_code" yaml:"status_code"`
+	// Status of the image version on the node
+	// Enum: error,created,active,initializing,unknown
+	// Example: active
+	Status string `json:"status" yaml:"status"`
+	// Error message if the synchronization to the node failed
+	// Example: not enough disk space
+	ErrorMessage string `json:"error_message,omitempty" yaml:"error_message,omitempty"`
+	// UTC timestamp of the last successful synchronization to the node
+	// Example: 1610641117
+	SyncedAt int64 `json:"synced_at" yaml:"synced_at"`
+}
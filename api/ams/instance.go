@@ -19,8 +19,6 @@
 package api
 
 import (
-	"fmt"
-
 	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared"
 )
 
@@ -424,6 +422,31 @@ type InstanceExecControl struct {
 	Signal int `json:"signal" yaml:"signal"`
 }
 
+// InstanceConsoleType describes the type of console of an instance
+type InstanceConsoleType string
+
+const (
+	// InstanceConsoleTypeConsole specifies the serial console of an instance
+	InstanceConsoleTypeConsole InstanceConsoleType = "console"
+	// InstanceConsoleTypeVGA specifies the graphical console of an instance
+	InstanceConsoleTypeVGA InstanceConsoleType = "vga"
+)
+
+// InstanceConsolePost represents a request to attach to the console of an instance
+//
+// swagger:model
+//
+// API extension: instance_console
+type InstanceConsolePost struct {
+	// Type of the console to attach to (console, vga)
+	// Example: console
+	Type InstanceConsoleType `json:"type" yaml:"type"`
+	// Width of the terminal. Only used for the serial console.
+	Width int `json:"width" yaml:"width"`
+	// Height of the terminal. Only used for the serial console.
+	Height int `json:"height" yaml:"height"`
+}
+
 // InstanceDelete describes a request used to delete a instance
 //
 // swagger:model
@@ -441,110 +464,15 @@ type InstancePatch struct {
 }
 
 // MapInstanceToContainer converts an instance API object to a container one
+//
+// Deprecated: use InstanceToContainer instead
 func MapInstanceToContainer(inst *Instance) Container {
-	c := Container{
-		ID:            inst.ID,
-		Name:          inst.Name,
-		Type:          ContainerTypeRegular,
-		StatusCode:    ContainerStatus(inst.StatusCode),
-		Status:        inst.Status,
-		Node:          inst.Node,
-		AppID:         inst.AppID,
-		AppName:       inst.AppName,
-		AppVersion:    inst.AppVersion,
-		ImageID:       inst.ImageID,
-		ImageVersion:  inst.ImageVersion,
-		CreatedAt:     inst.CreatedAt,
-		Address:       inst.Address,
-		PublicAddress: inst.PublicAddress,
-		StoredLogs:    inst.StoredLogs,
-		ErrorMessage:  inst.ErrorMessage,
-		Architecture:  inst.Architecture,
-		Tags:          inst.Tags,
-	}
-	for _, service := range inst.Services {
-		c.Services = append(c.Services, ContainerService{
-			Port:        service.Port,
-			PortEnd:     service.PortEnd,
-			NodePort:    service.NodePort,
-			NodePortEnd: service.NodePortEnd,
-			Protocols:   service.Protocols,
-			Expose:      service.Expose,
-			Name:        service.Name,
-		})
-	}
-	c.Config.Platform = inst.Config.Platform
-	c.Config.BootPackage = inst.Config.BootPackage
-	c.Config.BootActivity = inst.Config.BootActivity
-	c.Config.MetricsServer = inst.Config.MetricsServer
-	c.Config.DisableWatchdog = inst.Config.DisableWatchdog
-	c.Config.DevMode = inst.Config.DevMode
-	c.Resources.CPUs = inst.Resources.CPUs
-
-	c.Resources.Memory = shared.GetByteSizeString(inst.Resources.Memory, 0)
-	c.Resources.DiskSize = shared.GetByteSizeString(inst.Resources.DiskSize, 0)
-	c.Resources.GPUSlots = inst.Resources.GPUSlots
-	c.Resources.VPUSlots = inst.Resources.VPUSlots
-	if inst.IsBase {
-		c.Type = ContainerTypeBase
-	}
-	return c
+	return InstanceToContainer(inst)
 }
 
 // MapContainerToInstance maps a container to an instance object
+//
+// Deprecated: use ContainerToInstance instead
 func MapContainerToInstance(c *Container) (Instance, error) {
-	inst := Instance{
-		ID:            c.ID,
-		Name:          c.Name,
-		StatusCode:    InstanceStatus(c.StatusCode),
-		Status:        c.Status,
-		Node:          c.Node,
-		AppID:         c.AppID,
-		AppName:       c.AppName,
-		AppVersion:    c.AppVersion,
-		ImageID:       c.ImageID,
-		ImageVersion:  c.ImageVersion,
-		CreatedAt:     c.CreatedAt,
-		Address:       c.Address,
-		PublicAddress: c.PublicAddress,
-		StoredLogs:    c.StoredLogs,
-		ErrorMessage:  c.ErrorMessage,
-		Architecture:  c.Architecture,
-		Tags:          c.Tags,
-	}
-	for _, service := range c.Services {
-		inst.Services = append(inst.Services, InstanceService{
-			Port:        service.Port,
-			PortEnd:     service.PortEnd,
-			NodePort:    service.NodePort,
-			NodePortEnd: service.NodePortEnd,
-			Protocols:   service.Protocols,
-			Expose:      service.Expose,
-			Name:        service.Name,
-		})
-	}
-	inst.Config.Platform = c.Config.Platform
-	inst.Config.BootPackage = c.Config.BootPackage
-	inst.Config.BootActivity = c.Config.BootActivity
-	inst.Config.MetricsServer = c.Config.MetricsServer
-	inst.Config.DisableWatchdog = c.Config.DisableWatchdog
-	inst.Config.DevMode = c.Config.DevMode
-	inst.Resources.CPUs = c.Resources.CPUs
-	inst.Resources.GPUSlots = c.Resources.GPUSlots
-	inst.Resources.VPUSlots = c.Resources.VPUSlots
-	inst.IsBase = c.Type == ContainerTypeBase
-
-	memory, err := shared.ParseByteSizeString(c.Resources.Memory)
-	if err != nil {
-		return Instance{}, fmt.Errorf("failed to parse memory resource value: %w", err)
-	}
-	inst.Resources.Memory = memory
-
-	diskSize, err := shared.ParseByteSizeString(c.Resources.DiskSize)
-	if err != nil {
-		return Instance{}, fmt.Errorf("failed to parse disk size resource value: %w", err)
-	}
-	inst.Resources.DiskSize = diskSize
-
-	return inst, nil
+	return ContainerToInstance(c)
 }
@@ -0,0 +1,123 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/constants"
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+)
+
+// Platform describes the Anbox platform an instance runs with
+type Platform string
+
+const (
+	// PlatformNull runs Anbox without any rendering or streaming
+	PlatformNull Platform = "null"
+	// PlatformWebRTC runs Anbox with GPU rendering and streams its display
+	// via WebRTC
+	PlatformWebRTC Platform = "webrtc"
+	// PlatformSwrast runs Anbox with software rendering and without streaming
+	PlatformSwrast Platform = "swrast"
+)
+
+// KnownPlatforms returns all platforms the SDK knows about
+func KnownPlatforms() []Platform {
+	return []Platform{PlatformNull, PlatformWebRTC, PlatformSwrast}
+}
+
+// ValidatePlatform checks that the given platform is known. An empty platform
+// is valid and lets AMS pick its default.
+func ValidatePlatform(platform Platform) error {
+	if len(platform) == 0 {
+		return nil
+	}
+	for _, p := range KnownPlatforms() {
+		if p == platform {
+			return nil
+		}
+	}
+	known := []string{}
+	for _, p := range KnownPlatforms() {
+		known = append(known, string(p))
+	}
+	return errors.NewInvalidArgument(fmt.Sprintf("platform %q (supported: %s)", platform, strings.Join(known, ", ")))
+}
+
+// ValidateBootPackage checks that the given boot package or activity is a
+// valid Android package name
+func ValidateBootPackage(name string) error {
+	if match, _ := regexp.MatchString(constants.AndroidPackageNamePattern, name); !match {
+		return errors.NewInvalidArgument(fmt.Sprintf("boot package %q", name))
+	}
+	return nil
+}
+
+// ValidateServices checks the given service definitions for invalid port
+// ranges and protocols and for services using the same port and protocol
+func ValidateServices(services []NetworkServiceSpec) error {
+	type portRange struct {
+		start, end int
+		name       string
+	}
+	used := map[NetworkProtocol][]portRange{}
+
+	for n, service := range services {
+		name := service.Name
+		if len(name) == 0 {
+			name = fmt.Sprintf("#%d", n)
+		}
+
+		if service.Port < 1 || service.Port > 65535 {
+			return errors.NewInvalidArgument(fmt.Sprintf("port %d of service %s", service.Port, name))
+		}
+		end := service.Port
+		if service.PortEnd != 0 {
+			if service.PortEnd < service.Port || service.PortEnd > 65535 {
+				return errors.NewInvalidArgument(fmt.Sprintf("port end %d of service %s", service.PortEnd, name))
+			}
+			end = service.PortEnd
+		}
+
+		for _, protocol := range service.Protocols {
+			if NetworkProtocolFromString(string(protocol)) == NetworkProtocolUnknown {
+				return errors.NewInvalidArgument(fmt.Sprintf("protocol %q of service %s", protocol, name))
+			}
+			for _, r := range used[protocol] {
+				if service.Port <= r.end && end >= r.start {
+					return fmt.Errorf("service %s uses %s ports already used by service %s", name, protocol, r.name)
+				}
+			}
+			used[protocol] = append(used[protocol], portRange{start: service.Port, end: end, name: name})
+		}
+	}
+	return nil
+}
+
+// ValidatePlatformServices checks the platform and services an instance is
+// requested with before the request is sent to AMS
+func ValidatePlatformServices(platform Platform, services []NetworkServiceSpec) error {
+	if err := ValidatePlatform(platform); err != nil {
+		return err
+	}
+	return ValidateServices(services)
+}
@@ -48,3 +48,42 @@ type RegistryApplication struct {
 	Versions      map[int]*RegistryApplicationVersion `json:"versions" yaml:"versions"`
 	VM            bool                                `json:"vm" yaml:"vm"`
 }
+
+// RegistryMode describes how AMS synchronizes applications with the
+// configured application registry
+type RegistryMode string
+
+const (
+	// RegistryModeManual requires applications to be pushed or pulled explicitly
+	RegistryModeManual RegistryMode = "manual"
+	// RegistryModePull makes AMS automatically pull applications from the registry
+	RegistryModePull RegistryMode = "pull"
+	// RegistryModePush makes AMS automatically push published applications to the registry
+	RegistryModePush RegistryMode = "push"
+)
+
+// RegistryConfig describes the configuration AMS uses to connect to an
+// application registry. It maps to the `registry.*` configuration items.
+type RegistryConfig struct {
+	// URL of the application registry
+	URL string `json:"url" yaml:"url"`
+	// Mode of synchronization with the registry
+	Mode RegistryMode `json:"mode" yaml:"mode"`
+	// Interval in which AMS checks the registry for updates, e.g. "1h"
+	UpdateInterval string `json:"update_interval" yaml:"update_interval"`
+	// Comma separated list of tags used to filter applications pulled from the registry
+	Filter string `json:"filter" yaml:"filter"`
+	// Fingerprint of the registry certificate AMS trusts
+	Fingerprint string `json:"fingerprint" yaml:"fingerprint"`
+}
+
+// RegistryConfigPatch describes changes to the application registry
+// configuration. Nil fields are left untouched, an empty value clears the
+// configuration item.
+type RegistryConfigPatch struct {
+	URL            *string       `json:"url,omitempty" yaml:"url,omitempty"`
+	Mode           *RegistryMode `json:"mode,omitempty" yaml:"mode,omitempty"`
+	UpdateInterval *string       `json:"update_interval,omitempty" yaml:"update_interval,omitempty"`
+	Filter         *string       `json:"filter,omitempty" yaml:"filter,omitempty"`
+	Fingerprint    *string       `json:"fingerprint,omitempty" yaml:"fingerprint,omitempty"`
+}
@@ -0,0 +1,282 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+
+	api "github.com/anbox-cloud/ams-sdk/api/ams"
+	"github.com/anbox-cloud/ams-sdk/examples/ams/common"
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/client"
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+	restclient "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/client"
+)
+
+// newFlagSet returns the flag set of the given sub command
+func newFlagSet(name string) *flag.FlagSet {
+	return flag.NewFlagSet(name, flag.ExitOnError)
+}
+
+// instanceID parses the flags of sub commands which take only an instance ID
+func instanceID(name string, args []string) (string, error) {
+	flags := newFlagSet(name)
+	id := flags.String("id", "", "Instance id")
+	flags.Parse(args)
+	if len(*id) == 0 {
+		return "", errors.NewInvalidArgument("id")
+	}
+	return *id, nil
+}
+
+// wait waits for the given operation to finish and prints the created
+// resources
+func wait(op restclient.Operation) error {
+	if err := op.Wait(context.Background()); err != nil {
+		return err
+	}
+	common.PrintCreated(op.Get().Resources)
+	return nil
+}
+
+func runStatus(c client.Client, args []string) error {
+	status, _, err := c.RetrieveServiceStatus()
+	if err != nil {
+		return err
+	}
+	return common.DumpData(status)
+}
+
+func runList(c client.Client, args []string) error {
+	instances, err := c.ListInstances()
+	if err != nil {
+		return err
+	}
+	for _, i := range instances {
+		what := i.AppName
+		if len(what) == 0 {
+			what = i.ImageID
+		}
+		fmt.Printf("%s\t%s\t%s\n", i.ID, i.Status, what)
+	}
+	return nil
+}
+
+func runShow(c client.Client, args []string) error {
+	id, err := instanceID("show", args)
+	if err != nil {
+		return err
+	}
+	instance, _, err := c.RetrieveInstanceByID(id)
+	if err != nil {
+		return err
+	}
+	return common.DumpData(instance)
+}
+
+func runLaunch(c client.Client, args []string) error {
+	flags := newFlagSet("launch")
+	app := flags.String("app", "", "Application to launch an instance of")
+	image := flags.String("image", "", "Image to launch an instance from instead of an application")
+	version := flags.Int("version", -1, "Version of the application or image. Defaults to the latest one")
+	node := flags.String("node", "", "Node to launch the instance on")
+	userdata := flags.String("userdata", "", "Userdata passed to the instance")
+	vm := flags.Bool("vm", false, "Launch a virtual machine instead of a container")
+	flags.Parse(args)
+
+	details := &api.InstancesPost{
+		Type: api.InstanceTypeContainer,
+		Node: *node,
+	}
+	if *vm {
+		details.Type = api.InstanceTypeVM
+	}
+	if len(*userdata) > 0 {
+		details.Userdata = userdata
+	}
+
+	switch {
+	case len(*app) > 0 && len(*image) == 0:
+		details.ApplicationID = *app
+		if *version >= 0 {
+			details.ApplicationVersion = version
+		}
+	case len(*image) > 0 && len(*app) == 0:
+		details.ImageID = *image
+		if *version >= 0 {
+			details.ImageVersion = version
+		}
+	default:
+		return fmt.Errorf("Either an application or an image has to be given")
+	}
+
+	op, err := c.LaunchInstance(details, false)
+	if err != nil {
+		return err
+	}
+	return wait(op)
+}
+
+func runStart(c client.Client, args []string) error {
+	id, err := instanceID("start", args)
+	if err != nil {
+		return err
+	}
+	op, err := c.StartInstance(id, false)
+	if err != nil {
+		return err
+	}
+	return op.Wait(context.Background())
+}
+
+func runStop(c client.Client, args []string) error {
+	id, err := instanceID("stop", args)
+	if err != nil {
+		return err
+	}
+	op, err := c.StopInstance(id, false)
+	if err != nil {
+		return err
+	}
+	return op.Wait(context.Background())
+}
+
+func runDelete(c client.Client, args []string) error {
+	flags := newFlagSet("delete")
+	id := flags.String("id", "", "Instance id")
+	force := flags.Bool("force", false, "Delete the instance even if it is running")
+	flags.Parse(args)
+	if len(*id) == 0 {
+		return errors.NewInvalidArgument("id")
+	}
+
+	op, err := c.DeleteInstanceByID(*id, *force)
+	if err != nil {
+		return err
+	}
+	return op.Wait(context.Background())
+}
+
+func runExec(c client.Client, args []string) error {
+	flags := newFlagSet("exec")
+	id := flags.String("id", "", "Instance id")
+	flags.Parse(args)
+	if len(*id) == 0 {
+		return errors.NewInvalidArgument("id")
+	}
+	if flags.NArg() == 0 {
+		return errors.NewInvalidArgument("command")
+	}
+
+	// Attaches the local terminal and passes the exit code of the command on
+	code, err := c.RunInteractiveShell(context.Background(), *id, flags.Args())
+	if err != nil {
+		return err
+	}
+	if code != 0 {
+		os.Exit(code)
+	}
+	return nil
+}
+
+func runLogs(c client.Client, args []string) error {
+	flags := newFlagSet("logs")
+	id := flags.String("id", "", "Instance id")
+	name := flags.String("name", "", "Name of the log file to show")
+	flags.Parse(args)
+	if len(*id) == 0 {
+		return errors.NewInvalidArgument("id")
+	}
+	if len(*name) == 0 {
+		return errors.NewInvalidArgument("name")
+	}
+
+	return c.RetrieveInstanceLog(*id, *name, func(header *http.Header, body io.ReadCloser) error {
+		_, err := io.Copy(os.Stdout, body)
+		return err
+	})
+}
+
+func runEvents(c client.Client, args []string) error {
+	listener, err := c.GetEvents()
+	if err != nil {
+		return err
+	}
+	defer listener.Disconnect()
+
+	_, err = listener.AddHandler(nil, func(event interface{}) {
+		common.DumpData(event)
+		fmt.Println()
+	})
+	if err != nil {
+		return err
+	}
+
+	// Print events until interrupted
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	<-interrupt
+	return nil
+}
+
+func runUpload(c client.Client, args []string) error {
+	flags := newFlagSet("upload")
+	kind := flags.String("type", "application", "Type of the package, either application or image")
+	path := flags.String("path", "", "Path of the package to upload")
+	name := flags.String("name", "", "Name of the image. Only used for images")
+	flags.Parse(args)
+	if len(*path) == 0 {
+		return errors.NewInvalidArgument("path")
+	}
+
+	// The channel receives the number of bytes sent with every write
+	sentBytes := make(chan float64)
+	go func() {
+		total := float64(0)
+		for n := range sentBytes {
+			total += n
+			fmt.Fprintf(os.Stderr, "\rUploaded %s bytes", strconv.FormatFloat(total, 'f', 0, 64))
+		}
+	}()
+
+	var op restclient.Operation
+	var err error
+	switch *kind {
+	case "application":
+		op, err = c.CreateApplication(*path, sentBytes)
+	case "image":
+		if len(*name) == 0 {
+			return errors.NewInvalidArgument("name")
+		}
+		op, err = c.AddImage(*name, *path, false, sentBytes)
+	default:
+		return errors.NewInvalidArgument("type")
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr)
+	return wait(op)
+}
@@ -0,0 +1,105 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+
+	"github.com/anbox-cloud/ams-sdk/examples/ams/common"
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/client"
+)
+
+// command describes a single sub command of the CLI
+type command struct {
+	usage string
+	run   func(c client.Client, args []string) error
+}
+
+var commands = map[string]command{
+	"status": {"Show the status of the AMS service", runStatus},
+	"list":   {"List all instances", runList},
+	"show":   {"Show the details of an instance", runShow},
+	"launch": {"Launch a new instance", runLaunch},
+	"start":  {"Start an instance", runStart},
+	"stop":   {"Stop an instance", runStop},
+	"delete": {"Delete an instance", runDelete},
+	"exec":   {"Execute a command inside an instance", runExec},
+	"logs":   {"Show a log file of an instance", runLogs},
+	"events": {"Print the events of the AMS service", runEvents},
+	"upload": {"Upload an application or image package", runUpload},
+}
+
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [connection flags] <command> [flags]\n\nCommands:\n", os.Args[0])
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(flag.CommandLine.Output(), "  %-8s %s\n", name, commands[name].usage)
+	}
+	fmt.Fprintf(flag.CommandLine.Output(), "\nConnection flags:\n")
+	flag.PrintDefaults()
+}
+
+// newClient connects with the given flags or, if no URL is given, with the
+// settings of the AMS_* environment variables
+func newClient(conn *common.ConnectionCmd) client.Client {
+	if len(conn.ServiceURL) == 0 {
+		c, err := client.NewClientFromEnv()
+		if err != nil {
+			log.Fatal(err)
+		}
+		return c
+	}
+
+	if err := conn.Validate(); err != nil {
+		log.Fatal(err)
+	}
+	return conn.NewClient()
+}
+
+func main() {
+	conn := &common.ConnectionCmd{}
+	flag.StringVar(&conn.ClientCert, "cert", "", "Path to the file with the client certificate to use to connect to AMS")
+	flag.StringVar(&conn.ClientKey, "key", "", "Path to the file with the client key to use to connect to AMS")
+	flag.StringVar(&conn.ServiceURL, "url", "", "URL of the AMS server. If not set the AMS_* environment variables are used")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(1)
+	}
+	cmd, ok := commands[flag.Arg(0)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", flag.Arg(0))
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	if err := cmd.run(newClient(conn), flag.Args()[1:]); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,111 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/amstest"
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/client"
+	"github.com/anbox-cloud/ams-sdk/pkg/network"
+)
+
+type integrationCmd struct {
+	clientCert string
+	clientKey  string
+	serviceURL string
+	opts       amstest.Options
+}
+
+func (c *integrationCmd) parse() {
+	flag.StringVar(&c.clientCert, "cert", "", "Path to the file with the client certificate to use to connect to AMS")
+	flag.StringVar(&c.clientKey, "key", "", "Path to the file with the client key to use to connect to AMS")
+	flag.StringVar(&c.serviceURL, "url", "", "URL of the AMS server. If not set an in-process test server is started")
+	flag.StringVar(&c.opts.ImageID, "image", "", "Image to launch an instance from for the instance lifecycle check")
+	flag.StringVar(&c.opts.ApplicationID, "application", "", "Application to launch an instance of for the instance lifecycle check")
+	flag.DurationVar(&c.opts.Timeout, "timeout", 5*time.Minute, "Time a single check can take")
+	flag.Parse()
+}
+
+func (c *integrationCmd) newClient() (client.Client, error) {
+	if len(c.clientCert) == 0 || len(c.clientKey) == 0 {
+		return nil, fmt.Errorf("Please provide a certificate and key path")
+	}
+
+	u, err := url.Parse(c.serviceURL)
+	if err != nil {
+		return nil, err
+	}
+	serverCert, err := network.GetRemoteCertificate(c.serviceURL)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := network.GetTLSConfig(c.clientCert, c.clientKey, "", serverCert)
+	if err != nil {
+		return nil, err
+	}
+	return client.New(u, tlsConfig)
+}
+
+func main() {
+	cmd := &integrationCmd{}
+	cmd.parse()
+
+	var c client.Client
+	var err error
+	if len(cmd.serviceURL) == 0 {
+		srv := amstest.NewServer()
+		defer srv.Close()
+		c, err = srv.Client()
+		// The test server launches instances without any image
+		if len(cmd.opts.ImageID) == 0 && len(cmd.opts.ApplicationID) == 0 {
+			cmd.opts.ImageID = "default"
+		}
+	} else {
+		c, err = cmd.newClient()
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	failed := 0
+	results := amstest.Run(context.Background(), c, cmd.opts, amstest.Checks(cmd.opts)...)
+	for _, result := range results {
+		status := "PASS"
+		if result.Err != nil {
+			status = "FAIL"
+			failed++
+		}
+		fmt.Printf("%s\t%s\t%v\n", status, result.Name, result.Duration.Round(time.Millisecond))
+		if result.Err != nil {
+			fmt.Printf("\t%v\n", result.Err)
+		}
+	}
+
+	if failed > 0 {
+		fmt.Printf("%d of %d checks failed\n", failed, len(results))
+		os.Exit(1)
+	}
+}
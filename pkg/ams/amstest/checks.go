@@ -0,0 +1,176 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package amstest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	api "github.com/anbox-cloud/ams-sdk/api/ams"
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/client"
+)
+
+// Options control the resources the checks use
+type Options struct {
+	// ImageID is the image the instance lifecycle check launches a raw
+	// instance from. Set either ImageID or ApplicationID.
+	ImageID string
+	// ApplicationID is the application the instance lifecycle check launches
+	// an instance of
+	ApplicationID string
+	// Timeout limits the time a single check can take. Defaults to 5 minutes.
+	Timeout time.Duration
+}
+
+// Check describes a single conformance check
+type Check struct {
+	Name string
+	Run  func(ctx context.Context, c client.Client) error
+}
+
+// Result holds the outcome of a single check
+type Result struct {
+	Name     string
+	Err      error
+	Duration time.Duration
+}
+
+// Checks returns the default set of conformance checks. The instance lifecycle
+// check is only included if an image or application is set in the options.
+func Checks(opts Options) []Check {
+	checks := []Check{
+		{Name: "service-status", Run: checkServiceStatus},
+		{Name: "list-nodes", Run: checkListNodes},
+		{Name: "list-applications", Run: checkListApplications},
+		{Name: "list-instances", Run: checkListInstances},
+		{Name: "list-containers", Run: checkListContainers},
+	}
+	if len(opts.ImageID) > 0 || len(opts.ApplicationID) > 0 {
+		checks = append(checks, Check{
+			Name: "instance-lifecycle",
+			Run: func(ctx context.Context, c client.Client) error {
+				return checkInstanceLifecycle(ctx, c, opts)
+			},
+		})
+	}
+	return checks
+}
+
+// Run runs the given checks one after another against the client and returns
+// their results. A failing check does not stop the remaining checks.
+func Run(ctx context.Context, c client.Client, opts Options, checks ...Check) []Result {
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Minute
+	}
+
+	results := make([]Result, 0, len(checks))
+	for _, check := range checks {
+		checkCtx, cancel := context.WithTimeout(ctx, timeout)
+		start := time.Now()
+		err := check.Run(checkCtx, c)
+		cancel()
+		results = append(results, Result{
+			Name:     check.Name,
+			Err:      err,
+			Duration: time.Since(start),
+		})
+	}
+	return results
+}
+
+func checkServiceStatus(ctx context.Context, c client.Client) error {
+	status, _, err := c.RetrieveServiceStatus()
+	if err != nil {
+		return err
+	}
+	if len(status.APIVersion) == 0 {
+		return fmt.Errorf("service status does not include an API version")
+	}
+	return nil
+}
+
+func checkListNodes(ctx context.Context, c client.Client) error {
+	nodes, err := c.ListNodes()
+	if err != nil {
+		return err
+	}
+	if len(nodes) == 0 {
+		return fmt.Errorf("no nodes available")
+	}
+	return nil
+}
+
+func checkListApplications(ctx context.Context, c client.Client) error {
+	_, err := c.ListApplications()
+	return err
+}
+
+func checkListInstances(ctx context.Context, c client.Client) error {
+	_, err := c.ListInstances()
+	return err
+}
+
+func checkListContainers(ctx context.Context, c client.Client) error {
+	_, err := c.ListContainers()
+	return err
+}
+
+func checkInstanceLifecycle(ctx context.Context, c client.Client, opts Options) error {
+	results, err := c.LaunchInstances(&api.InstancesPost{
+		ImageID:       opts.ImageID,
+		ApplicationID: opts.ApplicationID,
+	}, 1, false)
+	if err != nil {
+		return err
+	}
+	result := results[0]
+	if result.Err != nil {
+		return result.Err
+	}
+
+	defer func() {
+		if operation, err := c.DeleteInstanceByID(result.ID, true); err == nil {
+			operation.Wait(context.Background())
+		}
+	}()
+
+	instance, err := c.WaitForInstanceStatus(ctx, result.ID, api.InstanceStatusRunning)
+	if err != nil {
+		return err
+	}
+	if instance.ID != result.ID {
+		return fmt.Errorf("retrieved instance %s instead of %s", instance.ID, result.ID)
+	}
+
+	operation, err := c.UpdateInstanceByID(result.ID, &api.InstancePatch{DesiredStatus: strPtr("stopped")}, false)
+	if err != nil {
+		return err
+	}
+	if err := operation.Wait(ctx); err != nil {
+		return err
+	}
+	_, err = c.WaitForInstanceStatus(ctx, result.ID, api.InstanceStatusStopped)
+	return err
+}
+
+func strPtr(s string) *string {
+	return &s
+}
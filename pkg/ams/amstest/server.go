@@ -0,0 +1,339 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package amstest provides a minimal AMS compatible server and a set of
+// conformance checks exercising the SDK against it. The same checks can be
+// run against a real AMS deployment to validate custom patches of the SDK or
+// new AMS releases.
+package amstest
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	api "github.com/anbox-cloud/ams-sdk/api/ams"
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/client"
+	restapi "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/api"
+	restclient "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/client"
+)
+
+// DefaultExtensions are the API extensions the test server advertises if no
+// others are given
+var DefaultExtensions = []string{api.ExtensionInstanceSupport}
+
+// Server is a minimal in-memory implementation of the AMS REST API. It
+// supports the endpoints for the service status, nodes, applications,
+// instances and operations. All operations complete immediately.
+type Server struct {
+	srv        *httptest.Server
+	extensions []string
+
+	lock       sync.Mutex
+	nodes      []api.Node
+	instances  map[string]*api.Instance
+	operations map[string]*restapi.Operation
+	lastID     int
+}
+
+// NewServer starts a new test server advertising the given API extensions. If
+// none are given DefaultExtensions are used. The server has a single node
+// named "lxd0".
+func NewServer(extensions ...string) *Server {
+	if len(extensions) == 0 {
+		extensions = DefaultExtensions
+	}
+
+	s := &Server{
+		extensions: extensions,
+		nodes: []api.Node{{
+			Name:       "lxd0",
+			Address:    "127.0.0.1",
+			StatusCode: api.NodeStatusOnline,
+			Status:     "online",
+		}},
+		instances:  map[string]*api.Instance{},
+		operations: map[string]*restapi.Operation{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/1.0", s.handleStatus)
+	mux.HandleFunc("/1.0/nodes", s.handleNodes)
+	mux.HandleFunc("/1.0/applications", s.handleApplications)
+	mux.HandleFunc("/1.0/instances", s.handleInstances)
+	mux.HandleFunc("/1.0/instances/", s.handleInstance)
+	mux.HandleFunc("/1.0/operations/", s.handleOperation)
+	s.srv = httptest.NewTLSServer(mux)
+	return s
+}
+
+// URL returns the URL of the server
+func (s *Server) URL() string {
+	return s.srv.URL
+}
+
+// TLSConfig returns a TLS configuration trusting the certificate of the server
+func (s *Server) TLSConfig() *tls.Config {
+	pool := x509.NewCertPool()
+	pool.AddCert(s.srv.Certificate())
+	return &tls.Config{RootCAs: pool}
+}
+
+// Client returns a new client connected to the server
+func (s *Server) Client(opts ...restclient.Option) (client.Client, error) {
+	u, err := url.Parse(s.srv.URL)
+	if err != nil {
+		return nil, err
+	}
+	return client.New(u, s.TLSConfig(), opts...)
+}
+
+// Close shuts the server down
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+func (s *Server) nextID() string {
+	s.lastID++
+	return fmt.Sprintf("amstest%013d", s.lastID)
+}
+
+func writeSync(w http.ResponseWriter, metadata interface{}) {
+	b, err := json.Marshal(metadata)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(restapi.Response{
+		Type:       restapi.ResponseTypeSync,
+		Status:     restapi.Success.String(),
+		StatusCode: int(restapi.Success),
+		Metadata:   b,
+	})
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(restapi.Response{
+		Type:  restapi.ResponseTypeError,
+		Code:  code,
+		Error: msg,
+	})
+}
+
+// writeOperation creates a completed operation affecting the given resources
+// and writes the async response for it
+func (s *Server) writeOperation(w http.ResponseWriter, description string, resources map[string][]string) {
+	now := time.Now()
+	op := &restapi.Operation{
+		ID:          s.nextID(),
+		Class:       "task",
+		Description: description,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		Status:      restapi.Success.String(),
+		StatusCode:  restapi.Success,
+		Resources:   resources,
+		Metadata:    map[string]interface{}{},
+	}
+	s.operations[op.ID] = op
+
+	b, _ := json.Marshal(op)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", path.Join("/1.0/operations", op.ID))
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(restapi.Response{
+		Type:       restapi.ResponseTypeAsync,
+		Status:     restapi.OperationCreated.String(),
+		StatusCode: int(restapi.OperationCreated),
+		Operation:  path.Join("/1.0/operations", op.ID),
+		Metadata:   b,
+	})
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	writeSync(w, api.ServiceStatus{
+		APIExtensions: s.extensions,
+		APIStatus:     "stable",
+		APIVersion:    restapi.Version,
+		Auth:          "trusted",
+		AuthMethods:   []string{"2waySSL"},
+	})
+}
+
+func (s *Server) handleNodes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	writeSync(w, s.nodes)
+}
+
+func (s *Server) handleApplications(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	writeSync(w, []api.Application{})
+}
+
+func (s *Server) handleInstances(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	switch r.Method {
+	case http.MethodGet:
+		instances := []api.Instance{}
+		for _, instance := range s.instances {
+			instances = append(instances, *instance)
+		}
+		writeSync(w, instances)
+	case http.MethodPost:
+		details := api.InstancesPost{}
+		if err := json.NewDecoder(r.Body).Decode(&details); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if len(details.ApplicationID) == 0 && len(details.ImageID) == 0 {
+			writeError(w, http.StatusBadRequest, "either application or image must be specified")
+			return
+		}
+
+		instanceType := details.Type
+		if instanceType == api.InstanceTypeAny {
+			instanceType = api.InstanceTypeContainer
+		}
+		node := details.Node
+		if len(node) == 0 {
+			node = s.nodes[0].Name
+		}
+
+		status := api.InstanceStatusRunning
+		if details.NoStart {
+			status = api.InstanceStatusStopped
+		}
+
+		id := s.nextID()
+		name := details.Name
+		if len(name) == 0 {
+			name = fmt.Sprintf("ams-%s", id)
+		}
+		s.instances[id] = &api.Instance{
+			ID:         id,
+			Name:       name,
+			Type:       instanceType,
+			StatusCode: status,
+			Status:     status.String(),
+			Node:       node,
+			AppID:      details.ApplicationID,
+			ImageID:    details.ImageID,
+			CreatedAt:  time.Now().Unix(),
+			Tags:       details.Tags,
+		}
+		s.writeOperation(w, "Creating instance", map[string][]string{
+			"instances": {path.Join("/1.0/instances", id)},
+		})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) handleInstance(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/1.0/instances/")
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	instance, ok := s.instances[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeSync(w, instance)
+	case http.MethodPatch:
+		details := api.InstancePatch{}
+		if err := json.NewDecoder(r.Body).Decode(&details); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if details.DesiredStatus != nil {
+			switch *details.DesiredStatus {
+			case "running":
+				instance.StatusCode = api.InstanceStatusRunning
+			case "stopped":
+				instance.StatusCode = api.InstanceStatusStopped
+			default:
+				writeError(w, http.StatusBadRequest, "invalid desired status")
+				return
+			}
+			instance.Status = instance.StatusCode.String()
+		}
+		s.writeOperation(w, "Updating instance", map[string][]string{
+			"instances": {path.Join("/1.0/instances", id)},
+		})
+	case http.MethodDelete:
+		delete(s.instances, id)
+		s.writeOperation(w, "Deleting instance", map[string][]string{
+			"instances": {path.Join("/1.0/instances", id)},
+		})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) handleOperation(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/1.0/operations/"), "/wait")
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	op, ok := s.operations[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeSync(w, op)
+	case http.MethodDelete:
+		// Operations complete immediately and can't be cancelled anymore
+		writeError(w, http.StatusBadRequest, "operation can not be cancelled")
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
@@ -0,0 +1,359 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package autoscale keeps the size of a fleet of instances in line with its
+// load. AMS lifecycle events and a periodic interval trigger the evaluation
+// of user supplied scale-up and scale-down policies. The autoscaler debounces
+// bursts of events, enforces cooldowns between scaling actions and waits for
+// the operations of a scaling action before evaluating the policies again.
+package autoscale
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	api "github.com/anbox-cloud/ams-sdk/api/ams"
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/client"
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+	restclient "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/client"
+)
+
+const (
+	defaultInterval          = 30 * time.Second
+	defaultDebounce          = 5 * time.Second
+	defaultScaleUpCooldown   = time.Minute
+	defaultScaleDownCooldown = 5 * time.Minute
+)
+
+// Trigger describes what caused an evaluation of the policies
+type Trigger string
+
+const (
+	// TriggerStart is the evaluation done when the autoscaler starts
+	TriggerStart Trigger = "start"
+	// TriggerInterval is a periodic evaluation
+	TriggerInterval Trigger = "interval"
+	// TriggerEvent is an evaluation caused by AMS lifecycle events
+	TriggerEvent Trigger = "event"
+)
+
+// Snapshot describes the state of the fleet the policies are evaluated on
+type Snapshot struct {
+	Time    time.Time
+	Trigger Trigger
+	// Events is the number of lifecycle events received since the last evaluation
+	Events int
+	// Instances lists all instances of the fleet
+	Instances []api.Instance
+	// Running is the number of instances which are running
+	Running int
+	// Pending is the number of instances which are not running yet
+	Pending int
+	// Failed is the number of instances in error status
+	Failed int
+	// Capacity is the utilization of the cluster
+	Capacity *client.ClusterCapacity
+	// Metrics holds the values returned by the Metrics function of the autoscaler
+	Metrics map[string]float64
+}
+
+// Size returns the number of instances of the fleet which are not failed
+func (s *Snapshot) Size() int {
+	return s.Running + s.Pending
+}
+
+// Policy returns the number of instances to add or remove. It is called with
+// the current state of the fleet on every evaluation.
+type Policy func(ctx context.Context, s *Snapshot) (int, error)
+
+// ScaleEvent describes a scaling action taken by the autoscaler
+type ScaleEvent struct {
+	Time    time.Time
+	Trigger Trigger
+	// From is the size of the fleet before the action
+	From int
+	// To is the size of the fleet the action converged to
+	To int
+	// Changes lists the instances launched and deleted
+	Changes []client.ContainerFleetChange
+	// Err is set when the action failed
+	Err error
+}
+
+// Autoscaler scales a fleet of instances according to its policies
+type Autoscaler struct {
+	// Client is used to talk to AMS
+	Client client.Client
+	// Fleet describes the instances to scale. The replicas of the spec are
+	// ignored.
+	Fleet client.ContainerFleetSpec
+	// Min is the minimum size of the fleet
+	Min int
+	// Max is the maximum size of the fleet. Zero means no limit.
+	Max int
+	// ScaleUp returns the number of instances to add. Optional.
+	ScaleUp Policy
+	// ScaleDown returns the number of instances to remove. Optional. It is
+	// ignored when ScaleUp asks for more instances.
+	ScaleDown Policy
+	// Metrics returns additional values, e.g. the number of active streaming
+	// sessions, which are passed to the policies. Optional.
+	Metrics func(ctx context.Context) (map[string]float64, error)
+	// Interval between two periodic evaluations. Defaults to 30 seconds.
+	Interval time.Duration
+	// Debounce is the time events are collected for before they trigger an
+	// evaluation. Defaults to 5 seconds.
+	Debounce time.Duration
+	// ScaleUpCooldown is the minimum time between two scale-ups. Defaults
+	// to 1 minute.
+	ScaleUpCooldown time.Duration
+	// ScaleDownCooldown is the minimum time between any scaling action and a
+	// following scale-down. Defaults to 5 minutes.
+	ScaleDownCooldown time.Duration
+	// OnScale is called after every scaling action. Optional.
+	OnScale func(e ScaleEvent)
+	// OnError is called when an evaluation fails. Optional.
+	OnError func(err error)
+
+	lastScaleUp time.Time
+	lastScale   time.Time
+	events      int64
+}
+
+// Run evaluates the policies until the given context is cancelled and
+// returns the error of the context. Failed evaluations are reported through
+// OnError and do not stop the autoscaler. When the event stream breaks the
+// autoscaler falls back to the periodic evaluation and reconnects on the next
+// interval.
+func (a *Autoscaler) Run(ctx context.Context) error {
+	if a.Client == nil {
+		return errs.NewInvalidArgument("client")
+	}
+	if a.Min < 0 || (a.Max > 0 && a.Max < a.Min) {
+		return errs.NewInvalidArgument("min")
+	}
+	if len(a.Fleet.Details.ApplicationID) == 0 && len(a.Fleet.Details.ImageID) == 0 {
+		return errs.NewInvalidArgument("fleet")
+	}
+
+	interval := durationOrDefault(a.Interval, defaultInterval)
+	debounce := durationOrDefault(a.Debounce, defaultDebounce)
+
+	notify := make(chan struct{}, 1)
+	var listener *restclient.EventListener
+	subscribe := func() {
+		if listener != nil && listener.IsActive() {
+			return
+		}
+		l, err := a.Client.GetEvents()
+		if err != nil {
+			a.reportError(err)
+			return
+		}
+		_, err = l.AddHandler([]string{string(api.EventTypeLifecycle)}, func(data interface{}) {
+			atomic.AddInt64(&a.events, 1)
+			select {
+			case notify <- struct{}{}:
+			default:
+			}
+		})
+		if err != nil {
+			l.Disconnect()
+			a.reportError(err)
+			return
+		}
+		listener = l
+	}
+	defer func() {
+		if listener != nil {
+			listener.Disconnect()
+		}
+	}()
+
+	subscribe()
+	a.evaluate(ctx, TriggerStart)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var debounceTimer *time.Timer
+	var debounced <-chan time.Time
+	defer func() {
+		if debounceTimer != nil {
+			debounceTimer.Stop()
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			subscribe()
+			a.evaluate(ctx, TriggerInterval)
+		case <-notify:
+			// Collect following events until the debounce period is over
+			if debounced == nil {
+				debounceTimer = time.NewTimer(debounce)
+				debounced = debounceTimer.C
+			}
+		case <-debounced:
+			debounced = nil
+			a.evaluate(ctx, TriggerEvent)
+		}
+	}
+}
+
+// evaluate runs the policies on the current state of the fleet and scales
+// the fleet if needed
+func (a *Autoscaler) evaluate(ctx context.Context, trigger Trigger) {
+	snapshot, err := a.snapshot(ctx, trigger)
+	if err != nil {
+		a.reportError(err)
+		return
+	}
+
+	up, err := runPolicy(ctx, a.ScaleUp, snapshot)
+	if err != nil {
+		a.reportError(err)
+		return
+	}
+	down := 0
+	if up <= 0 {
+		down, err = runPolicy(ctx, a.ScaleDown, snapshot)
+		if err != nil {
+			a.reportError(err)
+			return
+		}
+	}
+
+	now := time.Now()
+	current := snapshot.Size()
+	desired := current
+	if up > 0 && now.Sub(a.lastScaleUp) >= durationOrDefault(a.ScaleUpCooldown, defaultScaleUpCooldown) {
+		desired += up
+	} else if down > 0 && now.Sub(a.lastScale) >= durationOrDefault(a.ScaleDownCooldown, defaultScaleDownCooldown) {
+		desired -= down
+	}
+	// The limits are enforced regardless of the cooldowns
+	if desired < a.Min {
+		desired = a.Min
+	}
+	if a.Max > 0 && desired > a.Max {
+		desired = a.Max
+	}
+
+	// Failed instances are replaced even if the size does not change
+	if desired == current && snapshot.Failed == 0 {
+		return
+	}
+
+	spec := a.Fleet
+	spec.Replicas = desired
+	changes, err := a.Client.EnsureContainerFleet(ctx, &spec, nil)
+	if err == nil {
+		for _, change := range changes {
+			if change.Err != nil {
+				err = change.Err
+				break
+			}
+		}
+	}
+
+	if desired > current {
+		a.lastScaleUp = now
+	}
+	if desired != current {
+		a.lastScale = now
+	}
+	if a.OnScale != nil {
+		a.OnScale(ScaleEvent{
+			Time:    now,
+			Trigger: trigger,
+			From:    current,
+			To:      desired,
+			Changes: changes,
+			Err:     err,
+		})
+	}
+}
+
+// snapshot collects the current state of the fleet
+func (a *Autoscaler) snapshot(ctx context.Context, trigger Trigger) (*Snapshot, error) {
+	filter := "app_id=" + a.Fleet.Details.ApplicationID
+	if len(a.Fleet.Details.ApplicationID) == 0 {
+		filter = "image_id=" + a.Fleet.Details.ImageID
+	}
+	instances, err := a.Client.ListInstancesWithFilters([]string{filter})
+	if err != nil {
+		return nil, err
+	}
+	capacity, err := a.Client.RetrieveClusterCapacity()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Snapshot{
+		Time:     time.Now(),
+		Trigger:  trigger,
+		Events:   int(atomic.SwapInt64(&a.events, 0)),
+		Capacity: capacity,
+	}
+	for _, instance := range instances {
+		if !a.Fleet.Contains(&instance) {
+			continue
+		}
+		s.Instances = append(s.Instances, instance)
+		switch instance.StatusCode {
+		case api.InstanceStatusRunning:
+			s.Running++
+		case api.InstanceStatusError:
+			s.Failed++
+		default:
+			s.Pending++
+		}
+	}
+
+	if a.Metrics != nil {
+		s.Metrics, err = a.Metrics(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func (a *Autoscaler) reportError(err error) {
+	if a.OnError != nil {
+		a.OnError(err)
+	}
+}
+
+func runPolicy(ctx context.Context, p Policy, s *Snapshot) (int, error) {
+	if p == nil {
+		return 0, nil
+	}
+	return p(ctx, s)
+}
+
+func durationOrDefault(d, def time.Duration) time.Duration {
+	if d <= 0 {
+		return def
+	}
+	return d
+}
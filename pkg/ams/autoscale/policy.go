@@ -0,0 +1,74 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package autoscale
+
+import (
+	"context"
+	"math"
+)
+
+// MetricAbove returns a policy which asks for step instances when the metric
+// with the given name is above the threshold. A missing metric never
+// triggers the policy.
+func MetricAbove(name string, threshold float64, step int) Policy {
+	return func(ctx context.Context, s *Snapshot) (int, error) {
+		if value, ok := s.Metrics[name]; ok && value > threshold {
+			return step, nil
+		}
+		return 0, nil
+	}
+}
+
+// MetricBelow returns a policy which asks for step instances when the metric
+// with the given name is below the threshold. A missing metric never
+// triggers the policy.
+func MetricBelow(name string, threshold float64, step int) Policy {
+	return func(ctx context.Context, s *Snapshot) (int, error) {
+		if value, ok := s.Metrics[name]; ok && value < threshold {
+			return step, nil
+		}
+		return 0, nil
+	}
+}
+
+// PerInstance returns a scale-up policy which sizes the fleet so that every
+// instance serves at most perInstance units of the metric with the given
+// name, e.g. streaming sessions.
+func PerInstance(name string, perInstance float64) Policy {
+	return func(ctx context.Context, s *Snapshot) (int, error) {
+		value, ok := s.Metrics[name]
+		if !ok || perInstance <= 0 {
+			return 0, nil
+		}
+		return int(math.Ceil(value/perInstance)) - s.Size(), nil
+	}
+}
+
+// Headroom returns a scale-up policy which keeps the given number of
+// instances on top of the value of the metric with the given name, e.g. to
+// always have idle instances ready for new sessions.
+func Headroom(name string, spare int) Policy {
+	return func(ctx context.Context, s *Snapshot) (int, error) {
+		value, ok := s.Metrics[name]
+		if !ok {
+			return 0, nil
+		}
+		return int(math.Ceil(value)) + spare - s.Size(), nil
+	}
+}
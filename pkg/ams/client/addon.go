@@ -19,6 +19,7 @@
 package client
 
 import (
+	"io"
 	"strconv"
 
 	api "github.com/anbox-cloud/ams-sdk/api/ams"
@@ -27,18 +28,40 @@ import (
 )
 
 // AddAddon adds a new addon and uploads the given addon package to AMS
-func (c *clientImpl) AddAddon(name string, packagePath string, sentBytes chan float64) (client.Operation, error) {
+func (c *clientImpl) AddAddon(name string, packagePath string, sentBytes chan float64, opts ...RequestOption) (client.Operation, error) {
 	details := api.AddonsPost{Name: name}
-	return c.upload("POST", client.APIPath("addons"), nil, packagePath, details, sentBytes)
+	return c.upload("POST", client.APIPath("addons"), nil, packagePath, details, sentBytes, newRequestOptions(opts))
+}
+
+// CreateAddon adds a new addon and streams the addon package read from the
+// given payload to AMS
+func (c *clientImpl) CreateAddon(name string, payload io.ReadSeeker, sentBytes chan float64, opts ...RequestOption) (client.Operation, error) {
+	if len(name) == 0 {
+		return nil, errs.NewInvalidArgument("name")
+	}
+	details := api.AddonsPost{Name: name}
+	return c.uploadStream("POST", client.APIPath("addons"), nil, payload, details, sentBytes, newRequestOptions(opts))
 }
 
 // UpdateAddon updates an existing addon
-func (c *clientImpl) UpdateAddon(name, packagePath string, sentBytes chan float64) (client.Operation, error) {
+func (c *clientImpl) UpdateAddon(name, packagePath string, sentBytes chan float64, opts ...RequestOption) (client.Operation, error) {
 	if len(name) == 0 {
 		return nil, errs.NewInvalidArgument("name")
 	}
+	o := newRequestOptions(opts)
 	details := api.AddonPatch{}
-	return c.upload("PATCH", client.APIPath("addons", name), nil, packagePath, details, sentBytes)
+	return c.upload("PATCH", client.APIPath("addons", name), nil, packagePath, details, sentBytes, o)
+}
+
+// UpdateAddonWithPayload updates an existing addon by streaming a new version
+// of the addon package read from the given payload to AMS
+func (c *clientImpl) UpdateAddonWithPayload(name string, payload io.ReadSeeker, sentBytes chan float64, opts ...RequestOption) (client.Operation, error) {
+	if len(name) == 0 {
+		return nil, errs.NewInvalidArgument("name")
+	}
+	o := newRequestOptions(opts)
+	details := api.AddonPatch{}
+	return c.uploadStream("PATCH", client.APIPath("addons", name), nil, payload, details, sentBytes, o)
 }
 
 // RetrieveAddon loads an addon from the connected AMS service
@@ -78,3 +101,12 @@ func (c *clientImpl) ListAddons() ([]api.Addon, error) {
 	_, err := c.QueryStruct("GET", client.APIPath("addons"), params, nil, nil, "", &addons)
 	return addons, err
 }
+
+// ListAddonVersions lists all versions of the given addon
+func (c *clientImpl) ListAddonVersions(name string) ([]api.AddonVersion, error) {
+	addon, _, err := c.RetrieveAddon(name)
+	if err != nil {
+		return nil, err
+	}
+	return addon.Versions, nil
+}
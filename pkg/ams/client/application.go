@@ -21,7 +21,6 @@ package client
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"io"
 	"net/http"
 	"strconv"
@@ -39,16 +38,16 @@ type ApplicationCreateArgs struct {
 }
 
 // CreateApplication creates a new application
-func (c *clientImpl) CreateApplication(packagePath string, sentBytes chan float64) (client.Operation, error) {
+func (c *clientImpl) CreateApplication(packagePath string, sentBytes chan float64, opts ...RequestOption) (client.Operation, error) {
 	return c.CreateApplicationWithArgs(&ApplicationCreateArgs{
 		PackagePath:   packagePath,
 		SentBytesChan: sentBytes,
-	})
+	}, opts...)
 }
 
 // CreateApplicationWithArgs creates a new application based on the provided arguments
-func (c *clientImpl) CreateApplicationWithArgs(args *ApplicationCreateArgs) (client.Operation, error) {
-	hasVMSupport, err := c.HasExtension("vm_support")
+func (c *clientImpl) CreateApplicationWithArgs(args *ApplicationCreateArgs, opts ...RequestOption) (client.Operation, error) {
+	hasVMSupport, err := c.HasExtension(api.ExtensionVMSupport)
 	if err != nil {
 		return nil, err
 	}
@@ -59,22 +58,23 @@ func (c *clientImpl) CreateApplicationWithArgs(args *ApplicationCreateArgs) (cli
 		"vm": strconv.FormatBool(args.VM),
 	}
 	return c.upload("POST", client.APIPath("applications"), params,
-		args.PackagePath, nil, args.SentBytesChan)
+		args.PackagePath, nil, args.SentBytesChan, newRequestOptions(opts))
 }
 
 // UpdateApplicationWithDetails updates specific fields of an existing application
-func (c *clientImpl) UpdateApplicationWithDetails(id string, details api.ApplicationPatch) error {
+func (c *clientImpl) UpdateApplicationWithDetails(id string, details api.ApplicationPatch, opts ...RequestOption) error {
 	if len(id) == 0 {
 		return errs.NewInvalidArgument("id")
 	}
+	o := newRequestOptions(opts)
 
-	b, err := json.Marshal(details)
+	b, err := c.Codec().Marshal(details)
 	if err != nil {
 		return err
 	}
 
 	header := http.Header{"Content-Type": []string{"application/json"}}
-	op, _, err := c.QueryOperation("PATCH", client.APIPath("applications", id), nil, header, bytes.NewReader(b), "")
+	op, _, err := c.QueryOperation("PATCH", client.APIPath("applications", id), nil, o.headerWith(header), bytes.NewReader(b), o.etag)
 	if err != nil {
 		return err
 	}
@@ -83,21 +83,23 @@ func (c *clientImpl) UpdateApplicationWithDetails(id string, details api.Applica
 }
 
 // UpdateApplicationWithPackage updates an existing application
-func (c *clientImpl) UpdateApplicationWithPackage(id, packagePath string, sentBytes chan float64) (client.Operation, error) {
+func (c *clientImpl) UpdateApplicationWithPackage(id, packagePath string, sentBytes chan float64, opts ...RequestOption) (client.Operation, error) {
 	if len(id) == 0 {
 		return nil, errs.NewInvalidArgument("id")
 	}
-	return c.upload("PATCH", client.APIPath("applications", id), nil, packagePath, nil, sentBytes)
+	o := newRequestOptions(opts)
+	return c.upload("PATCH", client.APIPath("applications", id), nil, packagePath, nil, sentBytes, o)
 }
 
 // UpdateApplication updates an existing application
-func (c *clientImpl) UpdateApplication(id string) (client.Operation, error) {
+func (c *clientImpl) UpdateApplication(id string, opts ...RequestOption) (client.Operation, error) {
 	if len(id) == 0 {
 		return nil, errs.NewInvalidArgument("id")
 	}
+	o := newRequestOptions(opts)
 
 	header := http.Header{"Content-Type": []string{"application/json"}}
-	op, _, err := c.QueryOperation("PATCH", client.APIPath("applications", id), nil, header, nil, "")
+	op, _, err := c.QueryOperation("PATCH", client.APIPath("applications", id), nil, o.headerWith(header), nil, o.etag)
 	if err != nil {
 		return nil, err
 	}
@@ -153,7 +155,7 @@ func (c *clientImpl) DeleteApplicationByID(id string, force bool) (client.Operat
 		return nil, errs.NewInvalidArgument("id")
 	}
 	details := api.ApplicationDelete{Force: force}
-	b, err := json.Marshal(details)
+	b, err := c.Codec().Marshal(details)
 	if err != nil {
 		return nil, err
 	}
@@ -167,7 +169,7 @@ func (c *clientImpl) DeleteApplications(ids []string, force bool) (client.Operat
 		return nil, errs.NewInvalidArgument("ids")
 	}
 	details := api.ApplicationsDelete{IDs: ids, Force: force}
-	b, err := json.Marshal(details)
+	b, err := c.Codec().Marshal(details)
 	if err != nil {
 		return nil, err
 	}
@@ -183,7 +185,7 @@ func (c *clientImpl) ExportApplicationByVersion(id string, version int, download
 	if version < 0 {
 		return errs.NewInvalidArgument("version")
 	}
-	hasAppImageExportSupport, err := c.HasExtension("application_image_export")
+	hasAppImageExportSupport, err := c.HasExtension(api.ExtensionApplicationImageExport)
 	if err != nil {
 		return err
 	}
@@ -198,7 +200,7 @@ func (c *clientImpl) changeApplicationVersion(id string, version int, details *a
 	if len(id) == 0 {
 		return nil, errs.NewInvalidArgument("id")
 	}
-	b, err := json.Marshal(details)
+	b, err := c.Codec().Marshal(details)
 	if err != nil {
 		return nil, err
 	}
@@ -229,7 +231,7 @@ func (c *clientImpl) DeleteApplicationVersion(id string, version int, force bool
 		return nil, errs.NewInvalidArgument("version")
 	}
 	details := api.ApplicationVersionDelete{Force: force}
-	b, err := json.Marshal(details)
+	b, err := c.Codec().Marshal(details)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,184 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"context"
+	"fmt"
+
+	api "github.com/anbox-cloud/ams-sdk/api/ams"
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared"
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/client"
+)
+
+// ApplicationSpec describes the desired state of a single application for
+// EnsureApplication. In addition to the settings of a FleetApplicationSpec it
+// allows managing the resources and the manifest settings of the application.
+// Fields left nil are not managed and keep their current value.
+type ApplicationSpec struct {
+	FleetApplicationSpec `yaml:",inline"`
+	// PackagePath is the application package used to create the application
+	// when it does not exist yet
+	PackagePath string `json:"package_path,omitempty" yaml:"package_path,omitempty"`
+	// Resources overrides the resources of the instance type
+	Resources *api.ApplicationResources `json:"resources,omitempty" yaml:"resources,omitempty"`
+	// BootActivity is the Android activity started when an instance boots
+	BootActivity *string `json:"boot_activity,omitempty" yaml:"boot_activity,omitempty"`
+	// Features lists the features enabled for the application
+	Features *[]string `json:"features,omitempty" yaml:"features,omitempty"`
+	// Watchdog holds the watchdog settings of the application
+	Watchdog *api.ApplicationWatchdog `json:"watchdog,omitempty" yaml:"watchdog,omitempty"`
+}
+
+// EnsureApplication reconciles a single application towards the given spec
+// and returns the changes made. The application is created from the package
+// of the spec if it does not exist yet. Only settings which differ from the
+// current state are sent to AMS, so applying the same spec twice results in
+// no changes the second time. With the DryRun option the changes are only
+// computed.
+//
+// The manifest settings are compared against the latest version of the
+// application. Changing them makes AMS create a new version. When
+// PublishedVersions is nil the published state of the versions is left
+// untouched.
+func (c *clientImpl) EnsureApplication(ctx context.Context, spec *ApplicationSpec, opts *FleetApplyOptions) ([]FleetChange, error) {
+	if spec == nil {
+		return nil, errs.NewInvalidArgument("spec")
+	}
+	if len(spec.Name) == 0 {
+		return nil, errs.NewInvalidArgument("name")
+	}
+	if opts == nil {
+		opts = &FleetApplyOptions{}
+	}
+
+	app, err := c.findApplicationByName(spec.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []FleetChange{}
+	if app == nil {
+		change := FleetChange{Resource: FleetResourceApplication, Name: spec.Name, To: spec.Name}
+		if len(spec.PackagePath) == 0 {
+			change.Err = errs.NewErrNotFound(fmt.Sprintf("application %s", spec.Name))
+			return append(changes, change), nil
+		}
+		changes = append(changes, change)
+		if opts.DryRun {
+			return changes, nil
+		}
+		if err := waitFleetOperation(ctx, func() (client.Operation, error) {
+			return c.CreateApplication(spec.PackagePath, nil)
+		}); err != nil {
+			changes[0].Err = err
+			return changes, err
+		}
+		app, err = c.findApplicationByName(spec.Name)
+		if err != nil {
+			return changes, err
+		}
+		if app == nil {
+			return changes, errs.NewErrNotFound(fmt.Sprintf("application %s", spec.Name))
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return changes, err
+	}
+
+	existing := fleetApplicationSpec(app)
+	desired := spec.FleetApplicationSpec
+	if desired.PublishedVersions == nil {
+		desired.PublishedVersions = existing.PublishedVersions
+	}
+
+	patch, fieldChanges := fleetApplicationPatch(&existing, &desired)
+	fieldChanges = append(fieldChanges, ensureApplicationPatch(app, spec, &patch)...)
+	if len(fieldChanges) > 0 && !opts.DryRun {
+		if err := c.UpdateApplicationWithDetails(app.ID, patch); err != nil {
+			for n := range fieldChanges {
+				fieldChanges[n].Err = err
+			}
+		}
+	}
+	changes = append(changes, fieldChanges...)
+
+	return append(changes, c.applyFleetApplicationVersions(ctx, app.ID, &existing, &desired, opts.DryRun)...), nil
+}
+
+// ensureApplicationPatch adds the resource and manifest settings of the spec
+// which differ from the application to the patch
+func ensureApplicationPatch(app *api.Application, spec *ApplicationSpec, patch *api.ApplicationPatch) []FleetChange {
+	changes := []FleetChange{}
+	if spec.Resources != nil && *spec.Resources != app.Resources {
+		r := *spec.Resources
+		patch.Resources = &api.ApplicationResourcesPost{
+			CPUs:     &r.CPUs,
+			Memory:   &r.Memory,
+			DiskSize: &r.DiskSize,
+			GPUSlots: &r.GPUSlots,
+			VPUSlots: &r.VPUSlots,
+		}
+		changes = append(changes, fleetFieldChange(FleetResourceApplication, spec.Name, "resources",
+			fmt.Sprintf("%+v", app.Resources), fmt.Sprintf("%+v", r)))
+	}
+
+	latest := latestApplicationVersion(app)
+	if spec.BootActivity != nil && *spec.BootActivity != latest.BootActivity {
+		patch.BootActivity = spec.BootActivity
+		changes = append(changes, fleetFieldChange(FleetResourceApplication, spec.Name, "boot_activity", latest.BootActivity, *spec.BootActivity))
+	}
+	if spec.Features != nil && !shared.CompareSlicesUnordered(*spec.Features, latest.Features) {
+		patch.Features = spec.Features
+		changes = append(changes, fleetFieldChange(FleetResourceApplication, spec.Name, "features", joinFleetList(latest.Features), joinFleetList(*spec.Features)))
+	}
+	if spec.Watchdog != nil && (spec.Watchdog.Disabled != latest.Watchdog.Disabled ||
+		!shared.CompareSlicesUnordered(spec.Watchdog.AllowedPackages, latest.Watchdog.AllowedPackages)) {
+		patch.Watchdog = spec.Watchdog
+		changes = append(changes, fleetFieldChange(FleetResourceApplication, spec.Name, "watchdog",
+			fmt.Sprintf("%+v", latest.Watchdog), fmt.Sprintf("%+v", *spec.Watchdog)))
+	}
+	return changes
+}
+
+// latestApplicationVersion returns the version of the application with the
+// highest number or an empty version if the application has none
+func latestApplicationVersion(app *api.Application) api.ApplicationVersion {
+	latest := api.ApplicationVersion{Number: -1}
+	for _, v := range app.Versions {
+		if v.Number > latest.Number {
+			latest = v
+		}
+	}
+	return latest
+}
+
+func (c *clientImpl) findApplicationByName(name string) (*api.Application, error) {
+	apps, err := c.ListApplicationsWithFilters([]string{fmt.Sprintf("name=%s", name)})
+	if err != nil {
+		return nil, err
+	}
+	for n := range apps {
+		if apps[n].Name == name {
+			return &apps[n], nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,156 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	api "github.com/anbox-cloud/ams-sdk/api/ams"
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+)
+
+const (
+	// Number of logs retrieved at the same time
+	defaultLogWorkers = 4
+	// Maximum length of a single log line
+	maxLogLineLength = 1024 * 1024
+)
+
+// ApplicationLogFilter narrows down the containers of an application logs are
+// retrieved from
+type ApplicationLogFilter struct {
+	// Statuses limits the containers to those in one of the given statuses
+	Statuses []api.ContainerStatus
+	// Node limits the containers to those running on the given node
+	Node string
+}
+
+// ApplicationLogResult describes the result of retrieving the log of a single
+// container of an application
+type ApplicationLogResult struct {
+	// Container is the ID of the container the log was retrieved from
+	Container string
+	// Lines is the number of lines written to the sink
+	Lines int
+	// Err is set when the log could not be retrieved completely
+	Err error
+}
+
+func (f *ApplicationLogFilter) matches(container *api.Container) bool {
+	if f == nil {
+		return true
+	}
+	if len(f.Node) > 0 && container.Node != f.Node {
+		return false
+	}
+	if len(f.Statuses) == 0 {
+		return true
+	}
+	for _, status := range f.Statuses {
+		if container.StatusCode == status {
+			return true
+		}
+	}
+	return false
+}
+
+// RetrieveApplicationLogs retrieves the log with the given name from all
+// containers of the application with the given ID which match the filter and
+// have the log stored. The logs are retrieved concurrently and their lines are
+// written to the sink prefixed with the ID of the container they belong to.
+// Lines are written atomically, but lines of different containers interleave.
+// The returned results contain an entry per container the log was retrieved
+// from.
+func (c *clientImpl) RetrieveApplicationLogs(appID, name string, filter *ApplicationLogFilter, sink io.Writer) ([]ApplicationLogResult, error) {
+	if len(appID) == 0 {
+		return nil, errs.NewInvalidArgument("appID")
+	}
+	if len(name) == 0 {
+		return nil, errs.NewInvalidArgument("name")
+	}
+	if sink == nil {
+		return nil, errs.NewInvalidArgument("sink")
+	}
+
+	containers, err := c.ListContainers()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []string{}
+	for n := range containers {
+		container := &containers[n]
+		if container.AppID != appID || !filter.matches(container) {
+			continue
+		}
+		for _, log := range container.StoredLogs {
+			if log == name {
+				ids = append(ids, container.ID)
+				break
+			}
+		}
+	}
+
+	results := make([]ApplicationLogResult, len(ids))
+	jobs := make(chan int)
+	var sinkLock sync.Mutex
+	var wg sync.WaitGroup
+
+	workers := defaultLogWorkers
+	if len(ids) < workers {
+		workers = len(ids)
+	}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range jobs {
+				id := ids[n]
+				lines := 0
+				err := c.RetrieveContainerLog(id, name, func(header *http.Header, body io.ReadCloser) error {
+					scanner := bufio.NewScanner(body)
+					scanner.Buffer(make([]byte, 64*1024), maxLogLineLength)
+					for scanner.Scan() {
+						sinkLock.Lock()
+						_, err := fmt.Fprintf(sink, "[%s] %s\n", id, scanner.Text())
+						sinkLock.Unlock()
+						if err != nil {
+							return err
+						}
+						lines++
+					}
+					return scanner.Err()
+				})
+				results[n] = ApplicationLogResult{Container: id, Lines: lines, Err: err}
+			}
+		}()
+	}
+
+	for n := range ids {
+		jobs <- n
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results, nil
+}
@@ -0,0 +1,177 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	api "github.com/anbox-cloud/ams-sdk/api/ams"
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+)
+
+// BootstrapFailure describes a base instance which failed to prepare a
+// version of an application
+type BootstrapFailure struct {
+	// InstanceID is the ID of the base instance
+	InstanceID string
+	// Node is the node the base instance ran on
+	Node string
+	// Message is the error the base instance failed with
+	Message string
+}
+
+// ApplicationVersionError is returned when a version of an application failed
+// to become ready
+type ApplicationVersionError struct {
+	ApplicationID string
+	Version       int
+	// Message is the error reported for the application version
+	Message string
+	// Failures lists the base instances which failed to prepare the version
+	Failures []BootstrapFailure
+}
+
+// Error returns the error string
+func (e *ApplicationVersionError) Error() string {
+	msg := fmt.Sprintf("version %d of application %s failed", e.Version, e.ApplicationID)
+	if len(e.Message) > 0 {
+		msg = fmt.Sprintf("%s: %s", msg, e.Message)
+	}
+	if len(e.Failures) > 0 {
+		failures := make([]string, 0, len(e.Failures))
+		for _, f := range e.Failures {
+			failures = append(failures, fmt.Sprintf("%s on node %s: %s", f.InstanceID, f.Node, f.Message))
+		}
+		msg = fmt.Sprintf("%s (%s)", msg, strings.Join(failures, "; "))
+	}
+	return msg
+}
+
+// WaitApplicationReady blocks until the given version of the application with
+// the given ID is active, which means it is available on all nodes of the
+// cluster, or the context is done. A negative version waits for the latest
+// version of the application. Status changes are detected through lifecycle
+// events. If events cannot be received the status is polled. If the version
+// fails, an ApplicationVersionError describing the failed base instances is
+// returned.
+func (c *clientImpl) WaitApplicationReady(ctx context.Context, id string, version int) (*api.Application, error) {
+	if len(id) == 0 {
+		return nil, errs.NewInvalidArgument("id")
+	}
+
+	changed := make(chan struct{}, 1)
+	interval := waitPollInterval
+
+	listener, err := c.GetEvents()
+	if err == nil {
+		defer listener.Disconnect()
+		_, err = listener.AddHandler([]string{string(api.EventTypeLifecycle)}, func(data interface{}) {
+			if lifecycleEventSource(data) != id {
+				return
+			}
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+		})
+		if err == nil {
+			interval = waitPollIntervalWithEvents
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		app, _, err := c.RetrieveApplicationByID(id)
+		if err != nil {
+			return nil, err
+		}
+
+		v := applicationVersion(app, version)
+		if v != nil {
+			switch v.StatusCode {
+			case api.ImageStatusActive:
+				return app, nil
+			case api.ImageStatusError:
+				return app, c.applicationVersionError(app, v)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return app, ctx.Err()
+		case <-changed:
+		case <-ticker.C:
+		}
+	}
+}
+
+// applicationVersionError collects the failures of the base instances which
+// prepared the given application version
+func (c *clientImpl) applicationVersionError(app *api.Application, v *api.ApplicationVersion) error {
+	e := &ApplicationVersionError{
+		ApplicationID: app.ID,
+		Version:       v.Number,
+		Message:       v.ErrorMessage,
+	}
+
+	// The failures are additional information only, so errors listing the
+	// base instances are ignored
+	filters := []string{
+		"base=true",
+		fmt.Sprintf("app_id=%s", app.ID),
+		fmt.Sprintf("app_version=%d", v.Number),
+	}
+	instances, err := c.ListInstancesWithFilters(filters)
+	if err != nil {
+		return e
+	}
+	for _, instance := range instances {
+		if instance.StatusCode != api.InstanceStatusError {
+			continue
+		}
+		e.Failures = append(e.Failures, BootstrapFailure{
+			InstanceID: instance.ID,
+			Node:       instance.Node,
+			Message:    instance.ErrorMessage,
+		})
+	}
+	return e
+}
+
+// applicationVersion returns the given version of the application or its
+// latest version if the given version is negative. Returns nil if the version
+// does not exist (yet).
+func applicationVersion(app *api.Application, version int) *api.ApplicationVersion {
+	var found *api.ApplicationVersion
+	for n := range app.Versions {
+		v := &app.Versions[n]
+		if version >= 0 && v.Number == version {
+			return v
+		}
+		if version < 0 && (found == nil || v.Number > found.Number) {
+			found = v
+		}
+	}
+	return found
+}
@@ -0,0 +1,160 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	api "github.com/anbox-cloud/ams-sdk/api/ams"
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/client"
+)
+
+func (c *clientImpl) checkBackupSupport() error {
+	hasBackupSupport, err := c.HasExtension(api.ExtensionInstanceBackups)
+	if err != nil {
+		return err
+	}
+	if !hasBackupSupport {
+		return errs.NewErrNotSupported("api extension \"instance_backups\"")
+	}
+	return nil
+}
+
+// CreateInstanceBackup creates a new backup of the instance with the given ID
+func (c *clientImpl) CreateInstanceBackup(id string, details *api.InstanceBackupsPost) (client.Operation, error) {
+	if len(id) == 0 {
+		return nil, errs.NewInvalidArgument("id")
+	}
+	if err := c.checkBackupSupport(); err != nil {
+		return nil, err
+	}
+	if details == nil {
+		details = &api.InstanceBackupsPost{}
+	}
+
+	b, err := c.Codec().Marshal(details)
+	if err != nil {
+		return nil, err
+	}
+	op, _, err := c.QueryOperation("POST", client.APIPath("instances", id, "backups"), nil, nil, bytes.NewReader(b), "")
+	return op, err
+}
+
+// ListInstanceBackups lists all backups of the instance with the given ID
+func (c *clientImpl) ListInstanceBackups(id string) ([]api.InstanceBackup, error) {
+	if len(id) == 0 {
+		return nil, errs.NewInvalidArgument("id")
+	}
+	if err := c.checkBackupSupport(); err != nil {
+		return nil, err
+	}
+
+	backups := []api.InstanceBackup{}
+	params := client.QueryParams{
+		"recursion": "1",
+	}
+	_, err := c.QueryStruct("GET", client.APIPath("instances", id, "backups"), params, nil, nil, "", &backups)
+	return backups, err
+}
+
+// RetrieveInstanceBackup retrieves a single backup of the instance with the
+// given ID
+func (c *clientImpl) RetrieveInstanceBackup(id, name string) (*api.InstanceBackup, string, error) {
+	if len(id) == 0 {
+		return nil, "", errs.NewInvalidArgument("id")
+	}
+	if len(name) == 0 {
+		return nil, "", errs.NewInvalidArgument("name")
+	}
+	if err := c.checkBackupSupport(); err != nil {
+		return nil, "", err
+	}
+
+	backup := &api.InstanceBackup{}
+	etag, err := c.QueryStruct("GET", client.APIPath("instances", id, "backups", name), nil, nil, nil, "", backup)
+	return backup, etag, err
+}
+
+// RestoreInstanceBackup restores the instance with the given ID from one of
+// its backups
+func (c *clientImpl) RestoreInstanceBackup(id, name string, details *api.InstanceBackupRestorePost) (client.Operation, error) {
+	if len(id) == 0 {
+		return nil, errs.NewInvalidArgument("id")
+	}
+	if len(name) == 0 {
+		return nil, errs.NewInvalidArgument("name")
+	}
+	if err := c.checkBackupSupport(); err != nil {
+		return nil, err
+	}
+	if details == nil {
+		details = &api.InstanceBackupRestorePost{}
+	}
+
+	b, err := c.Codec().Marshal(details)
+	if err != nil {
+		return nil, err
+	}
+	op, _, err := c.QueryOperation("POST", client.APIPath("instances", id, "backups", name, "restore"), nil, nil, bytes.NewReader(b), "")
+	return op, err
+}
+
+// DeleteInstanceBackup deletes a single backup of the instance with the given ID
+func (c *clientImpl) DeleteInstanceBackup(id, name string) (client.Operation, error) {
+	if len(id) == 0 {
+		return nil, errs.NewInvalidArgument("id")
+	}
+	if len(name) == 0 {
+		return nil, errs.NewInvalidArgument("name")
+	}
+	if err := c.checkBackupSupport(); err != nil {
+		return nil, err
+	}
+
+	op, _, err := c.QueryOperation("DELETE", client.APIPath("instances", id, "backups", name), nil, nil, nil, "")
+	return op, err
+}
+
+// ExportInstanceBackup downloads a backup of the instance with the given ID.
+// The downloader is called with the stream of the backup tarball.
+func (c *clientImpl) ExportInstanceBackup(id, name string, downloader func(header *http.Header, body io.ReadCloser) error) error {
+	if len(id) == 0 {
+		return errs.NewInvalidArgument("id")
+	}
+	if len(name) == 0 {
+		return errs.NewInvalidArgument("name")
+	}
+	if err := c.checkBackupSupport(); err != nil {
+		return err
+	}
+
+	return c.download(client.APIPath("instances", id, "backups", name, "export"), nil, nil, downloader)
+}
+
+// ImportInstanceBackup creates a new instance from a backup tarball read from
+// the given payload, e.g. one previously downloaded with ExportInstanceBackup
+func (c *clientImpl) ImportInstanceBackup(payload io.ReadSeeker, sentBytes chan float64, opts ...RequestOption) (client.Operation, error) {
+	if err := c.checkBackupSupport(); err != nil {
+		return nil, err
+	}
+	return c.uploadStream("POST", client.APIPath("instances", "backups"), nil, payload, nil, sentBytes, newRequestOptions(opts))
+}
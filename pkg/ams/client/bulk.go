@@ -0,0 +1,154 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	api "github.com/anbox-cloud/ams-sdk/api/ams"
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/client"
+)
+
+const (
+	// Number of status updates sent to AMS at the same time by a bulk action
+	defaultBulkConcurrency = 8
+)
+
+// Action describes a status change applied to several containers or
+// instances at once
+type Action string
+
+const (
+	// ActionStart starts stopped containers or instances
+	ActionStart Action = "start"
+	// ActionStop stops running containers or instances
+	ActionStop Action = "stop"
+	// ActionRestart stops and starts containers or instances again
+	ActionRestart Action = "restart"
+)
+
+// ActionResult describes the result of applying an action to a single
+// container or instance
+type ActionResult struct {
+	// Operation tracks the status change on the AMS side. For a restart it
+	// tracks starting the container or instance again. Nil if Err is set.
+	Operation client.Operation
+	// Err is set when the action could not be applied
+	Err error
+}
+
+// RunContainerAction applies the action to all containers with the given IDs.
+// AMS has no bulk endpoint for status changes, so one update per container is
+// sent with a bounded number of concurrent requests. The returned map holds a
+// result per ID, so a partial failure does not hide the containers the action
+// was applied to. A restart waits for the container to stop before it is
+// started again; noWait only applies to the final operation.
+func (c *clientImpl) RunContainerAction(ctx context.Context, ids []string, action Action, noWait bool) (map[string]ActionResult, error) {
+	return runBulkAction(ctx, ids, action, noWait, c.setContainerStatus)
+}
+
+// RunInstanceAction applies the action to all instances with the given IDs.
+// See RunContainerAction for details.
+func (c *clientImpl) RunInstanceAction(ctx context.Context, ids []string, action Action, noWait bool) (map[string]ActionResult, error) {
+	return runBulkAction(ctx, ids, action, noWait, c.setInstanceStatus)
+}
+
+// setStatusFunc updates the desired status of a single container or instance
+type setStatusFunc func(id, desired string, noWait bool) (client.Operation, error)
+
+func (c *clientImpl) setContainerStatus(id, desired string, noWait bool) (client.Operation, error) {
+	return c.UpdateContainerByID(id, &api.ContainerPatch{DesiredStatus: &desired}, noWait)
+}
+
+func (c *clientImpl) setInstanceStatus(id, desired string, noWait bool) (client.Operation, error) {
+	return c.UpdateInstanceByID(id, &api.InstancePatch{DesiredStatus: &desired}, noWait)
+}
+
+func runBulkAction(ctx context.Context, ids []string, action Action, noWait bool, setStatus setStatusFunc) (map[string]ActionResult, error) {
+	if len(ids) == 0 {
+		return nil, errs.NewInvalidArgument("ids")
+	}
+	switch action {
+	case ActionStart, ActionStop, ActionRestart:
+	default:
+		return nil, errs.NewInvalidArgument(fmt.Sprintf("action %q", action))
+	}
+
+	results := make(map[string]ActionResult, len(ids))
+	var lock sync.Mutex
+	sem := make(chan struct{}, defaultBulkConcurrency)
+	var wg sync.WaitGroup
+
+	// Duplicates are removed before any worker writes to the results
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := results[id]; ok {
+			continue
+		}
+		results[id] = ActionResult{}
+		unique = append(unique, id)
+	}
+
+	for _, id := range unique {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(id string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			op, err := applyAction(ctx, id, action, noWait, setStatus)
+			lock.Lock()
+			results[id] = ActionResult{Operation: op, Err: err}
+			lock.Unlock()
+		}(id)
+	}
+	wg.Wait()
+
+	return results, nil
+}
+
+func applyAction(ctx context.Context, id string, action Action, noWait bool, setStatus setStatusFunc) (client.Operation, error) {
+	running := api.InstanceStatusRunning.String()
+	stopped := api.InstanceStatusStopped.String()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	switch action {
+	case ActionStart:
+		return setStatus(id, running, noWait)
+	case ActionStop:
+		return setStatus(id, stopped, noWait)
+	}
+
+	op, err := setStatus(id, stopped, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := op.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return setStatus(id, running, noWait)
+}
@@ -0,0 +1,225 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"sort"
+
+	api "github.com/anbox-cloud/ams-sdk/api/ams"
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared"
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+)
+
+// ResourceUsage describes how much of a resource is allocated compared to
+// how much is available for allocation
+type ResourceUsage struct {
+	// Available is the amount which can be allocated in total, which includes
+	// overcommitment configured through the allocation rates of a node
+	Available int64 `json:"available" yaml:"available"`
+	// Allocated is the amount allocated by instances
+	Allocated int64 `json:"allocated" yaml:"allocated"`
+}
+
+// Free returns the amount which is still available for allocation. It is
+// negative if more than available is allocated.
+func (r ResourceUsage) Free() int64 {
+	return r.Available - r.Allocated
+}
+
+func (r *ResourceUsage) add(o ResourceUsage) {
+	r.Available += o.Available
+	r.Allocated += o.Allocated
+}
+
+// NodeResourceStatus describes the utilization of a single node
+type NodeResourceStatus struct {
+	// Name of the node
+	Name string `json:"name" yaml:"name"`
+	// Status of the node
+	Status api.NodeStatus `json:"status" yaml:"status"`
+	// Schedulable is true if new instances can be placed on the node
+	Schedulable bool `json:"schedulable" yaml:"schedulable"`
+	// CPUs usage in number of CPU cores
+	CPUs ResourceUsage `json:"cpus" yaml:"cpus"`
+	// Memory usage in bytes
+	Memory ResourceUsage `json:"memory" yaml:"memory"`
+	// DiskSize usage in bytes
+	DiskSize ResourceUsage `json:"disk_size" yaml:"disk_size"`
+	// GPUSlots usage in number of GPU slots
+	GPUSlots ResourceUsage `json:"gpu_slots" yaml:"gpu_slots"`
+	// GPUEncoderSlots usage in number of GPU encoder slots
+	GPUEncoderSlots ResourceUsage `json:"gpu_encoder_slots" yaml:"gpu_encoder_slots"`
+	// VPUSlots usage in number of VPU slots
+	VPUSlots ResourceUsage `json:"vpu_slots" yaml:"vpu_slots"`
+	// Instances is the number of instances allocating resources on the node
+	Instances int `json:"instances" yaml:"instances"`
+	// InstancesByStatus counts all instances on the node by their status
+	InstancesByStatus map[string]int `json:"instances_by_status" yaml:"instances_by_status"`
+}
+
+// ClusterCapacity summarizes the utilization of all nodes of the cluster
+type ClusterCapacity struct {
+	// Nodes lists the utilization of each node ordered by name
+	Nodes []NodeResourceStatus `json:"nodes" yaml:"nodes"`
+	// Schedulable sums up the resources of all nodes new instances can be
+	// placed on
+	Schedulable struct {
+		Nodes           int           `json:"nodes" yaml:"nodes"`
+		CPUs            ResourceUsage `json:"cpus" yaml:"cpus"`
+		Memory          ResourceUsage `json:"memory" yaml:"memory"`
+		DiskSize        ResourceUsage `json:"disk_size" yaml:"disk_size"`
+		GPUSlots        ResourceUsage `json:"gpu_slots" yaml:"gpu_slots"`
+		GPUEncoderSlots ResourceUsage `json:"gpu_encoder_slots" yaml:"gpu_encoder_slots"`
+		VPUSlots        ResourceUsage `json:"vpu_slots" yaml:"vpu_slots"`
+	} `json:"schedulable" yaml:"schedulable"`
+	// Instances is the number of instances allocating resources in the cluster
+	Instances int `json:"instances" yaml:"instances"`
+}
+
+// RetrieveNodeStatus returns the resource utilization of the node with the
+// given name. AMS only reports the resources of a node, so the allocations
+// are derived from the instances running on it.
+func (c *clientImpl) RetrieveNodeStatus(name string) (*NodeResourceStatus, error) {
+	if len(name) == 0 {
+		return nil, errs.NewInvalidArgument("name")
+	}
+	node, _, err := c.RetrieveNodeByName(name)
+	if err != nil {
+		return nil, err
+	}
+	instances, err := c.ListInstancesWithFilters([]string{"node=" + name})
+	if err != nil {
+		return nil, err
+	}
+	status := nodeResourceStatus(node, instances)
+	return &status, nil
+}
+
+// RetrieveClusterCapacity returns the resource utilization of all nodes and
+// a summary of the resources available for new instances
+func (c *clientImpl) RetrieveClusterCapacity() (*ClusterCapacity, error) {
+	nodes, err := c.ListNodes()
+	if err != nil {
+		return nil, err
+	}
+	instances, err := c.ListInstances()
+	if err != nil {
+		return nil, err
+	}
+
+	byNode := map[string][]api.Instance{}
+	for _, instance := range instances {
+		byNode[instance.Node] = append(byNode[instance.Node], instance)
+	}
+
+	capacity := &ClusterCapacity{Nodes: make([]NodeResourceStatus, 0, len(nodes))}
+	for n := range nodes {
+		status := nodeResourceStatus(&nodes[n], byNode[nodes[n].Name])
+		capacity.Nodes = append(capacity.Nodes, status)
+		capacity.Instances += status.Instances
+		if !status.Schedulable {
+			continue
+		}
+		s := &capacity.Schedulable
+		s.Nodes++
+		s.CPUs.add(status.CPUs)
+		s.Memory.add(status.Memory)
+		s.DiskSize.add(status.DiskSize)
+		s.GPUSlots.add(status.GPUSlots)
+		s.GPUEncoderSlots.add(status.GPUEncoderSlots)
+		s.VPUSlots.add(status.VPUSlots)
+	}
+	sort.Slice(capacity.Nodes, func(i, j int) bool { return capacity.Nodes[i].Name < capacity.Nodes[j].Name })
+	return capacity, nil
+}
+
+// allocatesResources returns true if an instance in the given status holds
+// resources on its node
+func allocatesResources(status api.InstanceStatus) bool {
+	switch status {
+	case api.InstanceStatusStopped, api.InstanceStatusDeleted, api.InstanceStatusError:
+		return false
+	default:
+		return true
+	}
+}
+
+func nodeResourceStatus(node *api.Node, instances []api.Instance) NodeResourceStatus {
+	status := NodeResourceStatus{
+		Name:              node.Name,
+		Status:            node.StatusCode,
+		Schedulable:       node.StatusCode == api.NodeStatusOnline && !node.Unschedulable,
+		InstancesByStatus: map[string]int{},
+	}
+
+	status.CPUs.Available = int64(float32(node.CPUs) * allocationRate(node.CPUAllocationRate))
+	if memory, err := shared.ParseByteSizeString(node.Memory); err == nil {
+		status.Memory.Available = int64(float32(memory) * allocationRate(node.MemoryAllocationRate))
+	}
+	if diskSize, err := shared.ParseByteSizeString(node.DiskSize); err == nil {
+		status.DiskSize.Available = diskSize
+	}
+
+	status.GPUSlots.Available = int64(node.GPUSlots)
+	status.GPUEncoderSlots.Available = int64(node.GPUEncoderSlots)
+	if len(node.GPUs) > 0 {
+		status.GPUSlots.Available, status.GPUEncoderSlots.Available = 0, 0
+		for _, gpu := range node.GPUs {
+			status.GPUSlots.Available += int64(gpu.Slots)
+			status.GPUEncoderSlots.Available += int64(gpu.EncoderSlots)
+			for _, allocation := range gpu.Allocations {
+				status.GPUSlots.Allocated += int64(allocation.Slots)
+				status.GPUEncoderSlots.Allocated += int64(allocation.EncoderSlots)
+			}
+		}
+	}
+	for _, vpu := range node.VPUs {
+		status.VPUSlots.Available += int64(vpu.Slots)
+		for _, allocation := range vpu.Allocations {
+			status.VPUSlots.Allocated += int64(allocation.Slots)
+		}
+	}
+
+	for _, instance := range instances {
+		if instance.Node != node.Name {
+			continue
+		}
+		status.InstancesByStatus[instance.StatusCode.String()]++
+		if !allocatesResources(instance.StatusCode) {
+			continue
+		}
+		status.Instances++
+		status.CPUs.Allocated += int64(instance.Resources.CPUs)
+		status.Memory.Allocated += instance.Resources.Memory
+		status.DiskSize.Allocated += instance.Resources.DiskSize
+		if len(node.GPUs) == 0 {
+			status.GPUSlots.Allocated += int64(instance.Resources.GPUSlots)
+		}
+	}
+
+	return status
+}
+
+// allocationRate returns the given rate or 1 if the node reports none
+func allocationRate(rate float32) float32 {
+	if rate <= 0 {
+		return 1
+	}
+	return rate
+}
@@ -21,7 +21,6 @@ package client
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 
 	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
@@ -45,7 +44,7 @@ func (c *clientImpl) AddCertificate(details *api.CertificatesPost) (*api.Respons
 		return nil, fmt.Errorf("No certificate specified")
 	}
 
-	b, err := json.Marshal(details)
+	b, err := c.Codec().Marshal(details)
 	if err != nil {
 		return nil, err
 	}
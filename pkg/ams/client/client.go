@@ -19,9 +19,11 @@
 package client
 
 import (
+	"context"
 	"crypto/tls"
 	"io"
 	"net/http"
+	"sync"
 	"time"
 
 	api "github.com/anbox-cloud/ams-sdk/api/ams"
@@ -36,75 +38,129 @@ const (
 	extendedTransportTimeout = 300 * time.Second
 )
 
-// Client is the interface used to communicate with an AMS server
-type Client interface {
-	// Nodes
+// InterfaceVersion is the version of the Client interface and its sub
+// interfaces. It is incremented whenever a method is removed or its signature
+// changes, adding methods does not change the version.
+const InterfaceVersion = 1
+
+//go:generate mockgen -source=client.go -destination=mocks/client.go -package=mocks
+
+// NodeClient manages the nodes of an AMS cluster
+type NodeClient interface {
 	ListNodes() ([]api.Node, error)
 	AddNode(node *api.NodesPost) (restclient.Operation, error)
 	RemoveNode(name string, force, keepInCluster bool) (restclient.Operation, error)
 	RetrieveNodeByName(name string) (*api.Node, string, error)
-	UpdateNode(name string, details *api.NodePatch) (restclient.Operation, error)
+	UpdateNode(name string, details *api.NodePatch, opts ...RequestOption) (restclient.Operation, error)
+	RetrieveNodeStatus(name string) (*NodeResourceStatus, error)
+	RetrieveClusterCapacity() (*ClusterCapacity, error)
+}
 
-	// Certificates
+// CertificateClient manages the client certificates trusted by AMS
+type CertificateClient interface {
 	ListCertificates() ([]restapi.Certificate, error)
 	AddCertificate(details *restapi.CertificatesPost) (*restapi.Response, error)
 	DeleteCertificate(fingerprint string) error
+}
 
-	// Containers
+// ContainerClient manages containers
+type ContainerClient interface {
 	ListContainers() ([]api.Container, error)
 	ListContainersWithFilters(filters []string) ([]api.Container, error)
 	LaunchContainer(details *api.ContainersPost, noWait bool) (restclient.Operation, error)
+	LaunchContainers(details *api.ContainersPost, count int, noWait bool) ([]LaunchResult, error)
+	LaunchContainerWithPlacement(details *api.ContainersPost, rules *PlacementRules, noWait bool) (restclient.Operation, error)
 	RetrieveContainerByID(id string) (*api.Container, string, error)
-	UpdateContainerByID(id string, details *api.ContainerPatch, noWait bool) (restclient.Operation, error)
+	UpdateContainerByID(id string, details *api.ContainerPatch, noWait bool, opts ...RequestOption) (restclient.Operation, error)
+	StartContainer(id string, noWait bool) (restclient.Operation, error)
+	StopContainer(id string, noWait bool) (restclient.Operation, error)
+	RestartContainer(ctx context.Context, id string, noWait bool) (restclient.Operation, error)
+	RunContainerAction(ctx context.Context, ids []string, action Action, noWait bool) (map[string]ActionResult, error)
+	ModifyContainer(id string, modify func(container *api.Container) (*api.ContainerPatch, error), noWait bool) (restclient.Operation, error)
 	DeleteContainerByID(id string, force bool) (restclient.Operation, error)
 	DeleteContainers(ids []string, force bool) (restclient.Operation, error)
 	RetrieveContainerLog(id, name string, downloader func(header *http.Header, body io.ReadCloser) error) error
+	OpenContainerLog(id, name string) (io.ReadSeeker, error)
+	FollowContainerLog(ctx context.Context, id, name string, w io.Writer) error
+	WaitForContainerStatus(ctx context.Context, id string, statuses ...api.ContainerStatus) (*api.Container, error)
+	WatchContainersWithInitialState(ctx context.Context, filters []string) (*ContainerWatch, error)
 	ExecuteContainer(id string, details *api.ContainerExecPost, args *ContainerExecArgs) (restclient.Operation, error)
+}
 
-	// Instances
+// InstanceClient manages instances
+type InstanceClient interface {
 	ListInstances() ([]api.Instance, error)
 	ListInstancesWithFilters(filters []string) ([]api.Instance, error)
 	LaunchInstance(details *api.InstancesPost, noWait bool) (restclient.Operation, error)
+	LaunchInstances(details *api.InstancesPost, count int, noWait bool) ([]LaunchResult, error)
+	LaunchInstanceWithPlacement(details *api.InstancesPost, rules *PlacementRules, noWait bool) (restclient.Operation, error)
+	PreviewPlacement(details *api.InstancesPost, rules *PlacementRules) (string, error)
 	RetrieveInstanceByID(id string) (*api.Instance, string, error)
-	UpdateInstanceByID(id string, details *api.InstancePatch, noWait bool) (restclient.Operation, error)
+	UpdateInstanceByID(id string, details *api.InstancePatch, noWait bool, opts ...RequestOption) (restclient.Operation, error)
+	StartInstance(id string, noWait bool) (restclient.Operation, error)
+	StopInstance(id string, noWait bool) (restclient.Operation, error)
+	RestartInstance(ctx context.Context, id string, noWait bool) (restclient.Operation, error)
+	RunInstanceAction(ctx context.Context, ids []string, action Action, noWait bool) (map[string]ActionResult, error)
+	ModifyInstance(id string, modify func(instance *api.Instance) (*api.InstancePatch, error), noWait bool) (restclient.Operation, error)
 	DeleteInstanceByID(id string, force bool) (restclient.Operation, error)
 	DeleteInstances(ids []string, force bool) (restclient.Operation, error)
 	RetrieveInstanceLog(id, name string, downloader func(header *http.Header, body io.ReadCloser) error) error
+	OpenInstanceLog(id, name string) (io.ReadSeeker, error)
+	FollowInstanceLog(ctx context.Context, id, name string, w io.Writer) error
+	WaitForInstanceStatus(ctx context.Context, id string, statuses ...api.InstanceStatus) (*api.Instance, error)
 	ExecuteInstance(id string, details *api.InstanceExecPost, args *InstanceExecArgs) (restclient.Operation, error)
+	ForwardPort(ctx context.Context, instanceID, localAddr string, remotePort int) (*PortForward, error)
+	AttachConsole(id string, stdin io.ReadCloser, stdout io.WriteCloser, resize <-chan ConsoleSize) (*Console, error)
+	RunInteractiveShell(ctx context.Context, id string, command []string) (int, error)
+}
 
-	// Config
+// ConfigClient manages the configuration of AMS
+type ConfigClient interface {
 	SetConfigItem(name, value string) error
 	RetrieveConfigItems() (map[string]interface{}, error)
+}
 
-	// Applications
-	CreateApplication(packagePath string, sentBytes chan float64) (restclient.Operation, error)
-	CreateApplicationWithArgs(args *ApplicationCreateArgs) (restclient.Operation, error)
-	UpdateApplicationWithPackage(id, packagePath string, sentBytes chan float64) (restclient.Operation, error)
-	UpdateApplicationWithDetails(id string, details api.ApplicationPatch) error
-	UpdateApplication(id string) (restclient.Operation, error)
+// ApplicationClient manages applications
+type ApplicationClient interface {
+	CreateApplication(packagePath string, sentBytes chan float64, opts ...RequestOption) (restclient.Operation, error)
+	CreateApplicationWithArgs(args *ApplicationCreateArgs, opts ...RequestOption) (restclient.Operation, error)
+	UpdateApplicationWithPackage(id, packagePath string, sentBytes chan float64, opts ...RequestOption) (restclient.Operation, error)
+	UpdateApplicationWithDetails(id string, details api.ApplicationPatch, opts ...RequestOption) error
+	UpdateApplication(id string, opts ...RequestOption) (restclient.Operation, error)
 	ListApplications() ([]api.Application, error)
 	ListApplicationsWithFilters(filters []string) ([]api.Application, error)
 	FindApplicationsByName(pattern string) ([]api.Application, error)
 	RetrieveApplicationByID(id string) (*api.Application, string, error)
+	WaitApplicationReady(ctx context.Context, id string, version int) (*api.Application, error)
 	DeleteApplicationByID(id string, force bool) (restclient.Operation, error)
 	DeleteApplications(ids []string, force bool) (restclient.Operation, error)
 	ExportApplicationByVersion(id string, version int, downloader func(header *http.Header, body io.ReadCloser) error) error
 	PublishApplicationVersion(id string, version int) (restclient.Operation, error)
 	RevokeApplicationVersion(id string, version int) (restclient.Operation, error)
 	DeleteApplicationVersion(id string, version int, force bool) (restclient.Operation, error)
+	RetrieveApplicationLogs(appID, name string, filter *ApplicationLogFilter, sink io.Writer) ([]ApplicationLogResult, error)
+}
 
-	// Addons
-	AddAddon(name string, packagePath string, sentBytes chan float64) (restclient.Operation, error)
-	UpdateAddon(name, packagePath string, sentBytes chan float64) (restclient.Operation, error)
+// AddonClient manages addons
+type AddonClient interface {
+	AddAddon(name string, packagePath string, sentBytes chan float64, opts ...RequestOption) (restclient.Operation, error)
+	CreateAddon(name string, payload io.ReadSeeker, sentBytes chan float64, opts ...RequestOption) (restclient.Operation, error)
+	UpdateAddon(name, packagePath string, sentBytes chan float64, opts ...RequestOption) (restclient.Operation, error)
+	UpdateAddonWithPayload(name string, payload io.ReadSeeker, sentBytes chan float64, opts ...RequestOption) (restclient.Operation, error)
 	RetrieveAddon(name string) (*api.Addon, string, error)
 	DeleteAddon(name string) (restclient.Operation, error)
 	DeleteAddonVersion(name string, version int) (restclient.Operation, error)
 	ListAddons() ([]api.Addon, error)
+	ListAddonVersions(name string) ([]api.AddonVersion, error)
+}
 
-	// Images
+// ImageClient manages images
+type ImageClient interface {
 	ListImages() ([]api.Image, error)
-	AddImage(name, packagePath string, isDefault bool, sentBytes chan float64) (restclient.Operation, error)
-	UpdateImage(id, packagePath string, sentBytes chan float64) (restclient.Operation, error)
+	AddImage(name, packagePath string, isDefault bool, sentBytes chan float64, opts ...RequestOption) (restclient.Operation, error)
+	CreateImage(name string, payload io.ReadSeeker, isDefault bool, sentBytes chan float64, opts ...RequestOption) (restclient.Operation, error)
+	UpdateImage(id, packagePath string, sentBytes chan float64, opts ...RequestOption) (restclient.Operation, error)
+	UpdateImageWithPayload(id string, payload io.ReadSeeker, sentBytes chan float64, opts ...RequestOption) (restclient.Operation, error)
 	ImportImage(name, path string, isDefault bool) (client.Operation, error)
 	ImportImageByType(name, path string, imgType api.ImageType, isDefault bool) (client.Operation, error)
 	SetDefaultImage(id string) error
@@ -113,45 +169,119 @@ type Client interface {
 	RetrieveImageByIDOrName(id string, imgType api.ImageType) (*api.Image, string, error)
 	RetrieveDefaultImage() (*api.Image, string, error)
 	TriggerImageSync(id string) error
+	StartImageSync(id string) (restclient.Operation, error)
+	ListImageNodeStatus(id string) ([]api.ImageNodeStatus, error)
+	WaitForImageSync(ctx context.Context, id string, version int) (*api.Image, error)
+	RetrieveImageChannel() (string, error)
+	SetImageChannel(channel string) error
+}
 
-	// Services
+// ServiceClient provides information about the AMS service and the connection to it
+type ServiceClient interface {
 	RetrieveServiceStatus() (*api.ServiceStatus, string, error)
+	ServerStatus(ctx context.Context) (*api.ServiceStatus, error)
+	Ping(ctx context.Context) error
 	HasExtension(name string) (bool, error)
+	Extensions() ([]string, error)
+	RefreshExtensions() ([]string, error)
 	ListTasks() ([]api.Task, error)
 	GetVersion() (string, error)
+	Health() restclient.Health
+	ClockSkew() restclient.ClockSkew
+	ConnectionInfo() *restclient.ConnectionInfo
+	GetEvents() (*restclient.EventListener, error)
+	SubscribeEvents(types []api.EventType, handler func(event *api.EventEnvelope)) (*restclient.EventListener, error)
+	Use(middlewares ...restclient.Middleware)
+	OpenStreams() []restclient.StreamInfo
+	CloseIdleStreams(olderThan time.Duration) int
+	Raw(ctx context.Context, method, path string, params restclient.QueryParams, body io.Reader) (*http.Response, error)
+	ListClusterServices() ([]api.ClusterService, error)
+	RetrieveClusterService(name string) (*api.ClusterService, string, error)
+	UnhealthyClusterServices() ([]api.ClusterService, error)
+}
 
-	// Registry
+// RegistryClient manages the synchronization with an application registry
+type RegistryClient interface {
 	ListApplicationsFromRegistry() ([]api.RegistryApplication, error)
 	PushApplicationToRegistry(id string) (client.Operation, error)
 	PullApplicationFromRegistry(id string) (client.Operation, error)
 	DeleteApplicationFromRegistry(id string) (client.Operation, error)
+	RetrieveRegistryConfig() (*api.RegistryConfig, error)
+	UpdateRegistryConfig(patch *api.RegistryConfigPatch) error
+	SyncApplicationsWithRegistry(mode api.RegistryMode) ([]RegistrySyncResult, error)
+}
 
-	GetEvents() (*restclient.EventListener, error)
-
-	// Operations
+// OperationClient manages the asynchronous operations of AMS
+type OperationClient interface {
 	ListOperations() (map[string][]*restapi.Operation, error)
+	ListOperationsWithFilter(ctx context.Context, filter *OperationFilter) ([]restapi.Operation, error)
+	RetrieveOperationByID(id string) (*restapi.Operation, string, error)
 	ShowOperation(id string) (*restapi.Operation, error)
 	CancelOperation(id string) error
+	OperationStreams(op restclient.Operation) (*OperationStreams, error)
+}
+
+// FleetClient exports and applies the declarative configuration of a cluster
+type FleetClient interface {
+	ExportFleetSpec(ctx context.Context) (*FleetSpec, error)
+	ApplyFleetSpec(ctx context.Context, spec *FleetSpec, opts *FleetApplyOptions) ([]FleetChange, error)
+	EnsureApplication(ctx context.Context, spec *ApplicationSpec, opts *FleetApplyOptions) ([]FleetChange, error)
+	EnsureContainerFleet(ctx context.Context, spec *ContainerFleetSpec, opts *FleetApplyOptions) ([]ContainerFleetChange, error)
+}
+
+// BackupClient manages instance backups
+type BackupClient interface {
+	CreateInstanceBackup(id string, details *api.InstanceBackupsPost) (restclient.Operation, error)
+	ListInstanceBackups(id string) ([]api.InstanceBackup, error)
+	RetrieveInstanceBackup(id, name string) (*api.InstanceBackup, string, error)
+	RestoreInstanceBackup(id, name string, details *api.InstanceBackupRestorePost) (restclient.Operation, error)
+	DeleteInstanceBackup(id, name string) (restclient.Operation, error)
+	ExportInstanceBackup(id, name string, downloader func(header *http.Header, body io.ReadCloser) error) error
+	ImportInstanceBackup(payload io.ReadSeeker, sentBytes chan float64, opts ...RequestOption) (restclient.Operation, error)
+}
+
+// Client is the interface used to communicate with an AMS server. Code which
+// only needs a subset of the functionality should depend on the narrower
+// interfaces it is composed of.
+type Client interface {
+	NodeClient
+	CertificateClient
+	ContainerClient
+	InstanceClient
+	ConfigClient
+	ApplicationClient
+	AddonClient
+	ImageClient
+	ServiceClient
+	RegistryClient
+	OperationClient
+	FleetClient
+	BackupClient
 }
 
 // clientImpl encapsulates a client to the AMS service and allows performing
 // various operations with the service
 type clientImpl struct {
 	restclient.Client
+	serviceStatusLock  sync.Mutex
 	serviceStatus      *api.ServiceStatus
 	hasInstanceSupport bool
+
+	containerEndpointsLock sync.Mutex
+	containerEndpoints     *bool
 }
 
 // New creates a new client talking to the AMS service at the specified URL or unix.socket path
-// and with the specified tls config, if provided
-func New(addr interface{}, tlsConfig *tls.Config) (Client, error) {
-	c, err := restclient.New(addr, tlsConfig)
+// and with the specified tls config, if provided. Additional options can be given to
+// customize the behaviour of the client.
+func New(addr interface{}, tlsConfig *tls.Config, opts ...restclient.Option) (Client, error) {
+	c, err := restclient.New(addr, tlsConfig, opts...)
 	if err != nil {
 		return nil, err
 	}
 
 	client := clientImpl{Client: c}
-	client.hasInstanceSupport, err = client.HasExtension("instance_support")
+	client.hasInstanceSupport, err = client.HasExtension(api.ExtensionInstanceSupport)
 	if err != nil {
 		return nil, err
 	}
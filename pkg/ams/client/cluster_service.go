@@ -0,0 +1,85 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	api "github.com/anbox-cloud/ams-sdk/api/ams"
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/client"
+)
+
+func (c *clientImpl) checkServiceRegistrySupport() error {
+	hasSupport, err := c.HasExtension(api.ExtensionServiceRegistry)
+	if err != nil {
+		return err
+	}
+	if !hasSupport {
+		return errs.NewErrNotSupported("api extension \"service_registry\"")
+	}
+	return nil
+}
+
+// ListClusterServices lists all service components registered with the
+// services registry of the cluster
+func (c *clientImpl) ListClusterServices() ([]api.ClusterService, error) {
+	if err := c.checkServiceRegistrySupport(); err != nil {
+		return nil, err
+	}
+
+	services := []api.ClusterService{}
+	params := client.QueryParams{
+		"recursion": "1",
+	}
+	_, err := c.QueryStruct("GET", client.APIPath("services"), params, nil, nil, "", &services)
+	return services, err
+}
+
+// RetrieveClusterService retrieves the service component with the given name
+func (c *clientImpl) RetrieveClusterService(name string) (*api.ClusterService, string, error) {
+	if len(name) == 0 {
+		return nil, "", errs.NewInvalidArgument("name")
+	}
+	if err := c.checkServiceRegistrySupport(); err != nil {
+		return nil, "", err
+	}
+
+	service := &api.ClusterService{}
+	etag, err := c.QueryStruct("GET", client.APIPath("services", name), nil, nil, nil, "", service)
+	if err != nil {
+		return nil, "", err
+	}
+	return service, etag, nil
+}
+
+// UnhealthyClusterServices returns all registered service components which do
+// not operate normally
+func (c *clientImpl) UnhealthyClusterServices() ([]api.ClusterService, error) {
+	services, err := c.ListClusterServices()
+	if err != nil {
+		return nil, err
+	}
+
+	unhealthy := []api.ClusterService{}
+	for n := range services {
+		if !services[n].IsHealthy() {
+			unhealthy = append(unhealthy, services[n])
+		}
+	}
+	return unhealthy, nil
+}
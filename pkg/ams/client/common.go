@@ -19,13 +19,14 @@
 package client
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"strings"
 
+	api "github.com/anbox-cloud/ams-sdk/api/ams"
 	"github.com/anbox-cloud/ams-sdk/pkg/ams/packages"
 	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared"
 	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
@@ -33,53 +34,74 @@ import (
 	"github.com/gorilla/websocket"
 )
 
-func (c *clientImpl) upload(httpOp, apiPath string, params client.QueryParams, packagePath string, details interface{}, sentBytes chan float64) (client.Operation, error) {
-	hasZipSupport, err := c.HasExtension("zip_archive_support")
+func (c *clientImpl) upload(httpOp, apiPath string, params client.QueryParams, packagePath string, details interface{}, sentBytes chan float64, o requestOptions) (client.Operation, error) {
+	if packages.IsZip(packagePath) {
+		if err := c.checkZipSupport(); err != nil {
+			return nil, err
+		}
+	}
+	f, err := openPayload(packagePath)
 	if err != nil {
 		return nil, err
 	}
+	defer f.Close()
+	return c.uploadStream(httpOp, apiPath, params, f, details, sentBytes, o)
+}
+
+func (c *clientImpl) checkZipSupport() error {
+	hasZipSupport, err := c.HasExtension(api.ExtensionZipArchiveSupport)
+	if err != nil {
+		return err
+	}
 	if !hasZipSupport {
-		if packages.IsZip(packagePath) {
-			return nil, errs.NewErrNotSupported("api extension \"zip_archive_support\"")
-		}
-		pkgType, err := packages.DetectPackageType(packagePath)
-		if err != nil {
+		return errs.NewErrNotSupported("api extension \"zip_archive_support\"")
+	}
+	return nil
+}
+
+func (c *clientImpl) uploadStream(httpOp, apiPath string, params client.QueryParams, payload io.ReadSeeker, details interface{}, sentBytes chan float64, o requestOptions) (client.Operation, error) {
+	if payload == nil {
+		return nil, errs.NewInvalidArgument("payload")
+	}
+	pkgType, err := packages.DetectPackageTypeFromReader(payload)
+	if err != nil {
+		return nil, err
+	}
+	if pkgType == packages.PackageTypeZip {
+		if err := c.checkZipSupport(); err != nil {
 			return nil, err
 		}
-		if pkgType == packages.PackageTypeZip {
-			return nil, errs.NewErrNotSupported("api extension \"zip_archive_support\"")
-		}
 	}
-	f, fingerprint, err := preparePayload(packagePath)
+	fingerprint, err := shared.GenerateFingerprint(payload)
 	if err != nil {
 		return nil, err
 	}
 	request := []byte{}
 	if details != nil {
-		request, err = json.Marshal(details)
+		request, err = c.Codec().Marshal(details)
 		if err != nil {
 			return nil, fmt.Errorf("could not marshal request metadata: %v", err)
 		}
 	}
 
-	header := http.Header{
+	header := o.headerWith(http.Header{
 		"Content-Type":      []string{"application/octet-stream"},
 		"X-AMS-Fingerprint": []string{fingerprint},
 		"X-AMS-Request":     []string{string(request)},
-	}
+	})
 
-	u := &shared.BufferedReader{Reader: f, Size: sentBytes}
+	u := &shared.BufferedReader{Reader: payload, Size: sentBytes}
 
 	c.SetTransportTimeout(extendedTransportTimeout)
-	op, _, err := c.QueryOperation(httpOp, apiPath, params, header, u, "")
-	c.SetTransportTimeout(client.DefaultTransportTimeout)
+	op, _, err := c.QueryOperation(httpOp, apiPath, params, header, u, o.etag)
+	c.SetTransportTimeout(c.TransportTimeout())
 	return op, err
 }
 
 func (c *clientImpl) download(path string, params client.QueryParams, header http.Header, downloader func(header *http.Header, body io.ReadCloser) error) error {
 	c.SetTransportTimeout(extendedTransportTimeout)
 	err := c.DownloadFile(path, params, header, downloader)
-	c.SetTransportTimeout(client.DefaultTransportTimeout)
+	c.SetTransportTimeout(c.TransportTimeout())
 	return err
 }
 
@@ -95,43 +117,15 @@ func convertFiltersToParams(filters []string) (client.QueryParams, error) {
 	return apiFilters, nil
 }
 
-func (c *clientImpl) rawWebsocket(url string) (*websocket.Conn, error) {
-	httpTransport := c.HTTPTransport()
-
-	dialer := websocket.Dialer{
-		NetDial:         httpTransport.Dial,
-		TLSClientConfig: httpTransport.TLSClientConfig,
-		Proxy:           httpTransport.Proxy,
-	}
-
-	headers := http.Header{}
-
-	conn, _, err := dialer.Dial(url, headers)
-	if err != nil {
-		return nil, err
-	}
-
-	return conn, err
-}
-
-func (c *clientImpl) websocket(path string) (*websocket.Conn, error) {
-	// Generate the URL
-	var url string
-	serviceURL := c.ServiceURL()
-	if strings.HasPrefix(serviceURL, "https://") {
-		url = fmt.Sprintf("wss://%s/1.0%s", strings.TrimPrefix(serviceURL, "https://"), path)
-	} else {
-		url = fmt.Sprintf("ws://%s/1.0%s", strings.TrimPrefix(serviceURL, "http://"), path)
-	}
-
-	return c.rawWebsocket(url)
+func (c *clientImpl) getOperationWebsocket(uuid string, secret string) (*websocket.Conn, error) {
+	return c.getOperationWebsocketWithContext(context.Background(), uuid, secret)
 }
 
-func (c *clientImpl) getOperationWebsocket(uuid string, secret string) (*websocket.Conn, error) {
+func (c *clientImpl) getOperationWebsocketWithContext(ctx context.Context, uuid string, secret string) (*websocket.Conn, error) {
 	path := fmt.Sprintf("/operations/%s/websocket", url.QueryEscape(uuid))
 	if secret != "" {
 		path = fmt.Sprintf("%s?secret=%s", path, url.QueryEscape(secret))
 	}
 
-	return c.websocket(path)
+	return c.WebsocketWithContext(ctx, client.APIPath()+path)
 }
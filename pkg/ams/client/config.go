@@ -21,7 +21,6 @@ package client
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 
 	api "github.com/anbox-cloud/ams-sdk/api/ams"
 	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/client"
@@ -34,7 +33,7 @@ func (c *clientImpl) SetConfigItem(name, value string) error {
 		Value: value,
 	}
 
-	b, err := json.Marshal(req)
+	b, err := c.Codec().Marshal(req)
 	if err != nil {
 		return err
 	}
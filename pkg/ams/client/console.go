@@ -0,0 +1,191 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	api "github.com/anbox-cloud/ams-sdk/api/ams"
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/client"
+	"github.com/anbox-cloud/ams-sdk/pkg/network"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// Time to wait for the AMS service to acknowledge a detach
+	consoleDetachTimeout = 5 * time.Second
+)
+
+// ConsoleSize describes the size of the terminal attached to a console
+type ConsoleSize struct {
+	Width  int
+	Height int
+}
+
+// Console represents a session attached to the console of an instance
+type Console struct {
+	operation client.Operation
+	data      *websocket.Conn
+	control   *websocket.Conn
+
+	controlLock sync.Mutex
+	done        chan struct{}
+	detachOnce  sync.Once
+}
+
+// Operation returns the operation which tracks the console session on the AMS
+// side
+func (c *Console) Operation() client.Operation {
+	return c.operation
+}
+
+// Done returns a channel which is closed once the console session ended,
+// either because it was detached or because the AMS service closed it
+func (c *Console) Done() <-chan struct{} {
+	return c.done
+}
+
+// Resize informs the instance about a new size of the attached terminal
+func (c *Console) Resize(width, height int) error {
+	if width <= 0 || height <= 0 {
+		return errs.NewInvalidArgument("size")
+	}
+
+	c.controlLock.Lock()
+	defer c.controlLock.Unlock()
+	return c.control.WriteJSON(windowResizeMessage(width, height))
+}
+
+// windowResizeMessage returns the control message informing the instance
+// about a new size of the attached terminal
+func windowResizeMessage(width, height int) api.InstanceExecControl {
+	return api.InstanceExecControl{
+		Command: "window-resize",
+		Args: map[string]string{
+			"width":  strconv.Itoa(width),
+			"height": strconv.Itoa(height),
+		},
+	}
+}
+
+// Detach detaches from the console without stopping the instance. Detaching
+// multiple times is safe.
+func (c *Console) Detach() error {
+	var err error
+	c.detachOnce.Do(func() {
+		closeMsg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+		err = c.data.WriteControl(websocket.CloseMessage, closeMsg, time.Now().Add(consoleDetachTimeout))
+
+		select {
+		case <-c.done:
+		case <-time.After(consoleDetachTimeout):
+		}
+
+		c.data.Close()
+		c.control.Close()
+	})
+	return err
+}
+
+// AttachConsole attaches the given reader and writer to the console of the
+// instance with the given ID. Sizes received on the resize channel are
+// forwarded to the instance through the control channel of the console. The
+// session ends when stdin is closed, Detach is called or the AMS service
+// closes the console.
+func (c *clientImpl) AttachConsole(id string, stdin io.ReadCloser, stdout io.WriteCloser, resize <-chan ConsoleSize) (*Console, error) {
+	if len(id) == 0 {
+		return nil, errs.NewInvalidArgument("id")
+	}
+	if stdin == nil || stdout == nil {
+		return nil, errs.NewInvalidArgument("stdin/stdout")
+	}
+
+	hasConsoleSupport, err := c.HasExtension(api.ExtensionInstanceConsole)
+	if err != nil {
+		return nil, err
+	}
+	if !hasConsoleSupport {
+		return nil, errs.NewErrNotSupported("api extension \"instance_console\"")
+	}
+
+	details := api.InstanceConsolePost{Type: api.InstanceConsoleTypeConsole}
+	b, err := c.Codec().Marshal(details)
+	if err != nil {
+		return nil, err
+	}
+
+	op, _, err := c.QueryOperation("POST", client.APIPath("instances", id, "console"), nil, nil, bytes.NewReader(b), "")
+	if err != nil {
+		return nil, err
+	}
+
+	fds := operationFDs(op)
+	if len(fds["0"]) == 0 || len(fds["control"]) == 0 {
+		return nil, errs.NewErrInvalidFormat("console operation")
+	}
+
+	data, err := c.getOperationWebsocket(op.Get().ID, fds["0"])
+	if err != nil {
+		return nil, err
+	}
+	control, err := c.getOperationWebsocket(op.Get().ID, fds["control"])
+	if err != nil {
+		data.Close()
+		return nil, err
+	}
+
+	console := &Console{
+		operation: op,
+		data:      data,
+		control:   control,
+		done:      make(chan struct{}),
+	}
+
+	readDone, writeDone := network.WebsocketConsoleMirror(data, stdout, stdin)
+	go func() {
+		select {
+		case <-readDone:
+		case <-writeDone:
+		}
+		close(console.done)
+	}()
+
+	if resize != nil {
+		go func() {
+			for {
+				select {
+				case size, ok := <-resize:
+					if !ok {
+						return
+					}
+					_ = console.Resize(size.Width, size.Height)
+				case <-console.done:
+					return
+				}
+			}
+		}()
+	}
+
+	return console, nil
+}
@@ -20,7 +20,6 @@ package client
 
 import (
 	"bytes"
-	"encoding/json"
 	"io"
 	"net/http"
 	"strconv"
@@ -40,10 +39,21 @@ type ContainerExecArgs struct {
 	Stderr   io.WriteCloser
 	Control  func(conn *websocket.Conn)
 	DataDone chan bool
+	// Recorder receives a copy of the data exchanged through the standard
+	// streams of the command. Optional.
+	Recorder SessionRecorder
 }
 
 // ListContainersWithFilters lists all available containers the AMS service currently manages
 func (c *clientImpl) ListContainersWithFilters(filters []string) ([]api.Container, error) {
+	viaInstances, err := c.containersViaInstances()
+	if err != nil {
+		return nil, err
+	}
+	if viaInstances {
+		return c.listContainersViaInstances(filters)
+	}
+
 	containers := []api.Container{}
 	params, err := convertFiltersToParams(filters)
 	if err != nil {
@@ -56,17 +66,33 @@ func (c *clientImpl) ListContainersWithFilters(filters []string) ([]api.Containe
 
 // ListContainers lists all available containers the AMS service currently manages
 func (c *clientImpl) ListContainers() ([]api.Container, error) {
+	viaInstances, err := c.containersViaInstances()
+	if err != nil {
+		return nil, err
+	}
+	if viaInstances {
+		return c.listContainersViaInstances(nil)
+	}
+
 	containers := []api.Container{}
 	params := client.QueryParams{
 		"recursion": "1",
 	}
-	_, err := c.QueryStruct("GET", client.APIPath("containers"), params, nil, nil, "", &containers)
+	_, err = c.QueryStruct("GET", client.APIPath("containers"), params, nil, nil, "", &containers)
 	return containers, err
 }
 
 // LaunchContainer launches a single new container on the AMS endpoint
 func (c *clientImpl) LaunchContainer(details *api.ContainersPost, noWait bool) (client.Operation, error) {
-	b, err := json.Marshal(details)
+	viaInstances, err := c.containersViaInstances()
+	if err != nil {
+		return nil, err
+	}
+	if viaInstances {
+		return c.launchContainerViaInstances(details, noWait)
+	}
+
+	b, err := c.Codec().Marshal(details)
 	if err != nil {
 		return nil, err
 	}
@@ -78,27 +104,49 @@ func (c *clientImpl) LaunchContainer(details *api.ContainersPost, noWait bool) (
 
 // RetrieveContainerByID retrieves a single container by its ID
 func (c *clientImpl) RetrieveContainerByID(id string) (*api.Container, string, error) {
+	return c.retrieveContainer(id, nil)
+}
+
+// retrieveContainer retrieves a single container by its ID with the given
+// additional headers
+func (c *clientImpl) retrieveContainer(id string, header http.Header) (*api.Container, string, error) {
 	if len(id) == 0 {
 		return nil, "", errs.NewInvalidArgument("id")
 	}
+	viaInstances, err := c.containersViaInstances()
+	if err != nil {
+		return nil, "", err
+	}
+	if viaInstances {
+		return c.retrieveContainerViaInstances(id, header)
+	}
 	container := &api.Container{}
-	etag, err := c.QueryStruct("GET", client.APIPath("containers", id), nil, nil, nil, "", container)
+	etag, err := c.QueryStruct("GET", client.APIPath("containers", id), nil, header, nil, "", container)
 	return container, etag, err
 }
 
 // UpdateContainerByID updates an existing container specified by its id
-func (c *clientImpl) UpdateContainerByID(id string, details *api.ContainerPatch, noWait bool) (client.Operation, error) {
+func (c *clientImpl) UpdateContainerByID(id string, details *api.ContainerPatch, noWait bool, opts ...RequestOption) (client.Operation, error) {
 	if len(id) == 0 {
 		return nil, errs.NewInvalidArgument("id")
 	}
 
-	b, err := json.Marshal(details)
+	viaInstances, err := c.containersViaInstances()
+	if err != nil {
+		return nil, err
+	}
+	if viaInstances {
+		return c.updateContainerViaInstances(id, details, noWait, opts...)
+	}
+
+	b, err := c.Codec().Marshal(details)
 	if err != nil {
 		return nil, err
 	}
 
+	o := newRequestOptions(opts)
 	params := client.QueryParams{"no_wait": strconv.FormatBool(noWait)}
-	op, _, err := c.QueryOperation("PATCH", client.APIPath("containers", id), params, nil, bytes.NewReader(b), "")
+	op, _, err := c.QueryOperation("PATCH", client.APIPath("containers", id), params, o.headerWith(nil), bytes.NewReader(b), o.etag)
 	return op, err
 }
 
@@ -108,10 +156,18 @@ func (c *clientImpl) DeleteContainerByID(id string, force bool) (client.Operatio
 		return nil, errs.NewInvalidArgument("id")
 	}
 
+	viaInstances, err := c.containersViaInstances()
+	if err != nil {
+		return nil, err
+	}
+	if viaInstances {
+		return c.DeleteInstanceByID(id, force)
+	}
+
 	details := api.ContainerDelete{
 		Force: force,
 	}
-	b, err := json.Marshal(details)
+	b, err := c.Codec().Marshal(details)
 	if err != nil {
 		return nil, err
 	}
@@ -126,11 +182,19 @@ func (c *clientImpl) DeleteContainers(ids []string, force bool) (client.Operatio
 		return nil, errs.NewInvalidArgument("ids")
 	}
 
+	viaInstances, err := c.containersViaInstances()
+	if err != nil {
+		return nil, err
+	}
+	if viaInstances {
+		return c.DeleteInstances(ids, force)
+	}
+
 	details := api.ContainersDelete{
 		IDs:   ids,
 		Force: force,
 	}
-	b, err := json.Marshal(details)
+	b, err := c.Codec().Marshal(details)
 	if err != nil {
 		return nil, err
 	}
@@ -147,7 +211,14 @@ func (c *clientImpl) RetrieveContainerLog(id, name string, downloader func(heade
 	if len(name) == 0 {
 		return errs.NewInvalidArgument("name")
 	}
-	hasContainerLogsSupport, err := c.HasExtension("container_logs")
+	viaInstances, err := c.containersViaInstances()
+	if err != nil {
+		return err
+	}
+	if viaInstances {
+		return c.RetrieveInstanceLog(id, name, downloader)
+	}
+	hasContainerLogsSupport, err := c.HasExtension(api.ExtensionContainerLogs)
 	if err != nil {
 		return err
 	}
@@ -163,7 +234,14 @@ func (c *clientImpl) ExecuteContainer(id string, details *api.ContainerExecPost,
 	if len(id) == 0 {
 		return nil, errs.NewInvalidArgument("id")
 	}
-	hasContainerExecSupport, err := c.HasExtension("container_exec")
+	viaInstances, err := c.containersViaInstances()
+	if err != nil {
+		return nil, err
+	}
+	if viaInstances {
+		return c.executeContainerViaInstances(id, details, args)
+	}
+	hasContainerExecSupport, err := c.HasExtension(api.ExtensionContainerExec)
 	if err != nil {
 		return nil, err
 	}
@@ -171,7 +249,7 @@ func (c *clientImpl) ExecuteContainer(id string, details *api.ContainerExecPost,
 		return nil, errs.NewErrNotSupported("api extension \"container_exec\"")
 	}
 
-	b, err := json.Marshal(details)
+	b, err := c.Codec().Marshal(details)
 	if err != nil {
 		return nil, err
 	}
@@ -181,17 +259,16 @@ func (c *clientImpl) ExecuteContainer(id string, details *api.ContainerExecPost,
 		return nil, err
 	}
 
+	if args != nil && args.Recorder != nil {
+		recorded := *args
+		recorded.Stdin, recorded.Stdout, recorded.Stderr = recordStreams(args.Recorder, args.Stdin, args.Stdout, args.Stderr)
+		args = &recorded
+	}
+
 	if args != nil {
 		opAPI := op.Get()
 
-		fds := map[string]string{}
-		value, ok := opAPI.Metadata["fds"]
-		if ok {
-			values := value.(map[string]interface{})
-			for k, v := range values {
-				fds[k] = v.(string)
-			}
-		}
+		fds := operationFDs(op)
 
 		if args.Control != nil && fds["control"] != "" {
 			conn, err := c.getOperationWebsocket(opAPI.ID, fds["control"])
@@ -214,7 +291,7 @@ func (c *clientImpl) ExecuteContainer(id string, details *api.ContainerExecPost,
 				go func() {
 					network.WebsocketSendStream(conn, args.Stdin, -1)
 					<-network.WebsocketRecvStream(args.Stdout, conn)
-					conn.Close()
+					network.CloseGracefully(conn, websocket.CloseNormalClosure, "", network.DefaultCloseTimeout)
 
 					if args.DataDone != nil {
 						close(args.DataDone)
@@ -277,7 +354,7 @@ func (c *clientImpl) ExecuteContainer(id string, details *api.ContainerExecPost,
 				}
 
 				for _, conn := range conns {
-					conn.Close()
+					network.CloseGracefully(conn, websocket.CloseNormalClosure, "", network.DefaultCloseTimeout)
 				}
 
 				if args.DataDone != nil {
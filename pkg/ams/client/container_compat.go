@@ -0,0 +1,130 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"errors"
+	"net/http"
+
+	api "github.com/anbox-cloud/ams-sdk/api/ams"
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/client"
+)
+
+// containerProbeNode is a node name which is not expected to exist. It is used
+// to probe the container endpoint without listing any container.
+const containerProbeNode = "ams-sdk-container-endpoint-probe"
+
+// containersViaInstances reports whether requests for containers have to be
+// served through the instances endpoints because the AMS service does not
+// provide the legacy container endpoints anymore. The result is determined
+// once by probing the container endpoint.
+func (c *clientImpl) containersViaInstances() (bool, error) {
+	if !c.hasInstanceSupport {
+		return false, nil
+	}
+
+	c.containerEndpointsLock.Lock()
+	defer c.containerEndpointsLock.Unlock()
+
+	if c.containerEndpoints == nil {
+		// The filter matches no container so the response stays small. Any
+		// answer but 404 shows that the endpoint exists.
+		params := client.QueryParams{"node": containerProbeNode}
+		_, _, err := c.CallAPI("GET", client.APIPath("containers"), params, nil, nil, "")
+		var statusErr *client.StatusError
+		if err != nil && (!errors.As(err, &statusErr) || statusErr.StatusCode >= http.StatusInternalServerError) {
+			return false, err
+		}
+		available := statusErr == nil || statusErr.StatusCode != http.StatusNotFound
+		c.containerEndpoints = &available
+	}
+
+	return !*c.containerEndpoints, nil
+}
+
+func instancesToContainers(instances []api.Instance) []api.Container {
+	containers := []api.Container{}
+	for n := range instances {
+		// Virtual machines cannot be represented as containers
+		if instances[n].Type == api.InstanceTypeVM {
+			continue
+		}
+		containers = append(containers, api.InstanceToContainer(&instances[n]))
+	}
+	return containers
+}
+
+func (c *clientImpl) listContainersViaInstances(filters []string) ([]api.Container, error) {
+	instances, err := c.ListInstancesWithFilters(filters)
+	if err != nil {
+		return nil, err
+	}
+	return instancesToContainers(instances), nil
+}
+
+func (c *clientImpl) launchContainerViaInstances(details *api.ContainersPost, noWait bool) (client.Operation, error) {
+	if details == nil {
+		return nil, errs.NewInvalidArgument("details")
+	}
+	if len(details.InstanceType) > 0 {
+		return nil, errs.NewErrNotSupported("instance type when launching through the instances API")
+	}
+	d := api.ContainersPostToInstancesPost(details)
+	return c.LaunchInstance(&d, noWait)
+}
+
+func (c *clientImpl) retrieveContainerViaInstances(id string, header http.Header) (*api.Container, string, error) {
+	instance, etag, err := c.retrieveInstance(id, header)
+	if err != nil {
+		return nil, "", err
+	}
+	if instance.Type == api.InstanceTypeVM {
+		return nil, "", errs.NewErrNotFound("container")
+	}
+	container := api.InstanceToContainer(instance)
+	return &container, etag, nil
+}
+
+func (c *clientImpl) updateContainerViaInstances(id string, details *api.ContainerPatch, noWait bool, opts ...RequestOption) (client.Operation, error) {
+	if details == nil {
+		return nil, errs.NewInvalidArgument("details")
+	}
+	patch := api.ContainerPatchToInstancePatch(details)
+	return c.UpdateInstanceByID(id, &patch, noWait, opts...)
+}
+
+func (c *clientImpl) executeContainerViaInstances(id string, details *api.ContainerExecPost, args *ContainerExecArgs) (client.Operation, error) {
+	if details == nil {
+		return nil, errs.NewInvalidArgument("details")
+	}
+	execDetails := api.ContainerExecPostToInstanceExecPost(details)
+	var execArgs *InstanceExecArgs
+	if args != nil {
+		execArgs = &InstanceExecArgs{
+			Stdin:    args.Stdin,
+			Stdout:   args.Stdout,
+			Stderr:   args.Stderr,
+			Control:  args.Control,
+			DataDone: args.DataDone,
+			Recorder: args.Recorder,
+		}
+	}
+	return c.ExecuteInstance(id, &execDetails, execArgs)
+}
@@ -0,0 +1,269 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"context"
+	"sort"
+
+	api "github.com/anbox-cloud/ams-sdk/api/ams"
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/client"
+)
+
+// ContainerFleetSpec describes a set of instances of the same application or
+// image which EnsureContainerFleet keeps at the desired size
+type ContainerFleetSpec struct {
+	// Details are used to launch new instances. Either the application or
+	// the image has to be set.
+	Details api.InstancesPost `json:"details" yaml:"details"`
+	// Replicas is the number of instances the fleet should have
+	Replicas int `json:"replicas" yaml:"replicas"`
+	// MaxPerNode limits the number of instances of the fleet on a single
+	// node. Zero means no limit.
+	MaxPerNode int `json:"max_per_node,omitempty" yaml:"max_per_node,omitempty"`
+	// Placement selects the nodes new instances are launched on. If neither
+	// Placement nor MaxPerNode is set, AMS selects the node.
+	Placement *PlacementRules `json:"placement,omitempty" yaml:"placement,omitempty"`
+}
+
+// Contains returns true if the given instance is part of the fleet: it is no
+// base instance, runs the application or image of the launch details and has
+// all of its tags. Deleted instances are not part of the fleet.
+func (s *ContainerFleetSpec) Contains(instance *api.Instance) bool {
+	return !instance.IsBase && instance.StatusCode != api.InstanceStatusDeleted &&
+		isSiblingInstance(instance, &s.Details) && hasAllTags(instance.Tags, s.Details.Tags)
+}
+
+// ContainerFleetAction is the kind of action EnsureContainerFleet performed
+type ContainerFleetAction string
+
+const (
+	// ContainerFleetActionLaunch launches a new instance
+	ContainerFleetActionLaunch ContainerFleetAction = "launch"
+	// ContainerFleetActionDelete deletes an existing instance
+	ContainerFleetActionDelete ContainerFleetAction = "delete"
+)
+
+// ContainerFleetChange describes a single instance launched or deleted to
+// converge a fleet
+type ContainerFleetChange struct {
+	Action ContainerFleetAction
+	// InstanceID is empty for launches in dry run mode or which failed
+	InstanceID string
+	// Node the instance runs or is launched on. Empty if AMS selects the node.
+	Node string
+	// Reason explains why the action was taken
+	Reason string
+	// Err is set when the action could not be performed
+	Err error
+}
+
+// EnsureContainerFleet converges the instances of the fleet described by the
+// given spec towards the desired number of replicas and returns the changes
+// made. See Contains for which instances belong to the fleet. The tags of the
+// launch details should be used to tell multiple fleets of the same
+// application apart.
+//
+// Failed instances are deleted and replaced. Instances which are still
+// starting count towards the replicas, stopped ones do not. When scaling down,
+// only running instances are removed, from the nodes running the most
+// instances of the fleet first, newest first. Running instances are never moved, so placement constraints
+// only apply to new instances. With the DryRun option the changes are only
+// computed. Calling EnsureContainerFleet periodically turns it into a simple
+// autoscaler.
+func (c *clientImpl) EnsureContainerFleet(ctx context.Context, spec *ContainerFleetSpec, opts *FleetApplyOptions) ([]ContainerFleetChange, error) {
+	if spec == nil {
+		return nil, errs.NewInvalidArgument("spec")
+	}
+	if len(spec.Details.ApplicationID) == 0 && len(spec.Details.ImageID) == 0 {
+		return nil, errs.NewInvalidArgument("details")
+	}
+	if spec.Replicas < 0 {
+		return nil, errs.NewInvalidArgument("replicas")
+	}
+	if opts == nil {
+		opts = &FleetApplyOptions{}
+	}
+
+	nodes, err := c.ListNodes()
+	if err != nil {
+		return nil, err
+	}
+	instances, err := c.ListInstances()
+	if err != nil {
+		return nil, err
+	}
+
+	details := spec.Details
+	perNode := map[string]int{}
+	running := []api.Instance{}
+	failed := []api.Instance{}
+	// Instances which are still starting count towards the replicas so they
+	// are not launched twice, but only running ones are removed when scaling
+	// down. Stopped instances count as neither.
+	active := 0
+	for _, instance := range instances {
+		if !spec.Contains(&instance) {
+			continue
+		}
+		switch instance.StatusCode {
+		case api.InstanceStatusError:
+			failed = append(failed, instance)
+			continue
+		case api.InstanceStatusStopped:
+			continue
+		case api.InstanceStatusRunning:
+			running = append(running, instance)
+		}
+		active++
+		perNode[instance.Node]++
+	}
+
+	changes := []ContainerFleetChange{}
+	remove := func(instance api.Instance, reason string, force bool) error {
+		change := ContainerFleetChange{
+			Action:     ContainerFleetActionDelete,
+			InstanceID: instance.ID,
+			Node:       instance.Node,
+			Reason:     reason,
+		}
+		if !opts.DryRun {
+			change.Err = waitFleetOperation(ctx, func() (client.Operation, error) {
+				return c.DeleteInstanceByID(instance.ID, force)
+			})
+		}
+		changes = append(changes, change)
+		return change.Err
+	}
+
+	for _, instance := range failed {
+		if err := ctx.Err(); err != nil {
+			return changes, err
+		}
+		remove(instance, "instance failed", true)
+	}
+
+	for _, instance := range containerFleetSurplus(running, perNode, len(running)-spec.Replicas) {
+		if err := ctx.Err(); err != nil {
+			return changes, err
+		}
+		if err := remove(instance, "scale down", false); err == nil {
+			perNode[instance.Node]--
+			active--
+		}
+	}
+
+	rules := spec.Placement
+	if rules == nil && spec.MaxPerNode > 0 {
+		rules = &PlacementRules{Spread: true}
+	}
+	for n := active; n < spec.Replicas; n++ {
+		if err := ctx.Err(); err != nil {
+			return changes, err
+		}
+
+		change := ContainerFleetChange{Action: ContainerFleetActionLaunch, Reason: "scale up"}
+		if rules != nil {
+			excluded := map[string]bool{}
+			for node, count := range perNode {
+				if spec.MaxPerNode > 0 && count >= spec.MaxPerNode {
+					excluded[node] = true
+				}
+			}
+			change.Node, change.Err = selectPlacementNode(nodes, instances, &details, rules, excluded)
+			if change.Err != nil {
+				// No node can take further instances, so all other launches
+				// would fail the same way
+				changes = append(changes, change)
+				break
+			}
+		}
+
+		if !opts.DryRun {
+			d := details
+			d.Node = change.Node
+			op, err := c.LaunchInstance(&d, false)
+			if err == nil {
+				change.InstanceID = launchedInstanceID(op)
+				err = op.Wait(ctx)
+			}
+			change.Err = err
+		}
+		changes = append(changes, change)
+		if change.Err != nil {
+			continue
+		}
+
+		// Account for the new instance in the placement of the next one
+		perNode[change.Node]++
+		instances = append(instances, api.Instance{
+			ID:         change.InstanceID,
+			Node:       change.Node,
+			AppID:      details.ApplicationID,
+			ImageID:    details.ImageID,
+			Tags:       details.Tags,
+			StatusCode: api.InstanceStatusCreated,
+		})
+	}
+
+	return changes, nil
+}
+
+// containerFleetSurplus selects count instances to remove, taking the newest
+// instance from the node with the most instances each time. The given per
+// node counts are not modified.
+func containerFleetSurplus(instances []api.Instance, perNode map[string]int, count int) []api.Instance {
+	if count <= 0 {
+		return nil
+	}
+
+	byNode := map[string][]api.Instance{}
+	counts := map[string]int{}
+	for _, instance := range instances {
+		byNode[instance.Node] = append(byNode[instance.Node], instance)
+		counts[instance.Node] = perNode[instance.Node]
+	}
+	for node := range byNode {
+		list := byNode[node]
+		sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt > list[j].CreatedAt })
+	}
+
+	surplus := []api.Instance{}
+	for len(surplus) < count {
+		// Instances without a node are grouped under the empty node name,
+		// so it cannot mark that no node was picked yet
+		busiest, found := "", false
+		for node, list := range byNode {
+			if len(list) == 0 {
+				continue
+			}
+			if !found || counts[node] > counts[busiest] || (counts[node] == counts[busiest] && node < busiest) {
+				busiest, found = node, true
+			}
+		}
+		if !found {
+			break
+		}
+		surplus = append(surplus, byNode[busiest][0])
+		byNode[busiest] = byNode[busiest][1:]
+		counts[busiest]--
+	}
+	return surplus
+}
@@ -0,0 +1,172 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	restclient "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/client"
+)
+
+// Environment variables read by NewClientFromEnv
+const (
+	// EnvURL holds the URL of the AMS service, or unix:// followed by the
+	// path of the unix socket of a local AMS service
+	EnvURL = "AMS_URL"
+	// EnvClientCert holds the path of the client certificate
+	EnvClientCert = "AMS_CLIENT_CERT"
+	// EnvClientKey holds the path of the private key of the client
+	EnvClientKey = "AMS_CLIENT_KEY"
+	// EnvServerCert holds the path of the certificate of the AMS service or
+	// the PEM encoded certificate itself
+	EnvServerCert = "AMS_SERVER_CERT"
+	// EnvCACert holds the path of the CA certificates used to verify the AMS
+	// service
+	EnvCACert = "AMS_CA_CERT"
+	// EnvInsecure disables the verification of the AMS service if set to true
+	EnvInsecure = "AMS_INSECURE"
+	// EnvTimeout holds the transport timeout of the client, e.g. "30s"
+	EnvTimeout = "AMS_TIMEOUT"
+	// EnvReadOnly makes the client read-only if set to true
+	EnvReadOnly = "AMS_READ_ONLY"
+)
+
+// EnvError is returned by NewClientFromEnv if the environment does not
+// describe a valid client configuration
+type EnvError struct {
+	// Missing lists the variables which are required but not set
+	Missing []string
+	// Invalid maps variables with an invalid value to the reason
+	Invalid map[string]string
+}
+
+// Error returns the error string
+func (e *EnvError) Error() string {
+	parts := []string{}
+	if len(e.Missing) > 0 {
+		parts = append(parts, fmt.Sprintf("missing %s", strings.Join(e.Missing, ", ")))
+	}
+	names := make([]string, 0, len(e.Invalid))
+	for name := range e.Invalid {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("invalid %s: %s", name, e.Invalid[name]))
+	}
+	return fmt.Sprintf("invalid client environment: %s", strings.Join(parts, "; "))
+}
+
+// ProfileFromEnv returns the connection profile described by the AMS_*
+// environment variables
+func ProfileFromEnv() (*ConnectionProfile, error) {
+	envErr := &EnvError{Invalid: map[string]string{}}
+
+	p := &ConnectionProfile{
+		Version:        ConnectionProfileVersion,
+		URL:            os.Getenv(EnvURL),
+		ClientCertFile: os.Getenv(EnvClientCert),
+		ClientKeyFile:  os.Getenv(EnvClientKey),
+		CAFile:         os.Getenv(EnvCACert),
+	}
+
+	if len(p.URL) == 0 {
+		envErr.Missing = append(envErr.Missing, EnvURL)
+	} else if u, err := url.Parse(p.URL); err != nil || len(u.Host) == 0 && u.Scheme != "unix" {
+		envErr.Invalid[EnvURL] = "not a valid URL"
+	}
+	if len(p.ClientCertFile) > 0 && len(p.ClientKeyFile) == 0 {
+		envErr.Missing = append(envErr.Missing, EnvClientKey)
+	}
+	if len(p.ClientKeyFile) > 0 && len(p.ClientCertFile) == 0 {
+		envErr.Missing = append(envErr.Missing, EnvClientCert)
+	}
+
+	if serverCert := os.Getenv(EnvServerCert); len(serverCert) > 0 {
+		if strings.HasPrefix(strings.TrimSpace(serverCert), "-----BEGIN") {
+			p.ServerCertificate = serverCert
+		} else if data, err := os.ReadFile(serverCert); err != nil {
+			envErr.Invalid[EnvServerCert] = err.Error()
+		} else {
+			p.ServerCertificate = string(data)
+		}
+	}
+
+	parseBool := func(name string, target *bool) {
+		value := os.Getenv(name)
+		if len(value) == 0 {
+			return
+		}
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			envErr.Invalid[name] = "not a boolean"
+			return
+		}
+		*target = b
+	}
+	parseBool(EnvInsecure, &p.Insecure)
+	parseBool(EnvReadOnly, &p.Options.ReadOnly)
+
+	if timeout := os.Getenv(EnvTimeout); len(timeout) > 0 {
+		if _, err := time.ParseDuration(timeout); err != nil {
+			envErr.Invalid[EnvTimeout] = "not a duration"
+		} else {
+			p.Options.Timeout = timeout
+		}
+	}
+
+	if len(envErr.Missing) > 0 || len(envErr.Invalid) > 0 {
+		return nil, envErr
+	}
+	return p, nil
+}
+
+// NewClientFromEnv creates a new client configured through the AMS_*
+// environment variables. The given options are applied after the ones
+// derived from the environment.
+func NewClientFromEnv(opts ...restclient.Option) (Client, error) {
+	p, err := ProfileFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(p.URL, "unix://") {
+		return New(strings.TrimPrefix(p.URL, "unix://"), nil, append(p.ClientOptions(), opts...)...)
+	}
+
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(p.URL)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := p.TLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return New(u, tlsConfig, append(p.ClientOptions(), opts...)...)
+}
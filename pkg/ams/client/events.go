@@ -0,0 +1,57 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	api "github.com/anbox-cloud/ams-sdk/api/ams"
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/client"
+)
+
+// SubscribeEvents connects to the events API and calls the handler with the
+// typed envelope of every event of the given types. All types are delivered
+// if none are given. Events which cannot be decoded are dropped. The returned
+// listener has to be disconnected once no further events are needed.
+func (c *clientImpl) SubscribeEvents(types []api.EventType, handler func(event *api.EventEnvelope)) (*client.EventListener, error) {
+	if handler == nil {
+		return nil, errs.NewInvalidArgument("handler")
+	}
+
+	var names []string
+	for _, t := range types {
+		names = append(names, string(t))
+	}
+
+	listener, err := c.GetEvents()
+	if err != nil {
+		return nil, err
+	}
+	_, err = listener.AddHandler(names, func(data interface{}) {
+		event, err := api.NewEventEnvelope(data)
+		if err != nil {
+			return
+		}
+		handler(event)
+	})
+	if err != nil {
+		listener.Disconnect()
+		return nil, err
+	}
+	return listener, nil
+}
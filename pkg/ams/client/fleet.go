@@ -0,0 +1,379 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	api "github.com/anbox-cloud/ams-sdk/api/ams"
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared"
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/client"
+)
+
+// FleetSpec declaratively describes the configuration of an AMS cluster. It is
+// returned by ExportFleetSpec and can be applied to the same or another
+// cluster with ApplyFleetSpec.
+type FleetSpec struct {
+	Applications []FleetApplicationSpec `json:"applications" yaml:"applications"`
+	Nodes        []FleetNodeSpec        `json:"nodes" yaml:"nodes"`
+	Config       map[string]string      `json:"config" yaml:"config"`
+}
+
+// FleetApplicationSpec describes the configuration of a single application
+type FleetApplicationSpec struct {
+	Name               string   `json:"name" yaml:"name"`
+	InstanceType       string   `json:"instance_type" yaml:"instance_type"`
+	Tags               []string `json:"tags" yaml:"tags"`
+	Addons             []string `json:"addons" yaml:"addons"`
+	InhibitAutoUpdates bool     `json:"inhibit_auto_updates" yaml:"inhibit_auto_updates"`
+	// Versions lists the available versions of the application
+	Versions []int `json:"versions" yaml:"versions"`
+	// PublishedVersions lists the versions of the application which are published
+	PublishedVersions []int `json:"published_versions" yaml:"published_versions"`
+}
+
+// FleetNodeSpec describes the configuration of a single node
+type FleetNodeSpec struct {
+	Name          string   `json:"name" yaml:"name"`
+	Tags          []string `json:"tags" yaml:"tags"`
+	Unschedulable bool     `json:"unschedulable" yaml:"unschedulable"`
+}
+
+// FleetResource is the kind of resource a FleetChange applies to
+type FleetResource string
+
+const (
+	// FleetResourceApplication is an application
+	FleetResourceApplication FleetResource = "application"
+	// FleetResourceNode is a node
+	FleetResourceNode FleetResource = "node"
+	// FleetResourceConfig is a configuration item
+	FleetResourceConfig FleetResource = "config"
+)
+
+// FleetChange describes a single difference between a fleet spec and a cluster
+type FleetChange struct {
+	Resource FleetResource
+	// Name of the application, node or configuration item
+	Name string
+	// Field which differs. Empty for configuration items and missing resources.
+	Field string
+	From  string
+	To    string
+	// Err is set when the change could not be applied
+	Err error
+}
+
+// String returns a human readable description of the change
+func (c FleetChange) String() string {
+	name := fmt.Sprintf("%s %s", c.Resource, c.Name)
+	if len(c.Field) > 0 {
+		name = fmt.Sprintf("%s %s", name, c.Field)
+	}
+	return fmt.Sprintf("%s: %q -> %q", name, c.From, c.To)
+}
+
+// FleetApplyOptions control how a fleet spec is applied
+type FleetApplyOptions struct {
+	// DryRun only computes the changes without applying them
+	DryRun bool
+}
+
+// ExportFleetSpec captures the applications, nodes and configuration of the
+// cluster as a fleet spec
+func (c *clientImpl) ExportFleetSpec(ctx context.Context) (*FleetSpec, error) {
+	apps, err := c.ListApplications()
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := c.ListNodes()
+	if err != nil {
+		return nil, err
+	}
+	config, err := c.RetrieveConfigItems()
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	spec := &FleetSpec{Config: map[string]string{}}
+	for _, app := range apps {
+		spec.Applications = append(spec.Applications, fleetApplicationSpec(&app))
+	}
+	for _, node := range nodes {
+		spec.Nodes = append(spec.Nodes, FleetNodeSpec{
+			Name:          node.Name,
+			Tags:          node.Tags,
+			Unschedulable: node.Unschedulable,
+		})
+	}
+	for name, value := range config {
+		spec.Config[name] = fmt.Sprint(value)
+	}
+
+	sort.Slice(spec.Applications, func(i, j int) bool { return spec.Applications[i].Name < spec.Applications[j].Name })
+	sort.Slice(spec.Nodes, func(i, j int) bool { return spec.Nodes[i].Name < spec.Nodes[j].Name })
+	return spec, nil
+}
+
+func fleetApplicationSpec(app *api.Application) FleetApplicationSpec {
+	spec := FleetApplicationSpec{
+		Name:               app.Name,
+		InstanceType:       app.InstanceType,
+		Tags:               app.Tags,
+		Addons:             app.Addons,
+		InhibitAutoUpdates: app.InhibitAutoUpdates,
+		Versions:           []int{},
+		PublishedVersions:  []int{},
+	}
+	for _, v := range app.Versions {
+		spec.Versions = append(spec.Versions, v.Number)
+		if v.Published {
+			spec.PublishedVersions = append(spec.PublishedVersions, v.Number)
+		}
+	}
+	return spec
+}
+
+// ApplyFleetSpec reconciles the cluster towards the given fleet spec and
+// returns the changes made. With the DryRun option the changes are only
+// computed, which allows previewing them.
+//
+// Only settings AMS allows to modify through its API are reconciled. Missing
+// applications and application versions cannot be created without their
+// package and missing nodes need to be added with AddNode, so both are
+// reported as changes with an ErrNotFound error. Resources which exist in the
+// cluster but not in the spec are left untouched.
+func (c *clientImpl) ApplyFleetSpec(ctx context.Context, spec *FleetSpec, opts *FleetApplyOptions) ([]FleetChange, error) {
+	if spec == nil {
+		return nil, errs.NewInvalidArgument("spec")
+	}
+	if opts == nil {
+		opts = &FleetApplyOptions{}
+	}
+
+	current, err := c.ExportFleetSpec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	apps, err := c.ListApplications()
+	if err != nil {
+		return nil, err
+	}
+	appIDs := map[string]string{}
+	for _, app := range apps {
+		appIDs[app.Name] = app.ID
+	}
+
+	changes := []FleetChange{}
+	for _, app := range spec.Applications {
+		if err := ctx.Err(); err != nil {
+			return changes, err
+		}
+		changes = append(changes, c.applyFleetApplication(ctx, current, appIDs[app.Name], &app, opts.DryRun)...)
+	}
+	for _, node := range spec.Nodes {
+		if err := ctx.Err(); err != nil {
+			return changes, err
+		}
+		changes = append(changes, c.applyFleetNode(ctx, current, &node, opts.DryRun)...)
+	}
+
+	names := make([]string, 0, len(spec.Config))
+	for name := range spec.Config {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := ctx.Err(); err != nil {
+			return changes, err
+		}
+		value, ok := current.Config[name]
+		if ok && value == spec.Config[name] {
+			continue
+		}
+		change := FleetChange{Resource: FleetResourceConfig, Name: name, From: value, To: spec.Config[name]}
+		if !opts.DryRun {
+			change.Err = c.SetConfigItem(name, spec.Config[name])
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
+
+func (c *clientImpl) applyFleetApplication(ctx context.Context, current *FleetSpec, id string, spec *FleetApplicationSpec, dryRun bool) []FleetChange {
+	var existing *FleetApplicationSpec
+	for n := range current.Applications {
+		if current.Applications[n].Name == spec.Name {
+			existing = &current.Applications[n]
+			break
+		}
+	}
+	if existing == nil {
+		return []FleetChange{{
+			Resource: FleetResourceApplication,
+			Name:     spec.Name,
+			To:       spec.Name,
+			Err:      errs.NewErrNotFound(fmt.Sprintf("application %s", spec.Name)),
+		}}
+	}
+
+	patch, changes := fleetApplicationPatch(existing, spec)
+	if len(changes) > 0 && !dryRun {
+		if err := c.UpdateApplicationWithDetails(id, patch); err != nil {
+			for n := range changes {
+				changes[n].Err = err
+			}
+		}
+	}
+
+	return append(changes, c.applyFleetApplicationVersions(ctx, id, existing, spec, dryRun)...)
+}
+
+// fleetApplicationPatch returns the patch and the changes required to move
+// the existing application to the given spec
+func fleetApplicationPatch(existing, spec *FleetApplicationSpec) (api.ApplicationPatch, []FleetChange) {
+	changes := []FleetChange{}
+	patch := api.ApplicationPatch{}
+	if spec.InstanceType != existing.InstanceType {
+		patch.InstanceType = &spec.InstanceType
+		changes = append(changes, fleetFieldChange(FleetResourceApplication, spec.Name, "instance_type", existing.InstanceType, spec.InstanceType))
+	}
+	if !shared.CompareSlicesUnordered(spec.Tags, existing.Tags) {
+		patch.Tags = &spec.Tags
+		changes = append(changes, fleetFieldChange(FleetResourceApplication, spec.Name, "tags", joinFleetList(existing.Tags), joinFleetList(spec.Tags)))
+	}
+	if !shared.CompareSlicesUnordered(spec.Addons, existing.Addons) {
+		patch.Addons = &spec.Addons
+		changes = append(changes, fleetFieldChange(FleetResourceApplication, spec.Name, "addons", joinFleetList(existing.Addons), joinFleetList(spec.Addons)))
+	}
+	if spec.InhibitAutoUpdates != existing.InhibitAutoUpdates {
+		patch.InhibitAutoUpdates = &spec.InhibitAutoUpdates
+		changes = append(changes, fleetFieldChange(FleetResourceApplication, spec.Name, "inhibit_auto_updates",
+			fmt.Sprint(existing.InhibitAutoUpdates), fmt.Sprint(spec.InhibitAutoUpdates)))
+	}
+	return patch, changes
+}
+
+// applyFleetApplicationVersions reports missing versions and publishes or
+// revokes the existing versions of the application according to the spec
+func (c *clientImpl) applyFleetApplicationVersions(ctx context.Context, id string, existing, spec *FleetApplicationSpec, dryRun bool) []FleetChange {
+	changes := []FleetChange{}
+	for _, version := range spec.Versions {
+		if !intInSlice(version, existing.Versions) {
+			change := fleetFieldChange(FleetResourceApplication, spec.Name, "versions", "", fmt.Sprint(version))
+			change.Err = errs.NewErrNotFound(fmt.Sprintf("version %d of application %s", version, spec.Name))
+			changes = append(changes, change)
+		}
+	}
+
+	for _, version := range existing.Versions {
+		published := intInSlice(version, existing.PublishedVersions)
+		wanted := intInSlice(version, spec.PublishedVersions)
+		if published == wanted {
+			continue
+		}
+		change := fleetFieldChange(FleetResourceApplication, spec.Name, fmt.Sprintf("version %d published", version),
+			fmt.Sprint(published), fmt.Sprint(wanted))
+		if !dryRun {
+			change.Err = waitFleetOperation(ctx, func() (client.Operation, error) {
+				if wanted {
+					return c.PublishApplicationVersion(id, version)
+				}
+				return c.RevokeApplicationVersion(id, version)
+			})
+		}
+		changes = append(changes, change)
+	}
+
+	return changes
+}
+
+func (c *clientImpl) applyFleetNode(ctx context.Context, current *FleetSpec, spec *FleetNodeSpec, dryRun bool) []FleetChange {
+	var existing *FleetNodeSpec
+	for n := range current.Nodes {
+		if current.Nodes[n].Name == spec.Name {
+			existing = &current.Nodes[n]
+			break
+		}
+	}
+	if existing == nil {
+		return []FleetChange{{
+			Resource: FleetResourceNode,
+			Name:     spec.Name,
+			To:       spec.Name,
+			Err:      errs.NewErrNotFound(fmt.Sprintf("node %s", spec.Name)),
+		}}
+	}
+
+	changes := []FleetChange{}
+	patch := api.NodePatch{}
+	if !shared.CompareSlicesUnordered(spec.Tags, existing.Tags) {
+		patch.Tags = &spec.Tags
+		changes = append(changes, fleetFieldChange(FleetResourceNode, spec.Name, "tags", joinFleetList(existing.Tags), joinFleetList(spec.Tags)))
+	}
+	if spec.Unschedulable != existing.Unschedulable {
+		patch.Unschedulable = &spec.Unschedulable
+		changes = append(changes, fleetFieldChange(FleetResourceNode, spec.Name, "unschedulable",
+			fmt.Sprint(existing.Unschedulable), fmt.Sprint(spec.Unschedulable)))
+	}
+	if len(changes) > 0 && !dryRun {
+		err := waitFleetOperation(ctx, func() (client.Operation, error) {
+			return c.UpdateNode(spec.Name, &patch)
+		})
+		for n := range changes {
+			changes[n].Err = err
+		}
+	}
+	return changes
+}
+
+func waitFleetOperation(ctx context.Context, f func() (client.Operation, error)) error {
+	op, err := f()
+	if err != nil {
+		return err
+	}
+	return op.Wait(ctx)
+}
+
+func fleetFieldChange(resource FleetResource, name, field, from, to string) FleetChange {
+	return FleetChange{Resource: resource, Name: name, Field: field, From: from, To: to}
+}
+
+func joinFleetList(values []string) string {
+	sorted := append([]string{}, values...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+func intInSlice(value int, list []int) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
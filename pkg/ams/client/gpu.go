@@ -0,0 +1,103 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"fmt"
+
+	api "github.com/anbox-cloud/ams-sdk/api/ams"
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+)
+
+// GPUTypeNodeTag is the key of the node tag describing the type of the GPUs
+// of a node, e.g. "gpu=nvidia"
+const GPUTypeNodeTag = "gpu"
+
+// GPURequirements describes the GPU an instance requires
+type GPURequirements struct {
+	// Slots is the number of GPU slots to allocate
+	Slots int `json:"slots" yaml:"slots"`
+	// EncoderSlots is the number of encoder slots the instance needs. AMS
+	// allocates encoder slots according to the video encoder of the
+	// application, so this is only used to check the node capabilities.
+	EncoderSlots int `json:"encoder_slots,omitempty" yaml:"encoder_slots,omitempty"`
+	// Type is the preferred type of GPU, e.g. "nvidia". Nodes tagged with
+	// "gpu=<type>" are preferred when placing the instance.
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+}
+
+// Validate checks the requirements for invalid values
+func (r GPURequirements) Validate() error {
+	if r.Slots < 0 {
+		return errs.NewInvalidArgument("gpu slots")
+	}
+	if r.EncoderSlots < 0 {
+		return errs.NewInvalidArgument("gpu encoder slots")
+	}
+	if r.EncoderSlots > 0 && r.Slots == 0 {
+		return fmt.Errorf("gpu encoder slots require gpu slots")
+	}
+	return nil
+}
+
+// nodeTag returns the node tag for the preferred GPU type
+func (r GPURequirements) nodeTag() string {
+	if len(r.Type) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s=%s", GPUTypeNodeTag, r.Type)
+}
+
+// CheckGPURequirements checks that at least one schedulable node of the
+// cluster has a GPU providing the required slots. The check is made against
+// the capabilities of the nodes, not against their current allocations.
+func CheckGPURequirements(c Client, r GPURequirements) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	if r.Slots == 0 {
+		return nil
+	}
+
+	nodes, err := c.ListNodes()
+	if err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		if node.StatusCode != api.NodeStatusOnline || node.Unschedulable {
+			continue
+		}
+		if nodeProvidesGPU(&node, r) {
+			return nil
+		}
+	}
+	return errs.NewErrNotFound(fmt.Sprintf("node providing a GPU with %d slots and %d encoder slots", r.Slots, r.EncoderSlots))
+}
+
+func nodeProvidesGPU(node *api.Node, r GPURequirements) bool {
+	if len(node.GPUs) == 0 {
+		return node.GPUSlots >= r.Slots && node.GPUEncoderSlots >= r.EncoderSlots
+	}
+	for _, gpu := range node.GPUs {
+		if gpu.Slots >= r.Slots && gpu.EncoderSlots >= r.EncoderSlots {
+			return true
+		}
+	}
+	return false
+}
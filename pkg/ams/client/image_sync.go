@@ -0,0 +1,104 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	api "github.com/anbox-cloud/ams-sdk/api/ams"
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/client"
+)
+
+// ListImageNodeStatus lists the synchronization status of all versions of the
+// image with the given ID or name on every node of the cluster
+func (c *clientImpl) ListImageNodeStatus(id string) ([]api.ImageNodeStatus, error) {
+	if len(id) == 0 {
+		return nil, errs.NewInvalidArgument("id")
+	}
+	hasSupport, err := c.HasExtension(api.ExtensionImageNodeStatus)
+	if err != nil {
+		return nil, err
+	}
+	if !hasSupport {
+		return nil, errs.NewErrNotSupported("api extension \"image_node_status\"")
+	}
+
+	status := []api.ImageNodeStatus{}
+	params := client.QueryParams{
+		"recursion": "1",
+	}
+	_, err = c.QueryStruct("GET", client.APIPath("images", id, "nodes"), params, nil, nil, "", &status)
+	return status, err
+}
+
+// WaitForImageSync blocks until the given version of the image with the given
+// ID or name is available on all nodes of the cluster or the context is done.
+// A negative version waits for the latest version of the image. An error is
+// returned if the image version enters the error status.
+func (c *clientImpl) WaitForImageSync(ctx context.Context, id string, version int) (*api.Image, error) {
+	if len(id) == 0 {
+		return nil, errs.NewInvalidArgument("id")
+	}
+
+	ticker := time.NewTicker(waitPollInterval)
+	defer ticker.Stop()
+
+	for {
+		image, _, err := c.RetrieveImageByIDOrName(id, api.ImageTypeAny)
+		if err != nil {
+			return nil, err
+		}
+
+		v := imageVersion(image, version)
+		if v != nil {
+			switch v.StatusCode {
+			case api.ImageStatusActive:
+				return image, nil
+			case api.ImageStatusError:
+				return image, fmt.Errorf("synchronization of version %d of image %s failed", v.Number, id)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return image, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// imageVersion returns the given version of the image or its latest version
+// if the given version is negative. Returns nil if the version does not exist
+// (yet).
+func imageVersion(image *api.Image, version int) *api.ImageVersion {
+	var found *api.ImageVersion
+	for n := range image.Versions {
+		v := &image.Versions[n]
+		if version >= 0 && v.Number == version {
+			return v
+		}
+		if version < 0 && (found == nil || v.Number > found.Number) {
+			found = v
+		}
+	}
+	return found
+}
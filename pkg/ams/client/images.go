@@ -21,10 +21,13 @@ package client
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"path"
 	"strconv"
+	"strings"
 
 	api "github.com/anbox-cloud/ams-sdk/api/ams"
 	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
@@ -42,12 +45,25 @@ func (c *clientImpl) ListImages() ([]api.Image, error) {
 }
 
 // AddImage adds a new image with the given payload
-func (c *clientImpl) AddImage(name, packagePath string, isDefault bool, sentBytes chan float64) (client.Operation, error) {
+func (c *clientImpl) AddImage(name, packagePath string, isDefault bool, sentBytes chan float64, opts ...RequestOption) (client.Operation, error) {
 	details := api.ImagesPost{
 		Name:    name,
 		Default: isDefault,
 	}
-	return c.upload("POST", client.APIPath("images"), nil, packagePath, details, sentBytes)
+	return c.upload("POST", client.APIPath("images"), nil, packagePath, details, sentBytes, newRequestOptions(opts))
+}
+
+// CreateImage adds a new image and streams the image package read from the
+// given payload to AMS
+func (c *clientImpl) CreateImage(name string, payload io.ReadSeeker, isDefault bool, sentBytes chan float64, opts ...RequestOption) (client.Operation, error) {
+	if len(name) == 0 {
+		return nil, errs.NewInvalidArgument("name")
+	}
+	details := api.ImagesPost{
+		Name:    name,
+		Default: isDefault,
+	}
+	return c.uploadStream("POST", client.APIPath("images"), nil, payload, details, sentBytes, newRequestOptions(opts))
 }
 
 // ImportImage imports a new image from the image server
@@ -64,7 +80,7 @@ func (c *clientImpl) ImportImageByType(name, path string, imgType api.ImageType,
 		Type:    imgType,
 	}
 
-	b, err := json.Marshal(&details)
+	b, err := c.Codec().Marshal(&details)
 	if err != nil {
 		return nil, fmt.Errorf("could not marshal request body: %v", err)
 	}
@@ -76,11 +92,24 @@ func (c *clientImpl) ImportImageByType(name, path string, imgType api.ImageType,
 }
 
 // UpdateImage updates an existing image with the given payload
-func (c *clientImpl) UpdateImage(id, packagePath string, sentBytes chan float64) (client.Operation, error) {
+func (c *clientImpl) UpdateImage(id, packagePath string, sentBytes chan float64, opts ...RequestOption) (client.Operation, error) {
+	o := newRequestOptions(opts)
+	details := api.ImagePatch{}
+	return c.upload("PATCH", client.APIPath("images", id), nil, packagePath, details, sentBytes, o)
+}
+
+// UpdateImageWithPayload updates an existing image by streaming a new version
+// of the image package read from the given payload to AMS
+func (c *clientImpl) UpdateImageWithPayload(id string, payload io.ReadSeeker, sentBytes chan float64, opts ...RequestOption) (client.Operation, error) {
+	if len(id) == 0 {
+		return nil, errs.NewInvalidArgument("id")
+	}
+	o := newRequestOptions(opts)
 	details := api.ImagePatch{}
-	return c.upload("PATCH", client.APIPath("images", id), nil, packagePath, details, sentBytes)
+	return c.uploadStream("PATCH", client.APIPath("images", id), nil, payload, details, sentBytes, o)
 }
 
+// SetDefaultImage marks the image with the given ID as the default one
 func (c *clientImpl) SetDefaultImage(id string) error {
 	d := new(bool)
 	*d = true
@@ -89,7 +118,7 @@ func (c *clientImpl) SetDefaultImage(id string) error {
 		Default: d,
 	}
 
-	b, err := json.Marshal(details)
+	b, err := c.Codec().Marshal(details)
 	if err != nil {
 		return fmt.Errorf("could not marshal request body: %v", err)
 	}
@@ -102,22 +131,34 @@ func (c *clientImpl) SetDefaultImage(id string) error {
 	return op.Wait(context.Background())
 }
 
+// TriggerImageSync forces AMS to synchronize the image with the image server
 func (c *clientImpl) TriggerImageSync(id string) error {
+	op, err := c.StartImageSync(id)
+	if err != nil {
+		return err
+	}
+	return op.Wait(context.Background())
+}
+
+// StartImageSync forces AMS to synchronize the image with the image server and
+// returns the operation tracking the synchronization
+func (c *clientImpl) StartImageSync(id string) (client.Operation, error) {
+	if len(id) == 0 {
+		return nil, errs.NewInvalidArgument("id")
+	}
+
 	details := api.ImagePatch{
 		ForceSync: true,
 	}
 
-	b, err := json.Marshal(details)
+	b, err := c.Codec().Marshal(details)
 	if err != nil {
-		return fmt.Errorf("could not marshal request body: %v", err)
+		return nil, fmt.Errorf("could not marshal request body: %v", err)
 	}
 
 	header := http.Header{"Content-Type": []string{"application/json"}}
 	op, _, err := c.QueryOperation("PATCH", client.APIPath("images", id), nil, header, bytes.NewReader(b), "")
-	if err != nil {
-		return err
-	}
-	return op.Wait(context.Background())
+	return op, err
 }
 
 // DeleteImageByIDOrName deletes an image identified by the given id or name
@@ -127,7 +168,7 @@ func (c *clientImpl) DeleteImageByIDOrName(id string, force bool, imgType api.Im
 	}
 
 	details := api.ImageDelete{Force: force}
-	b, err := json.Marshal(details)
+	b, err := c.Codec().Marshal(details)
 	if err != nil {
 		return nil, err
 	}
@@ -172,3 +213,45 @@ func (c *clientImpl) RetrieveImageByIDOrName(id string, imgType api.ImageType) (
 	etag, err := c.QueryStruct("GET", client.APIPath("images", id), params, nil, nil, "", i)
 	return i, etag, err
 }
+
+// imageServerURLConfigKey is the configuration item holding the URL of the
+// image server AMS synchronizes images from
+const imageServerURLConfigKey = "images.url"
+
+// RetrieveImageChannel returns the channel of the image server AMS currently
+// synchronizes its images from
+func (c *clientImpl) RetrieveImageChannel() (string, error) {
+	u, err := c.imageServerURL()
+	if err != nil {
+		return "", err
+	}
+	return path.Base(strings.TrimSuffix(u.Path, "/")), nil
+}
+
+// SetImageChannel switches the image server channel (e.g. "stable" or
+// "candidate") AMS synchronizes its images from. The channel is the last path
+// element of the configured image server URL.
+func (c *clientImpl) SetImageChannel(channel string) error {
+	if len(channel) == 0 || strings.Contains(channel, "/") {
+		return errs.NewInvalidArgument("channel")
+	}
+
+	u, err := c.imageServerURL()
+	if err != nil {
+		return err
+	}
+	u.Path = path.Join(path.Dir(strings.TrimSuffix(u.Path, "/")), channel) + "/"
+	return c.SetConfigItem(imageServerURLConfigKey, u.String())
+}
+
+func (c *clientImpl) imageServerURL() (*url.URL, error) {
+	config, err := c.RetrieveConfigItems()
+	if err != nil {
+		return nil, err
+	}
+	value, ok := config[imageServerURLConfigKey].(string)
+	if !ok || len(value) == 0 {
+		return nil, errs.NewErrNotFound(fmt.Sprintf("config item %s", imageServerURLConfigKey))
+	}
+	return url.Parse(value)
+}
@@ -20,7 +20,6 @@ package client
 
 import (
 	"bytes"
-	"encoding/json"
 	"io"
 	"net/http"
 	"strconv"
@@ -40,6 +39,9 @@ type InstanceExecArgs struct {
 	Stderr   io.WriteCloser
 	Control  func(conn *websocket.Conn)
 	DataDone chan bool
+	// Recorder receives a copy of the data exchanged through the standard
+	// streams of the command. Optional.
+	Recorder SessionRecorder
 }
 
 // ListInstancesWithFilters lists all available instances the AMS service currently manages
@@ -51,7 +53,7 @@ func (c *clientImpl) ListInstancesWithFilters(filters []string) ([]api.Instance,
 			return nil, err
 		}
 		for _, c := range containers {
-			inst, err := api.MapContainerToInstance(&c)
+			inst, err := api.ContainerToInstance(&c)
 			if err != nil {
 				return nil, err
 			}
@@ -80,7 +82,7 @@ func (c *clientImpl) ListInstances() ([]api.Instance, error) {
 			return nil, err
 		}
 		for _, c := range containers {
-			inst, err := api.MapContainerToInstance(&c)
+			inst, err := api.ContainerToInstance(&c)
 			if err != nil {
 				return nil, err
 			}
@@ -101,30 +103,7 @@ func (c *clientImpl) ListInstances() ([]api.Instance, error) {
 // LaunchInstance launches a single new instance on the AMS endpoint
 func (c *clientImpl) LaunchInstance(details *api.InstancesPost, noWait bool) (client.Operation, error) {
 	if !c.hasInstanceSupport {
-		d := api.ContainersPost{
-			ApplicationID:      details.ApplicationID,
-			ApplicationVersion: details.ApplicationVersion,
-			ImageID:            details.ImageID,
-			ImageVersion:       details.ImageVersion,
-			Node:               details.Node,
-			Userdata:           details.Userdata,
-			Addons:             details.Addons,
-			Services:           details.Services,
-			CPUs:               details.Resources.CPUs,
-			DiskSize:           details.Resources.DiskSize,
-			Memory:             details.Resources.Memory,
-			GPUSlots:           details.Resources.GPUSlots,
-			VPUSlots:           details.Resources.VPUSlots,
-			Tags:               details.Tags,
-		}
-		d.Config.Platform = details.Config.Platform
-		d.Config.BootPackage = details.Config.BootPackage
-		d.Config.BootActivity = details.Config.BootActivity
-		d.Config.MetricsServer = details.Config.MetricsServer
-		d.Config.DisableWatchdog = details.Config.DisableWatchdog
-		d.Config.Features = details.Config.Features
-		d.Config.DevMode = details.Config.DevMode
-		d.NoStart = details.NoStart
+		d := api.InstancesPostToContainersPost(details)
 
 		if len(d.ImageID) > 0 && (d.CPUs == nil || d.Memory == nil || d.DiskSize == nil) {
 			return nil, errs.NewInvalidArgument("resources")
@@ -133,7 +112,7 @@ func (c *clientImpl) LaunchInstance(details *api.InstancesPost, noWait bool) (cl
 		return c.LaunchContainer(&d, noWait)
 	}
 
-	hasVMSupport, err := c.HasExtension("vm_support")
+	hasVMSupport, err := c.HasExtension(api.ExtensionVMSupport)
 	if err != nil {
 		return nil, err
 	}
@@ -142,7 +121,7 @@ func (c *clientImpl) LaunchInstance(details *api.InstancesPost, noWait bool) (cl
 		return nil, errs.NewErrNotSupported("VM")
 	}
 
-	b, err := json.Marshal(details)
+	b, err := c.Codec().Marshal(details)
 	if err != nil {
 		return nil, err
 	}
@@ -153,15 +132,21 @@ func (c *clientImpl) LaunchInstance(details *api.InstancesPost, noWait bool) (cl
 
 // RetrieveInstanceByID retrieves a single instance by its ID
 func (c *clientImpl) RetrieveInstanceByID(id string) (*api.Instance, string, error) {
+	return c.retrieveInstance(id, nil)
+}
+
+// retrieveInstance retrieves a single instance by its ID with the given
+// additional headers
+func (c *clientImpl) retrieveInstance(id string, header http.Header) (*api.Instance, string, error) {
 	if len(id) == 0 {
 		return nil, "", errs.NewInvalidArgument("id")
 	}
 	if !c.hasInstanceSupport {
-		container, etag, err := c.RetrieveContainerByID(id)
+		container, etag, err := c.retrieveContainer(id, header)
 		if err != nil {
 			return nil, "", err
 		}
-		instance, err := api.MapContainerToInstance(container)
+		instance, err := api.ContainerToInstance(container)
 		if err != nil {
 			return nil, "", err
 		}
@@ -169,29 +154,29 @@ func (c *clientImpl) RetrieveInstanceByID(id string) (*api.Instance, string, err
 	}
 
 	instance := &api.Instance{}
-	etag, err := c.QueryStruct("GET", client.APIPath("instances", id), nil, nil, nil, "", instance)
+	etag, err := c.QueryStruct("GET", client.APIPath("instances", id), nil, header, nil, "", instance)
 	return instance, etag, err
 }
 
 // UpdateInstanceByID updates an existing instance specified by its id
-func (c *clientImpl) UpdateInstanceByID(id string, details *api.InstancePatch, noWait bool) (client.Operation, error) {
+func (c *clientImpl) UpdateInstanceByID(id string, details *api.InstancePatch, noWait bool, opts ...RequestOption) (client.Operation, error) {
 	if len(id) == 0 {
 		return nil, errs.NewInvalidArgument("id")
 	}
 
 	if !c.hasInstanceSupport {
-		return c.UpdateContainerByID(id, &api.ContainerPatch{
-			DesiredStatus: details.DesiredStatus,
-		}, noWait)
+		patch := api.InstancePatchToContainerPatch(details)
+		return c.UpdateContainerByID(id, &patch, noWait, opts...)
 	}
 
-	b, err := json.Marshal(details)
+	b, err := c.Codec().Marshal(details)
 	if err != nil {
 		return nil, err
 	}
 
+	o := newRequestOptions(opts)
 	params := client.QueryParams{"no_wait": strconv.FormatBool(noWait)}
-	op, _, err := c.QueryOperation("PATCH", client.APIPath("instances", id), params, nil, bytes.NewReader(b), "")
+	op, _, err := c.QueryOperation("PATCH", client.APIPath("instances", id), params, o.headerWith(nil), bytes.NewReader(b), o.etag)
 	return op, err
 }
 
@@ -208,7 +193,7 @@ func (c *clientImpl) DeleteInstanceByID(id string, force bool) (client.Operation
 	details := api.InstanceDelete{
 		Force: force,
 	}
-	b, err := json.Marshal(details)
+	b, err := c.Codec().Marshal(details)
 	if err != nil {
 		return nil, err
 	}
@@ -231,7 +216,7 @@ func (c *clientImpl) DeleteInstances(ids []string, force bool) (client.Operation
 		IDs:   ids,
 		Force: force,
 	}
-	b, err := json.Marshal(details)
+	b, err := c.Codec().Marshal(details)
 	if err != nil {
 		return nil, err
 	}
@@ -263,22 +248,18 @@ func (c *clientImpl) ExecuteInstance(id string, details *api.InstanceExecPost, a
 	}
 
 	if !c.hasInstanceSupport {
-		return c.ExecuteContainer(id, &api.ContainerExecPost{
-			Command:     details.Command,
-			Environment: details.Environment,
-			Interactive: details.Interactive,
-			Width:       details.Width,
-			Height:      details.Height,
-		}, &ContainerExecArgs{
+		execDetails := api.InstanceExecPostToContainerExecPost(details)
+		return c.ExecuteContainer(id, &execDetails, &ContainerExecArgs{
 			Stdin:    args.Stdin,
 			Stdout:   args.Stdout,
 			Stderr:   args.Stderr,
 			Control:  args.Control,
 			DataDone: args.DataDone,
+			Recorder: args.Recorder,
 		})
 	}
 
-	b, err := json.Marshal(details)
+	b, err := c.Codec().Marshal(details)
 	if err != nil {
 		return nil, err
 	}
@@ -288,17 +269,16 @@ func (c *clientImpl) ExecuteInstance(id string, details *api.InstanceExecPost, a
 		return nil, err
 	}
 
+	if args != nil && args.Recorder != nil {
+		recorded := *args
+		recorded.Stdin, recorded.Stdout, recorded.Stderr = recordStreams(args.Recorder, args.Stdin, args.Stdout, args.Stderr)
+		args = &recorded
+	}
+
 	if args != nil {
 		opAPI := op.Get()
 
-		fds := map[string]string{}
-		value, ok := opAPI.Metadata["fds"]
-		if ok {
-			values := value.(map[string]interface{})
-			for k, v := range values {
-				fds[k] = v.(string)
-			}
-		}
+		fds := operationFDs(op)
 
 		if args.Control != nil && fds["control"] != "" {
 			conn, err := c.getOperationWebsocket(opAPI.ID, fds["control"])
@@ -321,7 +301,7 @@ func (c *clientImpl) ExecuteInstance(id string, details *api.InstanceExecPost, a
 				go func() {
 					network.WebsocketSendStream(conn, args.Stdin, -1)
 					<-network.WebsocketRecvStream(args.Stdout, conn)
-					conn.Close()
+					network.CloseGracefully(conn, websocket.CloseNormalClosure, "", network.DefaultCloseTimeout)
 
 					if args.DataDone != nil {
 						close(args.DataDone)
@@ -379,21 +359,21 @@ func (c *clientImpl) ExecuteInstance(id string, details *api.InstanceExecPost, a
 					case <-dones[0]:
 						// Handle stdin finish, but don't wait for it
 						dones[0] = nil
-						_ = conns[0].Close()
+						_ = network.CloseGracefully(conns[0], websocket.CloseNormalClosure, "", network.DefaultCloseTimeout)
 					case <-dones[1]:
 						dones[1] = nil
-						_ = conns[1].Close()
+						_ = network.CloseGracefully(conns[1], websocket.CloseNormalClosure, "", network.DefaultCloseTimeout)
 						waitConns--
 					case <-dones[2]:
 						dones[2] = nil
-						_ = conns[2].Close()
+						_ = network.CloseGracefully(conns[2], websocket.CloseNormalClosure, "", network.DefaultCloseTimeout)
 						waitConns--
 					}
 
 					if waitConns <= 0 {
 						// Close stdin websocket if defined and not already closed.
 						if dones[0] != nil {
-							conns[0].Close()
+							network.CloseGracefully(conns[0], websocket.CloseNormalClosure, "", network.DefaultCloseTimeout)
 						}
 
 						break
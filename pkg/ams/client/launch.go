@@ -0,0 +1,117 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"path"
+	"sync"
+
+	api "github.com/anbox-cloud/ams-sdk/api/ams"
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/client"
+)
+
+const (
+	// Number of launch requests sent to AMS at the same time
+	defaultLaunchConcurrency = 8
+)
+
+// LaunchResult describes the result of launching a single container or
+// instance as part of a batch
+type LaunchResult struct {
+	// Index of the launch within the batch
+	Index int
+	// ID of the launched container or instance. Empty if Err is set or the ID
+	// was not yet assigned by AMS.
+	ID string
+	// Operation tracks the launch on the AMS side. Nil if Err is set.
+	Operation client.Operation
+	// Err is set when the launch could not be triggered
+	Err error
+}
+
+// LaunchContainers launches count containers with the given details. AMS
+// creates a single container per request, so the requests are sent
+// concurrently. The returned results are ordered by their index and contain an
+// operation or error per container, so a partial failure does not hide the
+// containers which were launched successfully.
+func (c *clientImpl) LaunchContainers(details *api.ContainersPost, count int, noWait bool) ([]LaunchResult, error) {
+	if details == nil {
+		return nil, errs.NewInvalidArgument("details")
+	}
+	if count <= 0 {
+		return nil, errs.NewInvalidArgument("count")
+	}
+	return launchBatch(count, func() (client.Operation, error) {
+		return c.LaunchContainer(details, noWait)
+	}), nil
+}
+
+// LaunchInstances launches count instances with the given details. See
+// LaunchContainers for details.
+func (c *clientImpl) LaunchInstances(details *api.InstancesPost, count int, noWait bool) ([]LaunchResult, error) {
+	if details == nil {
+		return nil, errs.NewInvalidArgument("details")
+	}
+	if count <= 0 {
+		return nil, errs.NewInvalidArgument("count")
+	}
+	return launchBatch(count, func() (client.Operation, error) {
+		return c.LaunchInstance(details, noWait)
+	}), nil
+}
+
+func launchBatch(count int, launch func() (client.Operation, error)) []LaunchResult {
+	results := make([]LaunchResult, count)
+	sem := make(chan struct{}, defaultLaunchConcurrency)
+	var wg sync.WaitGroup
+
+	for n := 0; n < count; n++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(n int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			op, err := launch()
+			results[n] = LaunchResult{Index: n, Operation: op, Err: err}
+			if err != nil {
+				return
+			}
+			results[n].ID = launchedInstanceID(op)
+		}(n)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// launchedInstanceID returns the ID of the container or instance the given
+// launch operation refers to or an empty string if AMS did not assign it yet
+func launchedInstanceID(op client.Operation) string {
+	resources := op.Get().Resources
+	for _, key := range []string{"instances", "containers"} {
+		if ids := resources[key]; len(ids) > 0 {
+			return path.Base(ids[0])
+		}
+	}
+	return ""
+}
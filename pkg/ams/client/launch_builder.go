@@ -0,0 +1,344 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	api "github.com/anbox-cloud/ams-sdk/api/ams"
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/constants"
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/client"
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/userdata"
+)
+
+// LaunchValidationError lists all problems LaunchBuilder found in the launch
+// details
+type LaunchValidationError struct {
+	Problems []string
+}
+
+// Error returns the error string
+func (e *LaunchValidationError) Error() string {
+	return fmt.Sprintf("invalid launch details: %s", strings.Join(e.Problems, "; "))
+}
+
+// LaunchBuilder assembles the details to launch a container with and
+// validates them before they are sent to AMS
+type LaunchBuilder struct {
+	details   api.ContainersPost
+	features  []string
+	placement *PlacementRules
+	gpu       *GPURequirements
+
+	userdataErr error
+}
+
+// NewLaunchBuilder returns a new and empty LaunchBuilder
+func NewLaunchBuilder() *LaunchBuilder {
+	return &LaunchBuilder{}
+}
+
+// FromApplication launches the container from the application with the given ID
+func (b *LaunchBuilder) FromApplication(id string) *LaunchBuilder {
+	b.details.ApplicationID = id
+	return b
+}
+
+// ApplicationVersion selects the version of the application to launch
+func (b *LaunchBuilder) ApplicationVersion(version int) *LaunchBuilder {
+	b.details.ApplicationVersion = &version
+	return b
+}
+
+// FromImage launches a raw container from the image with the given ID
+func (b *LaunchBuilder) FromImage(id string) *LaunchBuilder {
+	b.details.ImageID = id
+	return b
+}
+
+// ImageVersion selects the version of the image to launch
+func (b *LaunchBuilder) ImageVersion(version int) *LaunchBuilder {
+	b.details.ImageVersion = &version
+	return b
+}
+
+// InstanceType sets the instance type defining the resources of the container
+func (b *LaunchBuilder) InstanceType(instanceType string) *LaunchBuilder {
+	b.details.InstanceType = instanceType
+	return b
+}
+
+// CPUs sets the number of CPUs assigned to the container
+func (b *LaunchBuilder) CPUs(cpus int) *LaunchBuilder {
+	b.details.CPUs = &cpus
+	return b
+}
+
+// Memory sets the memory in bytes assigned to the container
+func (b *LaunchBuilder) Memory(bytes int64) *LaunchBuilder {
+	b.details.Memory = &bytes
+	return b
+}
+
+// DiskSize sets the disk size in bytes assigned to the container
+func (b *LaunchBuilder) DiskSize(bytes int64) *LaunchBuilder {
+	b.details.DiskSize = &bytes
+	return b
+}
+
+// GPUSlots sets the number of GPU slots assigned to the container
+func (b *LaunchBuilder) GPUSlots(slots int) *LaunchBuilder {
+	b.details.GPUSlots = &slots
+	return b
+}
+
+// GPU sets the GPU requirements of the container. Launch checks them against
+// the capabilities of the nodes and prefers nodes with the requested GPU type.
+func (b *LaunchBuilder) GPU(r GPURequirements) *LaunchBuilder {
+	b.gpu = &r
+	b.details.GPUSlots = &r.Slots
+	return b
+}
+
+// VPUSlots sets the number of VPU slots assigned to the container
+func (b *LaunchBuilder) VPUSlots(slots int) *LaunchBuilder {
+	b.details.VPUSlots = &slots
+	return b
+}
+
+// Node pins the container to the node with the given name
+func (b *LaunchBuilder) Node(node string) *LaunchBuilder {
+	b.details.Node = node
+	return b
+}
+
+// Placement selects the node to launch the container on according to the
+// given rules when launched through Launch. See PreviewPlacement for details.
+func (b *LaunchBuilder) Placement(rules *PlacementRules) *LaunchBuilder {
+	b.placement = rules
+	return b
+}
+
+// Userdata sets the userdata passed to the container
+func (b *LaunchBuilder) Userdata(userdata string) *LaunchBuilder {
+	b.details.Userdata = &userdata
+	b.userdataErr = nil
+	return b
+}
+
+// StructuredUserdata sets the userdata passed to the container from the
+// given key/value pairs. Encoding errors are reported by Build.
+func (b *LaunchBuilder) StructuredUserdata(u *userdata.Userdata) *LaunchBuilder {
+	encoded, err := u.Encode()
+	if err != nil {
+		b.userdataErr = err
+		return b
+	}
+	return b.Userdata(encoded)
+}
+
+// Addons adds addons to install in the container
+func (b *LaunchBuilder) Addons(addons ...string) *LaunchBuilder {
+	b.details.Addons = append(b.details.Addons, addons...)
+	return b
+}
+
+// Service adds a service the container exposes
+func (b *LaunchBuilder) Service(service api.NetworkServiceSpec) *LaunchBuilder {
+	b.details.Services = append(b.details.Services, service)
+	return b
+}
+
+// Expose adds a service listening on the given port. Without protocols the
+// service is opened for TCP.
+func (b *LaunchBuilder) Expose(name string, port int, mode api.ExposeMode, protocols ...api.NetworkProtocol) *LaunchBuilder {
+	return b.Service(api.NewServiceSpec(name, port, mode, protocols...))
+}
+
+// Tags adds tags to attach to the container
+func (b *LaunchBuilder) Tags(tags ...string) *LaunchBuilder {
+	b.details.Tags = append(b.details.Tags, tags...)
+	return b
+}
+
+// Platform sets the Anbox platform the container runs with
+func (b *LaunchBuilder) Platform(platform api.Platform) *LaunchBuilder {
+	b.details.Config.Platform = string(platform)
+	return b
+}
+
+// BootPackage sets the Android package started when the container boots
+func (b *LaunchBuilder) BootPackage(pkg string) *LaunchBuilder {
+	b.details.Config.BootPackage = pkg
+	return b
+}
+
+// BootActivity sets the Android activity started when the container boots
+func (b *LaunchBuilder) BootActivity(activity string) *LaunchBuilder {
+	b.details.Config.BootActivity = activity
+	return b
+}
+
+// MetricsServer sets the metrics server the container reports to
+func (b *LaunchBuilder) MetricsServer(server string) *LaunchBuilder {
+	b.details.Config.MetricsServer = server
+	return b
+}
+
+// DisableWatchdog disables the watchdog of the container
+func (b *LaunchBuilder) DisableWatchdog() *LaunchBuilder {
+	b.details.Config.DisableWatchdog = true
+	return b
+}
+
+// Features adds features to enable for the container
+func (b *LaunchBuilder) Features(features ...string) *LaunchBuilder {
+	b.features = append(b.features, features...)
+	return b
+}
+
+// DevMode enables the development mode of the container
+func (b *LaunchBuilder) DevMode() *LaunchBuilder {
+	b.details.Config.DevMode = true
+	return b
+}
+
+// NoStart creates the container without starting it
+func (b *LaunchBuilder) NoStart() *LaunchBuilder {
+	b.details.NoStart = true
+	return b
+}
+
+// Build validates the launch details and returns them. All problems found are
+// returned together as a *LaunchValidationError.
+func (b *LaunchBuilder) Build() (*api.ContainersPost, error) {
+	d := b.details
+	d.Config.Features = strings.Join(b.features, ",")
+
+	problems := []string{}
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	hasApp, hasImage := len(d.ApplicationID) > 0, len(d.ImageID) > 0
+	switch {
+	case hasApp && hasImage:
+		add("application and image are mutually exclusive")
+	case !hasApp && !hasImage:
+		add("either an application or an image is required")
+	}
+	if d.ApplicationVersion != nil && !hasApp {
+		add("application version requires an application")
+	}
+	if d.ImageVersion != nil && !hasImage {
+		add("image version requires an image")
+	}
+
+	hasResources := d.CPUs != nil || d.Memory != nil || d.DiskSize != nil || d.GPUSlots != nil || d.VPUSlots != nil
+	if len(d.InstanceType) > 0 && hasResources {
+		add("instance type and resources are mutually exclusive")
+	}
+	if hasImage && len(d.InstanceType) == 0 && (d.CPUs == nil || d.Memory == nil || d.DiskSize == nil) {
+		add("containers launched from an image require an instance type or cpus, memory and disk size")
+	}
+	if d.CPUs != nil && *d.CPUs <= 0 {
+		add("cpus must be positive")
+	}
+	if d.Memory != nil && *d.Memory <= 0 {
+		add("memory must be positive")
+	}
+	if d.DiskSize != nil && *d.DiskSize <= 0 {
+		add("disk size must be positive")
+	}
+	if d.GPUSlots != nil && *d.GPUSlots < 0 {
+		add("gpu slots must not be negative")
+	}
+	if b.gpu != nil {
+		if err := b.gpu.Validate(); err != nil {
+			add("%v", err)
+		}
+	}
+	if d.VPUSlots != nil && *d.VPUSlots < 0 {
+		add("vpu slots must not be negative")
+	}
+
+	if b.userdataErr != nil {
+		add("%v", b.userdataErr)
+	} else if d.Userdata != nil && len(*d.Userdata) > constants.MaxUserdataSize {
+		add("userdata exceeds %d bytes", constants.MaxUserdataSize)
+	}
+	for _, addon := range d.Addons {
+		if ok, _ := regexp.MatchString(constants.AddonNamePattern, addon); !ok || len(addon) == 0 {
+			add("invalid addon name %q", addon)
+		}
+	}
+	if err := api.ValidatePlatformServices(api.Platform(d.Config.Platform), d.Services); err != nil {
+		add("%v", err)
+	}
+	if len(d.Config.BootPackage) > 0 {
+		if err := api.ValidateBootPackage(d.Config.BootPackage); err != nil {
+			add("%v", err)
+		}
+	}
+	if len(d.Config.BootActivity) > 0 && len(d.Config.BootPackage) == 0 && !hasApp {
+		add("boot activity requires a boot package")
+	}
+	for _, feature := range b.features {
+		if len(feature) == 0 || strings.ContainsAny(feature, ", ") {
+			add("invalid feature %q", feature)
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, &LaunchValidationError{Problems: problems}
+	}
+	return &d, nil
+}
+
+// Launch validates the launch details and launches the container with them
+// on the node selected by the placement rules, if any. GPU requirements are
+// checked against the nodes of the cluster first.
+func (b *LaunchBuilder) Launch(c Client, noWait bool) (client.Operation, error) {
+	details, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	rules := b.placement
+	if b.gpu != nil {
+		if err := CheckGPURequirements(c, *b.gpu); err != nil {
+			return nil, err
+		}
+		if tag := b.gpu.nodeTag(); len(tag) > 0 {
+			r := PlacementRules{}
+			if rules != nil {
+				r = *rules
+			}
+			r.PreferredNodeTags = append(append([]string{}, r.PreferredNodeTags...), tag)
+			rules = &r
+		}
+	}
+
+	if rules != nil && len(details.Node) == 0 {
+		return c.LaunchContainerWithPlacement(details, rules, noWait)
+	}
+	return c.LaunchContainer(details, noWait)
+}
@@ -0,0 +1,67 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"context"
+
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/client"
+)
+
+// StartContainer starts the stopped container with the given ID
+func (c *clientImpl) StartContainer(id string, noWait bool) (client.Operation, error) {
+	return c.runAction(context.Background(), id, ActionStart, noWait, c.setContainerStatus)
+}
+
+// StopContainer stops the running container with the given ID
+func (c *clientImpl) StopContainer(id string, noWait bool) (client.Operation, error) {
+	return c.runAction(context.Background(), id, ActionStop, noWait, c.setContainerStatus)
+}
+
+// RestartContainer stops the container with the given ID and starts it again
+// once it stopped. The context limits waiting for the container to stop. The
+// returned operation tracks starting the container again; noWait only
+// applies to it.
+func (c *clientImpl) RestartContainer(ctx context.Context, id string, noWait bool) (client.Operation, error) {
+	return c.runAction(ctx, id, ActionRestart, noWait, c.setContainerStatus)
+}
+
+// StartInstance starts the stopped instance with the given ID
+func (c *clientImpl) StartInstance(id string, noWait bool) (client.Operation, error) {
+	return c.runAction(context.Background(), id, ActionStart, noWait, c.setInstanceStatus)
+}
+
+// StopInstance stops the running instance with the given ID
+func (c *clientImpl) StopInstance(id string, noWait bool) (client.Operation, error) {
+	return c.runAction(context.Background(), id, ActionStop, noWait, c.setInstanceStatus)
+}
+
+// RestartInstance stops the instance with the given ID and starts it again
+// once it stopped. See RestartContainer for details.
+func (c *clientImpl) RestartInstance(ctx context.Context, id string, noWait bool) (client.Operation, error) {
+	return c.runAction(ctx, id, ActionRestart, noWait, c.setInstanceStatus)
+}
+
+func (c *clientImpl) runAction(ctx context.Context, id string, action Action, noWait bool, setStatus setStatusFunc) (client.Operation, error) {
+	if len(id) == 0 {
+		return nil, errs.NewInvalidArgument("id")
+	}
+	return applyAction(ctx, id, action, noWait, setStatus)
+}
@@ -0,0 +1,244 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	api "github.com/anbox-cloud/ams-sdk/api/ams"
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/client"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// Interval in which a log is checked for new content when AMS cannot
+	// stream it
+	logFollowPollInterval = 2 * time.Second
+)
+
+// logFollowReconnectPolicy controls how following a log recovers from lost
+// connections and failed polls
+var logFollowReconnectPolicy = client.DefaultReconnectPolicy
+
+// lineWriter passes only complete lines to the underlying writer
+type lineWriter struct {
+	w   io.Writer
+	buf []byte
+}
+
+func (l *lineWriter) Write(p []byte) (int, error) {
+	l.buf = append(l.buf, p...)
+	if n := bytes.LastIndexByte(l.buf, '\n'); n >= 0 {
+		if _, err := l.w.Write(l.buf[:n+1]); err != nil {
+			return 0, err
+		}
+		l.buf = append(l.buf[:0], l.buf[n+1:]...)
+	}
+	return len(p), nil
+}
+
+// flush writes a trailing incomplete line
+func (l *lineWriter) flush() error {
+	if len(l.buf) == 0 {
+		return nil
+	}
+	_, err := l.w.Write(l.buf)
+	l.buf = l.buf[:0]
+	return err
+}
+
+// FollowContainerLog streams the lines appended to a specific log file of a
+// container to the given writer until the context is done. If AMS supports it,
+// the log is streamed over a websocket, otherwise it is polled with ranged
+// requests. After transient disconnects the stream is resumed at the position
+// it was interrupted at, so no lines are lost or duplicated.
+func (c *clientImpl) FollowContainerLog(ctx context.Context, id, name string, w io.Writer) error {
+	if len(id) == 0 {
+		return errs.NewInvalidArgument("id")
+	}
+	if len(name) == 0 {
+		return errs.NewInvalidArgument("name")
+	}
+	viaInstances, err := c.containersViaInstances()
+	if err != nil {
+		return err
+	}
+	if viaInstances {
+		return c.FollowInstanceLog(ctx, id, name, w)
+	}
+	return c.followLog(ctx, client.APIPath("containers", id, "logs", name), w)
+}
+
+// FollowInstanceLog streams the lines appended to a specific log file of an
+// instance to the given writer until the context is done. See
+// FollowContainerLog for details.
+func (c *clientImpl) FollowInstanceLog(ctx context.Context, id, name string, w io.Writer) error {
+	if len(id) == 0 {
+		return errs.NewInvalidArgument("id")
+	}
+	if len(name) == 0 {
+		return errs.NewInvalidArgument("name")
+	}
+	if !c.hasInstanceSupport {
+		return c.FollowContainerLog(ctx, id, name, w)
+	}
+	return c.followLog(ctx, client.APIPath("instances", id, "logs", name), w)
+}
+
+func (c *clientImpl) followLog(ctx context.Context, path string, w io.Writer) error {
+	if w == nil {
+		return errs.NewInvalidArgument("writer")
+	}
+
+	hasLogStreamSupport, err := c.HasExtension(api.ExtensionLogStreaming)
+	if err != nil {
+		return err
+	}
+
+	// Opening the log fails early for unknown logs and tells where new lines
+	// start
+	r, err := c.openLog(path)
+	if err != nil {
+		return err
+	}
+	reader := r.(*logReader)
+	offset := reader.size
+
+	lw := &lineWriter{w: w}
+	defer lw.flush()
+
+	attempts := 0
+	for {
+		previous := offset
+		if hasLogStreamSupport {
+			offset, err = c.streamLog(ctx, path, offset, lw)
+		} else {
+			offset, err = pollLog(ctx, reader, offset, lw)
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		if _, ok := err.(errs.ErrNotSupported); ok {
+			return err
+		}
+		if offset != previous {
+			attempts = 0
+		}
+		attempts++
+		if err != nil && logFollowReconnectPolicy.Exhausted(attempts) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(logFollowReconnectPolicy.Backoff(attempts)):
+		}
+	}
+}
+
+// streamLog streams the log starting at the given offset over a websocket
+// until the connection is closed and returns the offset to resume at. Lost
+// connections are re-established at the offset reached so far.
+func (c *clientImpl) streamLog(ctx context.Context, path string, offset int64, w io.Writer) (int64, error) {
+	dial := func(ctx context.Context) (*websocket.Conn, error) {
+		return c.Websocket(fmt.Sprintf("%s/stream?offset=%d", path, offset))
+	}
+	conn, err := client.NewReconnectingWebsocket(ctx, dial, logFollowReconnectPolicy)
+	if err != nil {
+		return offset, err
+	}
+	defer conn.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	for {
+		mt, data, err := conn.ReadMessage()
+		if err != nil {
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+				return offset, nil
+			}
+			return offset, err
+		}
+		if mt != websocket.BinaryMessage && mt != websocket.TextMessage {
+			continue
+		}
+		if _, err := w.Write(data); err != nil {
+			return offset, err
+		}
+		offset += int64(len(data))
+	}
+}
+
+// pollLog polls the log for content appended after the given offset until an
+// error occurs and returns the offset to resume at
+func pollLog(ctx context.Context, r *logReader, offset int64, w io.Writer) (int64, error) {
+	ticker := time.NewTicker(logFollowPollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Only wait for new content once everything known is read
+		if offset >= r.size {
+			select {
+			case <-ctx.Done():
+				return offset, nil
+			case <-ticker.C:
+			}
+		} else if ctx.Err() != nil {
+			return offset, nil
+		}
+
+		// Requesting the last byte already seen avoids an unsatisfiable range
+		// when nothing was appended since the last poll
+		start := offset
+		if start > 0 {
+			start--
+		}
+		if err := r.fill(start); err != nil {
+			return offset, err
+		}
+		if r.size < offset {
+			// The log was truncated or rotated, continue at its beginning
+			return 0, nil
+		}
+
+		skip := offset - start
+		if int64(len(r.buf)) <= skip {
+			continue
+		}
+		data := r.buf[skip:]
+		if _, err := w.Write(data); err != nil {
+			return offset, err
+		}
+		offset += int64(len(data))
+	}
+}
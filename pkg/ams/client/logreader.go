@@ -0,0 +1,175 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	api "github.com/anbox-cloud/ams-sdk/api/ams"
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/client"
+)
+
+const (
+	logChunkSize = 256 * 1024
+)
+
+// logReader implements io.ReadSeeker over a log file stored by AMS. Data is
+// fetched lazily in chunks with HTTP range requests.
+type logReader struct {
+	c    *clientImpl
+	path string
+	size int64
+
+	offset    int64
+	buf       []byte
+	bufOffset int64
+}
+
+// OpenContainerLog opens a specific log file of a container for reading. The
+// content is fetched lazily with ranged requests, so seeking to any position
+// does not require downloading the whole file. The size of the log is
+// determined when it is opened.
+func (c *clientImpl) OpenContainerLog(id, name string) (io.ReadSeeker, error) {
+	if len(id) == 0 {
+		return nil, errs.NewInvalidArgument("id")
+	}
+	if len(name) == 0 {
+		return nil, errs.NewInvalidArgument("name")
+	}
+	viaInstances, err := c.containersViaInstances()
+	if err != nil {
+		return nil, err
+	}
+	if viaInstances {
+		return c.OpenInstanceLog(id, name)
+	}
+	hasContainerLogsSupport, err := c.HasExtension(api.ExtensionContainerLogs)
+	if err != nil {
+		return nil, err
+	}
+	if !hasContainerLogsSupport {
+		return nil, errs.NewErrNotSupported("api extension \"container_logs\"")
+	}
+	return c.openLog(client.APIPath("containers", id, "logs", name))
+}
+
+// OpenInstanceLog opens a specific log file of an instance for reading. See
+// OpenContainerLog for details.
+func (c *clientImpl) OpenInstanceLog(id, name string) (io.ReadSeeker, error) {
+	if len(id) == 0 {
+		return nil, errs.NewInvalidArgument("id")
+	}
+	if len(name) == 0 {
+		return nil, errs.NewInvalidArgument("name")
+	}
+	if !c.hasInstanceSupport {
+		return c.OpenContainerLog(id, name)
+	}
+	return c.openLog(client.APIPath("instances", id, "logs", name))
+}
+
+func (c *clientImpl) openLog(path string) (io.ReadSeeker, error) {
+	r := &logReader{c: c, path: path}
+	// Fetching the first chunk tells us the total size of the log
+	if err := r.fill(0); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// fill fetches the chunk starting at the given offset
+func (r *logReader) fill(offset int64) error {
+	header := http.Header{
+		"Range": []string{fmt.Sprintf("bytes=%d-%d", offset, offset+logChunkSize-1)},
+	}
+	return r.c.DownloadFile(r.path, nil, header, func(h *http.Header, body io.ReadCloser) error {
+		size, err := parseContentRangeSize(h.Get("Content-Range"))
+		if err != nil {
+			return err
+		}
+		buf, err := io.ReadAll(body)
+		if err != nil {
+			return err
+		}
+		r.size = size
+		r.buf = buf
+		r.bufOffset = offset
+		return nil
+	})
+}
+
+// parseContentRangeSize returns the complete length of a resource from a
+// Content-Range header like "bytes 0-1023/4096"
+func parseContentRangeSize(value string) (int64, error) {
+	if len(value) == 0 {
+		return 0, errs.NewErrNotSupported("ranged log requests")
+	}
+	n := strings.LastIndex(value, "/")
+	if n < 0 || value[n+1:] == "*" {
+		return 0, errs.NewErrInvalidFormat("content range")
+	}
+	size, err := strconv.ParseInt(value[n+1:], 10, 64)
+	if err != nil {
+		return 0, errs.NewErrInvalidFormat("content range")
+	}
+	return size, nil
+}
+
+// Read implements io.Reader
+func (r *logReader) Read(p []byte) (int, error) {
+	if r.offset >= r.size {
+		return 0, io.EOF
+	}
+	if r.offset < r.bufOffset || r.offset >= r.bufOffset+int64(len(r.buf)) {
+		if err := r.fill(r.offset); err != nil {
+			return 0, err
+		}
+		if len(r.buf) == 0 {
+			return 0, io.EOF
+		}
+	}
+	n := copy(p, r.buf[r.offset-r.bufOffset:])
+	r.offset += int64(n)
+	return n, nil
+}
+
+// Seek implements io.Seeker
+func (r *logReader) Seek(offset int64, whence int) (int64, error) {
+	var abs int64
+	switch whence {
+	case io.SeekStart:
+		abs = offset
+	case io.SeekCurrent:
+		abs = r.offset + offset
+	case io.SeekEnd:
+		abs = r.size + offset
+	default:
+		return 0, errs.NewInvalidArgument("whence")
+	}
+	if abs < 0 {
+		return 0, errs.NewInvalidArgument("offset")
+	}
+	r.offset = abs
+	return abs, nil
+}
@@ -0,0 +1,4805 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: client.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	io "io"
+	http "net/http"
+	reflect "reflect"
+	time "time"
+
+	api "github.com/anbox-cloud/ams-sdk/api/ams"
+	client "github.com/anbox-cloud/ams-sdk/pkg/ams/client"
+	api0 "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/api"
+	client0 "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/client"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockNodeClient is a mock of NodeClient interface.
+type MockNodeClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockNodeClientMockRecorder
+}
+
+// MockNodeClientMockRecorder is the mock recorder for MockNodeClient.
+type MockNodeClientMockRecorder struct {
+	mock *MockNodeClient
+}
+
+// NewMockNodeClient creates a new mock instance.
+func NewMockNodeClient(ctrl *gomock.Controller) *MockNodeClient {
+	mock := &MockNodeClient{ctrl: ctrl}
+	mock.recorder = &MockNodeClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNodeClient) EXPECT() *MockNodeClientMockRecorder {
+	return m.recorder
+}
+
+// AddNode mocks base method.
+func (m *MockNodeClient) AddNode(node *api.NodesPost) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddNode", node)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddNode indicates an expected call of AddNode.
+func (mr *MockNodeClientMockRecorder) AddNode(node interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddNode", reflect.TypeOf((*MockNodeClient)(nil).AddNode), node)
+}
+
+// ListNodes mocks base method.
+func (m *MockNodeClient) ListNodes() ([]api.Node, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNodes")
+	ret0, _ := ret[0].([]api.Node)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNodes indicates an expected call of ListNodes.
+func (mr *MockNodeClientMockRecorder) ListNodes() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNodes", reflect.TypeOf((*MockNodeClient)(nil).ListNodes))
+}
+
+// RemoveNode mocks base method.
+func (m *MockNodeClient) RemoveNode(name string, force, keepInCluster bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveNode", name, force, keepInCluster)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveNode indicates an expected call of RemoveNode.
+func (mr *MockNodeClientMockRecorder) RemoveNode(name, force, keepInCluster interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveNode", reflect.TypeOf((*MockNodeClient)(nil).RemoveNode), name, force, keepInCluster)
+}
+
+// RetrieveClusterCapacity mocks base method.
+func (m *MockNodeClient) RetrieveClusterCapacity() (*client.ClusterCapacity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveClusterCapacity")
+	ret0, _ := ret[0].(*client.ClusterCapacity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveClusterCapacity indicates an expected call of RetrieveClusterCapacity.
+func (mr *MockNodeClientMockRecorder) RetrieveClusterCapacity() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveClusterCapacity", reflect.TypeOf((*MockNodeClient)(nil).RetrieveClusterCapacity))
+}
+
+// RetrieveNodeByName mocks base method.
+func (m *MockNodeClient) RetrieveNodeByName(name string) (*api.Node, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveNodeByName", name)
+	ret0, _ := ret[0].(*api.Node)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RetrieveNodeByName indicates an expected call of RetrieveNodeByName.
+func (mr *MockNodeClientMockRecorder) RetrieveNodeByName(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveNodeByName", reflect.TypeOf((*MockNodeClient)(nil).RetrieveNodeByName), name)
+}
+
+// RetrieveNodeStatus mocks base method.
+func (m *MockNodeClient) RetrieveNodeStatus(name string) (*client.NodeResourceStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveNodeStatus", name)
+	ret0, _ := ret[0].(*client.NodeResourceStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveNodeStatus indicates an expected call of RetrieveNodeStatus.
+func (mr *MockNodeClientMockRecorder) RetrieveNodeStatus(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveNodeStatus", reflect.TypeOf((*MockNodeClient)(nil).RetrieveNodeStatus), name)
+}
+
+// UpdateNode mocks base method.
+func (m *MockNodeClient) UpdateNode(name string, details *api.NodePatch, opts ...client.RequestOption) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{name, details}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateNode", varargs...)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateNode indicates an expected call of UpdateNode.
+func (mr *MockNodeClientMockRecorder) UpdateNode(name, details interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{name, details}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNode", reflect.TypeOf((*MockNodeClient)(nil).UpdateNode), varargs...)
+}
+
+// MockCertificateClient is a mock of CertificateClient interface.
+type MockCertificateClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockCertificateClientMockRecorder
+}
+
+// MockCertificateClientMockRecorder is the mock recorder for MockCertificateClient.
+type MockCertificateClientMockRecorder struct {
+	mock *MockCertificateClient
+}
+
+// NewMockCertificateClient creates a new mock instance.
+func NewMockCertificateClient(ctrl *gomock.Controller) *MockCertificateClient {
+	mock := &MockCertificateClient{ctrl: ctrl}
+	mock.recorder = &MockCertificateClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCertificateClient) EXPECT() *MockCertificateClientMockRecorder {
+	return m.recorder
+}
+
+// AddCertificate mocks base method.
+func (m *MockCertificateClient) AddCertificate(details *api0.CertificatesPost) (*api0.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCertificate", details)
+	ret0, _ := ret[0].(*api0.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddCertificate indicates an expected call of AddCertificate.
+func (mr *MockCertificateClientMockRecorder) AddCertificate(details interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCertificate", reflect.TypeOf((*MockCertificateClient)(nil).AddCertificate), details)
+}
+
+// DeleteCertificate mocks base method.
+func (m *MockCertificateClient) DeleteCertificate(fingerprint string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCertificate", fingerprint)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCertificate indicates an expected call of DeleteCertificate.
+func (mr *MockCertificateClientMockRecorder) DeleteCertificate(fingerprint interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCertificate", reflect.TypeOf((*MockCertificateClient)(nil).DeleteCertificate), fingerprint)
+}
+
+// ListCertificates mocks base method.
+func (m *MockCertificateClient) ListCertificates() ([]api0.Certificate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCertificates")
+	ret0, _ := ret[0].([]api0.Certificate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCertificates indicates an expected call of ListCertificates.
+func (mr *MockCertificateClientMockRecorder) ListCertificates() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCertificates", reflect.TypeOf((*MockCertificateClient)(nil).ListCertificates))
+}
+
+// MockContainerClient is a mock of ContainerClient interface.
+type MockContainerClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockContainerClientMockRecorder
+}
+
+// MockContainerClientMockRecorder is the mock recorder for MockContainerClient.
+type MockContainerClientMockRecorder struct {
+	mock *MockContainerClient
+}
+
+// NewMockContainerClient creates a new mock instance.
+func NewMockContainerClient(ctrl *gomock.Controller) *MockContainerClient {
+	mock := &MockContainerClient{ctrl: ctrl}
+	mock.recorder = &MockContainerClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockContainerClient) EXPECT() *MockContainerClientMockRecorder {
+	return m.recorder
+}
+
+// DeleteContainerByID mocks base method.
+func (m *MockContainerClient) DeleteContainerByID(id string, force bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteContainerByID", id, force)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteContainerByID indicates an expected call of DeleteContainerByID.
+func (mr *MockContainerClientMockRecorder) DeleteContainerByID(id, force interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteContainerByID", reflect.TypeOf((*MockContainerClient)(nil).DeleteContainerByID), id, force)
+}
+
+// DeleteContainers mocks base method.
+func (m *MockContainerClient) DeleteContainers(ids []string, force bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteContainers", ids, force)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteContainers indicates an expected call of DeleteContainers.
+func (mr *MockContainerClientMockRecorder) DeleteContainers(ids, force interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteContainers", reflect.TypeOf((*MockContainerClient)(nil).DeleteContainers), ids, force)
+}
+
+// ExecuteContainer mocks base method.
+func (m *MockContainerClient) ExecuteContainer(id string, details *api.ContainerExecPost, args *client.ContainerExecArgs) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteContainer", id, details, args)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecuteContainer indicates an expected call of ExecuteContainer.
+func (mr *MockContainerClientMockRecorder) ExecuteContainer(id, details, args interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteContainer", reflect.TypeOf((*MockContainerClient)(nil).ExecuteContainer), id, details, args)
+}
+
+// FollowContainerLog mocks base method.
+func (m *MockContainerClient) FollowContainerLog(ctx context.Context, id, name string, w io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FollowContainerLog", ctx, id, name, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FollowContainerLog indicates an expected call of FollowContainerLog.
+func (mr *MockContainerClientMockRecorder) FollowContainerLog(ctx, id, name, w interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FollowContainerLog", reflect.TypeOf((*MockContainerClient)(nil).FollowContainerLog), ctx, id, name, w)
+}
+
+// LaunchContainer mocks base method.
+func (m *MockContainerClient) LaunchContainer(details *api.ContainersPost, noWait bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LaunchContainer", details, noWait)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LaunchContainer indicates an expected call of LaunchContainer.
+func (mr *MockContainerClientMockRecorder) LaunchContainer(details, noWait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LaunchContainer", reflect.TypeOf((*MockContainerClient)(nil).LaunchContainer), details, noWait)
+}
+
+// LaunchContainerWithPlacement mocks base method.
+func (m *MockContainerClient) LaunchContainerWithPlacement(details *api.ContainersPost, rules *client.PlacementRules, noWait bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LaunchContainerWithPlacement", details, rules, noWait)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LaunchContainerWithPlacement indicates an expected call of LaunchContainerWithPlacement.
+func (mr *MockContainerClientMockRecorder) LaunchContainerWithPlacement(details, rules, noWait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LaunchContainerWithPlacement", reflect.TypeOf((*MockContainerClient)(nil).LaunchContainerWithPlacement), details, rules, noWait)
+}
+
+// LaunchContainers mocks base method.
+func (m *MockContainerClient) LaunchContainers(details *api.ContainersPost, count int, noWait bool) ([]client.LaunchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LaunchContainers", details, count, noWait)
+	ret0, _ := ret[0].([]client.LaunchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LaunchContainers indicates an expected call of LaunchContainers.
+func (mr *MockContainerClientMockRecorder) LaunchContainers(details, count, noWait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LaunchContainers", reflect.TypeOf((*MockContainerClient)(nil).LaunchContainers), details, count, noWait)
+}
+
+// ListContainers mocks base method.
+func (m *MockContainerClient) ListContainers() ([]api.Container, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListContainers")
+	ret0, _ := ret[0].([]api.Container)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListContainers indicates an expected call of ListContainers.
+func (mr *MockContainerClientMockRecorder) ListContainers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListContainers", reflect.TypeOf((*MockContainerClient)(nil).ListContainers))
+}
+
+// ListContainersWithFilters mocks base method.
+func (m *MockContainerClient) ListContainersWithFilters(filters []string) ([]api.Container, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListContainersWithFilters", filters)
+	ret0, _ := ret[0].([]api.Container)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListContainersWithFilters indicates an expected call of ListContainersWithFilters.
+func (mr *MockContainerClientMockRecorder) ListContainersWithFilters(filters interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListContainersWithFilters", reflect.TypeOf((*MockContainerClient)(nil).ListContainersWithFilters), filters)
+}
+
+// ModifyContainer mocks base method.
+func (m *MockContainerClient) ModifyContainer(id string, modify func(*api.Container) (*api.ContainerPatch, error), noWait bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModifyContainer", id, modify, noWait)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ModifyContainer indicates an expected call of ModifyContainer.
+func (mr *MockContainerClientMockRecorder) ModifyContainer(id, modify, noWait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModifyContainer", reflect.TypeOf((*MockContainerClient)(nil).ModifyContainer), id, modify, noWait)
+}
+
+// OpenContainerLog mocks base method.
+func (m *MockContainerClient) OpenContainerLog(id, name string) (io.ReadSeeker, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenContainerLog", id, name)
+	ret0, _ := ret[0].(io.ReadSeeker)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenContainerLog indicates an expected call of OpenContainerLog.
+func (mr *MockContainerClientMockRecorder) OpenContainerLog(id, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenContainerLog", reflect.TypeOf((*MockContainerClient)(nil).OpenContainerLog), id, name)
+}
+
+// RestartContainer mocks base method.
+func (m *MockContainerClient) RestartContainer(ctx context.Context, id string, noWait bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestartContainer", ctx, id, noWait)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestartContainer indicates an expected call of RestartContainer.
+func (mr *MockContainerClientMockRecorder) RestartContainer(ctx, id, noWait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestartContainer", reflect.TypeOf((*MockContainerClient)(nil).RestartContainer), ctx, id, noWait)
+}
+
+// RetrieveContainerByID mocks base method.
+func (m *MockContainerClient) RetrieveContainerByID(id string) (*api.Container, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveContainerByID", id)
+	ret0, _ := ret[0].(*api.Container)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RetrieveContainerByID indicates an expected call of RetrieveContainerByID.
+func (mr *MockContainerClientMockRecorder) RetrieveContainerByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveContainerByID", reflect.TypeOf((*MockContainerClient)(nil).RetrieveContainerByID), id)
+}
+
+// RetrieveContainerLog mocks base method.
+func (m *MockContainerClient) RetrieveContainerLog(id, name string, downloader func(*http.Header, io.ReadCloser) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveContainerLog", id, name, downloader)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetrieveContainerLog indicates an expected call of RetrieveContainerLog.
+func (mr *MockContainerClientMockRecorder) RetrieveContainerLog(id, name, downloader interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveContainerLog", reflect.TypeOf((*MockContainerClient)(nil).RetrieveContainerLog), id, name, downloader)
+}
+
+// RunContainerAction mocks base method.
+func (m *MockContainerClient) RunContainerAction(ctx context.Context, ids []string, action client.Action, noWait bool) (map[string]client.ActionResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunContainerAction", ctx, ids, action, noWait)
+	ret0, _ := ret[0].(map[string]client.ActionResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunContainerAction indicates an expected call of RunContainerAction.
+func (mr *MockContainerClientMockRecorder) RunContainerAction(ctx, ids, action, noWait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunContainerAction", reflect.TypeOf((*MockContainerClient)(nil).RunContainerAction), ctx, ids, action, noWait)
+}
+
+// StartContainer mocks base method.
+func (m *MockContainerClient) StartContainer(id string, noWait bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartContainer", id, noWait)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartContainer indicates an expected call of StartContainer.
+func (mr *MockContainerClientMockRecorder) StartContainer(id, noWait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartContainer", reflect.TypeOf((*MockContainerClient)(nil).StartContainer), id, noWait)
+}
+
+// StopContainer mocks base method.
+func (m *MockContainerClient) StopContainer(id string, noWait bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StopContainer", id, noWait)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StopContainer indicates an expected call of StopContainer.
+func (mr *MockContainerClientMockRecorder) StopContainer(id, noWait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopContainer", reflect.TypeOf((*MockContainerClient)(nil).StopContainer), id, noWait)
+}
+
+// UpdateContainerByID mocks base method.
+func (m *MockContainerClient) UpdateContainerByID(id string, details *api.ContainerPatch, noWait bool, opts ...client.RequestOption) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{id, details, noWait}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateContainerByID", varargs...)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateContainerByID indicates an expected call of UpdateContainerByID.
+func (mr *MockContainerClientMockRecorder) UpdateContainerByID(id, details, noWait interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{id, details, noWait}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateContainerByID", reflect.TypeOf((*MockContainerClient)(nil).UpdateContainerByID), varargs...)
+}
+
+// WaitForContainerStatus mocks base method.
+func (m *MockContainerClient) WaitForContainerStatus(ctx context.Context, id string, statuses ...api.ContainerStatus) (*api.Container, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, id}
+	for _, a := range statuses {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WaitForContainerStatus", varargs...)
+	ret0, _ := ret[0].(*api.Container)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WaitForContainerStatus indicates an expected call of WaitForContainerStatus.
+func (mr *MockContainerClientMockRecorder) WaitForContainerStatus(ctx, id interface{}, statuses ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, id}, statuses...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForContainerStatus", reflect.TypeOf((*MockContainerClient)(nil).WaitForContainerStatus), varargs...)
+}
+
+// WatchContainersWithInitialState mocks base method.
+func (m *MockContainerClient) WatchContainersWithInitialState(ctx context.Context, filters []string) (*client.ContainerWatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchContainersWithInitialState", ctx, filters)
+	ret0, _ := ret[0].(*client.ContainerWatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WatchContainersWithInitialState indicates an expected call of WatchContainersWithInitialState.
+func (mr *MockContainerClientMockRecorder) WatchContainersWithInitialState(ctx, filters interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchContainersWithInitialState", reflect.TypeOf((*MockContainerClient)(nil).WatchContainersWithInitialState), ctx, filters)
+}
+
+// MockInstanceClient is a mock of InstanceClient interface.
+type MockInstanceClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockInstanceClientMockRecorder
+}
+
+// MockInstanceClientMockRecorder is the mock recorder for MockInstanceClient.
+type MockInstanceClientMockRecorder struct {
+	mock *MockInstanceClient
+}
+
+// NewMockInstanceClient creates a new mock instance.
+func NewMockInstanceClient(ctrl *gomock.Controller) *MockInstanceClient {
+	mock := &MockInstanceClient{ctrl: ctrl}
+	mock.recorder = &MockInstanceClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInstanceClient) EXPECT() *MockInstanceClientMockRecorder {
+	return m.recorder
+}
+
+// AttachConsole mocks base method.
+func (m *MockInstanceClient) AttachConsole(id string, stdin io.ReadCloser, stdout io.WriteCloser, resize <-chan client.ConsoleSize) (*client.Console, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachConsole", id, stdin, stdout, resize)
+	ret0, _ := ret[0].(*client.Console)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AttachConsole indicates an expected call of AttachConsole.
+func (mr *MockInstanceClientMockRecorder) AttachConsole(id, stdin, stdout, resize interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachConsole", reflect.TypeOf((*MockInstanceClient)(nil).AttachConsole), id, stdin, stdout, resize)
+}
+
+// DeleteInstanceByID mocks base method.
+func (m *MockInstanceClient) DeleteInstanceByID(id string, force bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteInstanceByID", id, force)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteInstanceByID indicates an expected call of DeleteInstanceByID.
+func (mr *MockInstanceClientMockRecorder) DeleteInstanceByID(id, force interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteInstanceByID", reflect.TypeOf((*MockInstanceClient)(nil).DeleteInstanceByID), id, force)
+}
+
+// DeleteInstances mocks base method.
+func (m *MockInstanceClient) DeleteInstances(ids []string, force bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteInstances", ids, force)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteInstances indicates an expected call of DeleteInstances.
+func (mr *MockInstanceClientMockRecorder) DeleteInstances(ids, force interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteInstances", reflect.TypeOf((*MockInstanceClient)(nil).DeleteInstances), ids, force)
+}
+
+// ExecuteInstance mocks base method.
+func (m *MockInstanceClient) ExecuteInstance(id string, details *api.InstanceExecPost, args *client.InstanceExecArgs) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteInstance", id, details, args)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecuteInstance indicates an expected call of ExecuteInstance.
+func (mr *MockInstanceClientMockRecorder) ExecuteInstance(id, details, args interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteInstance", reflect.TypeOf((*MockInstanceClient)(nil).ExecuteInstance), id, details, args)
+}
+
+// FollowInstanceLog mocks base method.
+func (m *MockInstanceClient) FollowInstanceLog(ctx context.Context, id, name string, w io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FollowInstanceLog", ctx, id, name, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FollowInstanceLog indicates an expected call of FollowInstanceLog.
+func (mr *MockInstanceClientMockRecorder) FollowInstanceLog(ctx, id, name, w interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FollowInstanceLog", reflect.TypeOf((*MockInstanceClient)(nil).FollowInstanceLog), ctx, id, name, w)
+}
+
+// ForwardPort mocks base method.
+func (m *MockInstanceClient) ForwardPort(ctx context.Context, instanceID, localAddr string, remotePort int) (*client.PortForward, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForwardPort", ctx, instanceID, localAddr, remotePort)
+	ret0, _ := ret[0].(*client.PortForward)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ForwardPort indicates an expected call of ForwardPort.
+func (mr *MockInstanceClientMockRecorder) ForwardPort(ctx, instanceID, localAddr, remotePort interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForwardPort", reflect.TypeOf((*MockInstanceClient)(nil).ForwardPort), ctx, instanceID, localAddr, remotePort)
+}
+
+// LaunchInstance mocks base method.
+func (m *MockInstanceClient) LaunchInstance(details *api.InstancesPost, noWait bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LaunchInstance", details, noWait)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LaunchInstance indicates an expected call of LaunchInstance.
+func (mr *MockInstanceClientMockRecorder) LaunchInstance(details, noWait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LaunchInstance", reflect.TypeOf((*MockInstanceClient)(nil).LaunchInstance), details, noWait)
+}
+
+// LaunchInstanceWithPlacement mocks base method.
+func (m *MockInstanceClient) LaunchInstanceWithPlacement(details *api.InstancesPost, rules *client.PlacementRules, noWait bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LaunchInstanceWithPlacement", details, rules, noWait)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LaunchInstanceWithPlacement indicates an expected call of LaunchInstanceWithPlacement.
+func (mr *MockInstanceClientMockRecorder) LaunchInstanceWithPlacement(details, rules, noWait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LaunchInstanceWithPlacement", reflect.TypeOf((*MockInstanceClient)(nil).LaunchInstanceWithPlacement), details, rules, noWait)
+}
+
+// LaunchInstances mocks base method.
+func (m *MockInstanceClient) LaunchInstances(details *api.InstancesPost, count int, noWait bool) ([]client.LaunchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LaunchInstances", details, count, noWait)
+	ret0, _ := ret[0].([]client.LaunchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LaunchInstances indicates an expected call of LaunchInstances.
+func (mr *MockInstanceClientMockRecorder) LaunchInstances(details, count, noWait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LaunchInstances", reflect.TypeOf((*MockInstanceClient)(nil).LaunchInstances), details, count, noWait)
+}
+
+// ListInstances mocks base method.
+func (m *MockInstanceClient) ListInstances() ([]api.Instance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInstances")
+	ret0, _ := ret[0].([]api.Instance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInstances indicates an expected call of ListInstances.
+func (mr *MockInstanceClientMockRecorder) ListInstances() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInstances", reflect.TypeOf((*MockInstanceClient)(nil).ListInstances))
+}
+
+// ListInstancesWithFilters mocks base method.
+func (m *MockInstanceClient) ListInstancesWithFilters(filters []string) ([]api.Instance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInstancesWithFilters", filters)
+	ret0, _ := ret[0].([]api.Instance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInstancesWithFilters indicates an expected call of ListInstancesWithFilters.
+func (mr *MockInstanceClientMockRecorder) ListInstancesWithFilters(filters interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInstancesWithFilters", reflect.TypeOf((*MockInstanceClient)(nil).ListInstancesWithFilters), filters)
+}
+
+// ModifyInstance mocks base method.
+func (m *MockInstanceClient) ModifyInstance(id string, modify func(*api.Instance) (*api.InstancePatch, error), noWait bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModifyInstance", id, modify, noWait)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ModifyInstance indicates an expected call of ModifyInstance.
+func (mr *MockInstanceClientMockRecorder) ModifyInstance(id, modify, noWait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModifyInstance", reflect.TypeOf((*MockInstanceClient)(nil).ModifyInstance), id, modify, noWait)
+}
+
+// OpenInstanceLog mocks base method.
+func (m *MockInstanceClient) OpenInstanceLog(id, name string) (io.ReadSeeker, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenInstanceLog", id, name)
+	ret0, _ := ret[0].(io.ReadSeeker)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenInstanceLog indicates an expected call of OpenInstanceLog.
+func (mr *MockInstanceClientMockRecorder) OpenInstanceLog(id, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenInstanceLog", reflect.TypeOf((*MockInstanceClient)(nil).OpenInstanceLog), id, name)
+}
+
+// PreviewPlacement mocks base method.
+func (m *MockInstanceClient) PreviewPlacement(details *api.InstancesPost, rules *client.PlacementRules) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreviewPlacement", details, rules)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PreviewPlacement indicates an expected call of PreviewPlacement.
+func (mr *MockInstanceClientMockRecorder) PreviewPlacement(details, rules interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreviewPlacement", reflect.TypeOf((*MockInstanceClient)(nil).PreviewPlacement), details, rules)
+}
+
+// RestartInstance mocks base method.
+func (m *MockInstanceClient) RestartInstance(ctx context.Context, id string, noWait bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestartInstance", ctx, id, noWait)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestartInstance indicates an expected call of RestartInstance.
+func (mr *MockInstanceClientMockRecorder) RestartInstance(ctx, id, noWait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestartInstance", reflect.TypeOf((*MockInstanceClient)(nil).RestartInstance), ctx, id, noWait)
+}
+
+// RetrieveInstanceByID mocks base method.
+func (m *MockInstanceClient) RetrieveInstanceByID(id string) (*api.Instance, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveInstanceByID", id)
+	ret0, _ := ret[0].(*api.Instance)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RetrieveInstanceByID indicates an expected call of RetrieveInstanceByID.
+func (mr *MockInstanceClientMockRecorder) RetrieveInstanceByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveInstanceByID", reflect.TypeOf((*MockInstanceClient)(nil).RetrieveInstanceByID), id)
+}
+
+// RetrieveInstanceLog mocks base method.
+func (m *MockInstanceClient) RetrieveInstanceLog(id, name string, downloader func(*http.Header, io.ReadCloser) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveInstanceLog", id, name, downloader)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetrieveInstanceLog indicates an expected call of RetrieveInstanceLog.
+func (mr *MockInstanceClientMockRecorder) RetrieveInstanceLog(id, name, downloader interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveInstanceLog", reflect.TypeOf((*MockInstanceClient)(nil).RetrieveInstanceLog), id, name, downloader)
+}
+
+// RunInstanceAction mocks base method.
+func (m *MockInstanceClient) RunInstanceAction(ctx context.Context, ids []string, action client.Action, noWait bool) (map[string]client.ActionResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunInstanceAction", ctx, ids, action, noWait)
+	ret0, _ := ret[0].(map[string]client.ActionResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunInstanceAction indicates an expected call of RunInstanceAction.
+func (mr *MockInstanceClientMockRecorder) RunInstanceAction(ctx, ids, action, noWait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunInstanceAction", reflect.TypeOf((*MockInstanceClient)(nil).RunInstanceAction), ctx, ids, action, noWait)
+}
+
+// RunInteractiveShell mocks base method.
+func (m *MockInstanceClient) RunInteractiveShell(ctx context.Context, id string, command []string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunInteractiveShell", ctx, id, command)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunInteractiveShell indicates an expected call of RunInteractiveShell.
+func (mr *MockInstanceClientMockRecorder) RunInteractiveShell(ctx, id, command interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunInteractiveShell", reflect.TypeOf((*MockInstanceClient)(nil).RunInteractiveShell), ctx, id, command)
+}
+
+// StartInstance mocks base method.
+func (m *MockInstanceClient) StartInstance(id string, noWait bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartInstance", id, noWait)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartInstance indicates an expected call of StartInstance.
+func (mr *MockInstanceClientMockRecorder) StartInstance(id, noWait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartInstance", reflect.TypeOf((*MockInstanceClient)(nil).StartInstance), id, noWait)
+}
+
+// StopInstance mocks base method.
+func (m *MockInstanceClient) StopInstance(id string, noWait bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StopInstance", id, noWait)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StopInstance indicates an expected call of StopInstance.
+func (mr *MockInstanceClientMockRecorder) StopInstance(id, noWait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopInstance", reflect.TypeOf((*MockInstanceClient)(nil).StopInstance), id, noWait)
+}
+
+// UpdateInstanceByID mocks base method.
+func (m *MockInstanceClient) UpdateInstanceByID(id string, details *api.InstancePatch, noWait bool, opts ...client.RequestOption) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{id, details, noWait}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateInstanceByID", varargs...)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateInstanceByID indicates an expected call of UpdateInstanceByID.
+func (mr *MockInstanceClientMockRecorder) UpdateInstanceByID(id, details, noWait interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{id, details, noWait}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateInstanceByID", reflect.TypeOf((*MockInstanceClient)(nil).UpdateInstanceByID), varargs...)
+}
+
+// WaitForInstanceStatus mocks base method.
+func (m *MockInstanceClient) WaitForInstanceStatus(ctx context.Context, id string, statuses ...api.InstanceStatus) (*api.Instance, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, id}
+	for _, a := range statuses {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WaitForInstanceStatus", varargs...)
+	ret0, _ := ret[0].(*api.Instance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WaitForInstanceStatus indicates an expected call of WaitForInstanceStatus.
+func (mr *MockInstanceClientMockRecorder) WaitForInstanceStatus(ctx, id interface{}, statuses ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, id}, statuses...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForInstanceStatus", reflect.TypeOf((*MockInstanceClient)(nil).WaitForInstanceStatus), varargs...)
+}
+
+// MockConfigClient is a mock of ConfigClient interface.
+type MockConfigClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockConfigClientMockRecorder
+}
+
+// MockConfigClientMockRecorder is the mock recorder for MockConfigClient.
+type MockConfigClientMockRecorder struct {
+	mock *MockConfigClient
+}
+
+// NewMockConfigClient creates a new mock instance.
+func NewMockConfigClient(ctrl *gomock.Controller) *MockConfigClient {
+	mock := &MockConfigClient{ctrl: ctrl}
+	mock.recorder = &MockConfigClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConfigClient) EXPECT() *MockConfigClientMockRecorder {
+	return m.recorder
+}
+
+// RetrieveConfigItems mocks base method.
+func (m *MockConfigClient) RetrieveConfigItems() (map[string]interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveConfigItems")
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveConfigItems indicates an expected call of RetrieveConfigItems.
+func (mr *MockConfigClientMockRecorder) RetrieveConfigItems() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveConfigItems", reflect.TypeOf((*MockConfigClient)(nil).RetrieveConfigItems))
+}
+
+// SetConfigItem mocks base method.
+func (m *MockConfigClient) SetConfigItem(name, value string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetConfigItem", name, value)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetConfigItem indicates an expected call of SetConfigItem.
+func (mr *MockConfigClientMockRecorder) SetConfigItem(name, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetConfigItem", reflect.TypeOf((*MockConfigClient)(nil).SetConfigItem), name, value)
+}
+
+// MockApplicationClient is a mock of ApplicationClient interface.
+type MockApplicationClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockApplicationClientMockRecorder
+}
+
+// MockApplicationClientMockRecorder is the mock recorder for MockApplicationClient.
+type MockApplicationClientMockRecorder struct {
+	mock *MockApplicationClient
+}
+
+// NewMockApplicationClient creates a new mock instance.
+func NewMockApplicationClient(ctrl *gomock.Controller) *MockApplicationClient {
+	mock := &MockApplicationClient{ctrl: ctrl}
+	mock.recorder = &MockApplicationClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockApplicationClient) EXPECT() *MockApplicationClientMockRecorder {
+	return m.recorder
+}
+
+// CreateApplication mocks base method.
+func (m *MockApplicationClient) CreateApplication(packagePath string, sentBytes chan float64, opts ...client.RequestOption) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{packagePath, sentBytes}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateApplication", varargs...)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateApplication indicates an expected call of CreateApplication.
+func (mr *MockApplicationClientMockRecorder) CreateApplication(packagePath, sentBytes interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{packagePath, sentBytes}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateApplication", reflect.TypeOf((*MockApplicationClient)(nil).CreateApplication), varargs...)
+}
+
+// CreateApplicationWithArgs mocks base method.
+func (m *MockApplicationClient) CreateApplicationWithArgs(args *client.ApplicationCreateArgs, opts ...client.RequestOption) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{args}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateApplicationWithArgs", varargs...)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateApplicationWithArgs indicates an expected call of CreateApplicationWithArgs.
+func (mr *MockApplicationClientMockRecorder) CreateApplicationWithArgs(args interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{args}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateApplicationWithArgs", reflect.TypeOf((*MockApplicationClient)(nil).CreateApplicationWithArgs), varargs...)
+}
+
+// DeleteApplicationByID mocks base method.
+func (m *MockApplicationClient) DeleteApplicationByID(id string, force bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteApplicationByID", id, force)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteApplicationByID indicates an expected call of DeleteApplicationByID.
+func (mr *MockApplicationClientMockRecorder) DeleteApplicationByID(id, force interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteApplicationByID", reflect.TypeOf((*MockApplicationClient)(nil).DeleteApplicationByID), id, force)
+}
+
+// DeleteApplicationVersion mocks base method.
+func (m *MockApplicationClient) DeleteApplicationVersion(id string, version int, force bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteApplicationVersion", id, version, force)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteApplicationVersion indicates an expected call of DeleteApplicationVersion.
+func (mr *MockApplicationClientMockRecorder) DeleteApplicationVersion(id, version, force interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteApplicationVersion", reflect.TypeOf((*MockApplicationClient)(nil).DeleteApplicationVersion), id, version, force)
+}
+
+// DeleteApplications mocks base method.
+func (m *MockApplicationClient) DeleteApplications(ids []string, force bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteApplications", ids, force)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteApplications indicates an expected call of DeleteApplications.
+func (mr *MockApplicationClientMockRecorder) DeleteApplications(ids, force interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteApplications", reflect.TypeOf((*MockApplicationClient)(nil).DeleteApplications), ids, force)
+}
+
+// ExportApplicationByVersion mocks base method.
+func (m *MockApplicationClient) ExportApplicationByVersion(id string, version int, downloader func(*http.Header, io.ReadCloser) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportApplicationByVersion", id, version, downloader)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportApplicationByVersion indicates an expected call of ExportApplicationByVersion.
+func (mr *MockApplicationClientMockRecorder) ExportApplicationByVersion(id, version, downloader interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportApplicationByVersion", reflect.TypeOf((*MockApplicationClient)(nil).ExportApplicationByVersion), id, version, downloader)
+}
+
+// FindApplicationsByName mocks base method.
+func (m *MockApplicationClient) FindApplicationsByName(pattern string) ([]api.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindApplicationsByName", pattern)
+	ret0, _ := ret[0].([]api.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindApplicationsByName indicates an expected call of FindApplicationsByName.
+func (mr *MockApplicationClientMockRecorder) FindApplicationsByName(pattern interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindApplicationsByName", reflect.TypeOf((*MockApplicationClient)(nil).FindApplicationsByName), pattern)
+}
+
+// ListApplications mocks base method.
+func (m *MockApplicationClient) ListApplications() ([]api.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListApplications")
+	ret0, _ := ret[0].([]api.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListApplications indicates an expected call of ListApplications.
+func (mr *MockApplicationClientMockRecorder) ListApplications() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListApplications", reflect.TypeOf((*MockApplicationClient)(nil).ListApplications))
+}
+
+// ListApplicationsWithFilters mocks base method.
+func (m *MockApplicationClient) ListApplicationsWithFilters(filters []string) ([]api.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListApplicationsWithFilters", filters)
+	ret0, _ := ret[0].([]api.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListApplicationsWithFilters indicates an expected call of ListApplicationsWithFilters.
+func (mr *MockApplicationClientMockRecorder) ListApplicationsWithFilters(filters interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListApplicationsWithFilters", reflect.TypeOf((*MockApplicationClient)(nil).ListApplicationsWithFilters), filters)
+}
+
+// PublishApplicationVersion mocks base method.
+func (m *MockApplicationClient) PublishApplicationVersion(id string, version int) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishApplicationVersion", id, version)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishApplicationVersion indicates an expected call of PublishApplicationVersion.
+func (mr *MockApplicationClientMockRecorder) PublishApplicationVersion(id, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishApplicationVersion", reflect.TypeOf((*MockApplicationClient)(nil).PublishApplicationVersion), id, version)
+}
+
+// RetrieveApplicationByID mocks base method.
+func (m *MockApplicationClient) RetrieveApplicationByID(id string) (*api.Application, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveApplicationByID", id)
+	ret0, _ := ret[0].(*api.Application)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RetrieveApplicationByID indicates an expected call of RetrieveApplicationByID.
+func (mr *MockApplicationClientMockRecorder) RetrieveApplicationByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveApplicationByID", reflect.TypeOf((*MockApplicationClient)(nil).RetrieveApplicationByID), id)
+}
+
+// RetrieveApplicationLogs mocks base method.
+func (m *MockApplicationClient) RetrieveApplicationLogs(appID, name string, filter *client.ApplicationLogFilter, sink io.Writer) ([]client.ApplicationLogResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveApplicationLogs", appID, name, filter, sink)
+	ret0, _ := ret[0].([]client.ApplicationLogResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveApplicationLogs indicates an expected call of RetrieveApplicationLogs.
+func (mr *MockApplicationClientMockRecorder) RetrieveApplicationLogs(appID, name, filter, sink interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveApplicationLogs", reflect.TypeOf((*MockApplicationClient)(nil).RetrieveApplicationLogs), appID, name, filter, sink)
+}
+
+// RevokeApplicationVersion mocks base method.
+func (m *MockApplicationClient) RevokeApplicationVersion(id string, version int) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeApplicationVersion", id, version)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeApplicationVersion indicates an expected call of RevokeApplicationVersion.
+func (mr *MockApplicationClientMockRecorder) RevokeApplicationVersion(id, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeApplicationVersion", reflect.TypeOf((*MockApplicationClient)(nil).RevokeApplicationVersion), id, version)
+}
+
+// UpdateApplication mocks base method.
+func (m *MockApplicationClient) UpdateApplication(id string, opts ...client.RequestOption) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{id}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateApplication", varargs...)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateApplication indicates an expected call of UpdateApplication.
+func (mr *MockApplicationClientMockRecorder) UpdateApplication(id interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{id}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateApplication", reflect.TypeOf((*MockApplicationClient)(nil).UpdateApplication), varargs...)
+}
+
+// UpdateApplicationWithDetails mocks base method.
+func (m *MockApplicationClient) UpdateApplicationWithDetails(id string, details api.ApplicationPatch, opts ...client.RequestOption) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{id, details}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateApplicationWithDetails", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateApplicationWithDetails indicates an expected call of UpdateApplicationWithDetails.
+func (mr *MockApplicationClientMockRecorder) UpdateApplicationWithDetails(id, details interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{id, details}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateApplicationWithDetails", reflect.TypeOf((*MockApplicationClient)(nil).UpdateApplicationWithDetails), varargs...)
+}
+
+// UpdateApplicationWithPackage mocks base method.
+func (m *MockApplicationClient) UpdateApplicationWithPackage(id, packagePath string, sentBytes chan float64, opts ...client.RequestOption) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{id, packagePath, sentBytes}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateApplicationWithPackage", varargs...)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateApplicationWithPackage indicates an expected call of UpdateApplicationWithPackage.
+func (mr *MockApplicationClientMockRecorder) UpdateApplicationWithPackage(id, packagePath, sentBytes interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{id, packagePath, sentBytes}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateApplicationWithPackage", reflect.TypeOf((*MockApplicationClient)(nil).UpdateApplicationWithPackage), varargs...)
+}
+
+// WaitApplicationReady mocks base method.
+func (m *MockApplicationClient) WaitApplicationReady(ctx context.Context, id string, version int) (*api.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitApplicationReady", ctx, id, version)
+	ret0, _ := ret[0].(*api.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WaitApplicationReady indicates an expected call of WaitApplicationReady.
+func (mr *MockApplicationClientMockRecorder) WaitApplicationReady(ctx, id, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitApplicationReady", reflect.TypeOf((*MockApplicationClient)(nil).WaitApplicationReady), ctx, id, version)
+}
+
+// MockAddonClient is a mock of AddonClient interface.
+type MockAddonClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockAddonClientMockRecorder
+}
+
+// MockAddonClientMockRecorder is the mock recorder for MockAddonClient.
+type MockAddonClientMockRecorder struct {
+	mock *MockAddonClient
+}
+
+// NewMockAddonClient creates a new mock instance.
+func NewMockAddonClient(ctrl *gomock.Controller) *MockAddonClient {
+	mock := &MockAddonClient{ctrl: ctrl}
+	mock.recorder = &MockAddonClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAddonClient) EXPECT() *MockAddonClientMockRecorder {
+	return m.recorder
+}
+
+// AddAddon mocks base method.
+func (m *MockAddonClient) AddAddon(name, packagePath string, sentBytes chan float64, opts ...client.RequestOption) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{name, packagePath, sentBytes}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AddAddon", varargs...)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddAddon indicates an expected call of AddAddon.
+func (mr *MockAddonClientMockRecorder) AddAddon(name, packagePath, sentBytes interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{name, packagePath, sentBytes}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAddon", reflect.TypeOf((*MockAddonClient)(nil).AddAddon), varargs...)
+}
+
+// CreateAddon mocks base method.
+func (m *MockAddonClient) CreateAddon(name string, payload io.ReadSeeker, sentBytes chan float64, opts ...client.RequestOption) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{name, payload, sentBytes}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateAddon", varargs...)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAddon indicates an expected call of CreateAddon.
+func (mr *MockAddonClientMockRecorder) CreateAddon(name, payload, sentBytes interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{name, payload, sentBytes}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAddon", reflect.TypeOf((*MockAddonClient)(nil).CreateAddon), varargs...)
+}
+
+// DeleteAddon mocks base method.
+func (m *MockAddonClient) DeleteAddon(name string) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAddon", name)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAddon indicates an expected call of DeleteAddon.
+func (mr *MockAddonClientMockRecorder) DeleteAddon(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAddon", reflect.TypeOf((*MockAddonClient)(nil).DeleteAddon), name)
+}
+
+// DeleteAddonVersion mocks base method.
+func (m *MockAddonClient) DeleteAddonVersion(name string, version int) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAddonVersion", name, version)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAddonVersion indicates an expected call of DeleteAddonVersion.
+func (mr *MockAddonClientMockRecorder) DeleteAddonVersion(name, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAddonVersion", reflect.TypeOf((*MockAddonClient)(nil).DeleteAddonVersion), name, version)
+}
+
+// ListAddonVersions mocks base method.
+func (m *MockAddonClient) ListAddonVersions(name string) ([]api.AddonVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAddonVersions", name)
+	ret0, _ := ret[0].([]api.AddonVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAddonVersions indicates an expected call of ListAddonVersions.
+func (mr *MockAddonClientMockRecorder) ListAddonVersions(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAddonVersions", reflect.TypeOf((*MockAddonClient)(nil).ListAddonVersions), name)
+}
+
+// ListAddons mocks base method.
+func (m *MockAddonClient) ListAddons() ([]api.Addon, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAddons")
+	ret0, _ := ret[0].([]api.Addon)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAddons indicates an expected call of ListAddons.
+func (mr *MockAddonClientMockRecorder) ListAddons() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAddons", reflect.TypeOf((*MockAddonClient)(nil).ListAddons))
+}
+
+// RetrieveAddon mocks base method.
+func (m *MockAddonClient) RetrieveAddon(name string) (*api.Addon, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveAddon", name)
+	ret0, _ := ret[0].(*api.Addon)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RetrieveAddon indicates an expected call of RetrieveAddon.
+func (mr *MockAddonClientMockRecorder) RetrieveAddon(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveAddon", reflect.TypeOf((*MockAddonClient)(nil).RetrieveAddon), name)
+}
+
+// UpdateAddon mocks base method.
+func (m *MockAddonClient) UpdateAddon(name, packagePath string, sentBytes chan float64, opts ...client.RequestOption) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{name, packagePath, sentBytes}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateAddon", varargs...)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAddon indicates an expected call of UpdateAddon.
+func (mr *MockAddonClientMockRecorder) UpdateAddon(name, packagePath, sentBytes interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{name, packagePath, sentBytes}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAddon", reflect.TypeOf((*MockAddonClient)(nil).UpdateAddon), varargs...)
+}
+
+// UpdateAddonWithPayload mocks base method.
+func (m *MockAddonClient) UpdateAddonWithPayload(name string, payload io.ReadSeeker, sentBytes chan float64, opts ...client.RequestOption) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{name, payload, sentBytes}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateAddonWithPayload", varargs...)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAddonWithPayload indicates an expected call of UpdateAddonWithPayload.
+func (mr *MockAddonClientMockRecorder) UpdateAddonWithPayload(name, payload, sentBytes interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{name, payload, sentBytes}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAddonWithPayload", reflect.TypeOf((*MockAddonClient)(nil).UpdateAddonWithPayload), varargs...)
+}
+
+// MockImageClient is a mock of ImageClient interface.
+type MockImageClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockImageClientMockRecorder
+}
+
+// MockImageClientMockRecorder is the mock recorder for MockImageClient.
+type MockImageClientMockRecorder struct {
+	mock *MockImageClient
+}
+
+// NewMockImageClient creates a new mock instance.
+func NewMockImageClient(ctrl *gomock.Controller) *MockImageClient {
+	mock := &MockImageClient{ctrl: ctrl}
+	mock.recorder = &MockImageClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImageClient) EXPECT() *MockImageClientMockRecorder {
+	return m.recorder
+}
+
+// AddImage mocks base method.
+func (m *MockImageClient) AddImage(name, packagePath string, isDefault bool, sentBytes chan float64, opts ...client.RequestOption) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{name, packagePath, isDefault, sentBytes}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AddImage", varargs...)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddImage indicates an expected call of AddImage.
+func (mr *MockImageClientMockRecorder) AddImage(name, packagePath, isDefault, sentBytes interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{name, packagePath, isDefault, sentBytes}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddImage", reflect.TypeOf((*MockImageClient)(nil).AddImage), varargs...)
+}
+
+// CreateImage mocks base method.
+func (m *MockImageClient) CreateImage(name string, payload io.ReadSeeker, isDefault bool, sentBytes chan float64, opts ...client.RequestOption) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{name, payload, isDefault, sentBytes}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateImage", varargs...)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateImage indicates an expected call of CreateImage.
+func (mr *MockImageClientMockRecorder) CreateImage(name, payload, isDefault, sentBytes interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{name, payload, isDefault, sentBytes}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateImage", reflect.TypeOf((*MockImageClient)(nil).CreateImage), varargs...)
+}
+
+// DeleteImageByIDOrName mocks base method.
+func (m *MockImageClient) DeleteImageByIDOrName(id string, force bool, imgType api.ImageType) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteImageByIDOrName", id, force, imgType)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteImageByIDOrName indicates an expected call of DeleteImageByIDOrName.
+func (mr *MockImageClientMockRecorder) DeleteImageByIDOrName(id, force, imgType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteImageByIDOrName", reflect.TypeOf((*MockImageClient)(nil).DeleteImageByIDOrName), id, force, imgType)
+}
+
+// DeleteImageVersion mocks base method.
+func (m *MockImageClient) DeleteImageVersion(id string, version int) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteImageVersion", id, version)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteImageVersion indicates an expected call of DeleteImageVersion.
+func (mr *MockImageClientMockRecorder) DeleteImageVersion(id, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteImageVersion", reflect.TypeOf((*MockImageClient)(nil).DeleteImageVersion), id, version)
+}
+
+// ImportImage mocks base method.
+func (m *MockImageClient) ImportImage(name, path string, isDefault bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportImage", name, path, isDefault)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportImage indicates an expected call of ImportImage.
+func (mr *MockImageClientMockRecorder) ImportImage(name, path, isDefault interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportImage", reflect.TypeOf((*MockImageClient)(nil).ImportImage), name, path, isDefault)
+}
+
+// ImportImageByType mocks base method.
+func (m *MockImageClient) ImportImageByType(name, path string, imgType api.ImageType, isDefault bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportImageByType", name, path, imgType, isDefault)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportImageByType indicates an expected call of ImportImageByType.
+func (mr *MockImageClientMockRecorder) ImportImageByType(name, path, imgType, isDefault interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportImageByType", reflect.TypeOf((*MockImageClient)(nil).ImportImageByType), name, path, imgType, isDefault)
+}
+
+// ListImageNodeStatus mocks base method.
+func (m *MockImageClient) ListImageNodeStatus(id string) ([]api.ImageNodeStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListImageNodeStatus", id)
+	ret0, _ := ret[0].([]api.ImageNodeStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListImageNodeStatus indicates an expected call of ListImageNodeStatus.
+func (mr *MockImageClientMockRecorder) ListImageNodeStatus(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListImageNodeStatus", reflect.TypeOf((*MockImageClient)(nil).ListImageNodeStatus), id)
+}
+
+// ListImages mocks base method.
+func (m *MockImageClient) ListImages() ([]api.Image, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListImages")
+	ret0, _ := ret[0].([]api.Image)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListImages indicates an expected call of ListImages.
+func (mr *MockImageClientMockRecorder) ListImages() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListImages", reflect.TypeOf((*MockImageClient)(nil).ListImages))
+}
+
+// RetrieveDefaultImage mocks base method.
+func (m *MockImageClient) RetrieveDefaultImage() (*api.Image, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveDefaultImage")
+	ret0, _ := ret[0].(*api.Image)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RetrieveDefaultImage indicates an expected call of RetrieveDefaultImage.
+func (mr *MockImageClientMockRecorder) RetrieveDefaultImage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveDefaultImage", reflect.TypeOf((*MockImageClient)(nil).RetrieveDefaultImage))
+}
+
+// RetrieveImageByIDOrName mocks base method.
+func (m *MockImageClient) RetrieveImageByIDOrName(id string, imgType api.ImageType) (*api.Image, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveImageByIDOrName", id, imgType)
+	ret0, _ := ret[0].(*api.Image)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RetrieveImageByIDOrName indicates an expected call of RetrieveImageByIDOrName.
+func (mr *MockImageClientMockRecorder) RetrieveImageByIDOrName(id, imgType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveImageByIDOrName", reflect.TypeOf((*MockImageClient)(nil).RetrieveImageByIDOrName), id, imgType)
+}
+
+// RetrieveImageChannel mocks base method.
+func (m *MockImageClient) RetrieveImageChannel() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveImageChannel")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveImageChannel indicates an expected call of RetrieveImageChannel.
+func (mr *MockImageClientMockRecorder) RetrieveImageChannel() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveImageChannel", reflect.TypeOf((*MockImageClient)(nil).RetrieveImageChannel))
+}
+
+// SetDefaultImage mocks base method.
+func (m *MockImageClient) SetDefaultImage(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDefaultImage", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDefaultImage indicates an expected call of SetDefaultImage.
+func (mr *MockImageClientMockRecorder) SetDefaultImage(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDefaultImage", reflect.TypeOf((*MockImageClient)(nil).SetDefaultImage), id)
+}
+
+// SetImageChannel mocks base method.
+func (m *MockImageClient) SetImageChannel(channel string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetImageChannel", channel)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetImageChannel indicates an expected call of SetImageChannel.
+func (mr *MockImageClientMockRecorder) SetImageChannel(channel interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetImageChannel", reflect.TypeOf((*MockImageClient)(nil).SetImageChannel), channel)
+}
+
+// StartImageSync mocks base method.
+func (m *MockImageClient) StartImageSync(id string) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartImageSync", id)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartImageSync indicates an expected call of StartImageSync.
+func (mr *MockImageClientMockRecorder) StartImageSync(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartImageSync", reflect.TypeOf((*MockImageClient)(nil).StartImageSync), id)
+}
+
+// TriggerImageSync mocks base method.
+func (m *MockImageClient) TriggerImageSync(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TriggerImageSync", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TriggerImageSync indicates an expected call of TriggerImageSync.
+func (mr *MockImageClientMockRecorder) TriggerImageSync(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TriggerImageSync", reflect.TypeOf((*MockImageClient)(nil).TriggerImageSync), id)
+}
+
+// UpdateImage mocks base method.
+func (m *MockImageClient) UpdateImage(id, packagePath string, sentBytes chan float64, opts ...client.RequestOption) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{id, packagePath, sentBytes}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateImage", varargs...)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateImage indicates an expected call of UpdateImage.
+func (mr *MockImageClientMockRecorder) UpdateImage(id, packagePath, sentBytes interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{id, packagePath, sentBytes}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateImage", reflect.TypeOf((*MockImageClient)(nil).UpdateImage), varargs...)
+}
+
+// UpdateImageWithPayload mocks base method.
+func (m *MockImageClient) UpdateImageWithPayload(id string, payload io.ReadSeeker, sentBytes chan float64, opts ...client.RequestOption) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{id, payload, sentBytes}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateImageWithPayload", varargs...)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateImageWithPayload indicates an expected call of UpdateImageWithPayload.
+func (mr *MockImageClientMockRecorder) UpdateImageWithPayload(id, payload, sentBytes interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{id, payload, sentBytes}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateImageWithPayload", reflect.TypeOf((*MockImageClient)(nil).UpdateImageWithPayload), varargs...)
+}
+
+// WaitForImageSync mocks base method.
+func (m *MockImageClient) WaitForImageSync(ctx context.Context, id string, version int) (*api.Image, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitForImageSync", ctx, id, version)
+	ret0, _ := ret[0].(*api.Image)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WaitForImageSync indicates an expected call of WaitForImageSync.
+func (mr *MockImageClientMockRecorder) WaitForImageSync(ctx, id, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForImageSync", reflect.TypeOf((*MockImageClient)(nil).WaitForImageSync), ctx, id, version)
+}
+
+// MockServiceClient is a mock of ServiceClient interface.
+type MockServiceClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceClientMockRecorder
+}
+
+// MockServiceClientMockRecorder is the mock recorder for MockServiceClient.
+type MockServiceClientMockRecorder struct {
+	mock *MockServiceClient
+}
+
+// NewMockServiceClient creates a new mock instance.
+func NewMockServiceClient(ctrl *gomock.Controller) *MockServiceClient {
+	mock := &MockServiceClient{ctrl: ctrl}
+	mock.recorder = &MockServiceClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockServiceClient) EXPECT() *MockServiceClientMockRecorder {
+	return m.recorder
+}
+
+// ClockSkew mocks base method.
+func (m *MockServiceClient) ClockSkew() client0.ClockSkew {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClockSkew")
+	ret0, _ := ret[0].(client0.ClockSkew)
+	return ret0
+}
+
+// ClockSkew indicates an expected call of ClockSkew.
+func (mr *MockServiceClientMockRecorder) ClockSkew() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClockSkew", reflect.TypeOf((*MockServiceClient)(nil).ClockSkew))
+}
+
+// CloseIdleStreams mocks base method.
+func (m *MockServiceClient) CloseIdleStreams(olderThan time.Duration) int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseIdleStreams", olderThan)
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// CloseIdleStreams indicates an expected call of CloseIdleStreams.
+func (mr *MockServiceClientMockRecorder) CloseIdleStreams(olderThan interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseIdleStreams", reflect.TypeOf((*MockServiceClient)(nil).CloseIdleStreams), olderThan)
+}
+
+// ConnectionInfo mocks base method.
+func (m *MockServiceClient) ConnectionInfo() *client0.ConnectionInfo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConnectionInfo")
+	ret0, _ := ret[0].(*client0.ConnectionInfo)
+	return ret0
+}
+
+// ConnectionInfo indicates an expected call of ConnectionInfo.
+func (mr *MockServiceClientMockRecorder) ConnectionInfo() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConnectionInfo", reflect.TypeOf((*MockServiceClient)(nil).ConnectionInfo))
+}
+
+// Extensions mocks base method.
+func (m *MockServiceClient) Extensions() ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Extensions")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Extensions indicates an expected call of Extensions.
+func (mr *MockServiceClientMockRecorder) Extensions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Extensions", reflect.TypeOf((*MockServiceClient)(nil).Extensions))
+}
+
+// GetEvents mocks base method.
+func (m *MockServiceClient) GetEvents() (*client0.EventListener, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEvents")
+	ret0, _ := ret[0].(*client0.EventListener)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEvents indicates an expected call of GetEvents.
+func (mr *MockServiceClientMockRecorder) GetEvents() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvents", reflect.TypeOf((*MockServiceClient)(nil).GetEvents))
+}
+
+// GetVersion mocks base method.
+func (m *MockServiceClient) GetVersion() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVersion")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVersion indicates an expected call of GetVersion.
+func (mr *MockServiceClientMockRecorder) GetVersion() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVersion", reflect.TypeOf((*MockServiceClient)(nil).GetVersion))
+}
+
+// HasExtension mocks base method.
+func (m *MockServiceClient) HasExtension(name string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasExtension", name)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasExtension indicates an expected call of HasExtension.
+func (mr *MockServiceClientMockRecorder) HasExtension(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasExtension", reflect.TypeOf((*MockServiceClient)(nil).HasExtension), name)
+}
+
+// Health mocks base method.
+func (m *MockServiceClient) Health() client0.Health {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Health")
+	ret0, _ := ret[0].(client0.Health)
+	return ret0
+}
+
+// Health indicates an expected call of Health.
+func (mr *MockServiceClientMockRecorder) Health() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Health", reflect.TypeOf((*MockServiceClient)(nil).Health))
+}
+
+// ListClusterServices mocks base method.
+func (m *MockServiceClient) ListClusterServices() ([]api.ClusterService, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListClusterServices")
+	ret0, _ := ret[0].([]api.ClusterService)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListClusterServices indicates an expected call of ListClusterServices.
+func (mr *MockServiceClientMockRecorder) ListClusterServices() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClusterServices", reflect.TypeOf((*MockServiceClient)(nil).ListClusterServices))
+}
+
+// ListTasks mocks base method.
+func (m *MockServiceClient) ListTasks() ([]api.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTasks")
+	ret0, _ := ret[0].([]api.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTasks indicates an expected call of ListTasks.
+func (mr *MockServiceClientMockRecorder) ListTasks() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTasks", reflect.TypeOf((*MockServiceClient)(nil).ListTasks))
+}
+
+// OpenStreams mocks base method.
+func (m *MockServiceClient) OpenStreams() []client0.StreamInfo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenStreams")
+	ret0, _ := ret[0].([]client0.StreamInfo)
+	return ret0
+}
+
+// OpenStreams indicates an expected call of OpenStreams.
+func (mr *MockServiceClientMockRecorder) OpenStreams() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenStreams", reflect.TypeOf((*MockServiceClient)(nil).OpenStreams))
+}
+
+// Ping mocks base method.
+func (m *MockServiceClient) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockServiceClientMockRecorder) Ping(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockServiceClient)(nil).Ping), ctx)
+}
+
+// Raw mocks base method.
+func (m *MockServiceClient) Raw(ctx context.Context, method, path string, params client0.QueryParams, body io.Reader) (*http.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Raw", ctx, method, path, params, body)
+	ret0, _ := ret[0].(*http.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Raw indicates an expected call of Raw.
+func (mr *MockServiceClientMockRecorder) Raw(ctx, method, path, params, body interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Raw", reflect.TypeOf((*MockServiceClient)(nil).Raw), ctx, method, path, params, body)
+}
+
+// RefreshExtensions mocks base method.
+func (m *MockServiceClient) RefreshExtensions() ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshExtensions")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshExtensions indicates an expected call of RefreshExtensions.
+func (mr *MockServiceClientMockRecorder) RefreshExtensions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshExtensions", reflect.TypeOf((*MockServiceClient)(nil).RefreshExtensions))
+}
+
+// RetrieveClusterService mocks base method.
+func (m *MockServiceClient) RetrieveClusterService(name string) (*api.ClusterService, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveClusterService", name)
+	ret0, _ := ret[0].(*api.ClusterService)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RetrieveClusterService indicates an expected call of RetrieveClusterService.
+func (mr *MockServiceClientMockRecorder) RetrieveClusterService(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveClusterService", reflect.TypeOf((*MockServiceClient)(nil).RetrieveClusterService), name)
+}
+
+// RetrieveServiceStatus mocks base method.
+func (m *MockServiceClient) RetrieveServiceStatus() (*api.ServiceStatus, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveServiceStatus")
+	ret0, _ := ret[0].(*api.ServiceStatus)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RetrieveServiceStatus indicates an expected call of RetrieveServiceStatus.
+func (mr *MockServiceClientMockRecorder) RetrieveServiceStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveServiceStatus", reflect.TypeOf((*MockServiceClient)(nil).RetrieveServiceStatus))
+}
+
+// ServerStatus mocks base method.
+func (m *MockServiceClient) ServerStatus(ctx context.Context) (*api.ServiceStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ServerStatus", ctx)
+	ret0, _ := ret[0].(*api.ServiceStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ServerStatus indicates an expected call of ServerStatus.
+func (mr *MockServiceClientMockRecorder) ServerStatus(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ServerStatus", reflect.TypeOf((*MockServiceClient)(nil).ServerStatus), ctx)
+}
+
+// SubscribeEvents mocks base method.
+func (m *MockServiceClient) SubscribeEvents(types []api.EventType, handler func(*api.EventEnvelope)) (*client0.EventListener, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeEvents", types, handler)
+	ret0, _ := ret[0].(*client0.EventListener)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeEvents indicates an expected call of SubscribeEvents.
+func (mr *MockServiceClientMockRecorder) SubscribeEvents(types, handler interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeEvents", reflect.TypeOf((*MockServiceClient)(nil).SubscribeEvents), types, handler)
+}
+
+// UnhealthyClusterServices mocks base method.
+func (m *MockServiceClient) UnhealthyClusterServices() ([]api.ClusterService, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnhealthyClusterServices")
+	ret0, _ := ret[0].([]api.ClusterService)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnhealthyClusterServices indicates an expected call of UnhealthyClusterServices.
+func (mr *MockServiceClientMockRecorder) UnhealthyClusterServices() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnhealthyClusterServices", reflect.TypeOf((*MockServiceClient)(nil).UnhealthyClusterServices))
+}
+
+// Use mocks base method.
+func (m *MockServiceClient) Use(middlewares ...client0.Middleware) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range middlewares {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Use", varargs...)
+}
+
+// Use indicates an expected call of Use.
+func (mr *MockServiceClientMockRecorder) Use(middlewares ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Use", reflect.TypeOf((*MockServiceClient)(nil).Use), middlewares...)
+}
+
+// MockRegistryClient is a mock of RegistryClient interface.
+type MockRegistryClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockRegistryClientMockRecorder
+}
+
+// MockRegistryClientMockRecorder is the mock recorder for MockRegistryClient.
+type MockRegistryClientMockRecorder struct {
+	mock *MockRegistryClient
+}
+
+// NewMockRegistryClient creates a new mock instance.
+func NewMockRegistryClient(ctrl *gomock.Controller) *MockRegistryClient {
+	mock := &MockRegistryClient{ctrl: ctrl}
+	mock.recorder = &MockRegistryClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRegistryClient) EXPECT() *MockRegistryClientMockRecorder {
+	return m.recorder
+}
+
+// DeleteApplicationFromRegistry mocks base method.
+func (m *MockRegistryClient) DeleteApplicationFromRegistry(id string) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteApplicationFromRegistry", id)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteApplicationFromRegistry indicates an expected call of DeleteApplicationFromRegistry.
+func (mr *MockRegistryClientMockRecorder) DeleteApplicationFromRegistry(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteApplicationFromRegistry", reflect.TypeOf((*MockRegistryClient)(nil).DeleteApplicationFromRegistry), id)
+}
+
+// ListApplicationsFromRegistry mocks base method.
+func (m *MockRegistryClient) ListApplicationsFromRegistry() ([]api.RegistryApplication, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListApplicationsFromRegistry")
+	ret0, _ := ret[0].([]api.RegistryApplication)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListApplicationsFromRegistry indicates an expected call of ListApplicationsFromRegistry.
+func (mr *MockRegistryClientMockRecorder) ListApplicationsFromRegistry() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListApplicationsFromRegistry", reflect.TypeOf((*MockRegistryClient)(nil).ListApplicationsFromRegistry))
+}
+
+// PullApplicationFromRegistry mocks base method.
+func (m *MockRegistryClient) PullApplicationFromRegistry(id string) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PullApplicationFromRegistry", id)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PullApplicationFromRegistry indicates an expected call of PullApplicationFromRegistry.
+func (mr *MockRegistryClientMockRecorder) PullApplicationFromRegistry(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PullApplicationFromRegistry", reflect.TypeOf((*MockRegistryClient)(nil).PullApplicationFromRegistry), id)
+}
+
+// PushApplicationToRegistry mocks base method.
+func (m *MockRegistryClient) PushApplicationToRegistry(id string) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PushApplicationToRegistry", id)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PushApplicationToRegistry indicates an expected call of PushApplicationToRegistry.
+func (mr *MockRegistryClientMockRecorder) PushApplicationToRegistry(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PushApplicationToRegistry", reflect.TypeOf((*MockRegistryClient)(nil).PushApplicationToRegistry), id)
+}
+
+// RetrieveRegistryConfig mocks base method.
+func (m *MockRegistryClient) RetrieveRegistryConfig() (*api.RegistryConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveRegistryConfig")
+	ret0, _ := ret[0].(*api.RegistryConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveRegistryConfig indicates an expected call of RetrieveRegistryConfig.
+func (mr *MockRegistryClientMockRecorder) RetrieveRegistryConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveRegistryConfig", reflect.TypeOf((*MockRegistryClient)(nil).RetrieveRegistryConfig))
+}
+
+// SyncApplicationsWithRegistry mocks base method.
+func (m *MockRegistryClient) SyncApplicationsWithRegistry(mode api.RegistryMode) ([]client.RegistrySyncResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncApplicationsWithRegistry", mode)
+	ret0, _ := ret[0].([]client.RegistrySyncResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SyncApplicationsWithRegistry indicates an expected call of SyncApplicationsWithRegistry.
+func (mr *MockRegistryClientMockRecorder) SyncApplicationsWithRegistry(mode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncApplicationsWithRegistry", reflect.TypeOf((*MockRegistryClient)(nil).SyncApplicationsWithRegistry), mode)
+}
+
+// UpdateRegistryConfig mocks base method.
+func (m *MockRegistryClient) UpdateRegistryConfig(patch *api.RegistryConfigPatch) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRegistryConfig", patch)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRegistryConfig indicates an expected call of UpdateRegistryConfig.
+func (mr *MockRegistryClientMockRecorder) UpdateRegistryConfig(patch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRegistryConfig", reflect.TypeOf((*MockRegistryClient)(nil).UpdateRegistryConfig), patch)
+}
+
+// MockOperationClient is a mock of OperationClient interface.
+type MockOperationClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockOperationClientMockRecorder
+}
+
+// MockOperationClientMockRecorder is the mock recorder for MockOperationClient.
+type MockOperationClientMockRecorder struct {
+	mock *MockOperationClient
+}
+
+// NewMockOperationClient creates a new mock instance.
+func NewMockOperationClient(ctrl *gomock.Controller) *MockOperationClient {
+	mock := &MockOperationClient{ctrl: ctrl}
+	mock.recorder = &MockOperationClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOperationClient) EXPECT() *MockOperationClientMockRecorder {
+	return m.recorder
+}
+
+// CancelOperation mocks base method.
+func (m *MockOperationClient) CancelOperation(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelOperation", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelOperation indicates an expected call of CancelOperation.
+func (mr *MockOperationClientMockRecorder) CancelOperation(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelOperation", reflect.TypeOf((*MockOperationClient)(nil).CancelOperation), id)
+}
+
+// ListOperations mocks base method.
+func (m *MockOperationClient) ListOperations() (map[string][]*api0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOperations")
+	ret0, _ := ret[0].(map[string][]*api0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOperations indicates an expected call of ListOperations.
+func (mr *MockOperationClientMockRecorder) ListOperations() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOperations", reflect.TypeOf((*MockOperationClient)(nil).ListOperations))
+}
+
+// ListOperationsWithFilter mocks base method.
+func (m *MockOperationClient) ListOperationsWithFilter(ctx context.Context, filter *client.OperationFilter) ([]api0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOperationsWithFilter", ctx, filter)
+	ret0, _ := ret[0].([]api0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOperationsWithFilter indicates an expected call of ListOperationsWithFilter.
+func (mr *MockOperationClientMockRecorder) ListOperationsWithFilter(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOperationsWithFilter", reflect.TypeOf((*MockOperationClient)(nil).ListOperationsWithFilter), ctx, filter)
+}
+
+// OperationStreams mocks base method.
+func (m *MockOperationClient) OperationStreams(op client0.Operation) (*client.OperationStreams, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OperationStreams", op)
+	ret0, _ := ret[0].(*client.OperationStreams)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OperationStreams indicates an expected call of OperationStreams.
+func (mr *MockOperationClientMockRecorder) OperationStreams(op interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OperationStreams", reflect.TypeOf((*MockOperationClient)(nil).OperationStreams), op)
+}
+
+// RetrieveOperationByID mocks base method.
+func (m *MockOperationClient) RetrieveOperationByID(id string) (*api0.Operation, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveOperationByID", id)
+	ret0, _ := ret[0].(*api0.Operation)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RetrieveOperationByID indicates an expected call of RetrieveOperationByID.
+func (mr *MockOperationClientMockRecorder) RetrieveOperationByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveOperationByID", reflect.TypeOf((*MockOperationClient)(nil).RetrieveOperationByID), id)
+}
+
+// ShowOperation mocks base method.
+func (m *MockOperationClient) ShowOperation(id string) (*api0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShowOperation", id)
+	ret0, _ := ret[0].(*api0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ShowOperation indicates an expected call of ShowOperation.
+func (mr *MockOperationClientMockRecorder) ShowOperation(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShowOperation", reflect.TypeOf((*MockOperationClient)(nil).ShowOperation), id)
+}
+
+// MockFleetClient is a mock of FleetClient interface.
+type MockFleetClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockFleetClientMockRecorder
+}
+
+// MockFleetClientMockRecorder is the mock recorder for MockFleetClient.
+type MockFleetClientMockRecorder struct {
+	mock *MockFleetClient
+}
+
+// NewMockFleetClient creates a new mock instance.
+func NewMockFleetClient(ctrl *gomock.Controller) *MockFleetClient {
+	mock := &MockFleetClient{ctrl: ctrl}
+	mock.recorder = &MockFleetClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFleetClient) EXPECT() *MockFleetClientMockRecorder {
+	return m.recorder
+}
+
+// ApplyFleetSpec mocks base method.
+func (m *MockFleetClient) ApplyFleetSpec(ctx context.Context, spec *client.FleetSpec, opts *client.FleetApplyOptions) ([]client.FleetChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyFleetSpec", ctx, spec, opts)
+	ret0, _ := ret[0].([]client.FleetChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyFleetSpec indicates an expected call of ApplyFleetSpec.
+func (mr *MockFleetClientMockRecorder) ApplyFleetSpec(ctx, spec, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyFleetSpec", reflect.TypeOf((*MockFleetClient)(nil).ApplyFleetSpec), ctx, spec, opts)
+}
+
+// EnsureApplication mocks base method.
+func (m *MockFleetClient) EnsureApplication(ctx context.Context, spec *client.ApplicationSpec, opts *client.FleetApplyOptions) ([]client.FleetChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureApplication", ctx, spec, opts)
+	ret0, _ := ret[0].([]client.FleetChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnsureApplication indicates an expected call of EnsureApplication.
+func (mr *MockFleetClientMockRecorder) EnsureApplication(ctx, spec, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureApplication", reflect.TypeOf((*MockFleetClient)(nil).EnsureApplication), ctx, spec, opts)
+}
+
+// EnsureContainerFleet mocks base method.
+func (m *MockFleetClient) EnsureContainerFleet(ctx context.Context, spec *client.ContainerFleetSpec, opts *client.FleetApplyOptions) ([]client.ContainerFleetChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureContainerFleet", ctx, spec, opts)
+	ret0, _ := ret[0].([]client.ContainerFleetChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnsureContainerFleet indicates an expected call of EnsureContainerFleet.
+func (mr *MockFleetClientMockRecorder) EnsureContainerFleet(ctx, spec, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureContainerFleet", reflect.TypeOf((*MockFleetClient)(nil).EnsureContainerFleet), ctx, spec, opts)
+}
+
+// ExportFleetSpec mocks base method.
+func (m *MockFleetClient) ExportFleetSpec(ctx context.Context) (*client.FleetSpec, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportFleetSpec", ctx)
+	ret0, _ := ret[0].(*client.FleetSpec)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportFleetSpec indicates an expected call of ExportFleetSpec.
+func (mr *MockFleetClientMockRecorder) ExportFleetSpec(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportFleetSpec", reflect.TypeOf((*MockFleetClient)(nil).ExportFleetSpec), ctx)
+}
+
+// MockBackupClient is a mock of BackupClient interface.
+type MockBackupClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockBackupClientMockRecorder
+}
+
+// MockBackupClientMockRecorder is the mock recorder for MockBackupClient.
+type MockBackupClientMockRecorder struct {
+	mock *MockBackupClient
+}
+
+// NewMockBackupClient creates a new mock instance.
+func NewMockBackupClient(ctrl *gomock.Controller) *MockBackupClient {
+	mock := &MockBackupClient{ctrl: ctrl}
+	mock.recorder = &MockBackupClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBackupClient) EXPECT() *MockBackupClientMockRecorder {
+	return m.recorder
+}
+
+// CreateInstanceBackup mocks base method.
+func (m *MockBackupClient) CreateInstanceBackup(id string, details *api.InstanceBackupsPost) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInstanceBackup", id, details)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateInstanceBackup indicates an expected call of CreateInstanceBackup.
+func (mr *MockBackupClientMockRecorder) CreateInstanceBackup(id, details interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInstanceBackup", reflect.TypeOf((*MockBackupClient)(nil).CreateInstanceBackup), id, details)
+}
+
+// DeleteInstanceBackup mocks base method.
+func (m *MockBackupClient) DeleteInstanceBackup(id, name string) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteInstanceBackup", id, name)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteInstanceBackup indicates an expected call of DeleteInstanceBackup.
+func (mr *MockBackupClientMockRecorder) DeleteInstanceBackup(id, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteInstanceBackup", reflect.TypeOf((*MockBackupClient)(nil).DeleteInstanceBackup), id, name)
+}
+
+// ExportInstanceBackup mocks base method.
+func (m *MockBackupClient) ExportInstanceBackup(id, name string, downloader func(*http.Header, io.ReadCloser) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportInstanceBackup", id, name, downloader)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportInstanceBackup indicates an expected call of ExportInstanceBackup.
+func (mr *MockBackupClientMockRecorder) ExportInstanceBackup(id, name, downloader interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportInstanceBackup", reflect.TypeOf((*MockBackupClient)(nil).ExportInstanceBackup), id, name, downloader)
+}
+
+// ImportInstanceBackup mocks base method.
+func (m *MockBackupClient) ImportInstanceBackup(payload io.ReadSeeker, sentBytes chan float64, opts ...client.RequestOption) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{payload, sentBytes}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ImportInstanceBackup", varargs...)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportInstanceBackup indicates an expected call of ImportInstanceBackup.
+func (mr *MockBackupClientMockRecorder) ImportInstanceBackup(payload, sentBytes interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{payload, sentBytes}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportInstanceBackup", reflect.TypeOf((*MockBackupClient)(nil).ImportInstanceBackup), varargs...)
+}
+
+// ListInstanceBackups mocks base method.
+func (m *MockBackupClient) ListInstanceBackups(id string) ([]api.InstanceBackup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInstanceBackups", id)
+	ret0, _ := ret[0].([]api.InstanceBackup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInstanceBackups indicates an expected call of ListInstanceBackups.
+func (mr *MockBackupClientMockRecorder) ListInstanceBackups(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInstanceBackups", reflect.TypeOf((*MockBackupClient)(nil).ListInstanceBackups), id)
+}
+
+// RestoreInstanceBackup mocks base method.
+func (m *MockBackupClient) RestoreInstanceBackup(id, name string, details *api.InstanceBackupRestorePost) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreInstanceBackup", id, name, details)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreInstanceBackup indicates an expected call of RestoreInstanceBackup.
+func (mr *MockBackupClientMockRecorder) RestoreInstanceBackup(id, name, details interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreInstanceBackup", reflect.TypeOf((*MockBackupClient)(nil).RestoreInstanceBackup), id, name, details)
+}
+
+// RetrieveInstanceBackup mocks base method.
+func (m *MockBackupClient) RetrieveInstanceBackup(id, name string) (*api.InstanceBackup, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveInstanceBackup", id, name)
+	ret0, _ := ret[0].(*api.InstanceBackup)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RetrieveInstanceBackup indicates an expected call of RetrieveInstanceBackup.
+func (mr *MockBackupClientMockRecorder) RetrieveInstanceBackup(id, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveInstanceBackup", reflect.TypeOf((*MockBackupClient)(nil).RetrieveInstanceBackup), id, name)
+}
+
+// MockClient is a mock of Client interface.
+type MockClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientMockRecorder
+}
+
+// MockClientMockRecorder is the mock recorder for MockClient.
+type MockClientMockRecorder struct {
+	mock *MockClient
+}
+
+// NewMockClient creates a new mock instance.
+func NewMockClient(ctrl *gomock.Controller) *MockClient {
+	mock := &MockClient{ctrl: ctrl}
+	mock.recorder = &MockClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClient) EXPECT() *MockClientMockRecorder {
+	return m.recorder
+}
+
+// AddAddon mocks base method.
+func (m *MockClient) AddAddon(name, packagePath string, sentBytes chan float64, opts ...client.RequestOption) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{name, packagePath, sentBytes}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AddAddon", varargs...)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddAddon indicates an expected call of AddAddon.
+func (mr *MockClientMockRecorder) AddAddon(name, packagePath, sentBytes interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{name, packagePath, sentBytes}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAddon", reflect.TypeOf((*MockClient)(nil).AddAddon), varargs...)
+}
+
+// AddCertificate mocks base method.
+func (m *MockClient) AddCertificate(details *api0.CertificatesPost) (*api0.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCertificate", details)
+	ret0, _ := ret[0].(*api0.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddCertificate indicates an expected call of AddCertificate.
+func (mr *MockClientMockRecorder) AddCertificate(details interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCertificate", reflect.TypeOf((*MockClient)(nil).AddCertificate), details)
+}
+
+// AddImage mocks base method.
+func (m *MockClient) AddImage(name, packagePath string, isDefault bool, sentBytes chan float64, opts ...client.RequestOption) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{name, packagePath, isDefault, sentBytes}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AddImage", varargs...)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddImage indicates an expected call of AddImage.
+func (mr *MockClientMockRecorder) AddImage(name, packagePath, isDefault, sentBytes interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{name, packagePath, isDefault, sentBytes}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddImage", reflect.TypeOf((*MockClient)(nil).AddImage), varargs...)
+}
+
+// AddNode mocks base method.
+func (m *MockClient) AddNode(node *api.NodesPost) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddNode", node)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddNode indicates an expected call of AddNode.
+func (mr *MockClientMockRecorder) AddNode(node interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddNode", reflect.TypeOf((*MockClient)(nil).AddNode), node)
+}
+
+// ApplyFleetSpec mocks base method.
+func (m *MockClient) ApplyFleetSpec(ctx context.Context, spec *client.FleetSpec, opts *client.FleetApplyOptions) ([]client.FleetChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyFleetSpec", ctx, spec, opts)
+	ret0, _ := ret[0].([]client.FleetChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyFleetSpec indicates an expected call of ApplyFleetSpec.
+func (mr *MockClientMockRecorder) ApplyFleetSpec(ctx, spec, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyFleetSpec", reflect.TypeOf((*MockClient)(nil).ApplyFleetSpec), ctx, spec, opts)
+}
+
+// AttachConsole mocks base method.
+func (m *MockClient) AttachConsole(id string, stdin io.ReadCloser, stdout io.WriteCloser, resize <-chan client.ConsoleSize) (*client.Console, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachConsole", id, stdin, stdout, resize)
+	ret0, _ := ret[0].(*client.Console)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AttachConsole indicates an expected call of AttachConsole.
+func (mr *MockClientMockRecorder) AttachConsole(id, stdin, stdout, resize interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachConsole", reflect.TypeOf((*MockClient)(nil).AttachConsole), id, stdin, stdout, resize)
+}
+
+// CancelOperation mocks base method.
+func (m *MockClient) CancelOperation(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelOperation", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelOperation indicates an expected call of CancelOperation.
+func (mr *MockClientMockRecorder) CancelOperation(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelOperation", reflect.TypeOf((*MockClient)(nil).CancelOperation), id)
+}
+
+// ClockSkew mocks base method.
+func (m *MockClient) ClockSkew() client0.ClockSkew {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClockSkew")
+	ret0, _ := ret[0].(client0.ClockSkew)
+	return ret0
+}
+
+// ClockSkew indicates an expected call of ClockSkew.
+func (mr *MockClientMockRecorder) ClockSkew() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClockSkew", reflect.TypeOf((*MockClient)(nil).ClockSkew))
+}
+
+// CloseIdleStreams mocks base method.
+func (m *MockClient) CloseIdleStreams(olderThan time.Duration) int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseIdleStreams", olderThan)
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// CloseIdleStreams indicates an expected call of CloseIdleStreams.
+func (mr *MockClientMockRecorder) CloseIdleStreams(olderThan interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseIdleStreams", reflect.TypeOf((*MockClient)(nil).CloseIdleStreams), olderThan)
+}
+
+// ConnectionInfo mocks base method.
+func (m *MockClient) ConnectionInfo() *client0.ConnectionInfo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConnectionInfo")
+	ret0, _ := ret[0].(*client0.ConnectionInfo)
+	return ret0
+}
+
+// ConnectionInfo indicates an expected call of ConnectionInfo.
+func (mr *MockClientMockRecorder) ConnectionInfo() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConnectionInfo", reflect.TypeOf((*MockClient)(nil).ConnectionInfo))
+}
+
+// CreateAddon mocks base method.
+func (m *MockClient) CreateAddon(name string, payload io.ReadSeeker, sentBytes chan float64, opts ...client.RequestOption) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{name, payload, sentBytes}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateAddon", varargs...)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAddon indicates an expected call of CreateAddon.
+func (mr *MockClientMockRecorder) CreateAddon(name, payload, sentBytes interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{name, payload, sentBytes}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAddon", reflect.TypeOf((*MockClient)(nil).CreateAddon), varargs...)
+}
+
+// CreateApplication mocks base method.
+func (m *MockClient) CreateApplication(packagePath string, sentBytes chan float64, opts ...client.RequestOption) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{packagePath, sentBytes}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateApplication", varargs...)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateApplication indicates an expected call of CreateApplication.
+func (mr *MockClientMockRecorder) CreateApplication(packagePath, sentBytes interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{packagePath, sentBytes}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateApplication", reflect.TypeOf((*MockClient)(nil).CreateApplication), varargs...)
+}
+
+// CreateApplicationWithArgs mocks base method.
+func (m *MockClient) CreateApplicationWithArgs(args *client.ApplicationCreateArgs, opts ...client.RequestOption) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{args}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateApplicationWithArgs", varargs...)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateApplicationWithArgs indicates an expected call of CreateApplicationWithArgs.
+func (mr *MockClientMockRecorder) CreateApplicationWithArgs(args interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{args}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateApplicationWithArgs", reflect.TypeOf((*MockClient)(nil).CreateApplicationWithArgs), varargs...)
+}
+
+// CreateImage mocks base method.
+func (m *MockClient) CreateImage(name string, payload io.ReadSeeker, isDefault bool, sentBytes chan float64, opts ...client.RequestOption) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{name, payload, isDefault, sentBytes}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateImage", varargs...)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateImage indicates an expected call of CreateImage.
+func (mr *MockClientMockRecorder) CreateImage(name, payload, isDefault, sentBytes interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{name, payload, isDefault, sentBytes}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateImage", reflect.TypeOf((*MockClient)(nil).CreateImage), varargs...)
+}
+
+// CreateInstanceBackup mocks base method.
+func (m *MockClient) CreateInstanceBackup(id string, details *api.InstanceBackupsPost) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInstanceBackup", id, details)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateInstanceBackup indicates an expected call of CreateInstanceBackup.
+func (mr *MockClientMockRecorder) CreateInstanceBackup(id, details interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInstanceBackup", reflect.TypeOf((*MockClient)(nil).CreateInstanceBackup), id, details)
+}
+
+// DeleteAddon mocks base method.
+func (m *MockClient) DeleteAddon(name string) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAddon", name)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAddon indicates an expected call of DeleteAddon.
+func (mr *MockClientMockRecorder) DeleteAddon(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAddon", reflect.TypeOf((*MockClient)(nil).DeleteAddon), name)
+}
+
+// DeleteAddonVersion mocks base method.
+func (m *MockClient) DeleteAddonVersion(name string, version int) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAddonVersion", name, version)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAddonVersion indicates an expected call of DeleteAddonVersion.
+func (mr *MockClientMockRecorder) DeleteAddonVersion(name, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAddonVersion", reflect.TypeOf((*MockClient)(nil).DeleteAddonVersion), name, version)
+}
+
+// DeleteApplicationByID mocks base method.
+func (m *MockClient) DeleteApplicationByID(id string, force bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteApplicationByID", id, force)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteApplicationByID indicates an expected call of DeleteApplicationByID.
+func (mr *MockClientMockRecorder) DeleteApplicationByID(id, force interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteApplicationByID", reflect.TypeOf((*MockClient)(nil).DeleteApplicationByID), id, force)
+}
+
+// DeleteApplicationFromRegistry mocks base method.
+func (m *MockClient) DeleteApplicationFromRegistry(id string) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteApplicationFromRegistry", id)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteApplicationFromRegistry indicates an expected call of DeleteApplicationFromRegistry.
+func (mr *MockClientMockRecorder) DeleteApplicationFromRegistry(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteApplicationFromRegistry", reflect.TypeOf((*MockClient)(nil).DeleteApplicationFromRegistry), id)
+}
+
+// DeleteApplicationVersion mocks base method.
+func (m *MockClient) DeleteApplicationVersion(id string, version int, force bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteApplicationVersion", id, version, force)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteApplicationVersion indicates an expected call of DeleteApplicationVersion.
+func (mr *MockClientMockRecorder) DeleteApplicationVersion(id, version, force interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteApplicationVersion", reflect.TypeOf((*MockClient)(nil).DeleteApplicationVersion), id, version, force)
+}
+
+// DeleteApplications mocks base method.
+func (m *MockClient) DeleteApplications(ids []string, force bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteApplications", ids, force)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteApplications indicates an expected call of DeleteApplications.
+func (mr *MockClientMockRecorder) DeleteApplications(ids, force interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteApplications", reflect.TypeOf((*MockClient)(nil).DeleteApplications), ids, force)
+}
+
+// DeleteCertificate mocks base method.
+func (m *MockClient) DeleteCertificate(fingerprint string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCertificate", fingerprint)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCertificate indicates an expected call of DeleteCertificate.
+func (mr *MockClientMockRecorder) DeleteCertificate(fingerprint interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCertificate", reflect.TypeOf((*MockClient)(nil).DeleteCertificate), fingerprint)
+}
+
+// DeleteContainerByID mocks base method.
+func (m *MockClient) DeleteContainerByID(id string, force bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteContainerByID", id, force)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteContainerByID indicates an expected call of DeleteContainerByID.
+func (mr *MockClientMockRecorder) DeleteContainerByID(id, force interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteContainerByID", reflect.TypeOf((*MockClient)(nil).DeleteContainerByID), id, force)
+}
+
+// DeleteContainers mocks base method.
+func (m *MockClient) DeleteContainers(ids []string, force bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteContainers", ids, force)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteContainers indicates an expected call of DeleteContainers.
+func (mr *MockClientMockRecorder) DeleteContainers(ids, force interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteContainers", reflect.TypeOf((*MockClient)(nil).DeleteContainers), ids, force)
+}
+
+// DeleteImageByIDOrName mocks base method.
+func (m *MockClient) DeleteImageByIDOrName(id string, force bool, imgType api.ImageType) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteImageByIDOrName", id, force, imgType)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteImageByIDOrName indicates an expected call of DeleteImageByIDOrName.
+func (mr *MockClientMockRecorder) DeleteImageByIDOrName(id, force, imgType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteImageByIDOrName", reflect.TypeOf((*MockClient)(nil).DeleteImageByIDOrName), id, force, imgType)
+}
+
+// DeleteImageVersion mocks base method.
+func (m *MockClient) DeleteImageVersion(id string, version int) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteImageVersion", id, version)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteImageVersion indicates an expected call of DeleteImageVersion.
+func (mr *MockClientMockRecorder) DeleteImageVersion(id, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteImageVersion", reflect.TypeOf((*MockClient)(nil).DeleteImageVersion), id, version)
+}
+
+// DeleteInstanceBackup mocks base method.
+func (m *MockClient) DeleteInstanceBackup(id, name string) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteInstanceBackup", id, name)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteInstanceBackup indicates an expected call of DeleteInstanceBackup.
+func (mr *MockClientMockRecorder) DeleteInstanceBackup(id, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteInstanceBackup", reflect.TypeOf((*MockClient)(nil).DeleteInstanceBackup), id, name)
+}
+
+// DeleteInstanceByID mocks base method.
+func (m *MockClient) DeleteInstanceByID(id string, force bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteInstanceByID", id, force)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteInstanceByID indicates an expected call of DeleteInstanceByID.
+func (mr *MockClientMockRecorder) DeleteInstanceByID(id, force interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteInstanceByID", reflect.TypeOf((*MockClient)(nil).DeleteInstanceByID), id, force)
+}
+
+// DeleteInstances mocks base method.
+func (m *MockClient) DeleteInstances(ids []string, force bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteInstances", ids, force)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteInstances indicates an expected call of DeleteInstances.
+func (mr *MockClientMockRecorder) DeleteInstances(ids, force interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteInstances", reflect.TypeOf((*MockClient)(nil).DeleteInstances), ids, force)
+}
+
+// EnsureApplication mocks base method.
+func (m *MockClient) EnsureApplication(ctx context.Context, spec *client.ApplicationSpec, opts *client.FleetApplyOptions) ([]client.FleetChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureApplication", ctx, spec, opts)
+	ret0, _ := ret[0].([]client.FleetChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnsureApplication indicates an expected call of EnsureApplication.
+func (mr *MockClientMockRecorder) EnsureApplication(ctx, spec, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureApplication", reflect.TypeOf((*MockClient)(nil).EnsureApplication), ctx, spec, opts)
+}
+
+// EnsureContainerFleet mocks base method.
+func (m *MockClient) EnsureContainerFleet(ctx context.Context, spec *client.ContainerFleetSpec, opts *client.FleetApplyOptions) ([]client.ContainerFleetChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureContainerFleet", ctx, spec, opts)
+	ret0, _ := ret[0].([]client.ContainerFleetChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnsureContainerFleet indicates an expected call of EnsureContainerFleet.
+func (mr *MockClientMockRecorder) EnsureContainerFleet(ctx, spec, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureContainerFleet", reflect.TypeOf((*MockClient)(nil).EnsureContainerFleet), ctx, spec, opts)
+}
+
+// ExecuteContainer mocks base method.
+func (m *MockClient) ExecuteContainer(id string, details *api.ContainerExecPost, args *client.ContainerExecArgs) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteContainer", id, details, args)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecuteContainer indicates an expected call of ExecuteContainer.
+func (mr *MockClientMockRecorder) ExecuteContainer(id, details, args interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteContainer", reflect.TypeOf((*MockClient)(nil).ExecuteContainer), id, details, args)
+}
+
+// ExecuteInstance mocks base method.
+func (m *MockClient) ExecuteInstance(id string, details *api.InstanceExecPost, args *client.InstanceExecArgs) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteInstance", id, details, args)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecuteInstance indicates an expected call of ExecuteInstance.
+func (mr *MockClientMockRecorder) ExecuteInstance(id, details, args interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteInstance", reflect.TypeOf((*MockClient)(nil).ExecuteInstance), id, details, args)
+}
+
+// ExportApplicationByVersion mocks base method.
+func (m *MockClient) ExportApplicationByVersion(id string, version int, downloader func(*http.Header, io.ReadCloser) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportApplicationByVersion", id, version, downloader)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportApplicationByVersion indicates an expected call of ExportApplicationByVersion.
+func (mr *MockClientMockRecorder) ExportApplicationByVersion(id, version, downloader interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportApplicationByVersion", reflect.TypeOf((*MockClient)(nil).ExportApplicationByVersion), id, version, downloader)
+}
+
+// ExportFleetSpec mocks base method.
+func (m *MockClient) ExportFleetSpec(ctx context.Context) (*client.FleetSpec, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportFleetSpec", ctx)
+	ret0, _ := ret[0].(*client.FleetSpec)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportFleetSpec indicates an expected call of ExportFleetSpec.
+func (mr *MockClientMockRecorder) ExportFleetSpec(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportFleetSpec", reflect.TypeOf((*MockClient)(nil).ExportFleetSpec), ctx)
+}
+
+// ExportInstanceBackup mocks base method.
+func (m *MockClient) ExportInstanceBackup(id, name string, downloader func(*http.Header, io.ReadCloser) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportInstanceBackup", id, name, downloader)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportInstanceBackup indicates an expected call of ExportInstanceBackup.
+func (mr *MockClientMockRecorder) ExportInstanceBackup(id, name, downloader interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportInstanceBackup", reflect.TypeOf((*MockClient)(nil).ExportInstanceBackup), id, name, downloader)
+}
+
+// Extensions mocks base method.
+func (m *MockClient) Extensions() ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Extensions")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Extensions indicates an expected call of Extensions.
+func (mr *MockClientMockRecorder) Extensions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Extensions", reflect.TypeOf((*MockClient)(nil).Extensions))
+}
+
+// FindApplicationsByName mocks base method.
+func (m *MockClient) FindApplicationsByName(pattern string) ([]api.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindApplicationsByName", pattern)
+	ret0, _ := ret[0].([]api.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindApplicationsByName indicates an expected call of FindApplicationsByName.
+func (mr *MockClientMockRecorder) FindApplicationsByName(pattern interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindApplicationsByName", reflect.TypeOf((*MockClient)(nil).FindApplicationsByName), pattern)
+}
+
+// FollowContainerLog mocks base method.
+func (m *MockClient) FollowContainerLog(ctx context.Context, id, name string, w io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FollowContainerLog", ctx, id, name, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FollowContainerLog indicates an expected call of FollowContainerLog.
+func (mr *MockClientMockRecorder) FollowContainerLog(ctx, id, name, w interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FollowContainerLog", reflect.TypeOf((*MockClient)(nil).FollowContainerLog), ctx, id, name, w)
+}
+
+// FollowInstanceLog mocks base method.
+func (m *MockClient) FollowInstanceLog(ctx context.Context, id, name string, w io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FollowInstanceLog", ctx, id, name, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FollowInstanceLog indicates an expected call of FollowInstanceLog.
+func (mr *MockClientMockRecorder) FollowInstanceLog(ctx, id, name, w interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FollowInstanceLog", reflect.TypeOf((*MockClient)(nil).FollowInstanceLog), ctx, id, name, w)
+}
+
+// ForwardPort mocks base method.
+func (m *MockClient) ForwardPort(ctx context.Context, instanceID, localAddr string, remotePort int) (*client.PortForward, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForwardPort", ctx, instanceID, localAddr, remotePort)
+	ret0, _ := ret[0].(*client.PortForward)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ForwardPort indicates an expected call of ForwardPort.
+func (mr *MockClientMockRecorder) ForwardPort(ctx, instanceID, localAddr, remotePort interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForwardPort", reflect.TypeOf((*MockClient)(nil).ForwardPort), ctx, instanceID, localAddr, remotePort)
+}
+
+// GetEvents mocks base method.
+func (m *MockClient) GetEvents() (*client0.EventListener, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEvents")
+	ret0, _ := ret[0].(*client0.EventListener)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEvents indicates an expected call of GetEvents.
+func (mr *MockClientMockRecorder) GetEvents() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvents", reflect.TypeOf((*MockClient)(nil).GetEvents))
+}
+
+// GetVersion mocks base method.
+func (m *MockClient) GetVersion() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVersion")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVersion indicates an expected call of GetVersion.
+func (mr *MockClientMockRecorder) GetVersion() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVersion", reflect.TypeOf((*MockClient)(nil).GetVersion))
+}
+
+// HasExtension mocks base method.
+func (m *MockClient) HasExtension(name string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasExtension", name)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasExtension indicates an expected call of HasExtension.
+func (mr *MockClientMockRecorder) HasExtension(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasExtension", reflect.TypeOf((*MockClient)(nil).HasExtension), name)
+}
+
+// Health mocks base method.
+func (m *MockClient) Health() client0.Health {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Health")
+	ret0, _ := ret[0].(client0.Health)
+	return ret0
+}
+
+// Health indicates an expected call of Health.
+func (mr *MockClientMockRecorder) Health() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Health", reflect.TypeOf((*MockClient)(nil).Health))
+}
+
+// ImportImage mocks base method.
+func (m *MockClient) ImportImage(name, path string, isDefault bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportImage", name, path, isDefault)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportImage indicates an expected call of ImportImage.
+func (mr *MockClientMockRecorder) ImportImage(name, path, isDefault interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportImage", reflect.TypeOf((*MockClient)(nil).ImportImage), name, path, isDefault)
+}
+
+// ImportImageByType mocks base method.
+func (m *MockClient) ImportImageByType(name, path string, imgType api.ImageType, isDefault bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportImageByType", name, path, imgType, isDefault)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportImageByType indicates an expected call of ImportImageByType.
+func (mr *MockClientMockRecorder) ImportImageByType(name, path, imgType, isDefault interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportImageByType", reflect.TypeOf((*MockClient)(nil).ImportImageByType), name, path, imgType, isDefault)
+}
+
+// ImportInstanceBackup mocks base method.
+func (m *MockClient) ImportInstanceBackup(payload io.ReadSeeker, sentBytes chan float64, opts ...client.RequestOption) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{payload, sentBytes}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ImportInstanceBackup", varargs...)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportInstanceBackup indicates an expected call of ImportInstanceBackup.
+func (mr *MockClientMockRecorder) ImportInstanceBackup(payload, sentBytes interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{payload, sentBytes}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportInstanceBackup", reflect.TypeOf((*MockClient)(nil).ImportInstanceBackup), varargs...)
+}
+
+// LaunchContainer mocks base method.
+func (m *MockClient) LaunchContainer(details *api.ContainersPost, noWait bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LaunchContainer", details, noWait)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LaunchContainer indicates an expected call of LaunchContainer.
+func (mr *MockClientMockRecorder) LaunchContainer(details, noWait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LaunchContainer", reflect.TypeOf((*MockClient)(nil).LaunchContainer), details, noWait)
+}
+
+// LaunchContainerWithPlacement mocks base method.
+func (m *MockClient) LaunchContainerWithPlacement(details *api.ContainersPost, rules *client.PlacementRules, noWait bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LaunchContainerWithPlacement", details, rules, noWait)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LaunchContainerWithPlacement indicates an expected call of LaunchContainerWithPlacement.
+func (mr *MockClientMockRecorder) LaunchContainerWithPlacement(details, rules, noWait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LaunchContainerWithPlacement", reflect.TypeOf((*MockClient)(nil).LaunchContainerWithPlacement), details, rules, noWait)
+}
+
+// LaunchContainers mocks base method.
+func (m *MockClient) LaunchContainers(details *api.ContainersPost, count int, noWait bool) ([]client.LaunchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LaunchContainers", details, count, noWait)
+	ret0, _ := ret[0].([]client.LaunchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LaunchContainers indicates an expected call of LaunchContainers.
+func (mr *MockClientMockRecorder) LaunchContainers(details, count, noWait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LaunchContainers", reflect.TypeOf((*MockClient)(nil).LaunchContainers), details, count, noWait)
+}
+
+// LaunchInstance mocks base method.
+func (m *MockClient) LaunchInstance(details *api.InstancesPost, noWait bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LaunchInstance", details, noWait)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LaunchInstance indicates an expected call of LaunchInstance.
+func (mr *MockClientMockRecorder) LaunchInstance(details, noWait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LaunchInstance", reflect.TypeOf((*MockClient)(nil).LaunchInstance), details, noWait)
+}
+
+// LaunchInstanceWithPlacement mocks base method.
+func (m *MockClient) LaunchInstanceWithPlacement(details *api.InstancesPost, rules *client.PlacementRules, noWait bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LaunchInstanceWithPlacement", details, rules, noWait)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LaunchInstanceWithPlacement indicates an expected call of LaunchInstanceWithPlacement.
+func (mr *MockClientMockRecorder) LaunchInstanceWithPlacement(details, rules, noWait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LaunchInstanceWithPlacement", reflect.TypeOf((*MockClient)(nil).LaunchInstanceWithPlacement), details, rules, noWait)
+}
+
+// LaunchInstances mocks base method.
+func (m *MockClient) LaunchInstances(details *api.InstancesPost, count int, noWait bool) ([]client.LaunchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LaunchInstances", details, count, noWait)
+	ret0, _ := ret[0].([]client.LaunchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LaunchInstances indicates an expected call of LaunchInstances.
+func (mr *MockClientMockRecorder) LaunchInstances(details, count, noWait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LaunchInstances", reflect.TypeOf((*MockClient)(nil).LaunchInstances), details, count, noWait)
+}
+
+// ListAddonVersions mocks base method.
+func (m *MockClient) ListAddonVersions(name string) ([]api.AddonVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAddonVersions", name)
+	ret0, _ := ret[0].([]api.AddonVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAddonVersions indicates an expected call of ListAddonVersions.
+func (mr *MockClientMockRecorder) ListAddonVersions(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAddonVersions", reflect.TypeOf((*MockClient)(nil).ListAddonVersions), name)
+}
+
+// ListAddons mocks base method.
+func (m *MockClient) ListAddons() ([]api.Addon, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAddons")
+	ret0, _ := ret[0].([]api.Addon)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAddons indicates an expected call of ListAddons.
+func (mr *MockClientMockRecorder) ListAddons() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAddons", reflect.TypeOf((*MockClient)(nil).ListAddons))
+}
+
+// ListApplications mocks base method.
+func (m *MockClient) ListApplications() ([]api.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListApplications")
+	ret0, _ := ret[0].([]api.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListApplications indicates an expected call of ListApplications.
+func (mr *MockClientMockRecorder) ListApplications() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListApplications", reflect.TypeOf((*MockClient)(nil).ListApplications))
+}
+
+// ListApplicationsFromRegistry mocks base method.
+func (m *MockClient) ListApplicationsFromRegistry() ([]api.RegistryApplication, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListApplicationsFromRegistry")
+	ret0, _ := ret[0].([]api.RegistryApplication)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListApplicationsFromRegistry indicates an expected call of ListApplicationsFromRegistry.
+func (mr *MockClientMockRecorder) ListApplicationsFromRegistry() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListApplicationsFromRegistry", reflect.TypeOf((*MockClient)(nil).ListApplicationsFromRegistry))
+}
+
+// ListApplicationsWithFilters mocks base method.
+func (m *MockClient) ListApplicationsWithFilters(filters []string) ([]api.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListApplicationsWithFilters", filters)
+	ret0, _ := ret[0].([]api.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListApplicationsWithFilters indicates an expected call of ListApplicationsWithFilters.
+func (mr *MockClientMockRecorder) ListApplicationsWithFilters(filters interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListApplicationsWithFilters", reflect.TypeOf((*MockClient)(nil).ListApplicationsWithFilters), filters)
+}
+
+// ListCertificates mocks base method.
+func (m *MockClient) ListCertificates() ([]api0.Certificate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCertificates")
+	ret0, _ := ret[0].([]api0.Certificate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCertificates indicates an expected call of ListCertificates.
+func (mr *MockClientMockRecorder) ListCertificates() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCertificates", reflect.TypeOf((*MockClient)(nil).ListCertificates))
+}
+
+// ListClusterServices mocks base method.
+func (m *MockClient) ListClusterServices() ([]api.ClusterService, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListClusterServices")
+	ret0, _ := ret[0].([]api.ClusterService)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListClusterServices indicates an expected call of ListClusterServices.
+func (mr *MockClientMockRecorder) ListClusterServices() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClusterServices", reflect.TypeOf((*MockClient)(nil).ListClusterServices))
+}
+
+// ListContainers mocks base method.
+func (m *MockClient) ListContainers() ([]api.Container, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListContainers")
+	ret0, _ := ret[0].([]api.Container)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListContainers indicates an expected call of ListContainers.
+func (mr *MockClientMockRecorder) ListContainers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListContainers", reflect.TypeOf((*MockClient)(nil).ListContainers))
+}
+
+// ListContainersWithFilters mocks base method.
+func (m *MockClient) ListContainersWithFilters(filters []string) ([]api.Container, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListContainersWithFilters", filters)
+	ret0, _ := ret[0].([]api.Container)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListContainersWithFilters indicates an expected call of ListContainersWithFilters.
+func (mr *MockClientMockRecorder) ListContainersWithFilters(filters interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListContainersWithFilters", reflect.TypeOf((*MockClient)(nil).ListContainersWithFilters), filters)
+}
+
+// ListImageNodeStatus mocks base method.
+func (m *MockClient) ListImageNodeStatus(id string) ([]api.ImageNodeStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListImageNodeStatus", id)
+	ret0, _ := ret[0].([]api.ImageNodeStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListImageNodeStatus indicates an expected call of ListImageNodeStatus.
+func (mr *MockClientMockRecorder) ListImageNodeStatus(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListImageNodeStatus", reflect.TypeOf((*MockClient)(nil).ListImageNodeStatus), id)
+}
+
+// ListImages mocks base method.
+func (m *MockClient) ListImages() ([]api.Image, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListImages")
+	ret0, _ := ret[0].([]api.Image)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListImages indicates an expected call of ListImages.
+func (mr *MockClientMockRecorder) ListImages() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListImages", reflect.TypeOf((*MockClient)(nil).ListImages))
+}
+
+// ListInstanceBackups mocks base method.
+func (m *MockClient) ListInstanceBackups(id string) ([]api.InstanceBackup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInstanceBackups", id)
+	ret0, _ := ret[0].([]api.InstanceBackup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInstanceBackups indicates an expected call of ListInstanceBackups.
+func (mr *MockClientMockRecorder) ListInstanceBackups(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInstanceBackups", reflect.TypeOf((*MockClient)(nil).ListInstanceBackups), id)
+}
+
+// ListInstances mocks base method.
+func (m *MockClient) ListInstances() ([]api.Instance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInstances")
+	ret0, _ := ret[0].([]api.Instance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInstances indicates an expected call of ListInstances.
+func (mr *MockClientMockRecorder) ListInstances() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInstances", reflect.TypeOf((*MockClient)(nil).ListInstances))
+}
+
+// ListInstancesWithFilters mocks base method.
+func (m *MockClient) ListInstancesWithFilters(filters []string) ([]api.Instance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInstancesWithFilters", filters)
+	ret0, _ := ret[0].([]api.Instance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInstancesWithFilters indicates an expected call of ListInstancesWithFilters.
+func (mr *MockClientMockRecorder) ListInstancesWithFilters(filters interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInstancesWithFilters", reflect.TypeOf((*MockClient)(nil).ListInstancesWithFilters), filters)
+}
+
+// ListNodes mocks base method.
+func (m *MockClient) ListNodes() ([]api.Node, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNodes")
+	ret0, _ := ret[0].([]api.Node)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNodes indicates an expected call of ListNodes.
+func (mr *MockClientMockRecorder) ListNodes() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNodes", reflect.TypeOf((*MockClient)(nil).ListNodes))
+}
+
+// ListOperations mocks base method.
+func (m *MockClient) ListOperations() (map[string][]*api0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOperations")
+	ret0, _ := ret[0].(map[string][]*api0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOperations indicates an expected call of ListOperations.
+func (mr *MockClientMockRecorder) ListOperations() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOperations", reflect.TypeOf((*MockClient)(nil).ListOperations))
+}
+
+// ListOperationsWithFilter mocks base method.
+func (m *MockClient) ListOperationsWithFilter(ctx context.Context, filter *client.OperationFilter) ([]api0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOperationsWithFilter", ctx, filter)
+	ret0, _ := ret[0].([]api0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOperationsWithFilter indicates an expected call of ListOperationsWithFilter.
+func (mr *MockClientMockRecorder) ListOperationsWithFilter(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOperationsWithFilter", reflect.TypeOf((*MockClient)(nil).ListOperationsWithFilter), ctx, filter)
+}
+
+// ListTasks mocks base method.
+func (m *MockClient) ListTasks() ([]api.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTasks")
+	ret0, _ := ret[0].([]api.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTasks indicates an expected call of ListTasks.
+func (mr *MockClientMockRecorder) ListTasks() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTasks", reflect.TypeOf((*MockClient)(nil).ListTasks))
+}
+
+// ModifyContainer mocks base method.
+func (m *MockClient) ModifyContainer(id string, modify func(*api.Container) (*api.ContainerPatch, error), noWait bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModifyContainer", id, modify, noWait)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ModifyContainer indicates an expected call of ModifyContainer.
+func (mr *MockClientMockRecorder) ModifyContainer(id, modify, noWait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModifyContainer", reflect.TypeOf((*MockClient)(nil).ModifyContainer), id, modify, noWait)
+}
+
+// ModifyInstance mocks base method.
+func (m *MockClient) ModifyInstance(id string, modify func(*api.Instance) (*api.InstancePatch, error), noWait bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModifyInstance", id, modify, noWait)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ModifyInstance indicates an expected call of ModifyInstance.
+func (mr *MockClientMockRecorder) ModifyInstance(id, modify, noWait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModifyInstance", reflect.TypeOf((*MockClient)(nil).ModifyInstance), id, modify, noWait)
+}
+
+// OpenContainerLog mocks base method.
+func (m *MockClient) OpenContainerLog(id, name string) (io.ReadSeeker, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenContainerLog", id, name)
+	ret0, _ := ret[0].(io.ReadSeeker)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenContainerLog indicates an expected call of OpenContainerLog.
+func (mr *MockClientMockRecorder) OpenContainerLog(id, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenContainerLog", reflect.TypeOf((*MockClient)(nil).OpenContainerLog), id, name)
+}
+
+// OpenInstanceLog mocks base method.
+func (m *MockClient) OpenInstanceLog(id, name string) (io.ReadSeeker, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenInstanceLog", id, name)
+	ret0, _ := ret[0].(io.ReadSeeker)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenInstanceLog indicates an expected call of OpenInstanceLog.
+func (mr *MockClientMockRecorder) OpenInstanceLog(id, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenInstanceLog", reflect.TypeOf((*MockClient)(nil).OpenInstanceLog), id, name)
+}
+
+// OpenStreams mocks base method.
+func (m *MockClient) OpenStreams() []client0.StreamInfo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenStreams")
+	ret0, _ := ret[0].([]client0.StreamInfo)
+	return ret0
+}
+
+// OpenStreams indicates an expected call of OpenStreams.
+func (mr *MockClientMockRecorder) OpenStreams() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenStreams", reflect.TypeOf((*MockClient)(nil).OpenStreams))
+}
+
+// OperationStreams mocks base method.
+func (m *MockClient) OperationStreams(op client0.Operation) (*client.OperationStreams, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OperationStreams", op)
+	ret0, _ := ret[0].(*client.OperationStreams)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OperationStreams indicates an expected call of OperationStreams.
+func (mr *MockClientMockRecorder) OperationStreams(op interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OperationStreams", reflect.TypeOf((*MockClient)(nil).OperationStreams), op)
+}
+
+// Ping mocks base method.
+func (m *MockClient) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockClientMockRecorder) Ping(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockClient)(nil).Ping), ctx)
+}
+
+// PreviewPlacement mocks base method.
+func (m *MockClient) PreviewPlacement(details *api.InstancesPost, rules *client.PlacementRules) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreviewPlacement", details, rules)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PreviewPlacement indicates an expected call of PreviewPlacement.
+func (mr *MockClientMockRecorder) PreviewPlacement(details, rules interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreviewPlacement", reflect.TypeOf((*MockClient)(nil).PreviewPlacement), details, rules)
+}
+
+// PublishApplicationVersion mocks base method.
+func (m *MockClient) PublishApplicationVersion(id string, version int) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishApplicationVersion", id, version)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishApplicationVersion indicates an expected call of PublishApplicationVersion.
+func (mr *MockClientMockRecorder) PublishApplicationVersion(id, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishApplicationVersion", reflect.TypeOf((*MockClient)(nil).PublishApplicationVersion), id, version)
+}
+
+// PullApplicationFromRegistry mocks base method.
+func (m *MockClient) PullApplicationFromRegistry(id string) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PullApplicationFromRegistry", id)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PullApplicationFromRegistry indicates an expected call of PullApplicationFromRegistry.
+func (mr *MockClientMockRecorder) PullApplicationFromRegistry(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PullApplicationFromRegistry", reflect.TypeOf((*MockClient)(nil).PullApplicationFromRegistry), id)
+}
+
+// PushApplicationToRegistry mocks base method.
+func (m *MockClient) PushApplicationToRegistry(id string) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PushApplicationToRegistry", id)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PushApplicationToRegistry indicates an expected call of PushApplicationToRegistry.
+func (mr *MockClientMockRecorder) PushApplicationToRegistry(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PushApplicationToRegistry", reflect.TypeOf((*MockClient)(nil).PushApplicationToRegistry), id)
+}
+
+// Raw mocks base method.
+func (m *MockClient) Raw(ctx context.Context, method, path string, params client0.QueryParams, body io.Reader) (*http.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Raw", ctx, method, path, params, body)
+	ret0, _ := ret[0].(*http.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Raw indicates an expected call of Raw.
+func (mr *MockClientMockRecorder) Raw(ctx, method, path, params, body interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Raw", reflect.TypeOf((*MockClient)(nil).Raw), ctx, method, path, params, body)
+}
+
+// RefreshExtensions mocks base method.
+func (m *MockClient) RefreshExtensions() ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshExtensions")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshExtensions indicates an expected call of RefreshExtensions.
+func (mr *MockClientMockRecorder) RefreshExtensions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshExtensions", reflect.TypeOf((*MockClient)(nil).RefreshExtensions))
+}
+
+// RemoveNode mocks base method.
+func (m *MockClient) RemoveNode(name string, force, keepInCluster bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveNode", name, force, keepInCluster)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveNode indicates an expected call of RemoveNode.
+func (mr *MockClientMockRecorder) RemoveNode(name, force, keepInCluster interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveNode", reflect.TypeOf((*MockClient)(nil).RemoveNode), name, force, keepInCluster)
+}
+
+// RestartContainer mocks base method.
+func (m *MockClient) RestartContainer(ctx context.Context, id string, noWait bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestartContainer", ctx, id, noWait)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestartContainer indicates an expected call of RestartContainer.
+func (mr *MockClientMockRecorder) RestartContainer(ctx, id, noWait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestartContainer", reflect.TypeOf((*MockClient)(nil).RestartContainer), ctx, id, noWait)
+}
+
+// RestartInstance mocks base method.
+func (m *MockClient) RestartInstance(ctx context.Context, id string, noWait bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestartInstance", ctx, id, noWait)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestartInstance indicates an expected call of RestartInstance.
+func (mr *MockClientMockRecorder) RestartInstance(ctx, id, noWait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestartInstance", reflect.TypeOf((*MockClient)(nil).RestartInstance), ctx, id, noWait)
+}
+
+// RestoreInstanceBackup mocks base method.
+func (m *MockClient) RestoreInstanceBackup(id, name string, details *api.InstanceBackupRestorePost) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreInstanceBackup", id, name, details)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreInstanceBackup indicates an expected call of RestoreInstanceBackup.
+func (mr *MockClientMockRecorder) RestoreInstanceBackup(id, name, details interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreInstanceBackup", reflect.TypeOf((*MockClient)(nil).RestoreInstanceBackup), id, name, details)
+}
+
+// RetrieveAddon mocks base method.
+func (m *MockClient) RetrieveAddon(name string) (*api.Addon, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveAddon", name)
+	ret0, _ := ret[0].(*api.Addon)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RetrieveAddon indicates an expected call of RetrieveAddon.
+func (mr *MockClientMockRecorder) RetrieveAddon(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveAddon", reflect.TypeOf((*MockClient)(nil).RetrieveAddon), name)
+}
+
+// RetrieveApplicationByID mocks base method.
+func (m *MockClient) RetrieveApplicationByID(id string) (*api.Application, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveApplicationByID", id)
+	ret0, _ := ret[0].(*api.Application)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RetrieveApplicationByID indicates an expected call of RetrieveApplicationByID.
+func (mr *MockClientMockRecorder) RetrieveApplicationByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveApplicationByID", reflect.TypeOf((*MockClient)(nil).RetrieveApplicationByID), id)
+}
+
+// RetrieveApplicationLogs mocks base method.
+func (m *MockClient) RetrieveApplicationLogs(appID, name string, filter *client.ApplicationLogFilter, sink io.Writer) ([]client.ApplicationLogResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveApplicationLogs", appID, name, filter, sink)
+	ret0, _ := ret[0].([]client.ApplicationLogResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveApplicationLogs indicates an expected call of RetrieveApplicationLogs.
+func (mr *MockClientMockRecorder) RetrieveApplicationLogs(appID, name, filter, sink interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveApplicationLogs", reflect.TypeOf((*MockClient)(nil).RetrieveApplicationLogs), appID, name, filter, sink)
+}
+
+// RetrieveClusterCapacity mocks base method.
+func (m *MockClient) RetrieveClusterCapacity() (*client.ClusterCapacity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveClusterCapacity")
+	ret0, _ := ret[0].(*client.ClusterCapacity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveClusterCapacity indicates an expected call of RetrieveClusterCapacity.
+func (mr *MockClientMockRecorder) RetrieveClusterCapacity() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveClusterCapacity", reflect.TypeOf((*MockClient)(nil).RetrieveClusterCapacity))
+}
+
+// RetrieveClusterService mocks base method.
+func (m *MockClient) RetrieveClusterService(name string) (*api.ClusterService, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveClusterService", name)
+	ret0, _ := ret[0].(*api.ClusterService)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RetrieveClusterService indicates an expected call of RetrieveClusterService.
+func (mr *MockClientMockRecorder) RetrieveClusterService(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveClusterService", reflect.TypeOf((*MockClient)(nil).RetrieveClusterService), name)
+}
+
+// RetrieveConfigItems mocks base method.
+func (m *MockClient) RetrieveConfigItems() (map[string]interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveConfigItems")
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveConfigItems indicates an expected call of RetrieveConfigItems.
+func (mr *MockClientMockRecorder) RetrieveConfigItems() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveConfigItems", reflect.TypeOf((*MockClient)(nil).RetrieveConfigItems))
+}
+
+// RetrieveContainerByID mocks base method.
+func (m *MockClient) RetrieveContainerByID(id string) (*api.Container, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveContainerByID", id)
+	ret0, _ := ret[0].(*api.Container)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RetrieveContainerByID indicates an expected call of RetrieveContainerByID.
+func (mr *MockClientMockRecorder) RetrieveContainerByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveContainerByID", reflect.TypeOf((*MockClient)(nil).RetrieveContainerByID), id)
+}
+
+// RetrieveContainerLog mocks base method.
+func (m *MockClient) RetrieveContainerLog(id, name string, downloader func(*http.Header, io.ReadCloser) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveContainerLog", id, name, downloader)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetrieveContainerLog indicates an expected call of RetrieveContainerLog.
+func (mr *MockClientMockRecorder) RetrieveContainerLog(id, name, downloader interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveContainerLog", reflect.TypeOf((*MockClient)(nil).RetrieveContainerLog), id, name, downloader)
+}
+
+// RetrieveDefaultImage mocks base method.
+func (m *MockClient) RetrieveDefaultImage() (*api.Image, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveDefaultImage")
+	ret0, _ := ret[0].(*api.Image)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RetrieveDefaultImage indicates an expected call of RetrieveDefaultImage.
+func (mr *MockClientMockRecorder) RetrieveDefaultImage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveDefaultImage", reflect.TypeOf((*MockClient)(nil).RetrieveDefaultImage))
+}
+
+// RetrieveImageByIDOrName mocks base method.
+func (m *MockClient) RetrieveImageByIDOrName(id string, imgType api.ImageType) (*api.Image, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveImageByIDOrName", id, imgType)
+	ret0, _ := ret[0].(*api.Image)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RetrieveImageByIDOrName indicates an expected call of RetrieveImageByIDOrName.
+func (mr *MockClientMockRecorder) RetrieveImageByIDOrName(id, imgType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveImageByIDOrName", reflect.TypeOf((*MockClient)(nil).RetrieveImageByIDOrName), id, imgType)
+}
+
+// RetrieveImageChannel mocks base method.
+func (m *MockClient) RetrieveImageChannel() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveImageChannel")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveImageChannel indicates an expected call of RetrieveImageChannel.
+func (mr *MockClientMockRecorder) RetrieveImageChannel() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveImageChannel", reflect.TypeOf((*MockClient)(nil).RetrieveImageChannel))
+}
+
+// RetrieveInstanceBackup mocks base method.
+func (m *MockClient) RetrieveInstanceBackup(id, name string) (*api.InstanceBackup, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveInstanceBackup", id, name)
+	ret0, _ := ret[0].(*api.InstanceBackup)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RetrieveInstanceBackup indicates an expected call of RetrieveInstanceBackup.
+func (mr *MockClientMockRecorder) RetrieveInstanceBackup(id, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveInstanceBackup", reflect.TypeOf((*MockClient)(nil).RetrieveInstanceBackup), id, name)
+}
+
+// RetrieveInstanceByID mocks base method.
+func (m *MockClient) RetrieveInstanceByID(id string) (*api.Instance, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveInstanceByID", id)
+	ret0, _ := ret[0].(*api.Instance)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RetrieveInstanceByID indicates an expected call of RetrieveInstanceByID.
+func (mr *MockClientMockRecorder) RetrieveInstanceByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveInstanceByID", reflect.TypeOf((*MockClient)(nil).RetrieveInstanceByID), id)
+}
+
+// RetrieveInstanceLog mocks base method.
+func (m *MockClient) RetrieveInstanceLog(id, name string, downloader func(*http.Header, io.ReadCloser) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveInstanceLog", id, name, downloader)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetrieveInstanceLog indicates an expected call of RetrieveInstanceLog.
+func (mr *MockClientMockRecorder) RetrieveInstanceLog(id, name, downloader interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveInstanceLog", reflect.TypeOf((*MockClient)(nil).RetrieveInstanceLog), id, name, downloader)
+}
+
+// RetrieveNodeByName mocks base method.
+func (m *MockClient) RetrieveNodeByName(name string) (*api.Node, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveNodeByName", name)
+	ret0, _ := ret[0].(*api.Node)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RetrieveNodeByName indicates an expected call of RetrieveNodeByName.
+func (mr *MockClientMockRecorder) RetrieveNodeByName(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveNodeByName", reflect.TypeOf((*MockClient)(nil).RetrieveNodeByName), name)
+}
+
+// RetrieveNodeStatus mocks base method.
+func (m *MockClient) RetrieveNodeStatus(name string) (*client.NodeResourceStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveNodeStatus", name)
+	ret0, _ := ret[0].(*client.NodeResourceStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveNodeStatus indicates an expected call of RetrieveNodeStatus.
+func (mr *MockClientMockRecorder) RetrieveNodeStatus(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveNodeStatus", reflect.TypeOf((*MockClient)(nil).RetrieveNodeStatus), name)
+}
+
+// RetrieveOperationByID mocks base method.
+func (m *MockClient) RetrieveOperationByID(id string) (*api0.Operation, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveOperationByID", id)
+	ret0, _ := ret[0].(*api0.Operation)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RetrieveOperationByID indicates an expected call of RetrieveOperationByID.
+func (mr *MockClientMockRecorder) RetrieveOperationByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveOperationByID", reflect.TypeOf((*MockClient)(nil).RetrieveOperationByID), id)
+}
+
+// RetrieveRegistryConfig mocks base method.
+func (m *MockClient) RetrieveRegistryConfig() (*api.RegistryConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveRegistryConfig")
+	ret0, _ := ret[0].(*api.RegistryConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveRegistryConfig indicates an expected call of RetrieveRegistryConfig.
+func (mr *MockClientMockRecorder) RetrieveRegistryConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveRegistryConfig", reflect.TypeOf((*MockClient)(nil).RetrieveRegistryConfig))
+}
+
+// RetrieveServiceStatus mocks base method.
+func (m *MockClient) RetrieveServiceStatus() (*api.ServiceStatus, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveServiceStatus")
+	ret0, _ := ret[0].(*api.ServiceStatus)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RetrieveServiceStatus indicates an expected call of RetrieveServiceStatus.
+func (mr *MockClientMockRecorder) RetrieveServiceStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveServiceStatus", reflect.TypeOf((*MockClient)(nil).RetrieveServiceStatus))
+}
+
+// RevokeApplicationVersion mocks base method.
+func (m *MockClient) RevokeApplicationVersion(id string, version int) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeApplicationVersion", id, version)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeApplicationVersion indicates an expected call of RevokeApplicationVersion.
+func (mr *MockClientMockRecorder) RevokeApplicationVersion(id, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeApplicationVersion", reflect.TypeOf((*MockClient)(nil).RevokeApplicationVersion), id, version)
+}
+
+// RunContainerAction mocks base method.
+func (m *MockClient) RunContainerAction(ctx context.Context, ids []string, action client.Action, noWait bool) (map[string]client.ActionResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunContainerAction", ctx, ids, action, noWait)
+	ret0, _ := ret[0].(map[string]client.ActionResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunContainerAction indicates an expected call of RunContainerAction.
+func (mr *MockClientMockRecorder) RunContainerAction(ctx, ids, action, noWait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunContainerAction", reflect.TypeOf((*MockClient)(nil).RunContainerAction), ctx, ids, action, noWait)
+}
+
+// RunInstanceAction mocks base method.
+func (m *MockClient) RunInstanceAction(ctx context.Context, ids []string, action client.Action, noWait bool) (map[string]client.ActionResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunInstanceAction", ctx, ids, action, noWait)
+	ret0, _ := ret[0].(map[string]client.ActionResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunInstanceAction indicates an expected call of RunInstanceAction.
+func (mr *MockClientMockRecorder) RunInstanceAction(ctx, ids, action, noWait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunInstanceAction", reflect.TypeOf((*MockClient)(nil).RunInstanceAction), ctx, ids, action, noWait)
+}
+
+// RunInteractiveShell mocks base method.
+func (m *MockClient) RunInteractiveShell(ctx context.Context, id string, command []string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunInteractiveShell", ctx, id, command)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunInteractiveShell indicates an expected call of RunInteractiveShell.
+func (mr *MockClientMockRecorder) RunInteractiveShell(ctx, id, command interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunInteractiveShell", reflect.TypeOf((*MockClient)(nil).RunInteractiveShell), ctx, id, command)
+}
+
+// ServerStatus mocks base method.
+func (m *MockClient) ServerStatus(ctx context.Context) (*api.ServiceStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ServerStatus", ctx)
+	ret0, _ := ret[0].(*api.ServiceStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ServerStatus indicates an expected call of ServerStatus.
+func (mr *MockClientMockRecorder) ServerStatus(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ServerStatus", reflect.TypeOf((*MockClient)(nil).ServerStatus), ctx)
+}
+
+// SetConfigItem mocks base method.
+func (m *MockClient) SetConfigItem(name, value string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetConfigItem", name, value)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetConfigItem indicates an expected call of SetConfigItem.
+func (mr *MockClientMockRecorder) SetConfigItem(name, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetConfigItem", reflect.TypeOf((*MockClient)(nil).SetConfigItem), name, value)
+}
+
+// SetDefaultImage mocks base method.
+func (m *MockClient) SetDefaultImage(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDefaultImage", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDefaultImage indicates an expected call of SetDefaultImage.
+func (mr *MockClientMockRecorder) SetDefaultImage(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDefaultImage", reflect.TypeOf((*MockClient)(nil).SetDefaultImage), id)
+}
+
+// SetImageChannel mocks base method.
+func (m *MockClient) SetImageChannel(channel string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetImageChannel", channel)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetImageChannel indicates an expected call of SetImageChannel.
+func (mr *MockClientMockRecorder) SetImageChannel(channel interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetImageChannel", reflect.TypeOf((*MockClient)(nil).SetImageChannel), channel)
+}
+
+// ShowOperation mocks base method.
+func (m *MockClient) ShowOperation(id string) (*api0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShowOperation", id)
+	ret0, _ := ret[0].(*api0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ShowOperation indicates an expected call of ShowOperation.
+func (mr *MockClientMockRecorder) ShowOperation(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShowOperation", reflect.TypeOf((*MockClient)(nil).ShowOperation), id)
+}
+
+// StartContainer mocks base method.
+func (m *MockClient) StartContainer(id string, noWait bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartContainer", id, noWait)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartContainer indicates an expected call of StartContainer.
+func (mr *MockClientMockRecorder) StartContainer(id, noWait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartContainer", reflect.TypeOf((*MockClient)(nil).StartContainer), id, noWait)
+}
+
+// StartImageSync mocks base method.
+func (m *MockClient) StartImageSync(id string) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartImageSync", id)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartImageSync indicates an expected call of StartImageSync.
+func (mr *MockClientMockRecorder) StartImageSync(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartImageSync", reflect.TypeOf((*MockClient)(nil).StartImageSync), id)
+}
+
+// StartInstance mocks base method.
+func (m *MockClient) StartInstance(id string, noWait bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartInstance", id, noWait)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartInstance indicates an expected call of StartInstance.
+func (mr *MockClientMockRecorder) StartInstance(id, noWait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartInstance", reflect.TypeOf((*MockClient)(nil).StartInstance), id, noWait)
+}
+
+// StopContainer mocks base method.
+func (m *MockClient) StopContainer(id string, noWait bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StopContainer", id, noWait)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StopContainer indicates an expected call of StopContainer.
+func (mr *MockClientMockRecorder) StopContainer(id, noWait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopContainer", reflect.TypeOf((*MockClient)(nil).StopContainer), id, noWait)
+}
+
+// StopInstance mocks base method.
+func (m *MockClient) StopInstance(id string, noWait bool) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StopInstance", id, noWait)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StopInstance indicates an expected call of StopInstance.
+func (mr *MockClientMockRecorder) StopInstance(id, noWait interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopInstance", reflect.TypeOf((*MockClient)(nil).StopInstance), id, noWait)
+}
+
+// SubscribeEvents mocks base method.
+func (m *MockClient) SubscribeEvents(types []api.EventType, handler func(*api.EventEnvelope)) (*client0.EventListener, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeEvents", types, handler)
+	ret0, _ := ret[0].(*client0.EventListener)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeEvents indicates an expected call of SubscribeEvents.
+func (mr *MockClientMockRecorder) SubscribeEvents(types, handler interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeEvents", reflect.TypeOf((*MockClient)(nil).SubscribeEvents), types, handler)
+}
+
+// SyncApplicationsWithRegistry mocks base method.
+func (m *MockClient) SyncApplicationsWithRegistry(mode api.RegistryMode) ([]client.RegistrySyncResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncApplicationsWithRegistry", mode)
+	ret0, _ := ret[0].([]client.RegistrySyncResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SyncApplicationsWithRegistry indicates an expected call of SyncApplicationsWithRegistry.
+func (mr *MockClientMockRecorder) SyncApplicationsWithRegistry(mode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncApplicationsWithRegistry", reflect.TypeOf((*MockClient)(nil).SyncApplicationsWithRegistry), mode)
+}
+
+// TriggerImageSync mocks base method.
+func (m *MockClient) TriggerImageSync(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TriggerImageSync", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TriggerImageSync indicates an expected call of TriggerImageSync.
+func (mr *MockClientMockRecorder) TriggerImageSync(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TriggerImageSync", reflect.TypeOf((*MockClient)(nil).TriggerImageSync), id)
+}
+
+// UnhealthyClusterServices mocks base method.
+func (m *MockClient) UnhealthyClusterServices() ([]api.ClusterService, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnhealthyClusterServices")
+	ret0, _ := ret[0].([]api.ClusterService)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnhealthyClusterServices indicates an expected call of UnhealthyClusterServices.
+func (mr *MockClientMockRecorder) UnhealthyClusterServices() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnhealthyClusterServices", reflect.TypeOf((*MockClient)(nil).UnhealthyClusterServices))
+}
+
+// UpdateAddon mocks base method.
+func (m *MockClient) UpdateAddon(name, packagePath string, sentBytes chan float64, opts ...client.RequestOption) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{name, packagePath, sentBytes}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateAddon", varargs...)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAddon indicates an expected call of UpdateAddon.
+func (mr *MockClientMockRecorder) UpdateAddon(name, packagePath, sentBytes interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{name, packagePath, sentBytes}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAddon", reflect.TypeOf((*MockClient)(nil).UpdateAddon), varargs...)
+}
+
+// UpdateAddonWithPayload mocks base method.
+func (m *MockClient) UpdateAddonWithPayload(name string, payload io.ReadSeeker, sentBytes chan float64, opts ...client.RequestOption) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{name, payload, sentBytes}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateAddonWithPayload", varargs...)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAddonWithPayload indicates an expected call of UpdateAddonWithPayload.
+func (mr *MockClientMockRecorder) UpdateAddonWithPayload(name, payload, sentBytes interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{name, payload, sentBytes}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAddonWithPayload", reflect.TypeOf((*MockClient)(nil).UpdateAddonWithPayload), varargs...)
+}
+
+// UpdateApplication mocks base method.
+func (m *MockClient) UpdateApplication(id string, opts ...client.RequestOption) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{id}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateApplication", varargs...)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateApplication indicates an expected call of UpdateApplication.
+func (mr *MockClientMockRecorder) UpdateApplication(id interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{id}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateApplication", reflect.TypeOf((*MockClient)(nil).UpdateApplication), varargs...)
+}
+
+// UpdateApplicationWithDetails mocks base method.
+func (m *MockClient) UpdateApplicationWithDetails(id string, details api.ApplicationPatch, opts ...client.RequestOption) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{id, details}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateApplicationWithDetails", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateApplicationWithDetails indicates an expected call of UpdateApplicationWithDetails.
+func (mr *MockClientMockRecorder) UpdateApplicationWithDetails(id, details interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{id, details}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateApplicationWithDetails", reflect.TypeOf((*MockClient)(nil).UpdateApplicationWithDetails), varargs...)
+}
+
+// UpdateApplicationWithPackage mocks base method.
+func (m *MockClient) UpdateApplicationWithPackage(id, packagePath string, sentBytes chan float64, opts ...client.RequestOption) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{id, packagePath, sentBytes}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateApplicationWithPackage", varargs...)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateApplicationWithPackage indicates an expected call of UpdateApplicationWithPackage.
+func (mr *MockClientMockRecorder) UpdateApplicationWithPackage(id, packagePath, sentBytes interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{id, packagePath, sentBytes}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateApplicationWithPackage", reflect.TypeOf((*MockClient)(nil).UpdateApplicationWithPackage), varargs...)
+}
+
+// UpdateContainerByID mocks base method.
+func (m *MockClient) UpdateContainerByID(id string, details *api.ContainerPatch, noWait bool, opts ...client.RequestOption) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{id, details, noWait}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateContainerByID", varargs...)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateContainerByID indicates an expected call of UpdateContainerByID.
+func (mr *MockClientMockRecorder) UpdateContainerByID(id, details, noWait interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{id, details, noWait}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateContainerByID", reflect.TypeOf((*MockClient)(nil).UpdateContainerByID), varargs...)
+}
+
+// UpdateImage mocks base method.
+func (m *MockClient) UpdateImage(id, packagePath string, sentBytes chan float64, opts ...client.RequestOption) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{id, packagePath, sentBytes}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateImage", varargs...)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateImage indicates an expected call of UpdateImage.
+func (mr *MockClientMockRecorder) UpdateImage(id, packagePath, sentBytes interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{id, packagePath, sentBytes}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateImage", reflect.TypeOf((*MockClient)(nil).UpdateImage), varargs...)
+}
+
+// UpdateImageWithPayload mocks base method.
+func (m *MockClient) UpdateImageWithPayload(id string, payload io.ReadSeeker, sentBytes chan float64, opts ...client.RequestOption) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{id, payload, sentBytes}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateImageWithPayload", varargs...)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateImageWithPayload indicates an expected call of UpdateImageWithPayload.
+func (mr *MockClientMockRecorder) UpdateImageWithPayload(id, payload, sentBytes interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{id, payload, sentBytes}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateImageWithPayload", reflect.TypeOf((*MockClient)(nil).UpdateImageWithPayload), varargs...)
+}
+
+// UpdateInstanceByID mocks base method.
+func (m *MockClient) UpdateInstanceByID(id string, details *api.InstancePatch, noWait bool, opts ...client.RequestOption) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{id, details, noWait}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateInstanceByID", varargs...)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateInstanceByID indicates an expected call of UpdateInstanceByID.
+func (mr *MockClientMockRecorder) UpdateInstanceByID(id, details, noWait interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{id, details, noWait}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateInstanceByID", reflect.TypeOf((*MockClient)(nil).UpdateInstanceByID), varargs...)
+}
+
+// UpdateNode mocks base method.
+func (m *MockClient) UpdateNode(name string, details *api.NodePatch, opts ...client.RequestOption) (client0.Operation, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{name, details}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateNode", varargs...)
+	ret0, _ := ret[0].(client0.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateNode indicates an expected call of UpdateNode.
+func (mr *MockClientMockRecorder) UpdateNode(name, details interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{name, details}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNode", reflect.TypeOf((*MockClient)(nil).UpdateNode), varargs...)
+}
+
+// UpdateRegistryConfig mocks base method.
+func (m *MockClient) UpdateRegistryConfig(patch *api.RegistryConfigPatch) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRegistryConfig", patch)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRegistryConfig indicates an expected call of UpdateRegistryConfig.
+func (mr *MockClientMockRecorder) UpdateRegistryConfig(patch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRegistryConfig", reflect.TypeOf((*MockClient)(nil).UpdateRegistryConfig), patch)
+}
+
+// Use mocks base method.
+func (m *MockClient) Use(middlewares ...client0.Middleware) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range middlewares {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Use", varargs...)
+}
+
+// Use indicates an expected call of Use.
+func (mr *MockClientMockRecorder) Use(middlewares ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Use", reflect.TypeOf((*MockClient)(nil).Use), middlewares...)
+}
+
+// WaitApplicationReady mocks base method.
+func (m *MockClient) WaitApplicationReady(ctx context.Context, id string, version int) (*api.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitApplicationReady", ctx, id, version)
+	ret0, _ := ret[0].(*api.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WaitApplicationReady indicates an expected call of WaitApplicationReady.
+func (mr *MockClientMockRecorder) WaitApplicationReady(ctx, id, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitApplicationReady", reflect.TypeOf((*MockClient)(nil).WaitApplicationReady), ctx, id, version)
+}
+
+// WaitForContainerStatus mocks base method.
+func (m *MockClient) WaitForContainerStatus(ctx context.Context, id string, statuses ...api.ContainerStatus) (*api.Container, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, id}
+	for _, a := range statuses {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WaitForContainerStatus", varargs...)
+	ret0, _ := ret[0].(*api.Container)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WaitForContainerStatus indicates an expected call of WaitForContainerStatus.
+func (mr *MockClientMockRecorder) WaitForContainerStatus(ctx, id interface{}, statuses ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, id}, statuses...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForContainerStatus", reflect.TypeOf((*MockClient)(nil).WaitForContainerStatus), varargs...)
+}
+
+// WaitForImageSync mocks base method.
+func (m *MockClient) WaitForImageSync(ctx context.Context, id string, version int) (*api.Image, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitForImageSync", ctx, id, version)
+	ret0, _ := ret[0].(*api.Image)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WaitForImageSync indicates an expected call of WaitForImageSync.
+func (mr *MockClientMockRecorder) WaitForImageSync(ctx, id, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForImageSync", reflect.TypeOf((*MockClient)(nil).WaitForImageSync), ctx, id, version)
+}
+
+// WaitForInstanceStatus mocks base method.
+func (m *MockClient) WaitForInstanceStatus(ctx context.Context, id string, statuses ...api.InstanceStatus) (*api.Instance, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, id}
+	for _, a := range statuses {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WaitForInstanceStatus", varargs...)
+	ret0, _ := ret[0].(*api.Instance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WaitForInstanceStatus indicates an expected call of WaitForInstanceStatus.
+func (mr *MockClientMockRecorder) WaitForInstanceStatus(ctx, id interface{}, statuses ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, id}, statuses...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForInstanceStatus", reflect.TypeOf((*MockClient)(nil).WaitForInstanceStatus), varargs...)
+}
+
+// WatchContainersWithInitialState mocks base method.
+func (m *MockClient) WatchContainersWithInitialState(ctx context.Context, filters []string) (*client.ContainerWatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchContainersWithInitialState", ctx, filters)
+	ret0, _ := ret[0].(*client.ContainerWatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WatchContainersWithInitialState indicates an expected call of WatchContainersWithInitialState.
+func (mr *MockClientMockRecorder) WatchContainersWithInitialState(ctx, filters interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchContainersWithInitialState", reflect.TypeOf((*MockClient)(nil).WatchContainersWithInitialState), ctx, filters)
+}
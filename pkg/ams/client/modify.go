@@ -0,0 +1,117 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"net/http"
+	"time"
+
+	api "github.com/anbox-cloud/ams-sdk/api/ams"
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/client"
+)
+
+var (
+	// modifyAttempts is how often a read-modify-write cycle is attempted
+	// before the conflict is returned to the caller
+	modifyAttempts = 5
+	// modifyBackoff is the delay before the first retry. It grows linearly
+	// with every further attempt.
+	modifyBackoff = 100 * time.Millisecond
+)
+
+// uncachedHeader makes retrievals bypass a stale response cache. The state a
+// patch is based on has to be current, otherwise its ETag is outdated and the
+// update is rejected on every attempt.
+func uncachedHeader() http.Header {
+	return http.Header{"Cache-Control": []string{"no-cache"}}
+}
+
+// retryOnConflict calls f until it succeeds, fails with an error other than
+// ErrPreconditionFailed or the attempts are used up
+func retryOnConflict(f func() error) error {
+	var err error
+	for attempt := 0; attempt < modifyAttempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(attempt) * modifyBackoff)
+		}
+		err = f()
+		if !errs.IsErrPreconditionFailed(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// ModifyContainer performs a read-modify-write cycle on the container with
+// the given ID. The modify function receives the current state of the
+// container and returns the patch to apply or nil if nothing has to change.
+// The patch is applied conditionally on the ETag of the retrieved state. If
+// the container was modified concurrently, the cycle is repeated with the new
+// state, so modify can be called multiple times and must not have side
+// effects. Returns a nil operation if modify did not return a patch.
+func (c *clientImpl) ModifyContainer(id string, modify func(container *api.Container) (*api.ContainerPatch, error), noWait bool) (client.Operation, error) {
+	if len(id) == 0 {
+		return nil, errs.NewInvalidArgument("id")
+	}
+	if modify == nil {
+		return nil, errs.NewInvalidArgument("modify")
+	}
+
+	var op client.Operation
+	err := retryOnConflict(func() error {
+		container, etag, err := c.retrieveContainer(id, uncachedHeader())
+		if err != nil {
+			return err
+		}
+		patch, err := modify(container)
+		if err != nil || patch == nil {
+			return err
+		}
+		op, err = c.UpdateContainerByID(id, patch, noWait, WithETag(etag))
+		return err
+	})
+	return op, err
+}
+
+// ModifyInstance performs a read-modify-write cycle on the instance with the
+// given ID. See ModifyContainer for details.
+func (c *clientImpl) ModifyInstance(id string, modify func(instance *api.Instance) (*api.InstancePatch, error), noWait bool) (client.Operation, error) {
+	if len(id) == 0 {
+		return nil, errs.NewInvalidArgument("id")
+	}
+	if modify == nil {
+		return nil, errs.NewInvalidArgument("modify")
+	}
+
+	var op client.Operation
+	err := retryOnConflict(func() error {
+		instance, etag, err := c.retrieveInstance(id, uncachedHeader())
+		if err != nil {
+			return err
+		}
+		patch, err := modify(instance)
+		if err != nil || patch == nil {
+			return err
+		}
+		op, err = c.UpdateInstanceByID(id, patch, noWait, WithETag(etag))
+		return err
+	})
+	return op, err
+}
@@ -0,0 +1,233 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	api "github.com/anbox-cloud/ams-sdk/api/ams"
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+)
+
+const (
+	// Number of clusters queried at the same time by default
+	defaultMultiClientConcurrency = 4
+)
+
+// ClusterError describes the failure of a call against a single cluster
+type ClusterError struct {
+	Cluster string
+	Err     error
+}
+
+// Error returns the error string
+func (e *ClusterError) Error() string {
+	return fmt.Sprintf("cluster %s: %v", e.Cluster, e.Err)
+}
+
+// Unwrap returns the error the call failed with
+func (e *ClusterError) Unwrap() error {
+	return e.Err
+}
+
+// MultiClusterError is returned by the fan-out calls of a MultiClient when
+// the call failed for some of the clusters. The results of the other
+// clusters are returned alongside.
+type MultiClusterError struct {
+	// Errors lists the failed clusters ordered by name
+	Errors []ClusterError
+}
+
+// Error returns the error string
+func (e *MultiClusterError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for n := range e.Errors {
+		msgs = append(msgs, e.Errors[n].Error())
+	}
+	return fmt.Sprintf("%d cluster(s) failed: %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// Clusters returns the names of the failed clusters
+func (e *MultiClusterError) Clusters() []string {
+	names := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		names = append(names, err.Cluster)
+	}
+	return names
+}
+
+// MultiClient wraps the clients of several AMS clusters, e.g. one per region
+// or datacenter, and sends queries to all of them in parallel
+type MultiClient struct {
+	// Concurrency limits the number of clusters queried at the same time.
+	// Defaults to 4.
+	Concurrency int
+
+	clients map[string]Client
+	labels  map[string]map[string]string
+	names   []string
+}
+
+// NewMultiClient returns a multi-cluster client for the given clients keyed
+// by the name of their cluster
+func NewMultiClient(clients map[string]Client) (*MultiClient, error) {
+	if len(clients) == 0 {
+		return nil, errs.NewInvalidArgument("clients")
+	}
+	m := &MultiClient{clients: map[string]Client{}, labels: map[string]map[string]string{}}
+	for name, c := range clients {
+		if len(name) == 0 || c == nil {
+			return nil, errs.NewInvalidArgument("clients")
+		}
+		m.clients[name] = c
+		m.names = append(m.names, name)
+	}
+	sort.Strings(m.names)
+	return m, nil
+}
+
+// Clusters returns the names of all clusters ordered by name
+func (m *MultiClient) Clusters() []string {
+	return append([]string{}, m.names...)
+}
+
+// Client returns the client of the cluster with the given name
+func (m *MultiClient) Client(name string) (Client, error) {
+	c, ok := m.clients[name]
+	if !ok {
+		return nil, errs.NewErrNotFound(fmt.Sprintf("cluster %s", name))
+	}
+	return c, nil
+}
+
+// SetClusterLabels attaches the given labels, e.g. region=eu, to the cluster
+// with the given name. Labels are used by placement strategies to route
+// requests. SetClusterLabels must not be called while the client is in use.
+func (m *MultiClient) SetClusterLabels(name string, labels map[string]string) error {
+	if _, ok := m.clients[name]; !ok {
+		return errs.NewErrNotFound(fmt.Sprintf("cluster %s", name))
+	}
+	m.labels[name] = labels
+	return nil
+}
+
+// ClusterLabels returns the labels of the cluster with the given name
+func (m *MultiClient) ClusterLabels(name string) map[string]string {
+	return m.labels[name]
+}
+
+// Each calls f for the client of every cluster in parallel and returns a
+// *MultiClusterError listing the clusters f failed for. Clusters which were
+// not started yet when the context is cancelled fail with the error of the
+// context.
+func (m *MultiClient) Each(ctx context.Context, f func(ctx context.Context, cluster string, c Client) error) error {
+	concurrency := m.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultMultiClientConcurrency
+	}
+
+	results := make([]error, len(m.names))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for n, name := range m.names {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[n] = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func(n int, name string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := ctx.Err(); err != nil {
+				results[n] = err
+				return
+			}
+			results[n] = f(ctx, name, m.clients[name])
+		}(n, name)
+	}
+	wg.Wait()
+
+	var failed []ClusterError
+	for n, err := range results {
+		if err != nil {
+			failed = append(failed, ClusterError{Cluster: m.names[n], Err: err})
+		}
+	}
+	if len(failed) > 0 {
+		return &MultiClusterError{Errors: failed}
+	}
+	return nil
+}
+
+// ListContainersEverywhere lists the containers of all clusters keyed by
+// cluster name. See Each for how failures are reported.
+func (m *MultiClient) ListContainersEverywhere(ctx context.Context) (map[string][]api.Container, error) {
+	return multiClientList(ctx, m, func(c Client) ([]api.Container, error) {
+		return c.ListContainers()
+	})
+}
+
+// ListInstancesEverywhere lists the instances of all clusters keyed by
+// cluster name. See Each for how failures are reported.
+func (m *MultiClient) ListInstancesEverywhere(ctx context.Context) (map[string][]api.Instance, error) {
+	return multiClientList(ctx, m, func(c Client) ([]api.Instance, error) {
+		return c.ListInstances()
+	})
+}
+
+// ListNodesEverywhere lists the nodes of all clusters keyed by cluster name.
+// See Each for how failures are reported.
+func (m *MultiClient) ListNodesEverywhere(ctx context.Context) (map[string][]api.Node, error) {
+	return multiClientList(ctx, m, func(c Client) ([]api.Node, error) {
+		return c.ListNodes()
+	})
+}
+
+// ListApplicationsEverywhere lists the applications of all clusters keyed by
+// cluster name. See Each for how failures are reported.
+func (m *MultiClient) ListApplicationsEverywhere(ctx context.Context) (map[string][]api.Application, error) {
+	return multiClientList(ctx, m, func(c Client) ([]api.Application, error) {
+		return c.ListApplications()
+	})
+}
+
+func multiClientList[T any](ctx context.Context, m *MultiClient, list func(c Client) ([]T, error)) (map[string][]T, error) {
+	var lock sync.Mutex
+	results := map[string][]T{}
+	err := m.Each(ctx, func(ctx context.Context, cluster string, c Client) error {
+		items, err := list(c)
+		if err != nil {
+			return err
+		}
+		lock.Lock()
+		results[cluster] = items
+		lock.Unlock()
+		return nil
+	})
+	return results, err
+}
@@ -0,0 +1,260 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	api "github.com/anbox-cloud/ams-sdk/api/ams"
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/client"
+)
+
+// ClusterInfo describes a cluster of a MultiClient to a placement strategy
+type ClusterInfo struct {
+	Name   string
+	Labels map[string]string
+	Client Client
+}
+
+// ClusterPlacement selects the clusters an instance is launched on. Rank
+// returns the names of the suitable clusters, the preferred one first. The
+// others are tried in order when a cluster rejects the launch because it is
+// out of capacity.
+type ClusterPlacement interface {
+	Rank(ctx context.Context, clusters []ClusterInfo, details *api.InstancesPost) ([]string, error)
+}
+
+// ClusterPlacementFunc allows using an ordinary function as ClusterPlacement
+type ClusterPlacementFunc func(ctx context.Context, clusters []ClusterInfo, details *api.InstancesPost) ([]string, error)
+
+// Rank calls f(ctx, clusters, details)
+func (f ClusterPlacementFunc) Rank(ctx context.Context, clusters []ClusterInfo, details *api.InstancesPost) ([]string, error) {
+	return f(ctx, clusters, details)
+}
+
+// PlaceByOrder returns a strategy preferring the clusters in the given order.
+// Clusters which are not listed are not used.
+func PlaceByOrder(names ...string) ClusterPlacement {
+	return ClusterPlacementFunc(func(ctx context.Context, clusters []ClusterInfo, details *api.InstancesPost) ([]string, error) {
+		known := map[string]bool{}
+		for _, c := range clusters {
+			known[c.Name] = true
+		}
+		ranked := []string{}
+		for _, name := range names {
+			if known[name] {
+				ranked = append(ranked, name)
+			}
+		}
+		return ranked, nil
+	})
+}
+
+// PlaceByLabels returns a strategy only considering the clusters which have
+// all of the given labels. The matching clusters are ranked by next, or by
+// name if next is nil.
+func PlaceByLabels(selector map[string]string, next ClusterPlacement) ClusterPlacement {
+	return ClusterPlacementFunc(func(ctx context.Context, clusters []ClusterInfo, details *api.InstancesPost) ([]string, error) {
+		matching := []ClusterInfo{}
+		for _, c := range clusters {
+			matches := true
+			for key, value := range selector {
+				if v, ok := c.Labels[key]; !ok || v != value {
+					matches = false
+					break
+				}
+			}
+			if matches {
+				matching = append(matching, c)
+			}
+		}
+		if next == nil {
+			return clusterNames(matching), nil
+		}
+		return next.Rank(ctx, matching, details)
+	})
+}
+
+// PlaceByLatency returns a strategy preferring the clusters answering a ping
+// the fastest. Clusters which cannot be reached are not used.
+func PlaceByLatency() ClusterPlacement {
+	return ClusterPlacementFunc(func(ctx context.Context, clusters []ClusterInfo, details *api.InstancesPost) ([]string, error) {
+		latency := map[string]time.Duration{}
+		reachable := []ClusterInfo{}
+		for _, c := range clusters {
+			start := time.Now()
+			if err := c.Client.Ping(ctx); err != nil {
+				continue
+			}
+			latency[c.Name] = time.Since(start)
+			reachable = append(reachable, c)
+		}
+		sort.SliceStable(reachable, func(i, j int) bool {
+			return latency[reachable[i].Name] < latency[reachable[j].Name]
+		})
+		return clusterNames(reachable), nil
+	})
+}
+
+// PlaceByCapacity returns a strategy preferring the clusters with the most
+// free CPUs, then the most free memory, on their schedulable nodes. Clusters
+// whose capacity cannot be retrieved are not used.
+func PlaceByCapacity() ClusterPlacement {
+	return ClusterPlacementFunc(func(ctx context.Context, clusters []ClusterInfo, details *api.InstancesPost) ([]string, error) {
+		capacity := map[string]*ClusterCapacity{}
+		available := []ClusterInfo{}
+		for _, c := range clusters {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			cc, err := c.Client.RetrieveClusterCapacity()
+			if err != nil {
+				continue
+			}
+			capacity[c.Name] = cc
+			available = append(available, c)
+		}
+		sort.SliceStable(available, func(i, j int) bool {
+			a, b := capacity[available[i].Name].Schedulable, capacity[available[j].Name].Schedulable
+			if a.CPUs.Free() != b.CPUs.Free() {
+				return a.CPUs.Free() > b.CPUs.Free()
+			}
+			return a.Memory.Free() > b.Memory.Free()
+		})
+		return clusterNames(available), nil
+	})
+}
+
+// capacityErrorMessages are parts of the messages AMS fails a launch with when
+// no node can take the instance
+var capacityErrorMessages = []string{
+	"no suitable node",
+	"no node available",
+	"not enough",
+	"insufficient",
+	"out of capacity",
+	"resources exhausted",
+}
+
+// IsCapacityError returns true if the error indicates that a cluster has no
+// capacity left for a new instance. AMS reports these failures as plain
+// messages, so the classification is based on their text.
+func IsCapacityError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, m := range capacityErrorMessages {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// RoutedLaunch describes an instance launched through a MultiClient
+type RoutedLaunch struct {
+	// Cluster the instance was launched on
+	Cluster string
+	// ID of the launched instance
+	ID string
+	// Operation of the launch
+	Operation client.Operation
+	// Rejected lists the clusters which were tried before and rejected the
+	// launch because they were out of capacity
+	Rejected []ClusterError
+}
+
+// LaunchInstance launches an instance on the first cluster ranked by the
+// given placement strategy which accepts it. The launch operation is waited
+// for, as AMS may only reject the launch once it tries to schedule the
+// instance. When a cluster is out of capacity, the instance it left behind is
+// deleted and the next ranked cluster is tried; any other failure is returned
+// right away.
+func (m *MultiClient) LaunchInstance(ctx context.Context, details *api.InstancesPost, placement ClusterPlacement) (*RoutedLaunch, error) {
+	if details == nil {
+		return nil, errs.NewInvalidArgument("details")
+	}
+	if placement == nil {
+		return nil, errs.NewInvalidArgument("placement")
+	}
+
+	clusters := make([]ClusterInfo, 0, len(m.names))
+	for _, name := range m.names {
+		clusters = append(clusters, ClusterInfo{Name: name, Labels: m.labels[name], Client: m.clients[name]})
+	}
+	ranked, err := placement.Rank(ctx, clusters, details)
+	if err != nil {
+		return nil, err
+	}
+	if len(ranked) == 0 {
+		return nil, errs.NewErrNotFound("cluster matching the placement strategy")
+	}
+
+	result := &RoutedLaunch{}
+	for _, name := range ranked {
+		c, ok := m.clients[name]
+		if !ok {
+			return result, errs.NewErrNotFound(fmt.Sprintf("cluster %s", name))
+		}
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		op, err := c.LaunchInstance(details, false)
+		id := ""
+		if err == nil {
+			id = launchedInstanceID(op)
+			err = op.Wait(ctx)
+		}
+		if err == nil {
+			result.Cluster = name
+			result.ID = id
+			result.Operation = op
+			return result, nil
+		}
+		if !IsCapacityError(err) {
+			return result, &ClusterError{Cluster: name, Err: err}
+		}
+
+		result.Rejected = append(result.Rejected, ClusterError{Cluster: name, Err: err})
+		if len(id) > 0 {
+			// Don't leave the failed instance behind on the rejecting cluster
+			if op, err := c.DeleteInstanceByID(id, true); err == nil {
+				_ = op.Wait(ctx)
+			}
+		}
+	}
+
+	last := result.Rejected[len(result.Rejected)-1].Err
+	return result, fmt.Errorf("all %d cluster(s) are out of capacity, last error: %w", len(result.Rejected), last)
+}
+
+func clusterNames(clusters []ClusterInfo) []string {
+	names := make([]string, 0, len(clusters))
+	for _, c := range clusters {
+		names = append(names, c.Name)
+	}
+	return names
+}
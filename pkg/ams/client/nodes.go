@@ -20,7 +20,6 @@ package client
 
 import (
 	"bytes"
-	"encoding/json"
 
 	api "github.com/anbox-cloud/ams-sdk/api/ams"
 	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
@@ -39,7 +38,7 @@ func (c *clientImpl) ListNodes() ([]api.Node, error) {
 
 // AddNode adds a new node to AMS
 func (c *clientImpl) AddNode(node *api.NodesPost) (client.Operation, error) {
-	b, err := json.Marshal(node)
+	b, err := c.Codec().Marshal(node)
 	if err != nil {
 		return nil, err
 	}
@@ -57,7 +56,7 @@ func (c *clientImpl) RemoveNode(name string, force, keepInCluster bool) (client.
 		Force:         force,
 		KeepInCluster: keepInCluster,
 	}
-	b, err := json.Marshal(details)
+	b, err := c.Codec().Marshal(details)
 	if err != nil {
 		return nil, err
 	}
@@ -77,17 +76,18 @@ func (c *clientImpl) RetrieveNodeByName(name string) (*api.Node, string, error)
 }
 
 // UpdateNode updates an existing node
-func (c *clientImpl) UpdateNode(name string, details *api.NodePatch) (client.Operation, error) {
+func (c *clientImpl) UpdateNode(name string, details *api.NodePatch, opts ...RequestOption) (client.Operation, error) {
 	if len(name) == 0 {
 		return nil, errs.NewInvalidArgument("name")
 	}
 	if details == nil {
 		return nil, errs.NewInvalidArgument("details")
 	}
-	b, err := json.Marshal(details)
+	b, err := c.Codec().Marshal(details)
 	if err != nil {
 		return nil, err
 	}
-	op, _, err := c.QueryOperation("PATCH", client.APIPath("nodes", name), nil, nil, bytes.NewReader(b), "")
+	o := newRequestOptions(opts)
+	op, _, err := c.QueryOperation("PATCH", client.APIPath("nodes", name), nil, o.headerWith(nil), bytes.NewReader(b), o.etag)
 	return op, err
 }
@@ -0,0 +1,114 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/client"
+	"github.com/gorilla/websocket"
+)
+
+// StreamControl is the name of the control stream of an operation
+const StreamControl = "control"
+
+// OperationStreams gives access to the websockets an operation like an exec
+// or console session exposes. Each stream is identified by its name, which is
+// either "control" or the number of a file descriptor.
+type OperationStreams struct {
+	client    *clientImpl
+	operation client.Operation
+	secrets   map[string]string
+}
+
+// OperationStreams returns the streams exposed by the given operation
+func (c *clientImpl) OperationStreams(op client.Operation) (*OperationStreams, error) {
+	if op == nil {
+		return nil, errs.NewInvalidArgument("operation")
+	}
+	secrets := operationFDs(op)
+	if len(secrets) == 0 {
+		return nil, errs.NewErrNotFound(fmt.Sprintf("streams of operation %s", op.Get().ID))
+	}
+	return &OperationStreams{client: c, operation: op, secrets: secrets}, nil
+}
+
+// Operation returns the operation the streams belong to
+func (s *OperationStreams) Operation() client.Operation {
+	return s.operation
+}
+
+// Names returns the sorted names of all streams of the operation
+func (s *OperationStreams) Names() []string {
+	names := make([]string, 0, len(s.secrets))
+	for name := range s.secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Has returns true if the operation exposes a stream with the given name
+func (s *OperationStreams) Has(name string) bool {
+	_, ok := s.secrets[name]
+	return ok
+}
+
+// Open connects to the stream with the given name
+func (s *OperationStreams) Open(name string) (*websocket.Conn, error) {
+	return s.OpenWithContext(context.Background(), name)
+}
+
+// OpenWithContext connects to the stream with the given name. The websocket
+// handshake is aborted when the context is done.
+func (s *OperationStreams) OpenWithContext(ctx context.Context, name string) (*websocket.Conn, error) {
+	secret, ok := s.secrets[name]
+	if !ok {
+		return nil, errs.NewErrNotFound(fmt.Sprintf("stream %s of operation %s", name, s.operation.Get().ID))
+	}
+	return s.client.getOperationWebsocketWithContext(ctx, s.operation.Get().ID, secret)
+}
+
+// Control connects to the control stream of the operation
+func (s *OperationStreams) Control() (*websocket.Conn, error) {
+	return s.Open(StreamControl)
+}
+
+// FD connects to the stream of the given file descriptor
+func (s *OperationStreams) FD(fd int) (*websocket.Conn, error) {
+	return s.Open(strconv.Itoa(fd))
+}
+
+// operationFDs returns the secrets of the streams listed in the metadata of
+// the given operation, indexed by stream name
+func operationFDs(op client.Operation) map[string]string {
+	fds := map[string]string{}
+	if values, ok := op.Get().Metadata["fds"].(map[string]interface{}); ok {
+		for k, v := range values {
+			if s, ok := v.(string); ok {
+				fds[k] = s
+			}
+		}
+	}
+	return fds
+}
@@ -19,10 +19,79 @@
 package client
 
 import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
 	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/api"
 	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/client"
 )
 
+// OperationFilter selects operations. All set criteria must match.
+type OperationFilter struct {
+	// Statuses the operation must have one of
+	Statuses []api.StatusCode
+	// Class of the operation, e.g. task or websocket
+	Class string
+	// Resource the operation must affect. Either a resource type like
+	// "instances" or a resource path like "/1.0/instances/<id>".
+	Resource string
+	// CreatedBefore and CreatedAfter limit the time the operation was created at
+	CreatedBefore time.Time
+	CreatedAfter  time.Time
+	// UpdatedBefore selects operations which were not updated since the
+	// given time, e.g. to find stuck operations
+	UpdatedBefore time.Time
+}
+
+func (f *OperationFilter) matches(op *api.Operation) bool {
+	if len(f.Statuses) > 0 {
+		found := false
+		for _, status := range f.Statuses {
+			if op.StatusCode == status {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(f.Class) > 0 && op.Class != f.Class {
+		return false
+	}
+	if len(f.Resource) > 0 && !operationAffects(op, f.Resource) {
+		return false
+	}
+	if !f.CreatedBefore.IsZero() && !op.CreatedAt.Before(f.CreatedBefore) {
+		return false
+	}
+	if !f.CreatedAfter.IsZero() && !op.CreatedAt.After(f.CreatedAfter) {
+		return false
+	}
+	if !f.UpdatedBefore.IsZero() && !op.UpdatedAt.Before(f.UpdatedBefore) {
+		return false
+	}
+	return true
+}
+
+func operationAffects(op *api.Operation, resource string) bool {
+	if !strings.HasPrefix(resource, "/") {
+		return len(op.Resources[resource]) > 0
+	}
+	for _, paths := range op.Resources {
+		for _, path := range paths {
+			if path == resource {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // ListOperations lists all operations arranged by their status
 func (c *clientImpl) ListOperations() (map[string][]*api.Operation, error) {
 	params := client.QueryParams{
@@ -33,6 +102,48 @@ func (c *clientImpl) ListOperations() (map[string][]*api.Operation, error) {
 	return operations, err
 }
 
+// ListOperationsWithFilter lists all operations of the cluster matching the
+// given filter, ordered by the time they were created at. A nil filter
+// matches all operations. AMS does not filter operations itself, so all
+// operations are retrieved and filtered by the client.
+func (c *clientImpl) ListOperationsWithFilter(ctx context.Context, filter *OperationFilter) ([]api.Operation, error) {
+	params := client.QueryParams{
+		"recursion": "1",
+	}
+	operations := map[string][]api.Operation{}
+	_, err := c.QueryStructWithContext(ctx, "GET", client.APIPath("operations"), params, nil, nil, "", &operations)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter == nil {
+		filter = &OperationFilter{}
+	}
+	result := []api.Operation{}
+	for _, ops := range operations {
+		for n := range ops {
+			if filter.matches(&ops[n]) {
+				result = append(result, ops[n])
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.Before(result[j].CreatedAt) })
+	return result, nil
+}
+
+// RetrieveOperationByID returns the operation with the given ID and its ETag
+func (c *clientImpl) RetrieveOperationByID(id string) (*api.Operation, string, error) {
+	if len(id) == 0 {
+		return nil, "", errs.NewInvalidArgument("id")
+	}
+	operation := &api.Operation{}
+	etag, err := c.QueryStruct("GET", client.APIPath("operations", url.PathEscape(id)), nil, nil, nil, "", operation)
+	if err != nil {
+		return nil, "", err
+	}
+	return operation, etag, nil
+}
+
 // ShowOperation shows details about a single operation
 func (c *clientImpl) ShowOperation(id string) (*api.Operation, error) {
 	var operation *api.Operation
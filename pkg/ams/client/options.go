@@ -0,0 +1,126 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/client"
+)
+
+// RequestOption allows customizing a request creating or updating an object
+type RequestOption func(o *requestOptions)
+
+type requestOptions struct {
+	etag   string
+	header http.Header
+}
+
+// WithETag makes an update conditional on the ETag returned when the object
+// was retrieved. If the object was modified since then the update is rejected
+// by AMS with an error of type ErrPreconditionFailed instead of silently
+// overwriting the concurrent change.
+func WithETag(etag string) RequestOption {
+	return func(o *requestOptions) {
+		o.etag = etag
+	}
+}
+
+// WithContentType overwrites the content type of an uploaded package which
+// defaults to application/octet-stream
+func WithContentType(contentType string) RequestOption {
+	return WithHeader("Content-Type", contentType)
+}
+
+// WithVersionLabel attaches a version label to an uploaded package
+func WithVersionLabel(label string) RequestOption {
+	return WithHeader("X-AMS-Version-Label", label)
+}
+
+// WithMetadata attaches an arbitrary metadata key/value pair to an uploaded
+// package. It is sent as X-AMS-Metadata-<key> header.
+func WithMetadata(key, value string) RequestOption {
+	return WithHeader(fmt.Sprintf("X-AMS-Metadata-%s", key), value)
+}
+
+// WithDryRun prevents the request from being sent. See WithDryRun of the REST
+// client for how dry-run requests are handled.
+func WithDryRun() RequestOption {
+	return WithHeader(client.DryRunHeader, "true")
+}
+
+// WithHeader sets an additional header on the request. For uploads, headers
+// set by the client itself, like the fingerprint of the package, are
+// overwritten.
+func WithHeader(key, value string) RequestOption {
+	return func(o *requestOptions) {
+		if o.header == nil {
+			o.header = http.Header{}
+		}
+		o.header.Set(key, value)
+	}
+}
+
+// Priority is a hint for AMS or proxies in front of it on how urgent a
+// request is. It allows deprioritizing bulk traffic relative to interactive
+// requests. AMS versions without support for priorities ignore the hint.
+type Priority string
+
+const (
+	// PriorityInteractive is meant for requests a user is waiting for
+	PriorityInteractive Priority = "interactive"
+	// PriorityNormal is the priority of requests without a hint
+	PriorityNormal Priority = "normal"
+	// PriorityBulk is meant for background traffic like reconciliation loops
+	// which can be delayed in favor of other requests
+	PriorityBulk Priority = "bulk"
+)
+
+// PriorityHeader is the header carrying the priority hint of a request
+const PriorityHeader = "X-AMS-Priority"
+
+// WithPriority attaches a priority hint to the request
+func WithPriority(priority Priority) RequestOption {
+	return WithHeader(PriorityHeader, string(priority))
+}
+
+func newRequestOptions(opts []RequestOption) requestOptions {
+	o := requestOptions{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return o
+}
+
+// headerWith returns the given header extended by the headers of the options
+func (o requestOptions) headerWith(header http.Header) http.Header {
+	if len(o.header) == 0 {
+		return header
+	}
+	if header == nil {
+		header = http.Header{}
+	}
+	for k, v := range o.header {
+		header[k] = v
+	}
+	return header
+}
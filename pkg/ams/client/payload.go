@@ -19,8 +19,6 @@
 package client
 
 import (
-	"crypto/sha256"
-	"fmt"
 	"io"
 	"os"
 
@@ -43,29 +41,9 @@ func (u *Uploader) Read(p []byte) (int, error) {
 	return n, err
 }
 
-func preparePayload(filepath string) (io.Reader, string, error) {
+func openPayload(filepath string) (*os.File, error) {
 	if !shared.PathExists(filepath) {
-		return nil, "", errs.NewErrNotFound("payload")
+		return nil, errs.NewErrNotFound("payload")
 	}
-
-	f, err := os.Open(filepath)
-	if err != nil {
-		return nil, "", err
-	}
-
-	hasher := sha256.New()
-	_, err = io.Copy(hasher, f)
-	if err != nil {
-		return nil, "", err
-	}
-
-	fingerprint := fmt.Sprintf("%x", hasher.Sum(nil))
-
-	// move cursor to the beginning of the file after hashing
-	_, err = f.Seek(0, 0)
-	if err != nil {
-		return nil, "", err
-	}
-
-	return f, fingerprint, nil
+	return os.Open(filepath)
 }
@@ -0,0 +1,250 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"sort"
+
+	api "github.com/anbox-cloud/ams-sdk/api/ams"
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/client"
+)
+
+// PlacementRules describe constraints for selecting the node a new instance is
+// launched on. AMS does not support such rules itself, so they are evaluated
+// on the client side on a best-effort basis: the node is selected from the
+// current state of the cluster which can change before the launch is
+// processed by AMS.
+type PlacementRules struct {
+	// Node restricts the placement to the node with the given name. Unlike
+	// setting the node in the launch details, the other rules are still
+	// checked for the node.
+	Node string `json:"node,omitempty" yaml:"node,omitempty"`
+	// PreferredNodeTags prefers nodes which have all of the given tags
+	PreferredNodeTags []string `json:"preferred_node_tags,omitempty" yaml:"preferred_node_tags,omitempty"`
+	// NodeTags restricts the placement to nodes which have all of the given tags
+	NodeTags []string `json:"node_tags,omitempty" yaml:"node_tags,omitempty"`
+	// Spread prefers nodes running the fewest instances of the same application
+	// or image, spreading the instances across the cluster
+	Spread bool `json:"spread,omitempty" yaml:"spread,omitempty"`
+	// AntiAffinityTags excludes nodes which run an instance having any of the
+	// given tags
+	AntiAffinityTags []string `json:"anti_affinity_tags,omitempty" yaml:"anti_affinity_tags,omitempty"`
+	// AffinityTags prefers nodes which run an instance having any of the given
+	// tags
+	AffinityTags []string `json:"affinity_tags,omitempty" yaml:"affinity_tags,omitempty"`
+	// AntiAffinityInstances excludes nodes which run one of the instances with
+	// the given IDs
+	AntiAffinityInstances []string `json:"anti_affinity_instances,omitempty" yaml:"anti_affinity_instances,omitempty"`
+	// RequireCapacity excludes nodes which don't have enough free resources
+	// for the resources requested in the launch details. Resources which are
+	// not set in the launch details, e.g. because they are defined by the
+	// application, are not checked.
+	RequireCapacity bool `json:"require_capacity,omitempty" yaml:"require_capacity,omitempty"`
+}
+
+type placementCandidate struct {
+	name      string
+	preferred bool
+	affinity  int
+	siblings  int
+	instances int
+}
+
+// PreviewPlacement returns the name of the node an instance with the given
+// details would be launched on according to the given placement rules. If the
+// details already specify a node it is returned as is.
+func (c *clientImpl) PreviewPlacement(details *api.InstancesPost, rules *PlacementRules) (string, error) {
+	if details == nil {
+		return "", errs.NewInvalidArgument("details")
+	}
+	if len(details.Node) > 0 || rules == nil {
+		return details.Node, nil
+	}
+
+	nodes, err := c.ListNodes()
+	if err != nil {
+		return "", err
+	}
+	instances, err := c.ListInstances()
+	if err != nil {
+		return "", err
+	}
+	return selectPlacementNode(nodes, instances, details, rules, nil)
+}
+
+// selectPlacementNode selects the node for an instance with the given details
+// from the given cluster state. Nodes contained in excluded are not considered.
+func selectPlacementNode(nodes []api.Node, instances []api.Instance, details *api.InstancesPost, rules *PlacementRules, excluded map[string]bool) (string, error) {
+	candidates := map[string]*placementCandidate{}
+	for n, node := range nodes {
+		if excluded[node.Name] {
+			continue
+		}
+		if node.StatusCode != api.NodeStatusOnline || node.Unschedulable || !hasAllTags(node.Tags, rules.NodeTags) {
+			continue
+		}
+		if len(rules.Node) > 0 && node.Name != rules.Node {
+			continue
+		}
+		if rules.RequireCapacity && !hasCapacity(nodeResourceStatus(&nodes[n], instances), details) {
+			continue
+		}
+		candidates[node.Name] = &placementCandidate{
+			name:      node.Name,
+			preferred: len(rules.PreferredNodeTags) > 0 && hasAllTags(node.Tags, rules.PreferredNodeTags),
+		}
+	}
+
+	for _, instance := range instances {
+		candidate, ok := candidates[instance.Node]
+		if !ok || instance.StatusCode == api.InstanceStatusDeleted {
+			continue
+		}
+		if hasAnyTag(instance.Tags, rules.AntiAffinityTags) || hasAnyTag([]string{instance.ID}, rules.AntiAffinityInstances) {
+			delete(candidates, instance.Node)
+			continue
+		}
+		if hasAnyTag(instance.Tags, rules.AffinityTags) {
+			candidate.affinity++
+		}
+		if isSiblingInstance(&instance, details) {
+			candidate.siblings++
+		}
+		candidate.instances++
+	}
+
+	if len(candidates) == 0 {
+		return "", errs.NewErrNotFound("node matching the placement rules")
+	}
+
+	sorted := make([]*placementCandidate, 0, len(candidates))
+	for _, candidate := range candidates {
+		sorted = append(sorted, candidate)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.preferred != b.preferred {
+			return a.preferred
+		}
+		if (a.affinity > 0) != (b.affinity > 0) {
+			return a.affinity > 0
+		}
+		if rules.Spread {
+			if a.siblings != b.siblings {
+				return a.siblings < b.siblings
+			}
+			if a.instances != b.instances {
+				return a.instances < b.instances
+			}
+		}
+		return a.name < b.name
+	})
+
+	return sorted[0].name, nil
+}
+
+// LaunchInstanceWithPlacement launches a new instance on the node selected by
+// the given placement rules. See PreviewPlacement for details.
+func (c *clientImpl) LaunchInstanceWithPlacement(details *api.InstancesPost, rules *PlacementRules, noWait bool) (client.Operation, error) {
+	node, err := c.PreviewPlacement(details, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	d := *details
+	d.Node = node
+	return c.LaunchInstance(&d, noWait)
+}
+
+// LaunchContainerWithPlacement launches a new container on the node selected
+// by the given placement rules. See PreviewPlacement for details.
+func (c *clientImpl) LaunchContainerWithPlacement(details *api.ContainersPost, rules *PlacementRules, noWait bool) (client.Operation, error) {
+	if details == nil {
+		return nil, errs.NewInvalidArgument("details")
+	}
+
+	instanceDetails := api.ContainersPostToInstancesPost(details)
+	node, err := c.PreviewPlacement(&instanceDetails, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	d := *details
+	d.Node = node
+	return c.LaunchContainer(&d, noWait)
+}
+
+// isSiblingInstance returns true if the instance runs the same application or
+// image an instance with the given details would run
+func isSiblingInstance(instance *api.Instance, details *api.InstancesPost) bool {
+	if len(details.ApplicationID) > 0 {
+		return instance.AppID == details.ApplicationID
+	}
+	return len(details.ImageID) > 0 && instance.ImageID == details.ImageID
+}
+
+// hasCapacity returns true if the node has enough free resources for the
+// resources requested by the given details
+func hasCapacity(status NodeResourceStatus, details *api.InstancesPost) bool {
+	r := details.Resources
+	if r.CPUs != nil && status.CPUs.Free() < int64(*r.CPUs) {
+		return false
+	}
+	if r.Memory != nil && status.Memory.Free() < *r.Memory {
+		return false
+	}
+	if r.DiskSize != nil && status.DiskSize.Free() < *r.DiskSize {
+		return false
+	}
+	if r.GPUSlots != nil && status.GPUSlots.Free() < int64(*r.GPUSlots) {
+		return false
+	}
+	if r.VPUSlots != nil && status.VPUSlots.Free() < int64(*r.VPUSlots) {
+		return false
+	}
+	return true
+}
+
+func hasAllTags(tags, required []string) bool {
+	for _, r := range required {
+		found := false
+		for _, t := range tags {
+			if t == r {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func hasAnyTag(tags, candidates []string) bool {
+	for _, c := range candidates {
+		for _, t := range tags {
+			if t == c {
+				return true
+			}
+		}
+	}
+	return false
+}
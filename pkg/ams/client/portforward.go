@@ -0,0 +1,162 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+
+	api "github.com/anbox-cloud/ams-sdk/api/ams"
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+)
+
+// PortForward represents an active forwarding of a local address to a port
+// inside an instance
+type PortForward struct {
+	listener net.Listener
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+
+	connsLock sync.Mutex
+	conns     map[net.Conn]struct{}
+}
+
+// Addr returns the local address connections are accepted on
+func (p *PortForward) Addr() net.Addr {
+	return p.listener.Addr()
+}
+
+// Close stops accepting new connections, closes all forwarded connections and
+// waits until their tunnels are torn down
+func (p *PortForward) Close() error {
+	p.cancel()
+	err := p.listener.Close()
+
+	p.connsLock.Lock()
+	for conn := range p.conns {
+		conn.Close()
+	}
+	p.connsLock.Unlock()
+
+	p.wg.Wait()
+	return err
+}
+
+func (p *PortForward) track(conn net.Conn, active bool) {
+	p.connsLock.Lock()
+	defer p.connsLock.Unlock()
+	if active {
+		p.conns[conn] = struct{}{}
+	} else {
+		delete(p.conns, conn)
+	}
+}
+
+// ForwardPort listens on the given local address and tunnels every accepted
+// TCP connection to the given port inside the instance, similar to
+// `kubectl port-forward`. AMS has no dedicated forwarding endpoint, so each
+// connection is tunneled through the websockets of a `nc` command executed in
+// the instance, which therefore must have `nc` installed. The forwarding stops
+// when the context is done or the returned PortForward is closed.
+func (c *clientImpl) ForwardPort(ctx context.Context, instanceID, localAddr string, remotePort int) (*PortForward, error) {
+	if len(instanceID) == 0 {
+		return nil, errs.NewInvalidArgument("instanceID")
+	}
+	if remotePort <= 0 || remotePort > 65535 {
+		return nil, errs.NewInvalidArgument("remotePort")
+	}
+
+	listener, err := net.Listen("tcp", localAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	p := &PortForward{
+		listener: listener,
+		cancel:   cancel,
+		conns:    map[net.Conn]struct{}{},
+	}
+
+	go func() {
+		<-ctx.Done()
+		p.listener.Close()
+	}()
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				// The listener is only closed when the forwarding is stopped
+				return
+			}
+
+			p.track(conn, true)
+			p.wg.Add(1)
+			go func() {
+				defer p.wg.Done()
+				defer p.track(conn, false)
+				defer conn.Close()
+				_ = c.tunnel(ctx, instanceID, remotePort, conn)
+			}()
+		}
+	}()
+
+	return p, nil
+}
+
+// tunnel forwards the given connection to the port inside the instance until
+// either side closes the connection
+func (c *clientImpl) tunnel(ctx context.Context, instanceID string, remotePort int, conn net.Conn) error {
+	dataDone := make(chan bool)
+	details := &api.InstanceExecPost{
+		Command:     []string{"nc", "127.0.0.1", strconv.Itoa(remotePort)},
+		Interactive: false,
+	}
+	args := &InstanceExecArgs{
+		Stdin:    conn,
+		Stdout:   conn,
+		Stderr:   nopWriteCloser{io.Discard},
+		DataDone: dataDone,
+	}
+
+	op, err := c.ExecuteInstance(instanceID, details, args)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case <-dataDone:
+	case <-ctx.Done():
+		_ = op.Cancel()
+		return ctx.Err()
+	}
+	return op.Wait(ctx)
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
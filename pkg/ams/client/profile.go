@@ -0,0 +1,219 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+	restclient "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/client"
+	"github.com/anbox-cloud/ams-sdk/pkg/network"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	// ConnectionProfileVersion is the version of the connection profile format
+	// written by ExportConnectionProfile
+	ConnectionProfileVersion = 1
+)
+
+// ProfileCodec is used to serialize and deserialize connection profiles
+type ProfileCodec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+type yamlProfileCodec struct{}
+
+func (yamlProfileCodec) Marshal(v interface{}) ([]byte, error)      { return yaml.Marshal(v) }
+func (yamlProfileCodec) Unmarshal(data []byte, v interface{}) error { return yaml.Unmarshal(data, v) }
+
+type jsonProfileCodec struct{}
+
+func (jsonProfileCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.MarshalIndent(v, "", "\t")
+}
+func (jsonProfileCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+var (
+	// ProfileCodecYAML serializes connection profiles as YAML. This is the default.
+	ProfileCodecYAML ProfileCodec = yamlProfileCodec{}
+	// ProfileCodecJSON serializes connection profiles as JSON
+	ProfileCodecJSON ProfileCodec = jsonProfileCodec{}
+)
+
+// ConnectionProfileOptions holds optional settings of a connection profile
+type ConnectionProfileOptions struct {
+	// Timeout for requests send to AMS, e.g. "30s". If empty the default
+	// transport timeout is used.
+	Timeout string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	// ReadOnly prevents the client from performing any request which would
+	// modify the state of the AMS service
+	ReadOnly bool `json:"read_only,omitempty" yaml:"read_only,omitempty"`
+}
+
+// ConnectionProfile describes how to connect to an AMS service in a portable
+// way so that a single vetted connection definition can be shared across
+// different tools. Secrets like the client key are only referenced by their
+// path and never embedded into the profile.
+type ConnectionProfile struct {
+	// Version of the profile format
+	Version int `json:"version" yaml:"version"`
+	// URL of the AMS service
+	URL string `json:"url" yaml:"url"`
+	// ServerCertificate is the PEM encoded certificate of the AMS service. If
+	// set, the service is verified against it.
+	ServerCertificate string `json:"server_certificate,omitempty" yaml:"server_certificate,omitempty"`
+	// CAFile references a file with the CA certificates used to verify the AMS
+	// service
+	CAFile string `json:"ca_file,omitempty" yaml:"ca_file,omitempty"`
+	// ClientCertFile references the file with the client certificate
+	ClientCertFile string `json:"client_cert_file,omitempty" yaml:"client_cert_file,omitempty"`
+	// ClientKeyFile references the file with the private key of the client
+	ClientKeyFile string `json:"client_key_file,omitempty" yaml:"client_key_file,omitempty"`
+	// Insecure disables the verification of the certificate of the AMS service
+	Insecure bool `json:"insecure,omitempty" yaml:"insecure,omitempty"`
+	// Options holds additional client settings
+	Options ConnectionProfileOptions `json:"options,omitempty" yaml:"options,omitempty"`
+}
+
+// Validate checks that the connection profile is complete and consistent
+func (p *ConnectionProfile) Validate() error {
+	if p.Version > ConnectionProfileVersion {
+		return fmt.Errorf("unsupported connection profile version %d", p.Version)
+	}
+	if len(p.URL) == 0 {
+		return errs.NewInvalidArgument("url")
+	}
+	if _, err := url.Parse(p.URL); err != nil {
+		return errs.NewInvalidArgument("url")
+	}
+	if (len(p.ClientCertFile) == 0) != (len(p.ClientKeyFile) == 0) {
+		return fmt.Errorf("client certificate and key must be specified together")
+	}
+	if len(p.Options.Timeout) > 0 {
+		if _, err := time.ParseDuration(p.Options.Timeout); err != nil {
+			return errs.NewInvalidArgument("options.timeout")
+		}
+	}
+	return nil
+}
+
+// TLSConfig returns the TLS configuration described by the profile. Paths to
+// referenced files are expanded with environment variables.
+func (p *ConnectionProfile) TLSConfig() (*tls.Config, error) {
+	var serverCert *x509.Certificate
+	if len(p.ServerCertificate) > 0 {
+		block, _ := pem.Decode([]byte(p.ServerCertificate))
+		if block == nil {
+			return nil, errs.NewErrInvalidFormat("server certificate")
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		serverCert = cert
+	}
+
+	tlsConfig, err := network.GetTLSConfig(os.ExpandEnv(p.ClientCertFile),
+		os.ExpandEnv(p.ClientKeyFile), os.ExpandEnv(p.CAFile), serverCert)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig.InsecureSkipVerify = p.Insecure
+	return tlsConfig, nil
+}
+
+// NewClient creates a new client connected to the AMS service described by
+// the profile
+func (p *ConnectionProfile) NewClient() (Client, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(p.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := p.TLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return New(u, tlsConfig, p.ClientOptions()...)
+}
+
+// ClientOptions returns the client options described by the profile
+func (p *ConnectionProfile) ClientOptions() []restclient.Option {
+	opts := []restclient.Option{}
+	if len(p.Options.Timeout) > 0 {
+		timeout, _ := time.ParseDuration(p.Options.Timeout)
+		opts = append(opts, restclient.WithTransportTimeout(timeout))
+	}
+	if p.Options.ReadOnly {
+		opts = append(opts, restclient.WithReadOnly())
+	}
+	return opts
+}
+
+// ExportConnectionProfile serializes the given profile with the given codec.
+// If no codec is given the profile is serialized as YAML.
+func ExportConnectionProfile(p *ConnectionProfile, codec ProfileCodec) ([]byte, error) {
+	if p == nil {
+		return nil, errs.NewInvalidArgument("profile")
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	if codec == nil {
+		codec = ProfileCodecYAML
+	}
+
+	profile := *p
+	profile.Version = ConnectionProfileVersion
+	return codec.Marshal(&profile)
+}
+
+// ImportConnectionProfile deserializes a profile previously written by
+// ExportConnectionProfile with the given codec. If no codec is given the
+// profile is expected to be YAML (or JSON, which is a subset of YAML).
+func ImportConnectionProfile(data []byte, codec ProfileCodec) (*ConnectionProfile, error) {
+	if codec == nil {
+		codec = ProfileCodecYAML
+	}
+
+	p := &ConnectionProfile{}
+	if err := codec.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("failed to parse connection profile: %v", err)
+	}
+
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
@@ -0,0 +1,167 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+)
+
+// SessionRecorder records the data exchanged with the command of an exec
+// session, e.g. for auditing operator shells
+type SessionRecorder interface {
+	// Input is called with the data sent to the standard input of the command
+	Input(data []byte)
+	// Output is called with the data the command wrote to its standard output
+	// or standard error
+	Output(data []byte)
+}
+
+// AsciicastHeader is the header of a session recorded in the asciicast v2
+// format
+type AsciicastHeader struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// AsciicastRecorder writes an exec session in the asciicast v2 format, which
+// can be replayed with asciinema
+type AsciicastRecorder struct {
+	lock  sync.Mutex
+	w     io.Writer
+	start time.Time
+	err   error
+}
+
+// NewAsciicastRecorder writes the header of a new recording with the given
+// terminal size to w and returns a recorder writing the session events to it
+func NewAsciicastRecorder(w io.Writer, width, height int, title string) (*AsciicastRecorder, error) {
+	if w == nil {
+		return nil, errs.NewInvalidArgument("writer")
+	}
+	if width <= 0 || height <= 0 {
+		return nil, errs.NewInvalidArgument("size")
+	}
+
+	start := time.Now()
+	header, err := json.Marshal(AsciicastHeader{
+		Version:   2,
+		Width:     width,
+		Height:    height,
+		Timestamp: start.Unix(),
+		Title:     title,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(append(header, '\n')); err != nil {
+		return nil, err
+	}
+	return &AsciicastRecorder{w: w, start: start}, nil
+}
+
+// Input records data sent to the command
+func (r *AsciicastRecorder) Input(data []byte) {
+	r.event("i", string(data))
+}
+
+// Output records data written by the command
+func (r *AsciicastRecorder) Output(data []byte) {
+	r.event("o", string(data))
+}
+
+// Resize records a new size of the terminal
+func (r *AsciicastRecorder) Resize(width, height int) {
+	r.event("r", fmt.Sprintf("%dx%d", width, height))
+}
+
+// Err returns the first error writing the recording failed with. Once an
+// error occurred no further events are recorded.
+func (r *AsciicastRecorder) Err() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.err
+}
+
+func (r *AsciicastRecorder) event(kind, data string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.err != nil {
+		return
+	}
+
+	elapsed := time.Since(r.start).Seconds()
+	line, err := json.Marshal([]interface{}{elapsed, kind, data})
+	if err == nil {
+		_, err = r.w.Write(append(line, '\n'))
+	}
+	r.err = err
+}
+
+// recordedReader passes all data read to the recorder
+type recordedReader struct {
+	io.ReadCloser
+	record func(data []byte)
+}
+
+func (r *recordedReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		r.record(p[:n])
+	}
+	return n, err
+}
+
+// recordedWriter passes all data written to the recorder
+type recordedWriter struct {
+	io.WriteCloser
+	record func(data []byte)
+}
+
+func (w *recordedWriter) Write(p []byte) (int, error) {
+	n, err := w.WriteCloser.Write(p)
+	if n > 0 {
+		w.record(p[:n])
+	}
+	return n, err
+}
+
+// recordStreams wraps the given streams so their data is passed to the
+// recorder. Streams which are not set stay unset.
+func recordStreams(rec SessionRecorder, stdin io.ReadCloser, stdout, stderr io.WriteCloser) (io.ReadCloser, io.WriteCloser, io.WriteCloser) {
+	if stdin != nil {
+		stdin = &recordedReader{ReadCloser: stdin, record: rec.Input}
+	}
+	if stdout != nil {
+		stdout = &recordedWriter{WriteCloser: stdout, record: rec.Output}
+	}
+	if stderr != nil {
+		stderr = &recordedWriter{WriteCloser: stderr, record: rec.Output}
+	}
+	return stdin, stdout, stderr
+}
@@ -19,10 +19,43 @@
 package client
 
 import (
+	"fmt"
+
 	api "github.com/anbox-cloud/ams-sdk/api/ams"
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
 	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/client"
 )
 
+const (
+	registryConfigURL            = "registry.url"
+	registryConfigMode           = "registry.mode"
+	registryConfigUpdateInterval = "registry.update_interval"
+	registryConfigFilter         = "registry.filter"
+	registryConfigFingerprint    = "registry.fingerprint"
+)
+
+// RegistrySyncResult describes the result of synchronizing a single application
+// with the application registry
+type RegistrySyncResult struct {
+	// Application is the name (for pulls) or ID (for pushes) of the application
+	Application string
+	// Operation tracks the synchronization on the AMS side. Nil if Err is set.
+	Operation client.Operation
+	// Err is set when the synchronization could not be triggered
+	Err error
+}
+
+func (c *clientImpl) checkRegistrySupport() error {
+	hasRegistrySupport, err := c.HasExtension(api.ExtensionRegistry)
+	if err != nil {
+		return err
+	}
+	if !hasRegistrySupport {
+		return errs.NewErrNotSupported("api extension \"registry\"")
+	}
+	return nil
+}
+
 // ListApplicationsFromRegistry returns a list of all availables applications through the
 // registered application registry
 func (c *clientImpl) ListApplicationsFromRegistry() ([]api.RegistryApplication, error) {
@@ -48,3 +81,105 @@ func (c *clientImpl) DeleteApplicationFromRegistry(id string) (client.Operation,
 	op, _, err := c.QueryOperation("DELETE", client.APIPath("registry", "applications", id), nil, nil, nil, "")
 	return op, err
 }
+
+// RetrieveRegistryConfig returns the application registry configuration of the
+// AMS service
+func (c *clientImpl) RetrieveRegistryConfig() (*api.RegistryConfig, error) {
+	items, err := c.RetrieveConfigItems()
+	if err != nil {
+		return nil, err
+	}
+
+	value := func(name string) string {
+		v, ok := items[name]
+		if !ok || v == nil {
+			return ""
+		}
+		return fmt.Sprintf("%v", v)
+	}
+
+	return &api.RegistryConfig{
+		URL:            value(registryConfigURL),
+		Mode:           api.RegistryMode(value(registryConfigMode)),
+		UpdateInterval: value(registryConfigUpdateInterval),
+		Filter:         value(registryConfigFilter),
+		Fingerprint:    value(registryConfigFingerprint),
+	}, nil
+}
+
+// UpdateRegistryConfig changes the application registry configuration of the
+// AMS service. Only the fields set in the patch are applied, an empty value
+// clears the configuration item.
+func (c *clientImpl) UpdateRegistryConfig(patch *api.RegistryConfigPatch) error {
+	if patch == nil {
+		return errs.NewInvalidArgument("patch")
+	}
+
+	items := []api.ConfigPost{}
+	add := func(name string, value *string) {
+		if value != nil {
+			items = append(items, api.ConfigPost{Name: name, Value: *value})
+		}
+	}
+	add(registryConfigURL, patch.URL)
+	if patch.Mode != nil {
+		switch *patch.Mode {
+		case "", api.RegistryModeManual, api.RegistryModePull, api.RegistryModePush:
+		default:
+			return errs.NewInvalidArgument("mode")
+		}
+		mode := string(*patch.Mode)
+		add(registryConfigMode, &mode)
+	}
+	add(registryConfigUpdateInterval, patch.UpdateInterval)
+	add(registryConfigFilter, patch.Filter)
+	add(registryConfigFingerprint, patch.Fingerprint)
+
+	for _, item := range items {
+		if err := c.SetConfigItem(item.Name, item.Value); err != nil {
+			return fmt.Errorf("failed to set %s: %v", item.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// SyncApplicationsWithRegistry triggers a synchronization of all applications
+// with the configured application registry. With RegistryModePull all
+// applications the registry offers are pulled, with RegistryModePush all
+// published applications are pushed. The returned results contain an operation
+// per application which can be used to track the status of the synchronization.
+func (c *clientImpl) SyncApplicationsWithRegistry(mode api.RegistryMode) ([]RegistrySyncResult, error) {
+	if err := c.checkRegistrySupport(); err != nil {
+		return nil, err
+	}
+
+	results := []RegistrySyncResult{}
+	switch mode {
+	case api.RegistryModePull:
+		apps, err := c.ListApplicationsFromRegistry()
+		if err != nil {
+			return nil, err
+		}
+		for _, app := range apps {
+			op, err := c.PullApplicationFromRegistry(app.Name)
+			results = append(results, RegistrySyncResult{Application: app.Name, Operation: op, Err: err})
+		}
+	case api.RegistryModePush:
+		apps, err := c.ListApplications()
+		if err != nil {
+			return nil, err
+		}
+		for _, app := range apps {
+			if !app.Published {
+				continue
+			}
+			op, err := c.PushApplicationToRegistry(app.ID)
+			results = append(results, RegistrySyncResult{Application: app.ID, Operation: op, Err: err})
+		}
+	default:
+		return nil, errs.NewInvalidArgument("mode")
+	}
+
+	return results, nil
+}
@@ -19,10 +19,19 @@
 package client
 
 import (
+	"context"
+	"fmt"
+	"time"
+
 	api "github.com/anbox-cloud/ams-sdk/api/ams"
 	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/client"
 )
 
+const (
+	// Timeout for probing the AMS service if the context has no deadline
+	defaultProbeTimeout = 5 * time.Second
+)
+
 // RetrieveServiceStatus returns the status of the AMS service
 func (c *clientImpl) RetrieveServiceStatus() (*api.ServiceStatus, string, error) {
 	status := &api.ServiceStatus{}
@@ -30,19 +39,75 @@ func (c *clientImpl) RetrieveServiceStatus() (*api.ServiceStatus, string, error)
 	return status, etag, err
 }
 
-// HasExtension checks if the AMS service the client is connected to supports
-// the given API extension. Returns true if the API extension is supported and
-// false otherwise.
-func (c *clientImpl) HasExtension(name string) (bool, error) {
+// ServerStatus returns the status of the AMS service, including its API
+// version, extensions and the cluster it is part of. If the context has no
+// deadline the request is limited to 5 seconds, which makes it suitable for
+// liveness probes.
+func (c *clientImpl) ServerStatus(ctx context.Context) (*api.ServiceStatus, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultProbeTimeout)
+		defer cancel()
+	}
+
+	status := &api.ServiceStatus{}
+	_, err := c.QueryStructWithContext(ctx, "GET", client.APIPath(""), nil, nil, nil, "", status)
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// Ping checks that the AMS service is reachable and trusts the client. See
+// ServerStatus for the timeout applied.
+func (c *clientImpl) Ping(ctx context.Context) error {
+	status, err := c.ServerStatus(ctx)
+	if err != nil {
+		return err
+	}
+	if status.Auth != "trusted" {
+		return fmt.Errorf("client is not trusted by the AMS service")
+	}
+	return nil
+}
+
+// Extensions returns the API extensions the AMS service supports. The list is
+// retrieved once and cached, use RefreshExtensions to update it, e.g. after
+// the AMS service was upgraded.
+func (c *clientImpl) Extensions() ([]string, error) {
+	c.serviceStatusLock.Lock()
+	defer c.serviceStatusLock.Unlock()
+
 	if c.serviceStatus == nil {
 		status, _, err := c.RetrieveServiceStatus()
 		if err != nil {
-			return false, err
+			return nil, err
 		}
 		c.serviceStatus = status
 	}
+	return append([]string{}, c.serviceStatus.APIExtensions...), nil
+}
+
+// RefreshExtensions retrieves the API extensions the AMS service supports
+// again and updates the cached list
+func (c *clientImpl) RefreshExtensions() ([]string, error) {
+	c.serviceStatusLock.Lock()
+	c.serviceStatus = nil
+	c.serviceStatusLock.Unlock()
+	return c.Extensions()
+}
+
+// HasExtension checks if the AMS service the client is connected to supports
+// the given API extension. Returns true if the API extension is supported and
+// false otherwise. See the Extension constants of the api package for the
+// known extensions.
+func (c *clientImpl) HasExtension(name string) (bool, error) {
+	extensions, err := c.Extensions()
+	if err != nil {
+		return false, err
+	}
 
-	for _, ext := range c.serviceStatus.APIExtensions {
+	for _, ext := range extensions {
 		if ext == name {
 			return true, nil
 		}
@@ -0,0 +1,137 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/signal"
+
+	api "github.com/anbox-cloud/ams-sdk/api/ams"
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+	"github.com/gorilla/websocket"
+	"golang.org/x/term"
+)
+
+const (
+	// Signal sent to the remote command when the context is cancelled, the
+	// same a shell receives when its terminal goes away
+	signalHangup = 1
+)
+
+// defaultShellCommand is run by RunInteractiveShell if no command is given
+var defaultShellCommand = []string{"/bin/sh"}
+
+// RunInteractiveShell runs the given command inside the instance with the
+// local terminal attached and returns the exit code of the command. If stdin
+// is a terminal it is put into raw mode for the duration of the session and
+// size changes of the terminal are forwarded to the instance. If no command
+// is given /bin/sh is started. The command receives a hangup signal and the
+// operation is cancelled when the context is done.
+func (c *clientImpl) RunInteractiveShell(ctx context.Context, id string, command []string) (int, error) {
+	if len(id) == 0 {
+		return -1, errs.NewInvalidArgument("id")
+	}
+	if len(command) == 0 {
+		command = defaultShellCommand
+	}
+
+	fd := int(os.Stdin.Fd())
+	interactive := term.IsTerminal(fd)
+
+	details := &api.InstanceExecPost{
+		Command:     command,
+		Environment: map[string]string{},
+		Interactive: interactive,
+	}
+	if value := os.Getenv("TERM"); len(value) > 0 {
+		details.Environment["TERM"] = value
+	}
+
+	if interactive {
+		width, height, err := term.GetSize(fd)
+		if err == nil {
+			details.Width = width
+			details.Height = height
+		}
+
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return -1, err
+		}
+		defer term.Restore(fd, state)
+	}
+
+	sessionDone := make(chan struct{})
+	defer close(sessionDone)
+
+	dataDone := make(chan bool)
+	op, err := c.ExecuteInstance(id, details, &InstanceExecArgs{
+		// The session must not close the stdin of the process
+		Stdin:    io.NopCloser(os.Stdin),
+		Stdout:   os.Stdout,
+		Stderr:   os.Stderr,
+		DataDone: dataDone,
+		Control: func(conn *websocket.Conn) {
+			handleShellControl(ctx, conn, fd, interactive, sessionDone)
+		},
+	})
+	if err != nil {
+		return -1, err
+	}
+
+	if err := op.Wait(ctx); err != nil {
+		return -1, err
+	}
+	<-dataDone
+
+	code, ok := op.Get().Metadata["return"].(float64)
+	if !ok {
+		return -1, errs.NewErrInvalidFormat("exec operation")
+	}
+	return int(code), nil
+}
+
+// handleShellControl forwards terminal size changes and the cancellation of
+// the context to the instance until the session is done
+func handleShellControl(ctx context.Context, conn *websocket.Conn, fd int, interactive bool, done <-chan struct{}) {
+	defer conn.Close()
+
+	resize := make(chan os.Signal, 1)
+	if interactive {
+		notifyResize(resize)
+		defer signal.Stop(resize)
+	}
+
+	for {
+		select {
+		case <-resize:
+			width, height, err := term.GetSize(fd)
+			if err == nil {
+				_ = conn.WriteJSON(windowResizeMessage(width, height))
+			}
+		case <-ctx.Done():
+			_ = conn.WriteJSON(api.InstanceExecControl{Command: "signal", Signal: signalHangup})
+			return
+		case <-done:
+			return
+		}
+	}
+}
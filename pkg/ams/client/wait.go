@@ -0,0 +1,131 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"time"
+
+	api "github.com/anbox-cloud/ams-sdk/api/ams"
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+)
+
+const (
+	// Interval in which the status is polled when events are received
+	waitPollIntervalWithEvents = 10 * time.Second
+	// Interval in which the status is polled when no events can be received
+	waitPollInterval = 2 * time.Second
+)
+
+// WaitForInstanceStatus blocks until the instance with the given ID reaches
+// one of the given statuses or the context is done. Status changes are
+// detected through lifecycle events. If events cannot be received the status
+// is polled. If the instance enters the error status and it is not one of the
+// expected statuses an error is returned.
+func (c *clientImpl) WaitForInstanceStatus(ctx context.Context, id string, statuses ...api.InstanceStatus) (*api.Instance, error) {
+	if len(id) == 0 {
+		return nil, errs.NewInvalidArgument("id")
+	}
+	if len(statuses) == 0 {
+		return nil, errs.NewInvalidArgument("statuses")
+	}
+
+	changed := make(chan struct{}, 1)
+	interval := waitPollInterval
+
+	listener, err := c.GetEvents()
+	if err == nil {
+		defer listener.Disconnect()
+		_, err = listener.AddHandler([]string{string(api.EventTypeLifecycle)}, func(data interface{}) {
+			if lifecycleEventSource(data) != id {
+				return
+			}
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+		})
+		if err == nil {
+			interval = waitPollIntervalWithEvents
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		instance, _, err := c.RetrieveInstanceByID(id)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, status := range statuses {
+			if instance.StatusCode == status {
+				return instance, nil
+			}
+		}
+		if instance.StatusCode == api.InstanceStatusError {
+			return instance, fmt.Errorf("instance %s failed: %s", id, instance.ErrorMessage)
+		}
+
+		select {
+		case <-ctx.Done():
+			return instance, ctx.Err()
+		case <-changed:
+		case <-ticker.C:
+		}
+	}
+}
+
+// WaitForContainerStatus blocks until the container with the given ID reaches
+// one of the given statuses or the context is done. See WaitForInstanceStatus
+// for details.
+func (c *clientImpl) WaitForContainerStatus(ctx context.Context, id string, statuses ...api.ContainerStatus) (*api.Container, error) {
+	instanceStatuses := make([]api.InstanceStatus, 0, len(statuses))
+	for _, status := range statuses {
+		instanceStatuses = append(instanceStatuses, api.InstanceStatus(status))
+	}
+
+	instance, err := c.WaitForInstanceStatus(ctx, id, instanceStatuses...)
+	if instance == nil {
+		return nil, err
+	}
+	container := api.InstanceToContainer(instance)
+	return &container, err
+}
+
+// lifecycleEventSource returns the ID of the object a lifecycle event was
+// reported for
+func lifecycleEventSource(data interface{}) string {
+	message, ok := data.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	metadata, ok := message["metadata"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	source, ok := metadata["source"].(string)
+	if !ok {
+		return ""
+	}
+	return path.Base(source)
+}
@@ -0,0 +1,229 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	api "github.com/anbox-cloud/ams-sdk/api/ams"
+)
+
+// ContainerWatchEvent describes a change of a watched container
+type ContainerWatchEvent struct {
+	// ID of the container
+	ID string
+	// Action reported by the lifecycle event
+	Action api.LifecycleEventAction
+	// Container is the state of the container after the event. Nil if the
+	// container was removed or does not match the filters of the watch anymore.
+	Container *api.Container
+}
+
+// ContainerWatch holds the initial state and the following changes of a set
+// of containers
+type ContainerWatch struct {
+	// Initial contains all containers matching the filters when the watch
+	// was started
+	Initial []api.Container
+	// Events receives all changes after the initial state was retrieved. It is
+	// closed when the context of the watch is done or the watch failed.
+	Events <-chan ContainerWatchEvent
+
+	lock sync.Mutex
+	err  error
+}
+
+// Err returns the reason the watch ended once Events was closed
+func (w *ContainerWatch) Err() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.err
+}
+
+func (w *ContainerWatch) setErr(err error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.err == nil {
+		w.err = err
+	}
+}
+
+// WatchContainersWithInitialState returns all containers matching the given
+// filters together with a channel receiving their following changes.
+//
+// AMS does not provide resource versions, so the event subscription is set up
+// before the containers are listed and all events received in the meantime
+// are delivered after the initial state. No transition is missed that way,
+// but a transition may be reported which is already part of the initial
+// state. The event listener runs handlers concurrently, so events can be
+// delivered in a different order than AMS emitted them and the sequence of
+// actions is not reliable. The state of a container is retrieved when its
+// event is delivered rather than taken from the event, so the last event
+// delivered for a container carries its current state.
+func (c *clientImpl) WatchContainersWithInitialState(ctx context.Context, filters []string) (*ContainerWatch, error) {
+	if _, err := convertFiltersToParams(filters); err != nil {
+		return nil, err
+	}
+
+	listener, err := c.GetEvents()
+	if err != nil {
+		return nil, err
+	}
+
+	// Event handlers are called concurrently, so the order of the queue
+	// depends on scheduling and not necessarily matches the order AMS sent
+	// the events in
+	var queueLock sync.Mutex
+	queue := []map[string]interface{}{}
+	queued := make(chan struct{}, 1)
+	_, err = listener.AddHandler([]string{string(api.EventTypeLifecycle)}, func(data interface{}) {
+		message, ok := data.(map[string]interface{})
+		if !ok {
+			return
+		}
+		queueLock.Lock()
+		queue = append(queue, message)
+		queueLock.Unlock()
+		select {
+		case queued <- struct{}{}:
+		default:
+		}
+	})
+	if err != nil {
+		listener.Disconnect()
+		return nil, err
+	}
+
+	containers, err := c.ListContainersWithFilters(filters)
+	if err != nil {
+		listener.Disconnect()
+		return nil, err
+	}
+
+	known := map[string]bool{}
+	for _, container := range containers {
+		known[container.ID] = true
+	}
+
+	events := make(chan ContainerWatchEvent)
+	watch := &ContainerWatch{Initial: containers, Events: events}
+
+	disconnected := make(chan struct{})
+	go func() {
+		if err := listener.Wait(); err != nil {
+			watch.setErr(err)
+		}
+		close(disconnected)
+	}()
+
+	go func() {
+		defer close(events)
+		defer listener.Disconnect()
+
+		for {
+			queueLock.Lock()
+			pending := queue
+			queue = nil
+			queueLock.Unlock()
+
+			for _, message := range pending {
+				event, ok, err := c.containerWatchEvent(message, filters, known)
+				if err != nil {
+					watch.setErr(err)
+					return
+				}
+				if !ok {
+					continue
+				}
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					watch.setErr(ctx.Err())
+					return
+				}
+			}
+
+			select {
+			case <-queued:
+			case <-disconnected:
+				watch.setErr(fmt.Errorf("event listener disconnected"))
+				return
+			case <-ctx.Done():
+				watch.setErr(ctx.Err())
+				return
+			}
+		}
+	}()
+
+	return watch, nil
+}
+
+// containerWatchEvent converts a lifecycle event into a watch event. Returns
+// false if the event does not affect a watched container.
+func (c *clientImpl) containerWatchEvent(message map[string]interface{}, filters []string, known map[string]bool) (ContainerWatchEvent, bool, error) {
+	id := lifecycleEventSource(message)
+	if len(id) == 0 {
+		return ContainerWatchEvent{}, false, nil
+	}
+	event := ContainerWatchEvent{ID: id, Action: lifecycleEventAction(message)}
+
+	if event.Action == api.LifecycleEventActionContainerRemoved ||
+		event.Action == api.LifecycleEventActionInstanceRemoved {
+		if !known[id] {
+			return event, false, nil
+		}
+		delete(known, id)
+		return event, true, nil
+	}
+
+	// Retrieve the current state restricted to the filters of the watch
+	// to find out if the container is (still) part of the watched set
+	containers, err := c.ListContainersWithFilters(append(append([]string{}, filters...), "id="+id))
+	if err != nil {
+		return event, false, err
+	}
+	for n := range containers {
+		if containers[n].ID == id {
+			known[id] = true
+			event.Container = &containers[n]
+			return event, true, nil
+		}
+	}
+	if !known[id] {
+		return event, false, nil
+	}
+	delete(known, id)
+	return event, true, nil
+}
+
+// lifecycleEventAction returns the action of a lifecycle event
+func lifecycleEventAction(data interface{}) api.LifecycleEventAction {
+	message, ok := data.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	metadata, ok := message["metadata"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	action, _ := metadata["action"].(string)
+	return api.LifecycleEventAction(action)
+}
@@ -0,0 +1,250 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package config reads the remote configuration shared with the amc command
+// line client and creates clients for the configured remotes, so tools can
+// reuse the remotes and credentials set up with amc.
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/client"
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared"
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+	restclient "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/client"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	// DefaultRemoteName is the name of the remote used when the
+	// configuration does not name a default one
+	DefaultRemoteName = "local"
+	// DefaultUnixSocketPath is the path of the unix socket of a local AMS
+	// installation
+	DefaultUnixSocketPath = "/var/snap/ams/common/server/unix.socket"
+	// DirEnvironmentVariable overwrites the directory holding the configuration
+	DirEnvironmentVariable = "AMC_CONF"
+
+	configFileName = "config.yml"
+	clientCertName = "client.crt"
+	clientKeyName  = "client.key"
+	serverCertsDir = "servercerts"
+)
+
+// Remote describes a single AMS service the client can connect to
+type Remote struct {
+	// Addr is the address of the AMS service, either a https:// URL or
+	// unix:// followed by the path of the unix socket
+	Addr string `yaml:"addr"`
+	// Public marks remotes which are accessed without a client certificate
+	Public bool `yaml:"public,omitempty"`
+}
+
+// Config holds the remotes known to the client
+type Config struct {
+	// DefaultRemote is the name of the remote used if no remote is specified
+	DefaultRemote string `yaml:"default-remote"`
+	// Remotes maps remote names to their details
+	Remotes map[string]Remote `yaml:"remotes"`
+
+	// Dir is the directory the configuration was loaded from. Client and
+	// server certificates are looked up in it.
+	Dir string `yaml:"-"`
+}
+
+// DefaultDir returns the directory the configuration is read from by
+// default. It can be overwritten with the AMC_CONF environment variable.
+func DefaultDir() (string, error) {
+	if dir := os.Getenv(DirEnvironmentVariable); len(dir) > 0 {
+		return dir, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "ams"), nil
+}
+
+// LoadDefault loads the configuration from the default directory
+func LoadDefault() (*Config, error) {
+	dir, err := DefaultDir()
+	if err != nil {
+		return nil, err
+	}
+	return Load(dir)
+}
+
+// Load reads the configuration from the given directory. If the directory
+// has no configuration yet, a configuration with only the local remote is
+// returned.
+func Load(dir string) (*Config, error) {
+	c := &Config{Dir: dir}
+
+	data, err := os.ReadFile(filepath.Join(dir, configFileName))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		if err := yaml.Unmarshal(data, c); err != nil {
+			return nil, fmt.Errorf("failed to parse configuration: %v", err)
+		}
+	}
+
+	if len(c.DefaultRemote) == 0 {
+		c.DefaultRemote = DefaultRemoteName
+	}
+	if c.Remotes == nil {
+		c.Remotes = map[string]Remote{}
+	}
+	if _, ok := c.Remotes[DefaultRemoteName]; !ok {
+		c.Remotes[DefaultRemoteName] = Remote{Addr: "unix://"}
+	}
+	return c, nil
+}
+
+// RemoteNames returns the sorted names of all configured remotes
+func (c *Config) RemoteNames() []string {
+	names := make([]string, 0, len(c.Remotes))
+	for name := range c.Remotes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Remote returns the remote with the given name. If no name is given the
+// default remote is returned.
+func (c *Config) Remote(name string) (Remote, error) {
+	if len(name) == 0 {
+		name = c.DefaultRemote
+	}
+	remote, ok := c.Remotes[name]
+	if !ok {
+		return Remote{}, errs.NewErrNotFound(fmt.Sprintf("remote %s", name))
+	}
+	return remote, nil
+}
+
+// ClientCertPath returns the path of the client certificate
+func (c *Config) ClientCertPath() string {
+	return filepath.Join(c.Dir, clientCertName)
+}
+
+// ClientKeyPath returns the path of the private key of the client
+func (c *Config) ClientKeyPath() string {
+	return filepath.Join(c.Dir, clientKeyName)
+}
+
+// ServerCertPath returns the path of the certificate stored for the remote
+// with the given name
+func (c *Config) ServerCertPath(name string) string {
+	return filepath.Join(c.Dir, serverCertsDir, fmt.Sprintf("%s.crt", name))
+}
+
+// Profile returns the connection profile of the remote with the given name.
+// If no name is given the default remote is used. Remotes without a stored
+// server certificate are verified against the system CAs. Remotes connected
+// through a unix socket have no profile.
+func (c *Config) Profile(name string) (*client.ConnectionProfile, error) {
+	if len(name) == 0 {
+		name = c.DefaultRemote
+	}
+	remote, err := c.Remote(name)
+	if err != nil {
+		return nil, err
+	}
+	if isUnixAddr(remote.Addr) {
+		return nil, errs.NewErrNotSupported(fmt.Sprintf("connection profile for unix socket remote %s", name))
+	}
+
+	profile := &client.ConnectionProfile{
+		Version: client.ConnectionProfileVersion,
+		URL:     remote.Addr,
+	}
+
+	serverCertPath := c.ServerCertPath(name)
+	if shared.PathExists(serverCertPath) {
+		data, err := os.ReadFile(serverCertPath)
+		if err != nil {
+			return nil, err
+		}
+		profile.ServerCertificate = string(data)
+	}
+
+	if !remote.Public && shared.PathExists(c.ClientCertPath()) {
+		profile.ClientCertFile = c.ClientCertPath()
+		profile.ClientKeyFile = c.ClientKeyPath()
+	}
+
+	return profile, nil
+}
+
+// NewClient creates a client for the remote with the given name. If no name
+// is given the default remote is used. The given options are applied after
+// the ones derived from the configuration.
+func (c *Config) NewClient(name string, opts ...restclient.Option) (client.Client, error) {
+	if len(name) == 0 {
+		name = c.DefaultRemote
+	}
+	remote, err := c.Remote(name)
+	if err != nil {
+		return nil, err
+	}
+	if isUnixAddr(remote.Addr) {
+		return client.New(unixSocketPath(remote.Addr), nil, opts...)
+	}
+
+	profile, err := c.Profile(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := profile.Validate(); err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(profile.URL)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := profile.TLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.New(u, tlsConfig, append(profile.ClientOptions(), opts...)...)
+}
+
+func isUnixAddr(addr string) bool {
+	return strings.HasPrefix(addr, "unix:")
+}
+
+// unixSocketPath returns the socket path of a unix:// address. An address
+// without a path refers to the socket of the local AMS installation.
+func unixSocketPath(addr string) string {
+	path := strings.TrimPrefix(strings.TrimPrefix(addr, "unix:"), "//")
+	if len(path) == 0 {
+		return DefaultUnixSocketPath
+	}
+	return path
+}
@@ -0,0 +1,411 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package eventsbridge reposts AMS events to HTTP webhooks. It allows systems
+// which can't hold the events websocket open to react to events: every event
+// matching the filters of a webhook is sent as a signed HTTP POST request,
+// retried on failure and handed to a dead-letter handler when it could not be
+// delivered.
+package eventsbridge
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	api "github.com/anbox-cloud/ams-sdk/api/ams"
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared"
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+	restclient "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/client"
+)
+
+const (
+	// HeaderDelivery carries the unique ID of a delivery. Retries of the same
+	// delivery use the same ID, so receivers can drop duplicates.
+	HeaderDelivery = "X-AMS-Delivery"
+	// HeaderEvent carries the type of the delivered event
+	HeaderEvent = "X-AMS-Event"
+	// HeaderTimestamp carries the time the request was signed at in seconds
+	// since the Unix epoch
+	HeaderTimestamp = "X-AMS-Timestamp"
+	// HeaderSignature carries the HMAC-SHA256 signature of the request
+	HeaderSignature = "X-AMS-Signature"
+
+	defaultQueueSize   = 100
+	defaultMaxAttempts = 5
+	defaultHTTPTimeout = 10 * time.Second
+)
+
+// EventSource provides the events to bridge. It is implemented by the AMS and
+// the REST client.
+type EventSource interface {
+	GetEvents() (*restclient.EventListener, error)
+}
+
+// Webhook describes a HTTP endpoint events are delivered to
+type Webhook struct {
+	// Name identifies the webhook in deliveries
+	Name string `json:"name" yaml:"name"`
+	// URL the events are posted to
+	URL string `json:"url" yaml:"url"`
+	// Types limits the delivered events to the given types. Empty means all.
+	Types []api.EventType `json:"types,omitempty" yaml:"types,omitempty"`
+	// Actions limits the delivered lifecycle events to the given actions.
+	// Empty means all. Events of other types are not affected.
+	Actions []api.LifecycleEventAction `json:"actions,omitempty" yaml:"actions,omitempty"`
+	// Secret is used to sign the requests. Requests are not signed if empty.
+	Secret string `json:"secret,omitempty" yaml:"secret,omitempty"`
+	// Headers are added to every request
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	// Filter allows dropping further events. Optional.
+	Filter func(event *api.Event) bool `json:"-" yaml:"-"`
+}
+
+// Matches returns true if the given event has to be delivered to the webhook
+func (w *Webhook) Matches(event *api.Event) bool {
+	if len(w.Types) > 0 {
+		found := false
+		for _, t := range w.Types {
+			if t == event.Type {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(w.Actions) > 0 && event.Type == api.EventTypeLifecycle {
+		action := lifecycleAction(event)
+		found := false
+		for _, a := range w.Actions {
+			if a == action {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return w.Filter == nil || w.Filter(event)
+}
+
+// Delivery describes the delivery of a single event to a webhook
+type Delivery struct {
+	// ID uniquely identifies the delivery
+	ID string `json:"id" yaml:"id"`
+	// Webhook is the name of the webhook
+	Webhook string `json:"webhook" yaml:"webhook"`
+	// URL the event was posted to
+	URL string `json:"url" yaml:"url"`
+	// Event is the delivered event
+	Event api.Event `json:"event" yaml:"event"`
+	// Attempts is the number of requests sent
+	Attempts int `json:"attempts" yaml:"attempts"`
+	// StatusCode is the HTTP status of the last response. Zero if no response
+	// was received.
+	StatusCode int `json:"status_code,omitempty" yaml:"status_code,omitempty"`
+	// Err is set if the event could not be delivered
+	Err error `json:"-" yaml:"-"`
+	// Error holds the message of Err
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// DeadLetterHandler is called for every event which could not be delivered
+type DeadLetterHandler func(d *Delivery)
+
+// DeadLetterWriter returns a dead-letter handler writing each failed delivery
+// as a JSON line to the given writer, e.g. a file the events can be replayed
+// from later
+func DeadLetterWriter(w io.Writer) DeadLetterHandler {
+	var lock sync.Mutex
+	enc := json.NewEncoder(w)
+	return func(d *Delivery) {
+		lock.Lock()
+		defer lock.Unlock()
+		_ = enc.Encode(d)
+	}
+}
+
+// Bridge delivers the events of a source to a set of webhooks
+type Bridge struct {
+	// Source provides the events
+	Source EventSource
+	// Webhooks the events are delivered to
+	Webhooks []Webhook
+	// HTTPClient sends the requests. Defaults to a client with a timeout of
+	// 10 seconds.
+	HTTPClient *http.Client
+	// Retry controls the delay between the attempts of a delivery and how many
+	// attempts are made. Defaults to 5 attempts with a delay growing from one
+	// second.
+	Retry restclient.ReconnectPolicy
+	// QueueSize is the number of events queued per webhook while a previous
+	// event is being delivered. Events which don't fit in the queue are
+	// dead-lettered. Defaults to 100.
+	QueueSize int
+	// DeadLetter is called for events which could not be delivered. Optional.
+	DeadLetter DeadLetterHandler
+	// OnDelivery is called after every delivery, successful or not. Optional.
+	OnDelivery func(d *Delivery)
+}
+
+// Run delivers events until the given context is cancelled or the event
+// stream of the source is disconnected. Each webhook has its own queue, so a
+// slow webhook does not delay the others. The event listener dispatches
+// events concurrently, so receivers should rely on the event timestamp
+// rather than the delivery order. Events still queued when Run returns are
+// dead-lettered.
+func (b *Bridge) Run(ctx context.Context) error {
+	if b.Source == nil {
+		return errs.NewInvalidArgument("source")
+	}
+	for n := range b.Webhooks {
+		if len(b.Webhooks[n].URL) == 0 {
+			return errs.NewInvalidArgument(fmt.Sprintf("url (webhook %s)", b.Webhooks[n].Name))
+		}
+	}
+
+	queueSize := b.QueueSize
+	if queueSize <= 0 {
+		queueSize = defaultQueueSize
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	queues := make([]chan *Delivery, len(b.Webhooks))
+	var wg sync.WaitGroup
+	for n := range b.Webhooks {
+		queues[n] = make(chan *Delivery, queueSize)
+		wg.Add(1)
+		go func(w *Webhook, queue chan *Delivery) {
+			defer wg.Done()
+			b.worker(ctx, w, queue)
+		}(&b.Webhooks[n], queues[n])
+	}
+
+	listener, err := b.Source.GetEvents()
+	if err != nil {
+		cancel()
+		wg.Wait()
+		return err
+	}
+	defer listener.Disconnect()
+
+	// Handlers are called concurrently, so guard the queues against being
+	// closed while an event is added
+	var lock sync.Mutex
+	closed := false
+	_, err = listener.AddHandler(nil, func(data interface{}) {
+		event, err := toEvent(data)
+		if err != nil {
+			return
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		if closed {
+			return
+		}
+		for n := range b.Webhooks {
+			w := &b.Webhooks[n]
+			if !w.Matches(event) {
+				continue
+			}
+			d := newDelivery(w, event)
+			select {
+			case queues[n] <- d:
+			default:
+				d.Err = fmt.Errorf("queue of webhook %s is full", w.Name)
+				b.deadLetter(d)
+			}
+		}
+	})
+	if err != nil {
+		cancel()
+		wg.Wait()
+		return err
+	}
+
+	disconnected := make(chan error, 1)
+	go func() {
+		disconnected <- listener.Wait()
+	}()
+
+	select {
+	case <-ctx.Done():
+		err = ctx.Err()
+	case err = <-disconnected:
+		if err == nil {
+			err = fmt.Errorf("event listener disconnected")
+		}
+	}
+
+	lock.Lock()
+	closed = true
+	lock.Unlock()
+	cancel()
+	wg.Wait()
+
+	for _, queue := range queues {
+		close(queue)
+		for d := range queue {
+			d.Err = err
+			b.deadLetter(d)
+		}
+	}
+	return err
+}
+
+// worker delivers the queued events to a single webhook until the context is
+// cancelled
+func (b *Bridge) worker(ctx context.Context, w *Webhook, queue chan *Delivery) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case d := <-queue:
+			b.deliver(ctx, w, d)
+			if b.OnDelivery != nil {
+				b.OnDelivery(d)
+			}
+			if d.Err != nil {
+				b.deadLetter(d)
+			}
+		}
+	}
+}
+
+// deliver posts the event to the webhook and retries until it was accepted,
+// the attempts are exhausted or the webhook rejected it permanently
+func (b *Bridge) deliver(ctx context.Context, w *Webhook, d *Delivery) {
+	body, err := json.Marshal(d.Event)
+	if err != nil {
+		d.Err = err
+		return
+	}
+
+	maxAttempts := b.Retry.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+
+	for {
+		d.Attempts++
+		retry := false
+		d.StatusCode, retry, d.Err = b.post(ctx, w, d, body)
+		if d.Err == nil || !retry || d.Attempts >= maxAttempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			d.Err = ctx.Err()
+			return
+		case <-time.After(b.Retry.Backoff(d.Attempts)):
+		}
+	}
+	if d.Err != nil {
+		d.Error = d.Err.Error()
+	}
+}
+
+// post sends a single request and returns if a failure is worth retrying
+func (b *Bridge) post(ctx context.Context, w *Webhook, d *Delivery, body []byte) (int, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, false, err
+	}
+	for name, value := range w.Headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "ams-sdk-eventsbridge")
+	req.Header.Set(HeaderDelivery, d.ID)
+	req.Header.Set(HeaderEvent, string(d.Event.Type))
+	if len(w.Secret) > 0 {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(HeaderTimestamp, timestamp)
+		req.Header.Set(HeaderSignature, Sign(w.Secret, timestamp, body))
+	}
+
+	httpClient := b.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: defaultHTTPTimeout}
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp.StatusCode, false, nil
+	}
+	// Other client errors won't go away by sending the same request again
+	retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests ||
+		resp.StatusCode == http.StatusRequestTimeout
+	return resp.StatusCode, retry, fmt.Errorf("webhook %s responded with %s", w.Name, resp.Status)
+}
+
+func (b *Bridge) deadLetter(d *Delivery) {
+	if d.Err != nil {
+		d.Error = d.Err.Error()
+	}
+	if b.DeadLetter != nil {
+		b.DeadLetter(d)
+	}
+}
+
+func newDelivery(w *Webhook, event *api.Event) *Delivery {
+	d := &Delivery{Webhook: w.Name, URL: w.URL, Event: *event}
+	if id, err := shared.GenerateRandomBytes(16); err == nil {
+		d.ID = hex.EncodeToString(id)
+	}
+	return d
+}
+
+// toEvent converts an event received from the event listener
+func toEvent(data interface{}) (*api.Event, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	event := &api.Event{}
+	if err := json.Unmarshal(b, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+func lifecycleAction(event *api.Event) api.LifecycleEventAction {
+	metadata, ok := event.Metadata.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	action, _ := metadata["action"].(string)
+	return api.LifecycleEventAction(action)
+}
@@ -0,0 +1,146 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package keyring stores client certificates and keys in the credential store
+// of the operating system instead of plain files. The Secret Service is used
+// on Linux, the Keychain on macOS and DPAPI protected files on Windows.
+package keyring
+
+import (
+	"crypto/tls"
+	"fmt"
+	"sync"
+
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+)
+
+// DefaultService is the service name credentials of the SDK are stored under
+const DefaultService = "ams-sdk"
+
+const (
+	certificateSuffix = "certificate"
+	keySuffix         = "key"
+)
+
+// Store describes a store for secrets. Secrets are identified by a service
+// and an account name.
+type Store interface {
+	// Get returns the secret stored for the given service and account. An
+	// ErrNotFound error is returned if no secret is stored.
+	Get(service, account string) ([]byte, error)
+	// Set stores the secret for the given service and account, replacing an
+	// already stored one
+	Set(service, account string, secret []byte) error
+	// Delete removes the secret stored for the given service and account
+	Delete(service, account string) error
+}
+
+// Default returns the credential store of the operating system. An
+// ErrNotSupported error is returned if the platform has no supported store.
+func Default() (Store, error) {
+	return newSystemStore()
+}
+
+// StoreCertificate stores the PEM encoded client certificate and key under the
+// given name
+func StoreCertificate(s Store, name string, certPEM, keyPEM []byte) error {
+	if len(name) == 0 {
+		return errs.NewInvalidArgument("name")
+	}
+	if _, err := tls.X509KeyPair(certPEM, keyPEM); err != nil {
+		return err
+	}
+	if err := s.Set(DefaultService, account(name, keySuffix), keyPEM); err != nil {
+		return err
+	}
+	return s.Set(DefaultService, account(name, certificateSuffix), certPEM)
+}
+
+// LoadCertificate returns the client certificate and key stored under the
+// given name. The result can be used as certificate of a tls.Config.
+func LoadCertificate(s Store, name string) (tls.Certificate, error) {
+	certPEM, err := s.Get(DefaultService, account(name, certificateSuffix))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	keyPEM, err := s.Get(DefaultService, account(name, keySuffix))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.X509KeyPair(certPEM, keyPEM)
+}
+
+// DeleteCertificate removes the client certificate and key stored under the
+// given name
+func DeleteCertificate(s Store, name string) error {
+	if err := s.Delete(DefaultService, account(name, certificateSuffix)); err != nil {
+		return err
+	}
+	return s.Delete(DefaultService, account(name, keySuffix))
+}
+
+func account(name, suffix string) string {
+	return fmt.Sprintf("%s.%s", name, suffix)
+}
+
+// MemoryStore keeps secrets in memory only. It is useful for tools which must
+// not persist credentials at all.
+type MemoryStore struct {
+	lock    sync.Mutex
+	secrets map[string][]byte
+}
+
+// NewMemoryStore returns an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{secrets: map[string][]byte{}}
+}
+
+// Get returns the secret stored for the given service and account
+func (m *MemoryStore) Get(service, account string) ([]byte, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	secret, ok := m.secrets[memoryKey(service, account)]
+	if !ok {
+		return nil, errs.NewErrNotFound(fmt.Sprintf("secret %s of service %s", account, service))
+	}
+	return append([]byte{}, secret...), nil
+}
+
+// Set stores the secret for the given service and account
+func (m *MemoryStore) Set(service, account string, secret []byte) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.secrets[memoryKey(service, account)] = append([]byte{}, secret...)
+	return nil
+}
+
+// Delete removes the secret stored for the given service and account
+func (m *MemoryStore) Delete(service, account string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	key := memoryKey(service, account)
+	if _, ok := m.secrets[key]; !ok {
+		return errs.NewErrNotFound(fmt.Sprintf("secret %s of service %s", account, service))
+	}
+	delete(m.secrets, key)
+	return nil
+}
+
+func memoryKey(service, account string) string {
+	return service + "\x00" + account
+}
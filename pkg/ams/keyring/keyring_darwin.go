@@ -0,0 +1,71 @@
+//go:build darwin
+
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package keyring
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+)
+
+const securityTool = "/usr/bin/security"
+
+// keychainStore stores secrets as generic passwords in the login keychain
+// using the security utility. Secrets are base64 encoded and passed through
+// stdin so they never show up in the process list.
+type keychainStore struct{}
+
+func newSystemStore() (Store, error) {
+	return &keychainStore{}, nil
+}
+
+func (k *keychainStore) Get(service, account string) ([]byte, error) {
+	out, err := exec.Command(securityTool, "find-generic-password", "-s", service, "-a", account, "-w").Output()
+	if err != nil {
+		return nil, errs.NewErrNotFound(fmt.Sprintf("secret %s of service %s", account, service))
+	}
+	return base64.StdEncoding.DecodeString(strings.TrimSpace(string(out)))
+}
+
+func (k *keychainStore) Set(service, account string, secret []byte) error {
+	command := fmt.Sprintf("add-generic-password -U -s %q -a %q -w %q\n",
+		service, account, base64.StdEncoding.EncodeToString(secret))
+	cmd := exec.Command(securityTool, "-i")
+	cmd.Stdin = strings.NewReader(command)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to store secret in keychain: %v %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+func (k *keychainStore) Delete(service, account string) error {
+	out, err := exec.Command(securityTool, "delete-generic-password", "-s", service, "-a", account).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to delete secret from keychain: %v %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+//go:build linux
+
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package keyring
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+)
+
+// secretServiceStore stores secrets through the Secret Service (e.g. GNOME
+// Keyring or KWallet) using the secret-tool utility of libsecret. Secrets are
+// base64 encoded as the Secret Service only handles text through it.
+type secretServiceStore struct {
+	tool string
+}
+
+func newSystemStore() (Store, error) {
+	tool, err := exec.LookPath("secret-tool")
+	if err != nil {
+		return nil, errs.NewErrNotSupported("secret service (secret-tool not found)")
+	}
+	return &secretServiceStore{tool: tool}, nil
+}
+
+func (s *secretServiceStore) run(stdin []byte, args ...string) ([]byte, error) {
+	cmd := exec.Command(s.tool, args...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); len(msg) > 0 {
+			return nil, fmt.Errorf("secret-tool failed: %s", msg)
+		}
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
+
+func (s *secretServiceStore) Get(service, account string) ([]byte, error) {
+	out, err := s.run(nil, "lookup", "service", service, "account", account)
+	if err != nil || len(out) == 0 {
+		// secret-tool exits with an error without output if nothing is stored
+		return nil, errs.NewErrNotFound(fmt.Sprintf("secret %s of service %s", account, service))
+	}
+	return base64.StdEncoding.DecodeString(strings.TrimSpace(string(out)))
+}
+
+func (s *secretServiceStore) Set(service, account string, secret []byte) error {
+	label := fmt.Sprintf("%s %s", service, account)
+	encoded := base64.StdEncoding.EncodeToString(secret)
+	_, err := s.run([]byte(encoded), "store", "--label", label, "service", service, "account", account)
+	return err
+}
+
+func (s *secretServiceStore) Delete(service, account string) error {
+	_, err := s.run(nil, "clear", "service", service, "account", account)
+	return err
+}
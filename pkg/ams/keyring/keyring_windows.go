@@ -0,0 +1,116 @@
+//go:build windows
+
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package keyring
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"unsafe"
+
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+	"golang.org/x/sys/windows"
+)
+
+// dpapiStore stores secrets in files encrypted with DPAPI for the current
+// user, so only processes of the same user can decrypt them
+type dpapiStore struct {
+	dir string
+}
+
+func newSystemStore() (Store, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	return &dpapiStore{dir: filepath.Join(dir, "ams", "keyring")}, nil
+}
+
+// path returns the file of the given secret. Names are hex encoded to be safe
+// to use as file names.
+func (d *dpapiStore) path(service, account string) string {
+	name := fmt.Sprintf("%s.%s", hex.EncodeToString([]byte(service)), hex.EncodeToString([]byte(account)))
+	return filepath.Join(d.dir, name)
+}
+
+func (d *dpapiStore) Get(service, account string) ([]byte, error) {
+	data, err := os.ReadFile(d.path(service, account))
+	if os.IsNotExist(err) {
+		return nil, errs.NewErrNotFound(fmt.Sprintf("secret %s of service %s", account, service))
+	} else if err != nil {
+		return nil, err
+	}
+	return dpapiCrypt(data, false)
+}
+
+func (d *dpapiStore) Set(service, account string, secret []byte) error {
+	data, err := dpapiCrypt(secret, true)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(d.dir, 0700); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so a secret is never half written
+	tmp, err := os.CreateTemp(d.dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), d.path(service, account))
+}
+
+func (d *dpapiStore) Delete(service, account string) error {
+	err := os.Remove(d.path(service, account))
+	if os.IsNotExist(err) {
+		return errs.NewErrNotFound(fmt.Sprintf("secret %s of service %s", account, service))
+	}
+	return err
+}
+
+// dpapiCrypt encrypts or decrypts the given data for the current user
+func dpapiCrypt(data []byte, encrypt bool) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, errs.NewInvalidArgument("data")
+	}
+	in := windows.DataBlob{Size: uint32(len(data)), Data: &data[0]}
+	var out windows.DataBlob
+	var err error
+	if encrypt {
+		err = windows.CryptProtectData(&in, nil, nil, 0, nil, windows.CRYPTPROTECT_UI_FORBIDDEN, &out)
+	} else {
+		err = windows.CryptUnprotectData(&in, nil, nil, 0, nil, windows.CRYPTPROTECT_UI_FORBIDDEN, &out)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer windows.LocalFree(windows.Handle(unsafe.Pointer(out.Data)))
+	return append([]byte{}, unsafe.Slice(out.Data, out.Size)...), nil
+}
@@ -0,0 +1,218 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package manifest models the manifest.yaml of an application package and
+// allows validating it and creating the package uploaded to AMS from a
+// directory.
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	api "github.com/anbox-cloud/ams-sdk/api/ams"
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/constants"
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/packages"
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	// FileName is the name of the manifest file within an application package
+	FileName = "manifest.yaml"
+	// APKFileName is the name of the APK file within an application package
+	APKFileName = "app.apk"
+)
+
+// Manifest describes the manifest.yaml of an application package
+type Manifest struct {
+	// Name of the application
+	Name string `yaml:"name"`
+	// Instance type required by the application
+	InstanceType string `yaml:"instance-type,omitempty"`
+	// Resources required by the application. Overrides the instance type.
+	Resources *api.ApplicationResources `yaml:"resources,omitempty"`
+	// Image the application is based on
+	Image string `yaml:"image,omitempty"`
+	// Names of the addons to enable for the application
+	Addons []string `yaml:"addons,omitempty"`
+	// Tags to attach to the application
+	Tags []string `yaml:"tags,omitempty"`
+	// Android package to start when an instance of the application boots
+	BootPackage string `yaml:"boot-package,omitempty"`
+	// Android activity to start when an instance of the application boots
+	BootActivity string `yaml:"boot-activity,omitempty"`
+	// Android permissions to grant to the application
+	RequiredPermissions []string `yaml:"required-permissions,omitempty"`
+	// Video encoder to use for the application
+	VideoEncoder api.VideoEncoderType `yaml:"video-encoder,omitempty"`
+	// Watchdog settings of the application
+	Watchdog *api.ApplicationWatchdog `yaml:"watchdog,omitempty"`
+	// Services the application exposes
+	Services []api.NetworkServiceSpec `yaml:"services,omitempty"`
+	// Extra data installed into the instance, keyed by the path in the package
+	ExtraData map[string]api.ApplicationExtraData `yaml:"extra-data,omitempty"`
+	// Features to enable for the application
+	Features []string `yaml:"features,omitempty"`
+	// Tags of the nodes the application may run on
+	NodeSelector []string `yaml:"node-selector,omitempty"`
+	// Hook settings of the application
+	Hooks *api.ApplicationHooks `yaml:"hooks,omitempty"`
+	// Bootstrap settings of the application
+	Bootstrap *api.ApplicationBootstrap `yaml:"bootstrap,omitempty"`
+}
+
+// knownFields lists the top-level fields of a manifest
+var knownFields = []string{
+	"name", "instance-type", "resources", "image", "addons", "tags",
+	"boot-package", "boot-activity", "required-permissions", "video-encoder",
+	"watchdog", "services", "extra-data", "features", "node-selector", "hooks",
+	"bootstrap",
+}
+
+// ValidationError lists all problems found in a manifest
+type ValidationError struct {
+	Problems []string
+}
+
+// Error returns the error string
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid manifest: %s", strings.Join(e.Problems, "; "))
+}
+
+// Parse reads a manifest from r. Unknown fields are rejected so that typos
+// don't go unnoticed until AMS processed the package.
+func Parse(r io.Reader) (*Manifest, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]interface{}{}
+	if err := yaml.Unmarshal(b, &fields); err != nil {
+		return nil, errs.NewErrMalformed(FileName)
+	}
+	unknown := []string{}
+	for name := range fields {
+		if !isKnownField(name) {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, &ValidationError{Problems: []string{fmt.Sprintf("unknown fields: %s", strings.Join(unknown, ", "))}}
+	}
+
+	m := &Manifest{}
+	if err := packages.ParseManifest(bytes.NewReader(b), m); err != nil {
+		return nil, errs.NewErrMalformed(FileName)
+	}
+	return m, nil
+}
+
+// Load reads the manifest of the application package in the given directory
+func Load(dir string) (*Manifest, error) {
+	f, err := os.Open(filepath.Join(dir, FileName))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// Marshal returns the YAML representation of the manifest
+func (m *Manifest) Marshal() ([]byte, error) {
+	return yaml.Marshal(m)
+}
+
+// Validate checks the manifest for invalid values. All problems found are
+// returned together as a *ValidationError.
+func (m *Manifest) Validate() error {
+	problems := []string{}
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if len(m.Name) == 0 {
+		add("name is required")
+	} else if !matches(constants.ApplicationNamePattern, m.Name) {
+		add("invalid name %q", m.Name)
+	}
+	if len(m.BootPackage) > 0 {
+		if err := api.ValidateBootPackage(m.BootPackage); err != nil {
+			add("boot-package: %v", err)
+		}
+	}
+	if len(m.BootActivity) > 0 {
+		if err := api.ValidateBootPackage(m.BootActivity); err != nil {
+			add("boot-activity: %v", err)
+		}
+	}
+	for _, addon := range m.Addons {
+		if len(addon) == 0 || !matches(constants.AddonNamePattern, addon) {
+			add("invalid addon name %q", addon)
+		}
+	}
+	if len(m.VideoEncoder) > 0 && api.VideoEncoderFromString(string(m.VideoEncoder)) == api.VideoEncoderTypeUnknown {
+		add("invalid video-encoder %q", m.VideoEncoder)
+	}
+	if m.Watchdog != nil {
+		if err := m.Watchdog.ValidateAllowedPackages(); err != nil {
+			add("watchdog: %v", err)
+		}
+	}
+	if err := api.ValidateServices(m.Services); err != nil {
+		add("services: %v", err)
+	}
+	for name, data := range m.ExtraData {
+		if len(data.Target) == 0 {
+			add("extra-data %s: target is required", name)
+		}
+	}
+	if m.Hooks != nil && len(m.Hooks.Timeout) > 0 {
+		if err := packages.ValidateHookTimeout(m.Hooks.Timeout); err != nil {
+			add("hooks: %v", err)
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+func isKnownField(name string) bool {
+	for _, f := range knownFields {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+func matches(pattern, value string) bool {
+	ok, _ := regexp.MatchString(pattern, value)
+	return ok
+}
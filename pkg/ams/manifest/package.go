@@ -0,0 +1,75 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package manifest
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/packages"
+)
+
+// WritePackage validates the manifest of the application package in the given
+// directory and writes the bzip2 compressed tarball expected by AMS when
+// creating an application to w. All files and directories in dir are included.
+func WritePackage(w io.Writer, dir string, opts packages.TarOptions) error {
+	m, err := Load(dir)
+	if err != nil {
+		return err
+	}
+	if err := m.Validate(); err != nil {
+		return err
+	}
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	content := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		content = append(content, entry.Name())
+	}
+
+	opts.Compression = packages.CompressionBzip2
+	return packages.WriteTarball(w, dir, content, opts)
+}
+
+// CreatePackage writes the application package for the given directory to
+// outputPath. See WritePackage for details.
+func CreatePackage(dir, outputPath string, opts packages.TarOptions) error {
+	if abs, err := filepath.Abs(outputPath); err == nil {
+		if absDir, err := filepath.Abs(dir); err == nil && filepath.Dir(abs) == absDir {
+			// Don't include the package in itself
+			opts.Exclude = append(opts.Exclude, filepath.Base(abs))
+		}
+	}
+
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	if err := WritePackage(f, dir, opts); err != nil {
+		f.Close()
+		os.Remove(outputPath)
+		return err
+	}
+	return f.Close()
+}
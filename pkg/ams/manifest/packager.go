@@ -0,0 +1,114 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package manifest
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/packages"
+)
+
+// Package describes the content of an application package
+type Package struct {
+	// Manifest of the application
+	Manifest *Manifest
+	// APK is the path of the APK file to include as app.apk. Optional for
+	// applications which don't install an APK.
+	APK string
+	// Files maps paths within the package to files or directories on disk
+	// to include, e.g. the extra data referenced by the manifest
+	Files map[string]string
+	// Type of the package. The zero value is packages.PackageTypeTarBZ2.
+	Type packages.PackageType
+	// ModTime is used as modification time of all files. Defaults to
+	// packages.ReproducibleModTime.
+	ModTime time.Time
+}
+
+// Write validates the manifest and writes the application package to w. The
+// same content always results in the same package, so its hash can be used
+// to detect changes.
+func (p *Package) Write(w io.Writer) error {
+	if p.Manifest == nil {
+		return &ValidationError{Problems: []string{"manifest is required"}}
+	}
+	if err := p.Manifest.Validate(); err != nil {
+		return err
+	}
+
+	data, err := p.Manifest.Marshal()
+	if err != nil {
+		return err
+	}
+
+	entries := []packages.ArchiveEntry{{Name: FileName, Data: data}}
+	if len(p.APK) > 0 {
+		entries = append(entries, packages.ArchiveEntry{Name: APKFileName, Path: p.APK})
+	}
+	for name, source := range p.Files {
+		entries = append(entries, packages.ArchiveEntry{Name: name, Path: source})
+	}
+
+	problems := []string{}
+	for name := range p.Manifest.ExtraData {
+		if !p.contains(name) {
+			problems = append(problems, fmt.Sprintf("extra-data %s is not part of the package", name))
+		}
+	}
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+
+	return packages.WriteArchive(w, entries, packages.ArchiveOptions{
+		Type:    p.Type,
+		ModTime: p.ModTime,
+	})
+}
+
+// Create writes the application package to outputPath. See Write for details.
+func (p *Package) Create(outputPath string) error {
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	if err := p.Write(f); err != nil {
+		f.Close()
+		os.Remove(outputPath)
+		return err
+	}
+	return f.Close()
+}
+
+// contains checks if the given path is provided by one of the files or
+// directories of the package
+func (p *Package) contains(name string) bool {
+	name = path.Clean(name)
+	for entry := range p.Files {
+		entry = path.Clean(entry)
+		if name == entry || strings.HasPrefix(name, entry+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package manifest
+
+import (
+	"fmt"
+
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/client"
+)
+
+// ValidateWithServer validates the manifest and additionally checks it against
+// the AMS service the given client is connected to: the referenced addons and
+// image have to exist.
+func (m *Manifest) ValidateWithServer(c client.Client) error {
+	problems := []string{}
+	if err := m.Validate(); err != nil {
+		verr, ok := err.(*ValidationError)
+		if !ok {
+			return err
+		}
+		problems = append(problems, verr.Problems...)
+	}
+
+	if len(m.Addons) > 0 {
+		addons, err := c.ListAddons()
+		if err != nil {
+			return err
+		}
+		available := map[string]bool{}
+		for _, addon := range addons {
+			available[addon.Name] = true
+		}
+		for _, name := range m.Addons {
+			if !available[name] {
+				problems = append(problems, fmt.Sprintf("addon %s does not exist", name))
+			}
+		}
+	}
+
+	if len(m.Image) > 0 {
+		images, err := c.ListImages()
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, image := range images {
+			if image.ID == m.Image || image.Name == m.Image {
+				found = true
+				break
+			}
+		}
+		if !found {
+			problems = append(problems, fmt.Sprintf("image %s does not exist", m.Image))
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
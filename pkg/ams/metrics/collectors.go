@@ -0,0 +1,59 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package metrics
+
+const (
+	// Namespace prefixes all metrics collected by the SDK
+	Namespace = "ams_sdk"
+)
+
+// Collectors bundles the metrics collected by the SDK
+type Collectors struct {
+	// Requests counts the requests sent to AMS by method, resource and status code
+	Requests *CounterVec
+	// RequestDuration observes the latency of requests sent to AMS by method and resource
+	RequestDuration *HistogramVec
+	// Retries counts requests which were sent again by method and resource
+	Retries *CounterVec
+	// WebsocketBytes counts the bytes transferred over websockets by direction
+	// (sent, received)
+	WebsocketBytes *CounterVec
+	// OperationWait observes the time spent waiting for operations to finish
+	// by result (success, failure, cancelled)
+	OperationWait *HistogramVec
+}
+
+// NewCollectors registers all SDK collectors with the given registry. If they
+// are already registered the existing ones are returned, so multiple clients
+// can share a single registry.
+func NewCollectors(r *Registry) *Collectors {
+	return &Collectors{
+		Requests: r.CounterVec(Namespace+"_requests_total",
+			"Total number of requests sent to AMS.", "method", "resource", "code"),
+		RequestDuration: r.HistogramVec(Namespace+"_request_duration_seconds",
+			"Latency of requests sent to AMS in seconds.", nil, "method", "resource"),
+		Retries: r.CounterVec(Namespace+"_retries_total",
+			"Total number of requests sent again to AMS.", "method", "resource"),
+		WebsocketBytes: r.CounterVec(Namespace+"_websocket_bytes_total",
+			"Total number of bytes transferred over websockets.", "direction"),
+		OperationWait: r.HistogramVec(Namespace+"_operation_wait_seconds",
+			"Time spent waiting for AMS operations to finish in seconds.",
+			[]float64{.1, .5, 1, 5, 10, 30, 60, 120, 300, 600, 1800}, "result"),
+	}
+}
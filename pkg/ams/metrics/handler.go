@@ -0,0 +1,76 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+const (
+	// ContentType is the content type of the Prometheus text exposition format
+	ContentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+// WriteText writes all metrics of the registry in the Prometheus text
+// exposition format to the given writer
+func (r *Registry) WriteText(w io.Writer) error {
+	r.lock.Lock()
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	families := make([]family, 0, len(names))
+	for _, name := range names {
+		families = append(families, r.families[name])
+	}
+	r.lock.Unlock()
+
+	b := &strings.Builder{}
+	for _, f := range families {
+		f.write(b)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Handler returns a HTTP handler serving the metrics of the given registry in
+// the Prometheus text exposition format
+func Handler(r *Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		if err := r.WriteText(w); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// Expose creates a registry with all SDK collectors registered and serves it
+// on the given path of the given mux. The returned registry is meant to be
+// handed to the clients through the WithMetrics option and can be used to
+// register additional metrics of the embedding service.
+func Expose(mux *http.ServeMux, path string) *Registry {
+	r := NewRegistry()
+	NewCollectors(r)
+	mux.Handle(path, Handler(r))
+	return r
+}
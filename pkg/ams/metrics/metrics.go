@@ -0,0 +1,297 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package metrics provides a minimal metrics registry which can be exposed in
+// the Prometheus text format without depending on the Prometheus client
+// libraries.
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type metricType string
+
+const (
+	typeCounter   metricType = "counter"
+	typeGauge     metricType = "gauge"
+	typeHistogram metricType = "histogram"
+)
+
+// DefaultBuckets are the default histogram buckets in seconds, suitable for
+// request latencies
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60}
+
+type family interface {
+	name() string
+	kind() metricType
+	write(b *strings.Builder)
+}
+
+// Registry holds a set of metric families
+type Registry struct {
+	lock     sync.Mutex
+	families map[string]family
+}
+
+// NewRegistry returns a new and empty registry
+func NewRegistry() *Registry {
+	return &Registry{families: map[string]family{}}
+}
+
+// register adds the family created by create to the registry or returns the
+// family already registered under the same name. It panics if a family of a
+// different type is registered under the same name.
+func (r *Registry) register(name string, kind metricType, create func() family) family {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if f, ok := r.families[name]; ok {
+		if f.kind() != kind {
+			panic(fmt.Sprintf("metric %s already registered as %s", name, f.kind()))
+		}
+		return f
+	}
+	f := create()
+	r.families[name] = f
+	return f
+}
+
+// CounterVec returns the counter family with the given name and label names,
+// creating it if it does not exist yet
+func (r *Registry) CounterVec(name, help string, labels ...string) *CounterVec {
+	return r.register(name, typeCounter, func() family {
+		return &CounterVec{vec: newVec(name, help, typeCounter, labels)}
+	}).(*CounterVec)
+}
+
+// GaugeVec returns the gauge family with the given name and label names,
+// creating it if it does not exist yet
+func (r *Registry) GaugeVec(name, help string, labels ...string) *GaugeVec {
+	return r.register(name, typeGauge, func() family {
+		return &GaugeVec{vec: newVec(name, help, typeGauge, labels)}
+	}).(*GaugeVec)
+}
+
+// HistogramVec returns the histogram family with the given name, buckets and
+// label names, creating it if it does not exist yet. If no buckets are given
+// DefaultBuckets are used.
+func (r *Registry) HistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	return r.register(name, typeHistogram, func() family {
+		if len(buckets) == 0 {
+			buckets = DefaultBuckets
+		}
+		b := append([]float64{}, buckets...)
+		sort.Float64s(b)
+		return &HistogramVec{vec: newVec(name, help, typeHistogram, labels), buckets: b}
+	}).(*HistogramVec)
+}
+
+type sample struct {
+	labelValues []string
+	value       float64
+	buckets     []uint64
+	count       uint64
+}
+
+type vec struct {
+	lock    sync.Mutex
+	metric  string
+	help    string
+	typ     metricType
+	labels  []string
+	samples map[string]*sample
+}
+
+func newVec(name, help string, typ metricType, labels []string) vec {
+	return vec{
+		metric:  name,
+		help:    help,
+		typ:     typ,
+		labels:  labels,
+		samples: map[string]*sample{},
+	}
+}
+
+func (v *vec) name() string     { return v.metric }
+func (v *vec) kind() metricType { return v.typ }
+
+// get returns the sample for the given label values. Must be called with the
+// lock held.
+func (v *vec) get(labelValues []string) *sample {
+	if len(labelValues) != len(v.labels) {
+		panic(fmt.Sprintf("metric %s expects %d label values, got %d", v.metric, len(v.labels), len(labelValues)))
+	}
+	key := strings.Join(labelValues, "\xff")
+	s, ok := v.samples[key]
+	if !ok {
+		s = &sample{labelValues: append([]string{}, labelValues...)}
+		v.samples[key] = s
+	}
+	return s
+}
+
+func (v *vec) sortedSamples() []*sample {
+	keys := make([]string, 0, len(v.samples))
+	for k := range v.samples {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	samples := make([]*sample, 0, len(keys))
+	for _, k := range keys {
+		samples = append(samples, v.samples[k])
+	}
+	return samples
+}
+
+func (v *vec) writeHeader(b *strings.Builder) {
+	fmt.Fprintf(b, "# HELP %s %s\n", v.metric, escapeHelp(v.help))
+	fmt.Fprintf(b, "# TYPE %s %s\n", v.metric, v.typ)
+}
+
+// CounterVec is a family of counters partitioned by labels
+type CounterVec struct {
+	vec
+}
+
+// Add adds the given non-negative value to the counter with the given label values
+func (c *CounterVec) Add(value float64, labelValues ...string) {
+	if value < 0 {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.get(labelValues).value += value
+}
+
+// Inc increments the counter with the given label values by one
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+func (c *CounterVec) write(b *strings.Builder) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.writeHeader(b)
+	for _, s := range c.sortedSamples() {
+		writeSample(b, c.metric, c.labels, s.labelValues, "", "", s.value)
+	}
+}
+
+// GaugeVec is a family of gauges partitioned by labels
+type GaugeVec struct {
+	vec
+}
+
+// Set sets the gauge with the given label values to the given value
+func (g *GaugeVec) Set(value float64, labelValues ...string) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.get(labelValues).value = value
+}
+
+// Add adds the given value to the gauge with the given label values
+func (g *GaugeVec) Add(value float64, labelValues ...string) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.get(labelValues).value += value
+}
+
+func (g *GaugeVec) write(b *strings.Builder) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.writeHeader(b)
+	for _, s := range g.sortedSamples() {
+		writeSample(b, g.metric, g.labels, s.labelValues, "", "", s.value)
+	}
+}
+
+// HistogramVec is a family of histograms partitioned by labels
+type HistogramVec struct {
+	vec
+	buckets []float64
+}
+
+// Observe adds a single observation to the histogram with the given label values
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	s := h.get(labelValues)
+	if s.buckets == nil {
+		s.buckets = make([]uint64, len(h.buckets))
+	}
+	for n, upper := range h.buckets {
+		if value <= upper {
+			s.buckets[n]++
+		}
+	}
+	s.count++
+	s.value += value
+}
+
+func (h *HistogramVec) write(b *strings.Builder) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.writeHeader(b)
+	for _, s := range h.sortedSamples() {
+		for n, upper := range h.buckets {
+			writeSample(b, h.metric+"_bucket", h.labels, s.labelValues, "le", formatFloat(upper), float64(s.buckets[n]))
+		}
+		writeSample(b, h.metric+"_bucket", h.labels, s.labelValues, "le", "+Inf", float64(s.count))
+		writeSample(b, h.metric+"_sum", h.labels, s.labelValues, "", "", s.value)
+		writeSample(b, h.metric+"_count", h.labels, s.labelValues, "", "", float64(s.count))
+	}
+}
+
+func writeSample(b *strings.Builder, name string, labels, values []string, extraLabel, extraValue string, value float64) {
+	b.WriteString(name)
+	if len(labels) > 0 || len(extraLabel) > 0 {
+		pairs := []string{}
+		for n, label := range labels {
+			pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", label, escapeLabelValue(values[n])))
+		}
+		if len(extraLabel) > 0 {
+			pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", extraLabel, extraValue))
+		}
+		fmt.Fprintf(b, "{%s}", strings.Join(pairs, ","))
+	}
+	fmt.Fprintf(b, " %s\n", formatFloat(value))
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+func escapeHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
+
+func escapeLabelValue(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`).Replace(s)
+}
@@ -0,0 +1,208 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package packages
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+)
+
+// ReproducibleModTime is the modification time WriteArchive uses for all
+// entries unless another one is given. It is the earliest time the zip format
+// can represent.
+var ReproducibleModTime = time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// ArchiveEntry describes a single entry of an archive written by WriteArchive
+type ArchiveEntry struct {
+	// Name of the entry within the archive, using forward slashes
+	Name string
+	// Path of the file or directory on disk providing the content. A
+	// directory is added with all of its content below Name.
+	Path string
+	// Data is the content of the entry if Path is empty
+	Data []byte
+}
+
+// ArchiveOptions controls how WriteArchive writes an archive
+type ArchiveOptions struct {
+	// Type of the archive. PackageTypeTarBZ2 and PackageTypeZip are supported.
+	Type PackageType
+	// ModTime is used as modification time of all entries. Defaults to
+	// ReproducibleModTime.
+	ModTime time.Time
+}
+
+type archiveFile struct {
+	name string
+	path string
+	data []byte
+	mode os.FileMode
+}
+
+// WriteArchive writes an archive with the given entries to w. Entries are
+// written in lexical order with a fixed modification time and without owner
+// information, so the same content always results in the same archive.
+func WriteArchive(w io.Writer, entries []ArchiveEntry, opts ArchiveOptions) error {
+	if len(entries) == 0 {
+		return errs.NewInvalidArgument("entries")
+	}
+	modTime := opts.ModTime
+	if modTime.IsZero() {
+		modTime = ReproducibleModTime
+	}
+
+	files, err := expandEntries(entries)
+	if err != nil {
+		return err
+	}
+
+	switch opts.Type {
+	case PackageTypeTarBZ2:
+		return writeTarArchive(w, files, modTime)
+	case PackageTypeZip:
+		return writeZipArchive(w, files, modTime)
+	default:
+		return errs.NewInvalidArgument("type")
+	}
+}
+
+// expandEntries resolves directories to their content and returns all files
+// sorted by name
+func expandEntries(entries []ArchiveEntry) ([]archiveFile, error) {
+	seen := map[string]archiveFile{}
+	add := func(f archiveFile) error {
+		if _, ok := seen[f.name]; ok {
+			return errs.NewErrAlreadyExists(fmt.Sprintf("archive entry %s", f.name))
+		}
+		seen[f.name] = f
+		return nil
+	}
+
+	for _, entry := range entries {
+		name := path.Clean(entry.Name)
+		if len(entry.Name) == 0 || path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return nil, errs.NewInvalidArgument(fmt.Sprintf("archive entry name %q", entry.Name))
+		}
+
+		if len(entry.Path) == 0 {
+			if err := add(archiveFile{name: name, data: entry.Data, mode: 0644}); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		err := filepath.Walk(entry.Path, func(p string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(entry.Path, p)
+			if err != nil {
+				return err
+			}
+			f := archiveFile{name: path.Join(name, filepath.ToSlash(rel)), path: p, mode: info.Mode()}
+			if !info.IsDir() && !info.Mode().IsRegular() {
+				return errs.NewErrNotSupported(fmt.Sprintf("file type of %s", p))
+			}
+			return add(f)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	files := make([]archiveFile, 0, len(seen))
+	for _, f := range seen {
+		files = append(files, f)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].name < files[j].name })
+	return files, nil
+}
+
+func (f archiveFile) open() (io.ReadCloser, int64, error) {
+	if len(f.path) == 0 {
+		return io.NopCloser(bytes.NewReader(f.data)), int64(len(f.data)), nil
+	}
+	file, err := os.Open(f.path)
+	if err != nil {
+		return nil, 0, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, 0, err
+	}
+	return file, info.Size(), nil
+}
+
+func writeTarArchive(w io.Writer, files []archiveFile, modTime time.Time) error {
+	sources := make([]tarSource, 0, len(files))
+	for _, f := range files {
+		sources = append(sources, tarSource{name: f.name, path: f.path, data: f.data})
+	}
+	return writeTarSources(w, sources, TarOptions{Compression: CompressionBzip2, ModTime: modTime})
+}
+
+func writeZipArchive(w io.Writer, files []archiveFile, modTime time.Time) error {
+	zw := zip.NewWriter(w)
+
+	write := func(f archiveFile) error {
+		hdr := &zip.FileHeader{
+			Name:     f.name,
+			Method:   zip.Deflate,
+			Modified: modTime,
+		}
+		hdr.SetMode(f.mode)
+		if f.mode.IsDir() {
+			hdr.Name += "/"
+			hdr.Method = zip.Store
+			_, err := zw.CreateHeader(hdr)
+			return err
+		}
+
+		r, _, err := f.open()
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+		dst, err := zw.CreateHeader(hdr)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(dst, r)
+		return err
+	}
+
+	for _, f := range files {
+		if err := write(f); err != nil {
+			zw.Close()
+			return err
+		}
+	}
+	return zw.Close()
+}
@@ -60,11 +60,28 @@ func DetectPackageType(filePath string) (PackageType, error) {
 	}
 	defer file.Close()
 
+	return DetectPackageTypeFromReader(file)
+}
+
+// DetectPackageTypeFromReader is used to auto-determine the type of a package by
+// looking at the magic bytes of the given reader. The reader is rewound to the
+// beginning once done.
+func DetectPackageTypeFromReader(r io.ReadSeeker) (PackageType, error) {
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return PackageTypeUnknown, err
+	}
+
 	buff := make([]byte, 512)
-	if _, err = file.Read(buff); err != nil {
+	n, err := io.ReadFull(r, buff)
+	if err != nil && err != io.ErrUnexpectedEOF {
 		return PackageTypeUnknown, err
 	}
-	mimeType := http.DetectContentType(buff)
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return PackageTypeUnknown, err
+	}
+
+	mimeType := http.DetectContentType(buff[:n])
 	switch mimeType {
 	case "application/zip":
 		return PackageTypeZip, nil
@@ -73,7 +90,6 @@ func DetectPackageType(filePath string) (PackageType, error) {
 	default:
 		return PackageTypeUnknown, nil
 	}
-
 }
 
 // IsTarball detects if the given package is a valid tarball
@@ -149,7 +165,7 @@ func CreateTempPackage(sources []string, packageType PackageType) (string, error
 	switch packageType {
 	case PackageTypeTarBZ2:
 		packagePath = filepath.Join(outputDir, "application.tar.bz2")
-		if err := shared.CreateBzip2Tarball(srcDir, packagePath, sources); err != nil {
+		if err := CreateTarball(packagePath, srcDir, sources, TarOptions{Compression: CompressionBzip2}); err != nil {
 			return "", fmt.Errorf("Failed to create tarball file")
 		}
 	case PackageTypeZip:
@@ -0,0 +1,515 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package packages
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+)
+
+// Compression describes the compression applied to a tarball
+type Compression int
+
+const (
+	// CompressionNone represents an uncompressed tarball
+	CompressionNone Compression = iota
+	// CompressionBzip2 represents a bzip2 compressed tarball (.tar.bz2)
+	CompressionBzip2
+	// CompressionXz represents a xz compressed tarball (.tar.xz)
+	CompressionXz
+)
+
+var (
+	bzip2Magic = []byte("BZh")
+	xzMagic    = []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}
+)
+
+// TarOptions controls how a tarball is created
+type TarOptions struct {
+	// Compression applied to the tarball
+	Compression Compression
+	// Exclude lists patterns as understood by path.Match. A file or directory
+	// is excluded if the pattern matches either its path relative to the root
+	// or its base name. Excluding a directory excludes all of its content.
+	Exclude []string
+	// ModTime, if set, is used as modification time of all entries so that
+	// the same content always results in the same tarball
+	ModTime time.Time
+}
+
+// UntarOptions controls how a tarball is extracted
+type UntarOptions struct {
+	// MaxSize limits the total size of all extracted files in bytes. Zero
+	// means no limit.
+	MaxSize int64
+}
+
+// WriteTarball writes a tarball with the given content to w. The content is
+// given as paths relative to root. Entries are written in lexical order and
+// without owner information, so the result only depends on the content. The
+// tarball is streamed, nothing is buffered on disk.
+func WriteTarball(w io.Writer, root string, content []string, opts TarOptions) error {
+	if len(content) == 0 {
+		return errs.NewInvalidArgument("content")
+	}
+
+	files, err := collectFiles(root, content, opts.Exclude)
+	if err != nil {
+		return err
+	}
+
+	sources := make([]tarSource, 0, len(files))
+	for _, name := range files {
+		sources = append(sources, tarSource{name: name, path: filepath.Join(root, filepath.FromSlash(name))})
+	}
+	return writeTarSources(w, sources, opts)
+}
+
+// tarSource describes where the content of a single tarball entry comes from
+type tarSource struct {
+	// name of the entry within the tarball
+	name string
+	// path of the file or directory on disk. Empty for entries whose content
+	// is given as data.
+	path string
+	data []byte
+}
+
+// writeTarSources writes a tarball with the given entries in the given order
+func writeTarSources(w io.Writer, sources []tarSource, opts TarOptions) error {
+	cw, err := compressWriter(w, opts.Compression)
+	if err != nil {
+		return err
+	}
+
+	tw := tar.NewWriter(cw)
+	for _, src := range sources {
+		if err := writeTarEntry(tw, src, opts.ModTime); err != nil {
+			tw.Close()
+			cw.Close()
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		cw.Close()
+		return err
+	}
+	return cw.Close()
+}
+
+// CreateTarball creates a tarball at outputPath with the given content. See
+// WriteTarball for details.
+func CreateTarball(outputPath, root string, content []string, opts TarOptions) error {
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+
+	if err := WriteTarball(f, root, content, opts); err != nil {
+		f.Close()
+		os.Remove(outputPath)
+		return err
+	}
+	return f.Close()
+}
+
+// ExtractTarball extracts the tarball read from r into dest. The compression
+// is detected automatically. Entries with absolute paths or paths or link
+// targets pointing outside of dest as well as device files are rejected. So are
+// entries which would be written through a symlink, e.g. one extracted from
+// the same tarball before.
+func ExtractTarball(r io.Reader, dest string, opts UntarOptions) error {
+	dest, err := filepath.Abs(dest)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		return err
+	}
+
+	dr, err := decompressReader(r)
+	if err != nil {
+		return err
+	}
+	defer dr.Close()
+
+	var total int64
+	tr := tar.NewReader(dr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		target, err := safeJoin(dest, hdr.Name)
+		if err != nil {
+			return err
+		}
+		// Symlinks extracted before must not redirect later entries
+		if err := checkNoSymlinks(dest, target, hdr.Typeflag != tar.TypeSymlink); err != nil {
+			return err
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, os.FileMode(hdr.Mode).Perm()|0700); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			total += hdr.Size
+			if opts.MaxSize > 0 && total > opts.MaxSize {
+				return fmt.Errorf("tarball exceeds the maximum size of %d bytes", opts.MaxSize)
+			}
+			if err := extractFile(tr, target, os.FileMode(hdr.Mode).Perm(), hdr.Size); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			linkTarget := hdr.Linkname
+			if !path.IsAbs(linkTarget) {
+				linkTarget = path.Join(path.Dir(hdr.Name), linkTarget)
+			}
+			if _, err := safeJoin(dest, linkTarget); err != nil || path.IsAbs(hdr.Linkname) {
+				return fmt.Errorf("symlink %s points outside of the destination", hdr.Name)
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			if err := os.Symlink(hdr.Linkname, target); err != nil {
+				return err
+			}
+		case tar.TypeLink:
+			source, err := safeJoin(dest, hdr.Linkname)
+			if err == nil {
+				err = checkNoSymlinks(dest, source, false)
+			}
+			if err != nil {
+				return fmt.Errorf("hardlink %s points outside of the destination", hdr.Name)
+			}
+			if err := os.Link(source, target); err != nil {
+				return err
+			}
+		case tar.TypeXGlobalHeader:
+			continue
+		default:
+			return fmt.Errorf("unsupported entry type %q for %s", hdr.Typeflag, hdr.Name)
+		}
+	}
+
+	return nil
+}
+
+// DetectCompression detects the compression of the tarball being read from r
+// and returns a reader which still provides the full tarball
+func DetectCompression(r io.Reader) (Compression, io.Reader, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(len(xzMagic))
+	if err != nil && err != io.EOF {
+		return CompressionNone, nil, err
+	}
+
+	switch {
+	case bytes.HasPrefix(magic, xzMagic):
+		return CompressionXz, br, nil
+	case bytes.HasPrefix(magic, bzip2Magic):
+		return CompressionBzip2, br, nil
+	default:
+		return CompressionNone, br, nil
+	}
+}
+
+// collectFiles returns all paths below the given content entries which are
+// not excluded in lexical order
+func collectFiles(root string, content, exclude []string) ([]string, error) {
+	for _, pattern := range exclude {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, errs.NewInvalidArgument(fmt.Sprintf("exclude pattern %q", pattern))
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, entry := range content {
+		start := filepath.Join(root, entry)
+		err := filepath.Walk(start, func(p string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(root, p)
+			if err != nil {
+				return err
+			}
+			rel = filepath.ToSlash(rel)
+			if isExcluded(rel, exclude) {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			seen[rel] = true
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	files := make([]string, 0, len(seen))
+	for name := range seen {
+		files = append(files, name)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func isExcluded(name string, exclude []string) bool {
+	for _, pattern := range exclude {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, path.Base(name)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func writeTarEntry(tw *tar.Writer, src tarSource, modTime time.Time) error {
+	if len(src.path) == 0 {
+		hdr := &tar.Header{
+			Name:     src.name,
+			Typeflag: tar.TypeReg,
+			Mode:     0644,
+			Size:     int64(len(src.data)),
+			ModTime:  modTime,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(src.data)
+		return err
+	}
+
+	p := src.path
+	info, err := os.Lstat(p)
+	if err != nil {
+		return err
+	}
+
+	link := ""
+	if info.Mode()&os.ModeSymlink != 0 {
+		link, err = os.Readlink(p)
+		if err != nil {
+			return err
+		}
+	}
+
+	hdr, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return err
+	}
+	hdr.Name = src.name
+	if info.IsDir() {
+		hdr.Name += "/"
+	}
+	hdr.Uid, hdr.Gid = 0, 0
+	hdr.Uname, hdr.Gname = "", ""
+	if !modTime.IsZero() {
+		hdr.ModTime = modTime
+		hdr.AccessTime = time.Time{}
+		hdr.ChangeTime = time.Time{}
+	}
+
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+
+	f, err := os.Open(p)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+func extractFile(r io.Reader, target string, mode os.FileMode, size int64) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC|openNoFollow, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.CopyN(f, r, size); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// safeJoin joins the given tarball entry name with dest and fails if the
+// result is not located below dest
+func safeJoin(dest, name string) (string, error) {
+	if path.IsAbs(name) || filepath.IsAbs(name) {
+		return "", fmt.Errorf("entry %s has an absolute path", name)
+	}
+	target := filepath.Join(dest, filepath.FromSlash(name))
+	if target != dest && !strings.HasPrefix(target, dest+string(os.PathSeparator)) {
+		return "", fmt.Errorf("entry %s points outside of the destination", name)
+	}
+	return target, nil
+}
+
+// checkNoSymlinks fails if any existing path component between dest and
+// target is a symlink. The target itself is only checked if includeTarget is
+// set.
+func checkNoSymlinks(dest, target string, includeTarget bool) error {
+	rel, err := filepath.Rel(dest, target)
+	if err != nil {
+		return err
+	}
+	if rel == "." {
+		return nil
+	}
+
+	parts := strings.Split(rel, string(os.PathSeparator))
+	if !includeTarget {
+		parts = parts[:len(parts)-1]
+	}
+	current := dest
+	for _, part := range parts {
+		current = filepath.Join(current, part)
+		info, err := os.Lstat(current)
+		if os.IsNotExist(err) {
+			// Nothing below a missing component can exist yet
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("entry %s is located below the symlink %s", rel, current)
+		}
+	}
+	return nil
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+// cmdWriteCloser streams everything written to it through an external
+// compression command
+type cmdWriteCloser struct {
+	io.WriteCloser
+	cmd *exec.Cmd
+}
+
+func (c *cmdWriteCloser) Close() error {
+	if err := c.WriteCloser.Close(); err != nil {
+		c.cmd.Wait()
+		return err
+	}
+	return c.cmd.Wait()
+}
+
+type cmdReadCloser struct {
+	io.ReadCloser
+	cmd *exec.Cmd
+}
+
+func (c *cmdReadCloser) Close() error {
+	c.ReadCloser.Close()
+	c.cmd.Wait()
+	return nil
+}
+
+// compressWriter returns a writer compressing everything written to it into
+// w. The Go standard library has no bzip2 or xz encoder, so the same tools
+// `tar` uses for compression are run.
+func compressWriter(w io.Writer, compression Compression) (io.WriteCloser, error) {
+	var name string
+	switch compression {
+	case CompressionNone:
+		return nopWriteCloser{w}, nil
+	case CompressionBzip2:
+		name = "bzip2"
+	case CompressionXz:
+		name = "xz"
+	default:
+		return nil, errs.NewInvalidArgument("compression")
+	}
+
+	cmd := exec.Command(name, "-c")
+	cmd.Stdout = w
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return &cmdWriteCloser{WriteCloser: stdin, cmd: cmd}, nil
+}
+
+func decompressReader(r io.Reader) (io.ReadCloser, error) {
+	compression, r, err := DetectCompression(r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch compression {
+	case CompressionBzip2:
+		return io.NopCloser(bzip2.NewReader(r)), nil
+	case CompressionXz:
+		cmd := exec.Command("xz", "-dc")
+		cmd.Stdin = r
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return nil, err
+		}
+		if err := cmd.Start(); err != nil {
+			return nil, err
+		}
+		return &cmdReadCloser{ReadCloser: stdout, cmd: cmd}, nil
+	default:
+		return io.NopCloser(r), nil
+	}
+}
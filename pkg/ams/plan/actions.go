@@ -0,0 +1,154 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package plan
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	api "github.com/anbox-cloud/ams-sdk/api/ams"
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/client"
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+)
+
+// InstanceKey returns the state key under which the ID of the instance
+// launched by the step with the given name is stored
+func InstanceKey(step string) string {
+	return fmt.Sprintf("%s.instance", step)
+}
+
+// instanceID returns the ID stored in the state for the given instance
+// reference. A reference starting with "@" refers to the instance launched
+// by the step with the given name, any other value is taken as instance ID.
+func instanceID(state *State, ref string) (string, error) {
+	if len(ref) > 1 && ref[0] == '@' {
+		id, ok := state.Get(InstanceKey(ref[1:]))
+		if !ok {
+			return "", errs.NewErrNotFound(fmt.Sprintf("instance launched by step %s", ref[1:]))
+		}
+		return id, nil
+	}
+	if len(ref) == 0 {
+		return "", errs.NewInvalidArgument("instance")
+	}
+	return ref, nil
+}
+
+// LaunchInstance returns a step which launches a new instance with the given
+// details. The ID of the launched instance is stored in the state under
+// InstanceKey(name). On rollback the instance is deleted again.
+func LaunchInstance(c client.Client, name string, details *api.InstancesPost, dependsOn ...string) Step {
+	return Step{
+		Name:      name,
+		DependsOn: dependsOn,
+		Run: func(ctx context.Context, state *State) error {
+			operation, err := c.LaunchInstance(details, false)
+			if err != nil {
+				return err
+			}
+			if err := operation.Wait(ctx); err != nil {
+				return err
+			}
+
+			resources := operation.Get().Resources
+			for _, key := range []string{"instances", "containers"} {
+				if ids := resources[key]; len(ids) > 0 {
+					state.Set(InstanceKey(name), path.Base(ids[0]))
+					return nil
+				}
+			}
+			return fmt.Errorf("operation did not return the ID of the launched instance")
+		},
+		Rollback: func(ctx context.Context, state *State) error {
+			id, ok := state.Get(InstanceKey(name))
+			if !ok {
+				return nil
+			}
+			operation, err := c.DeleteInstanceByID(id, true)
+			if err != nil {
+				return err
+			}
+			return operation.Wait(ctx)
+		},
+	}
+}
+
+// WaitForInstanceStatus returns a step which waits until the referenced
+// instance reached the given status. The instance is either referenced by its
+// ID or by "@<step>" for an instance launched by a previous step. The step fails
+// if the instance enters the error status.
+func WaitForInstanceStatus(c client.Client, name, instance string, status api.InstanceStatus, dependsOn ...string) Step {
+	return Step{
+		Name:      name,
+		DependsOn: dependsOn,
+		Run: func(ctx context.Context, state *State) error {
+			id, err := instanceID(state, instance)
+			if err != nil {
+				return err
+			}
+
+			_, err = c.WaitForInstanceStatus(ctx, id, status)
+			return err
+		},
+	}
+}
+
+// UpdateInstance returns a step which updates the referenced instance with
+// the given details. The instance is either referenced by its ID or by
+// "@<step>" for an instance launched by a previous step.
+func UpdateInstance(c client.Client, name, instance string, details *api.InstancePatch, dependsOn ...string) Step {
+	return Step{
+		Name:      name,
+		DependsOn: dependsOn,
+		Run: func(ctx context.Context, state *State) error {
+			id, err := instanceID(state, instance)
+			if err != nil {
+				return err
+			}
+			operation, err := c.UpdateInstanceByID(id, details, false)
+			if err != nil {
+				return err
+			}
+			return operation.Wait(ctx)
+		},
+	}
+}
+
+// DeleteInstance returns a step which deletes the referenced instance. The
+// instance is either referenced by its ID or by "@<step>" for an instance
+// launched by a previous step. A deleted instance cannot be restored, so the
+// step has no rollback.
+func DeleteInstance(c client.Client, name, instance string, force bool, dependsOn ...string) Step {
+	return Step{
+		Name:      name,
+		DependsOn: dependsOn,
+		Run: func(ctx context.Context, state *State) error {
+			id, err := instanceID(state, instance)
+			if err != nil {
+				return err
+			}
+			operation, err := c.DeleteInstanceByID(id, force)
+			if err != nil {
+				return err
+			}
+			return operation.Wait(ctx)
+		},
+	}
+}
@@ -0,0 +1,330 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package plan
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared"
+)
+
+const (
+	defaultRollbackTimeout = 5 * time.Minute
+)
+
+// StepStatus describes the status of a single step of a plan
+type StepStatus string
+
+const (
+	// StepStatusPending is the status of a step which did not run yet
+	StepStatusPending StepStatus = "pending"
+	// StepStatusRunning is the status of a step which is currently running
+	StepStatusRunning StepStatus = "running"
+	// StepStatusDone is the status of a step which completed successfully
+	StepStatusDone StepStatus = "done"
+	// StepStatusFailed is the status of a step which failed
+	StepStatusFailed StepStatus = "failed"
+	// StepStatusRolledBack is the status of a completed step which was rolled back
+	StepStatusRolledBack StepStatus = "rolled-back"
+	// StepStatusRollbackFailed is the status of a completed step which failed to roll back
+	StepStatusRollbackFailed StepStatus = "rollback-failed"
+)
+
+// StepRecord records the execution of a single step
+type StepRecord struct {
+	Name       string     `json:"name" yaml:"name"`
+	Status     StepStatus `json:"status" yaml:"status"`
+	Error      string     `json:"error,omitempty" yaml:"error,omitempty"`
+	StartedAt  time.Time  `json:"started_at,omitempty" yaml:"started_at,omitempty"`
+	FinishedAt time.Time  `json:"finished_at,omitempty" yaml:"finished_at,omitempty"`
+}
+
+// Progress records the execution of a plan. It can be serialized and passed to
+// a later execution of the same plan to resume it.
+type Progress struct {
+	// Steps maps step names to their execution record
+	Steps map[string]*StepRecord `json:"steps" yaml:"steps"`
+	// Completed lists the names of the completed steps in order of completion
+	Completed []string `json:"completed" yaml:"completed"`
+	// State holds the values steps shared through the plan state
+	State map[string]string `json:"state" yaml:"state"`
+}
+
+// NewProgress returns an empty progress
+func NewProgress() *Progress {
+	return &Progress{
+		Steps: map[string]*StepRecord{},
+		State: map[string]string{},
+	}
+}
+
+// State allows steps to share values, e.g. the ID of a launched instance
+// with the steps depending on it
+type State struct {
+	lock   sync.RWMutex
+	values map[string]string
+}
+
+// Get returns the value stored under the given key
+func (s *State) Get(key string) (string, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	v, ok := s.values[key]
+	return v, ok
+}
+
+// Set stores the value under the given key
+func (s *State) Set(key, value string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.values[key] = value
+}
+
+func (s *State) snapshot() map[string]string {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	values := make(map[string]string, len(s.values))
+	for k, v := range s.values {
+		values[k] = v
+	}
+	return values
+}
+
+// ExecutionError is returned when the execution of a plan failed
+type ExecutionError struct {
+	// Step is the name of the step which failed. Empty if the execution was
+	// aborted because the context was cancelled.
+	Step string
+	// Err is the error the step failed with
+	Err error
+	// RollbackErrors maps the names of steps which failed to roll back to their error
+	RollbackErrors map[string]error
+}
+
+// Error returns the error string
+func (e *ExecutionError) Error() string {
+	msg := fmt.Sprintf("plan execution aborted: %v", e.Err)
+	if len(e.Step) > 0 {
+		msg = fmt.Sprintf("step %s failed: %v", e.Step, e.Err)
+	}
+	if len(e.RollbackErrors) > 0 {
+		names := []string{}
+		for name := range e.RollbackErrors {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		msg = fmt.Sprintf("%s (rollback failed for: %s)", msg, strings.Join(names, ", "))
+	}
+	return msg
+}
+
+// Unwrap returns the error the step failed with
+func (e *ExecutionError) Unwrap() error {
+	return e.Err
+}
+
+// Executor runs plans
+type Executor struct {
+	// Concurrency defines how many independent steps can run at the same time.
+	// Defaults to 1.
+	Concurrency int
+	// Rollback enables rolling back all completed steps when a step fails
+	Rollback bool
+	// RollbackTimeout limits the time all rollbacks can take together.
+	// Defaults to 5 minutes.
+	RollbackTimeout time.Duration
+	// OnProgress is called whenever the status of a step changes. It can be
+	// used to persist the progress for a later resume.
+	OnProgress func(record StepRecord, progress *Progress)
+}
+
+type stepResult struct {
+	name string
+	err  error
+}
+
+// Execute runs the given plan. If a progress from a previous execution is
+// given, completed steps are skipped and the plan is resumed. The progress of
+// the execution is returned even if the execution failed.
+func (e *Executor) Execute(ctx context.Context, p *Plan, progress *Progress) (*Progress, error) {
+	sorted, err := p.sorted()
+	if err != nil {
+		return progress, err
+	}
+
+	if progress == nil {
+		progress = NewProgress()
+	}
+	if progress.Steps == nil {
+		progress.Steps = map[string]*StepRecord{}
+	}
+	state := &State{values: map[string]string{}}
+	for k, v := range progress.State {
+		state.values[k] = v
+	}
+
+	// Steps which were interrupted or failed in a previous run are retried
+	for _, name := range sorted {
+		record, ok := progress.Steps[name]
+		if !ok || record.Status != StepStatusDone {
+			progress.Steps[name] = &StepRecord{Name: name, Status: StepStatusPending}
+		}
+	}
+
+	concurrency := e.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	update := func(record *StepRecord) {
+		progress.State = state.snapshot()
+		if e.OnProgress != nil {
+			e.OnProgress(*record, progress)
+		}
+	}
+
+	ready := func(name string) bool {
+		if progress.Steps[name].Status != StepStatusPending {
+			return false
+		}
+		for _, dep := range p.steps[name].DependsOn {
+			if progress.Steps[dep].Status != StepStatusDone {
+				return false
+			}
+		}
+		return true
+	}
+
+	results := make(chan stepResult)
+	running := 0
+	var failure *ExecutionError
+
+	for {
+		if failure == nil && ctx.Err() != nil {
+			failure = &ExecutionError{Err: ctx.Err()}
+		}
+
+		if failure == nil {
+			for _, name := range sorted {
+				if running >= concurrency {
+					break
+				}
+				if !ready(name) {
+					continue
+				}
+
+				record := progress.Steps[name]
+				record.Status = StepStatusRunning
+				record.StartedAt = time.Now()
+				update(record)
+
+				running++
+				go func(step *Step) {
+					results <- stepResult{name: step.Name, err: step.Run(ctx, state)}
+				}(p.steps[name])
+			}
+		}
+
+		if running == 0 {
+			break
+		}
+
+		result := <-results
+		running--
+
+		record := progress.Steps[result.name]
+		record.FinishedAt = time.Now()
+		if result.err != nil {
+			record.Status = StepStatusFailed
+			record.Error = result.err.Error()
+			if failure == nil || len(failure.Step) == 0 {
+				failure = &ExecutionError{Step: result.name, Err: result.err}
+			}
+		} else {
+			record.Status = StepStatusDone
+			record.Error = ""
+			// Steps which failed to roll back in a previous run are still
+			// listed as completed and keep their position
+			if !shared.StringInSlice(result.name, progress.Completed) {
+				progress.Completed = append(progress.Completed, result.name)
+			}
+		}
+		update(record)
+	}
+
+	if failure == nil {
+		for _, name := range sorted {
+			if progress.Steps[name].Status != StepStatusDone {
+				return progress, fmt.Errorf("step %s could not be run", name)
+			}
+		}
+		return progress, nil
+	}
+
+	if e.Rollback {
+		failure.RollbackErrors = e.rollback(p, progress, state, update)
+	}
+
+	return progress, failure
+}
+
+func (e *Executor) rollback(p *Plan, progress *Progress, state *State, update func(record *StepRecord)) map[string]error {
+	timeout := e.RollbackTimeout
+	if timeout <= 0 {
+		timeout = defaultRollbackTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	rollbackErrors := map[string]error{}
+	remaining := []string{}
+
+	// Walk in reverse order of completion through the completed steps
+	for n := len(progress.Completed) - 1; n >= 0; n-- {
+		name := progress.Completed[n]
+		step, ok := p.steps[name]
+		if !ok || step.Rollback == nil {
+			remaining = append([]string{name}, remaining...)
+			continue
+		}
+
+		record := progress.Steps[name]
+		if err := step.Rollback(ctx, state); err != nil {
+			record.Status = StepStatusRollbackFailed
+			record.Error = err.Error()
+			rollbackErrors[name] = err
+			remaining = append([]string{name}, remaining...)
+		} else {
+			record.Status = StepStatusRolledBack
+		}
+		record.FinishedAt = time.Now()
+		update(record)
+	}
+
+	progress.Completed = remaining
+	if len(rollbackErrors) == 0 {
+		return nil
+	}
+	return rollbackErrors
+}
@@ -0,0 +1,133 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package plan allows declaring a set of SDK actions as a graph of steps which
+// are executed according to their dependencies. The progress of an execution
+// is recorded so that an interrupted plan can be resumed and completed steps
+// can be rolled back when a step fails.
+package plan
+
+import (
+	"context"
+	"fmt"
+
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+)
+
+// StepFunc describes a function executed as part of a step
+type StepFunc func(ctx context.Context, state *State) error
+
+// Step describes a single action of a plan
+type Step struct {
+	// Name uniquely identifies the step within a plan
+	Name string
+	// DependsOn lists the names of the steps which have to complete before
+	// this step is run
+	DependsOn []string
+	// Run performs the action of the step
+	Run StepFunc
+	// Rollback reverts the action of the step. Optional.
+	Rollback StepFunc
+}
+
+// Plan is a set of steps with dependencies between them
+type Plan struct {
+	steps map[string]*Step
+	order []string
+}
+
+// New returns a new and empty plan
+func New() *Plan {
+	return &Plan{steps: map[string]*Step{}}
+}
+
+// Add adds the given steps to the plan
+func (p *Plan) Add(steps ...Step) error {
+	for n := range steps {
+		step := steps[n]
+		if len(step.Name) == 0 {
+			return errs.NewInvalidArgument("name")
+		}
+		if step.Run == nil {
+			return errs.NewInvalidArgument(fmt.Sprintf("run (step %s)", step.Name))
+		}
+		if _, ok := p.steps[step.Name]; ok {
+			return errs.NewErrAlreadyExists(fmt.Sprintf("step %s", step.Name))
+		}
+		p.steps[step.Name] = &step
+		p.order = append(p.order, step.Name)
+	}
+	return nil
+}
+
+// Steps returns the names of all steps of the plan in the order they were added
+func (p *Plan) Steps() []string {
+	return append([]string{}, p.order...)
+}
+
+// Validate checks that all dependencies of the plan exist and no dependency
+// cycles are present
+func (p *Plan) Validate() error {
+	_, err := p.sorted()
+	return err
+}
+
+// sorted returns the steps of the plan in topological order
+func (p *Plan) sorted() ([]string, error) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	marks := map[string]int{}
+	sorted := []string{}
+
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch marks[name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("dependency cycle detected: %v", append(path, name))
+		}
+		marks[name] = visiting
+
+		step := p.steps[name]
+		for _, dep := range step.DependsOn {
+			if _, ok := p.steps[dep]; !ok {
+				return errs.NewErrNotFound(fmt.Sprintf("dependency %s of step %s", dep, name))
+			}
+			if err := visit(dep, append(path, name)); err != nil {
+				return err
+			}
+		}
+
+		marks[name] = visited
+		sorted = append(sorted, name)
+		return nil
+	}
+
+	for _, name := range p.order {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return sorted, nil
+}
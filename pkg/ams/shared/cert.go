@@ -0,0 +1,187 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package shared
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"time"
+
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+)
+
+const (
+	// DefaultCertificateLifetime is the lifetime of generated certificates if
+	// no other is given
+	DefaultCertificateLifetime = 10 * 365 * 24 * time.Hour
+	defaultCertOrganization    = "Anbox Cloud"
+)
+
+// CertificateOptions describe the certificate to generate
+type CertificateOptions struct {
+	// CommonName of the certificate subject. Defaults to "<user>@<hostname>".
+	CommonName string
+	// Organization of the certificate subject. Defaults to "Anbox Cloud".
+	Organization string
+	// Hosts lists the DNS names and IP addresses added as subject
+	// alternative names. Defaults to the hostname of the machine.
+	Hosts []string
+	// Lifetime of the certificate. Defaults to DefaultCertificateLifetime.
+	Lifetime time.Duration
+}
+
+func (o *CertificateOptions) withDefaults() CertificateOptions {
+	opts := CertificateOptions{}
+	if o != nil {
+		opts = *o
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "localhost"
+	}
+	if len(opts.CommonName) == 0 {
+		username := "root"
+		if u, err := user.Current(); err == nil {
+			username = u.Username
+		}
+		opts.CommonName = fmt.Sprintf("%s@%s", username, hostname)
+	}
+	if len(opts.Organization) == 0 {
+		opts.Organization = defaultCertOrganization
+	}
+	if len(opts.Hosts) == 0 {
+		opts.Hosts = []string{hostname}
+	}
+	if opts.Lifetime <= 0 {
+		opts.Lifetime = DefaultCertificateLifetime
+	}
+	return opts
+}
+
+// GenerateCertificate generates a self signed client certificate with an
+// ECDSA P-384 key and returns the PEM encoded certificate and key
+func GenerateCertificate(opts *CertificateOptions) ([]byte, []byte, error) {
+	o := opts.withDefaults()
+
+	key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate key: %v", err)
+	}
+
+	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
+	serialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate serial number: %v", err)
+	}
+
+	// Allow for some clock skew between client and server
+	notBefore := time.Now().Add(-time.Hour)
+	template := x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			CommonName:   o.CommonName,
+			Organization: []string{o.Organization},
+		},
+		NotBefore:             notBefore,
+		NotAfter:              notBefore.Add(o.Lifetime),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+	}
+	for _, host := range o.Hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create certificate: %v", err)
+	}
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal key: %v", err)
+	}
+
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPem := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+	return cert, keyPem, nil
+}
+
+// WriteCertificate generates a new certificate with the given options and
+// writes it to the certificate and key path. The key is only readable by the
+// current user. Existing files are overwritten.
+func WriteCertificate(certPath, keyPath string, opts *CertificateOptions) error {
+	if len(certPath) == 0 {
+		return errs.NewInvalidArgument("certPath")
+	}
+	if len(keyPath) == 0 {
+		return errs.NewInvalidArgument("keyPath")
+	}
+
+	cert, key, err := GenerateCertificate(opts)
+	if err != nil {
+		return err
+	}
+
+	for _, dir := range []string{filepath.Dir(certPath), filepath.Dir(keyPath)} {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return err
+		}
+	}
+
+	if err := WriteFileAtomic(keyPath, key, 0600); err != nil {
+		return err
+	}
+	return WriteFileAtomic(certPath, cert, 0644)
+}
+
+// EnsureCertificate generates a new certificate at the given paths if none
+// exists yet, the same way amc does on its first run. Returns true if a new
+// certificate was generated. The certificate has to be added to the trust
+// store of AMS before it can be used to connect.
+func EnsureCertificate(certPath, keyPath string, opts *CertificateOptions) (bool, error) {
+	certExists := PathExists(certPath)
+	keyExists := PathExists(keyPath)
+	if certExists && keyExists {
+		return false, nil
+	}
+	if certExists != keyExists {
+		return false, fmt.Errorf("found only one of certificate %s and key %s", certPath, keyPath)
+	}
+
+	if err := WriteCertificate(certPath, keyPath, opts); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -25,6 +25,9 @@ import (
 var (
 	// ErrAlreadyRunning is returned when an object is already running when it was started again
 	ErrAlreadyRunning = fmt.Errorf("Already running")
+	// ErrReadOnlyClient is returned when a read-only client is asked to perform a
+	// request which would modify the state of the AMS service
+	ErrReadOnlyClient = fmt.Errorf("Request not allowed for read-only client")
 )
 
 type content struct {
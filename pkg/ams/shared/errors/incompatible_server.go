@@ -0,0 +1,58 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package errors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrIncompatibleServer is returned when the AMS service does not provide the
+// capabilities a client requires
+type ErrIncompatibleServer struct {
+	// APIVersion is the API version of the service
+	APIVersion string
+	// RequiredAPIVersion is the minimum API version the client requires.
+	// Empty if the API version of the service is sufficient.
+	RequiredAPIVersion string
+	// MissingExtensions lists the required API extensions the service does
+	// not support
+	MissingExtensions []string
+}
+
+// Error returns the error string
+func (e ErrIncompatibleServer) Error() string {
+	reasons := []string{}
+	if len(e.RequiredAPIVersion) > 0 {
+		reasons = append(reasons, fmt.Sprintf("API version %s is older than required version %s", e.APIVersion, e.RequiredAPIVersion))
+	}
+	if len(e.MissingExtensions) > 0 {
+		reasons = append(reasons, fmt.Sprintf("missing API extensions %s", strings.Join(e.MissingExtensions, ", ")))
+	}
+	return fmt.Sprintf("Incompatible AMS service: %s", strings.Join(reasons, "; "))
+}
+
+// NewErrIncompatibleServer returns a new ErrIncompatibleServer struct
+func NewErrIncompatibleServer(apiVersion, requiredAPIVersion string, missingExtensions []string) ErrIncompatibleServer {
+	return ErrIncompatibleServer{
+		APIVersion:         apiVersion,
+		RequiredAPIVersion: requiredAPIVersion,
+		MissingExtensions:  missingExtensions,
+	}
+}
@@ -0,0 +1,49 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package errors
+
+import (
+	"fmt"
+)
+
+// ErrPreconditionFailed error struct for a conditional request which was
+// rejected because the resource was modified in the meantime
+type ErrPreconditionFailed struct {
+	content
+}
+
+// Error returns the error string
+func (e ErrPreconditionFailed) Error() string {
+	return fmt.Sprintf("%v was modified concurrently", e.What)
+}
+
+// NewErrPreconditionFailed returns a new ErrPreconditionFailed struct
+func NewErrPreconditionFailed(what string) ErrPreconditionFailed {
+	return ErrPreconditionFailed{content{what}}
+}
+
+// IsErrPreconditionFailed checks if the given error is of type ErrPreconditionFailed
+func IsErrPreconditionFailed(err error) bool {
+	switch err.(type) {
+	case ErrPreconditionFailed:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,280 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+)
+
+const (
+	// DefaultResponseCacheSize is the default number of responses kept by a
+	// cache created with NewResponseCache
+	DefaultResponseCacheSize = 256
+)
+
+// CachedResponse is a response stored in a ResponseCache
+type CachedResponse struct {
+	ETag   string
+	Header http.Header
+	Body   []byte
+	// ValidatedAt is the time the response was last confirmed to be current
+	// by the server
+	ValidatedAt time.Time
+}
+
+// staleWhileRevalidate configures serving cached responses while they are
+// refreshed in the background
+type staleWhileRevalidate struct {
+	maxStale  time.Duration
+	onRefresh func(path string, response *CachedResponse)
+
+	lock     sync.Mutex
+	inflight map[string]bool
+}
+
+// ResponseCache stores responses of GET requests together with their ETag.
+// Implementations must be safe for concurrent use.
+type ResponseCache interface {
+	Get(key string) (*CachedResponse, bool)
+	Set(key string, response *CachedResponse)
+}
+
+type cacheEntry struct {
+	key      string
+	response *CachedResponse
+}
+
+type lruResponseCache struct {
+	lock    sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+// NewResponseCache returns an in-memory response cache holding at most the
+// given number of responses. The least recently used responses are evicted
+// first. If size is not positive DefaultResponseCacheSize is used.
+func NewResponseCache(size int) ResponseCache {
+	if size <= 0 {
+		size = DefaultResponseCacheSize
+	}
+	return &lruResponseCache{
+		size:    size,
+		entries: map[string]*list.Element{},
+		order:   list.New(),
+	}
+}
+
+func (c *lruResponseCache) Get(key string) (*CachedResponse, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*cacheEntry).response, true
+}
+
+func (c *lruResponseCache) Set(key string, response *CachedResponse) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if e, ok := c.entries[key]; ok {
+		e.Value.(*cacheEntry).response = response
+		c.order.MoveToFront(e)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, response: response})
+	for c.order.Len() > c.size {
+		e := c.order.Back()
+		c.order.Remove(e)
+		delete(c.entries, e.Value.(*cacheEntry).key)
+	}
+}
+
+// WithResponseCache enables caching of GET responses which carry an ETag.
+// Subsequent requests for the same resource send If-None-Match and a 304 Not
+// Modified reply of the server is answered from the cache. Requests with a
+// Cache-Control: no-cache header are always validated with the server.
+func WithResponseCache(cache ResponseCache) Option {
+	return func(c *client) error {
+		if cache == nil {
+			return errs.NewInvalidArgument("cache")
+		}
+		c.cache = cache
+		return nil
+	}
+}
+
+// WithStaleWhileRevalidate makes the response cache answer GET requests
+// immediately with the cached response if it was validated within the given
+// bound. The response is then revalidated in the background and onRefresh,
+// if set, is called when the server returned fresher data. Responses older
+// than the bound are revalidated before they are returned. Requires a
+// response cache to be configured with WithResponseCache.
+func WithStaleWhileRevalidate(maxStale time.Duration, onRefresh func(path string, response *CachedResponse)) Option {
+	return func(c *client) error {
+		if maxStale <= 0 {
+			return errs.NewInvalidArgument("maxStale")
+		}
+		c.swr = &staleWhileRevalidate{
+			maxStale:  maxStale,
+			onRefresh: onRefresh,
+			inflight:  map[string]bool{},
+		}
+		return nil
+	}
+}
+
+func cacheKey(path string, params QueryParams) string {
+	v := url.Values{}
+	for key, value := range params {
+		v.Add(key, value)
+	}
+	return path + "?" + v.Encode()
+}
+
+// performCachedRequest behaves like performRequest but answers GET requests
+// from the response cache if the cached response is still valid
+func (c *client) performCachedRequest(ctx context.Context, method, path string, params QueryParams, header http.Header, body io.Reader, etag string) (*http.Response, error) {
+	if c.cache == nil || method != "GET" || len(etag) > 0 {
+		return c.performRequest(ctx, method, path, params, header, body, etag)
+	}
+
+	key := cacheKey(path, params)
+	cached, hit := c.cache.Get(key)
+	if hit && c.swr != nil && time.Since(cached.ValidatedAt) <= c.swr.maxStale && !noCache(header) {
+		c.revalidate(key, path, params, header, cached)
+		return cachedHTTPResponse(cached), nil
+	}
+
+	resp, _, err := c.validate(ctx, key, path, params, header, cached)
+	return resp, err
+}
+
+// noCache returns true if the request must not be answered from the cache
+// without validating it with the server
+func noCache(header http.Header) bool {
+	for _, v := range header.Values("Cache-Control") {
+		for _, directive := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(directive), "no-cache") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// validate sends the request, conditional on the ETag of the cached response
+// if given, and updates the cache with the reply. Returns whether the server
+// replied with a new response.
+func (c *client) validate(ctx context.Context, key, path string, params QueryParams, header http.Header, cached *CachedResponse) (*http.Response, bool, error) {
+	if cached != nil {
+		h := http.Header{}
+		for k, v := range header {
+			h[k] = v
+		}
+		h.Set("If-None-Match", cached.ETag)
+		header = h
+	}
+
+	resp, err := c.performRequest(ctx, "GET", path, params, header, nil, "")
+	if err != nil {
+		return nil, false, err
+	}
+
+	if cached != nil && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		validated := *cached
+		validated.ValidatedAt = time.Now()
+		c.cache.Set(key, &validated)
+
+		resp.StatusCode = http.StatusOK
+		resp.Status = http.StatusText(http.StatusOK)
+		resp.Header = cached.Header.Clone()
+		resp.Body = io.NopCloser(bytes.NewReader(cached.Body))
+		return resp, false, nil
+	}
+
+	respETag := resp.Header.Get("ETag")
+	if resp.StatusCode != http.StatusOK || len(respETag) == 0 {
+		return resp, false, nil
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, false, err
+	}
+	c.cache.Set(key, &CachedResponse{ETag: respETag, Header: resp.Header.Clone(), Body: b, ValidatedAt: time.Now()})
+	resp.Body = io.NopCloser(bytes.NewReader(b))
+	return resp, true, nil
+}
+
+// revalidate refreshes the cached response in the background. Only a single
+// revalidation per resource runs at a time.
+func (c *client) revalidate(key, path string, params QueryParams, header http.Header, cached *CachedResponse) {
+	c.swr.lock.Lock()
+	if c.swr.inflight[key] {
+		c.swr.lock.Unlock()
+		return
+	}
+	c.swr.inflight[key] = true
+	c.swr.lock.Unlock()
+
+	go func() {
+		defer func() {
+			c.swr.lock.Lock()
+			delete(c.swr.inflight, key)
+			c.swr.lock.Unlock()
+		}()
+
+		resp, fresh, err := c.validate(context.Background(), key, path, params, header, cached)
+		if err != nil {
+			return
+		}
+		resp.Body.Close()
+		if !fresh || c.swr.onRefresh == nil {
+			return
+		}
+		if refreshed, ok := c.cache.Get(key); ok {
+			c.swr.onRefresh(path, refreshed)
+		}
+	}()
+}
+
+// cachedHTTPResponse synthesizes a HTTP response from a cached one
+func cachedHTTPResponse(cached *CachedResponse) *http.Response {
+	return &http.Response{
+		Status:     http.StatusText(http.StatusOK),
+		StatusCode: http.StatusOK,
+		Header:     cached.Header.Clone(),
+		Body:       io.NopCloser(bytes.NewReader(cached.Body)),
+	}
+}
@@ -20,7 +20,6 @@ package client
 
 import (
 	"bytes"
-	"encoding/json"
 	"fmt"
 
 	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/api"
@@ -47,7 +46,7 @@ func (c *certificates) AddCertificate(base64PublicKey, trustPassword string) err
 		TrustPassword: trustPassword,
 	}
 
-	b, err := json.Marshal(req)
+	b, err := c.Codec().Marshal(req)
 	if err != nil {
 		return fmt.Errorf("Could not marshal request body: %v", err)
 	}
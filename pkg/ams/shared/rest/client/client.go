@@ -19,6 +19,7 @@
 package client
 
 import (
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"errors"
@@ -31,12 +32,20 @@ import (
 	"sync"
 	"time"
 
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/metrics"
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
 	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/api"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/net/http2"
+	"golang.org/x/oauth2"
 )
 
 // Default value for client requests to wait for a reply
 const (
 	DefaultTransportTimeout = 1 * time.Minute
+	// DefaultWebsocketHandshakeTimeout is the default time the client waits
+	// for a websocket handshake to complete
+	DefaultWebsocketHandshakeTimeout = 45 * time.Second
 )
 
 // Doer is the implementation of the Client engine
@@ -47,14 +56,53 @@ type Doer interface {
 // http client to use REST API
 type client struct {
 	Doer
+	middlewares []Middleware
+
+	http             *http.Client
+	http2            *http2.Transport
+	dialer           *net.Dialer
+	srvService       string
+	transportTimeout time.Duration
+
+	requestTimeout            time.Duration
+	operationWaitTimeout      time.Duration
+	websocketHandshakeTimeout time.Duration
+	websocketDial             WebsocketDialFunc
+
+	health   *healthTracker
+	skew     *skewTracker
+	connInfo connectionInfoTracker
+	cache    ResponseCache
+	swr      *staleWhileRevalidate
+	tracer   trace.Tracer
+	metrics  *metrics.Collectors
+	streams  *streamRegistry
+
+	readOnly       bool
+	dryRun         *DryRunOptions
+	sessions       *sessionCache
+	onDecodeReport func(report *DecodeReport)
+	tokens         oauth2.TokenSource
+	requirements   *Requirements
+
+	idempotencyKeys    bool
+	disableCompression bool
+	codec              Codec
+
+	limiter            *rateLimiter
+	retryAfterAttempts int
+	retryAfterMaxWait  time.Duration
 
 	serviceURL *url.URL
+	endpoints  *endpointPool
 
 	eventListeners     []*EventListener
 	eventListenersLock *sync.Mutex
+	eventReconnect     *ReconnectPolicy
 
-	// TODO for now this is not being filled anywhere
 	httpUserAgent string
+	extraHeader   http.Header
+	headerFuncs   []HeaderFunc
 }
 
 // QueryParams request query parameter
@@ -62,25 +110,40 @@ type QueryParams map[string]string
 
 // New returns a REST client. Depending on provided addr parameter, it connects to
 // a remote network server or through a unix socket
-func New(addr interface{}, tlsConfig *tls.Config) (Client, error) {
+func New(addr interface{}, tlsConfig *tls.Config, opts ...Option) (Client, error) {
 	if addr == nil {
 		return nil, errors.New("Empty address given")
 	}
 
+	var c *client
+	var err error
 	switch addr.(type) {
 	case *url.URL:
-		return newNetworkClient(addr.(*url.URL), tlsConfig)
+		c, err = newNetworkClient(addr.(*url.URL), tlsConfig)
 	case string:
-		return newUnixSocketClient(addr.(string))
+		c, err = newUnixSocketClient(addr.(string))
 	default:
 		return nil, errors.New("Invalid address type given")
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.applyOptions(opts); err != nil {
+		return nil, err
+	}
+
+	if err := c.checkRequirements(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
 }
 
 // newNetworkClient returns a new REST client pointing to remote address received as parameter
 // The connection is TLS enabled or not depending on the remote address schema.
 // If TLS is enabled, a proper TLS config must be supplied as second parameter
-func newNetworkClient(url *url.URL, tlsConfig *tls.Config) (Client, error) {
+func newNetworkClient(url *url.URL, tlsConfig *tls.Config) (*client, error) {
 	if url == nil {
 		return nil, fmt.Errorf("Invalid URL given")
 	}
@@ -89,22 +152,31 @@ func newNetworkClient(url *url.URL, tlsConfig *tls.Config) (Client, error) {
 		tlsConfig = &tls.Config{InsecureSkipVerify: true}
 	}
 
-	c := &client{
-		Doer: &http.Client{
-			Transport: &http.Transport{
-				TLSClientConfig: tlsConfig,
-			},
-			Timeout: DefaultTransportTimeout,
+	httpClient := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: tlsConfig,
 		},
-		serviceURL:         url,
-		eventListenersLock: &sync.Mutex{},
+		Timeout: DefaultTransportTimeout,
+	}
+
+	c := &client{
+		Doer:                      httpClient,
+		http:                      httpClient,
+		transportTimeout:          DefaultTransportTimeout,
+		websocketHandshakeTimeout: DefaultWebsocketHandshakeTimeout,
+		health:                    newHealthTracker(DefaultHealthHalfLife),
+		skew:                      newSkewTracker(),
+		streams:                   newStreamRegistry(),
+		serviceURL:                url,
+		eventListenersLock:        &sync.Mutex{},
+		extraHeader:               http.Header{},
 	}
 
 	return c, nil
 }
 
 // newUnixSocketClient returns a REST client pointing to local unix socket
-func newUnixSocketClient(path string) (Client, error) {
+func newUnixSocketClient(path string) (*client, error) {
 	// Setup a Unix socket dialer
 	unixDial := func(network, addr string) (net.Conn, error) {
 		raddr, err := net.ResolveUnixAddr("unix", path)
@@ -120,16 +192,25 @@ func newUnixSocketClient(path string) (Client, error) {
 		return nil, err
 	}
 
-	c := &client{
-		Doer: &http.Client{
-			Transport: &http.Transport{
-				Dial:              unixDial,
-				DisableKeepAlives: true,
-			},
-			Timeout: DefaultTransportTimeout,
+	httpClient := &http.Client{
+		Transport: &http.Transport{
+			Dial:              unixDial,
+			DisableKeepAlives: true,
 		},
-		serviceURL:         unixSocketServiceURL,
-		eventListenersLock: &sync.Mutex{},
+		Timeout: DefaultTransportTimeout,
+	}
+
+	c := &client{
+		Doer:                      httpClient,
+		http:                      httpClient,
+		transportTimeout:          DefaultTransportTimeout,
+		websocketHandshakeTimeout: DefaultWebsocketHandshakeTimeout,
+		health:                    newHealthTracker(DefaultHealthHalfLife),
+		skew:                      newSkewTracker(),
+		streams:                   newStreamRegistry(),
+		serviceURL:                unixSocketServiceURL,
+		eventListenersLock:        &sync.Mutex{},
+		extraHeader:               http.Header{},
 	}
 
 	return c, nil
@@ -156,26 +237,40 @@ func (c *client) ServiceURL() string {
 
 // HTTPTransport returns the HTTP transport the client uses internally
 func (c *client) HTTPTransport() *http.Transport {
-	return c.Doer.(*http.Client).Transport.(*http.Transport)
+	return c.http.Transport.(*http.Transport)
 }
 
-// SetTimeout overwrites default timeout of the client with a new one
+// SetTransportTimeout overwrites the timeout of the client with a new one
 func (c *client) SetTransportTimeout(timeout time.Duration) {
-	if c.Doer == nil {
+	if c.http == nil {
 		return
 	}
 
-	c.Doer.(*http.Client).Timeout = timeout
+	c.http.Timeout = timeout
+}
+
+// TransportTimeout returns the timeout the client was configured with
+func (c *client) TransportTimeout() time.Duration {
+	return c.transportTimeout
 }
 
 // QueryStruct sends a request to the server and stores response in a struct
 func (c *client) QueryStruct(method, path string, params QueryParams, header http.Header, body io.Reader, etag string, target interface{}) (string, error) {
-	resp, etag, err := c.CallAPI(method, path, params, header, body, etag)
+	return c.QueryStructWithContext(context.Background(), method, path, params, header, body, etag, target)
+}
+
+// QueryStructWithContext sends a request to the server and stores response in
+// a struct. The request is aborted when the context is done.
+func (c *client) QueryStructWithContext(ctx context.Context, method, path string, params QueryParams, header http.Header, body io.Reader, etag string, target interface{}) (string, error) {
+	ctx, span := c.startSpanWithContext(ctx, "QueryStruct", attrHTTPMethod.String(method), attrHTTPTarget.String(path))
+	resp, etag, err := c.callAPI(ctx, method, path, params, header, body, etag)
 	if err != nil {
+		endSpan(span, err)
 		return "", err
 	}
 
-	err = resp.MetadataAsStruct(&target)
+	err = c.decodeMetadata(method, path, resp.Metadata, &target)
+	endSpan(span, err)
 	return etag, err
 }
 
@@ -191,17 +286,43 @@ func pretty(input interface{}) string {
 // QueryOperation sends a request to the server that will return an async response in an Operation object
 // that allows additional logic like wait for completion or cancel it
 func (c *client) QueryOperation(method, path string, params QueryParams, header http.Header, body io.Reader, etag string) (Operation, string, error) {
-	// Attempt to setup an early event listener
-	listener, err := c.GetEvents()
+	if c.readOnly && !isSafeMethod(method) {
+		return nil, "", errs.ErrReadOnlyClient
+	}
+
+	// Attempt to setup an early event listener. Dry run operations have
+	// already succeeded, so no events are needed for them.
+	var listener *EventListener
+	if !c.isDryRun(method, header) {
+		l, err := c.GetEvents()
+		if err == nil {
+			listener = l
+		}
+	}
+
+	ctx, span := c.startSpan("QueryOperation", attrHTTPMethod.String(method), attrHTTPTarget.String(path))
+	ctx = withExpectedOperation(ctx)
+
+	// Generate the idempotency key here already so it is recorded with the
+	// operation span
+	header, err := c.withIdempotencyKey(method, header)
 	if err != nil {
-		listener = nil
+		if listener != nil {
+			listener.Disconnect()
+		}
+		endSpan(span, err)
+		return nil, "", err
+	}
+	if key := header.Get(IdempotencyKeyHeader); len(key) > 0 {
+		span.SetAttributes(attrIdempotencyKey.String(key))
 	}
 
-	resp, etag, err := c.CallAPI(method, path, params, header, body, etag)
+	resp, etag, err := c.callAPI(ctx, method, path, params, header, body, etag)
 	if err != nil {
 		if listener != nil {
 			listener.Disconnect()
 		}
+		endSpan(span, err)
 		return nil, "", err
 	}
 
@@ -210,14 +331,19 @@ func (c *client) QueryOperation(method, path string, params QueryParams, header
 		if listener != nil {
 			listener.Disconnect()
 		}
+		endSpan(span, err)
 		return nil, "", err
 	}
+	span.SetAttributes(attrOperationID.String(apiOp.ID))
+	endSpan(span, nil)
 
 	op := operation{
-		Operation: *apiOp,
-		c:         &operations{c},
-		listener:  listener,
-		chActive:  make(chan bool),
+		Operation:   *apiOp,
+		c:           &operations{c},
+		metrics:     c.metrics,
+		listener:    listener,
+		waitTimeout: c.operationWaitTimeout,
+		chActive:    make(chan bool),
 	}
 
 	return &op, etag, nil
@@ -225,7 +351,14 @@ func (c *client) QueryOperation(method, path string, params QueryParams, header
 
 // CallAPI requests a REST api method with provided query params and body and returns related http response
 func (c *client) CallAPI(method, path string, params QueryParams, header http.Header, body io.Reader, etag string) (*api.Response, string, error) {
-	resp, err := c.performRequest(method, path, params, header, body, etag)
+	ctx, span := c.startSpan("CallAPI", attrHTTPMethod.String(method), attrHTTPTarget.String(path))
+	resp, etag, err := c.callAPI(ctx, method, path, params, header, body, etag)
+	endSpan(span, err)
+	return resp, etag, err
+}
+
+func (c *client) callAPI(ctx context.Context, method, path string, params QueryParams, header http.Header, body io.Reader, etag string) (*api.Response, string, error) {
+	resp, err := c.performCachedRequest(ctx, method, path, params, header, body, etag)
 	if err != nil {
 		return nil, "", err
 	}
@@ -234,8 +367,11 @@ func (c *client) CallAPI(method, path string, params QueryParams, header http.He
 	return c.parseResponse(resp)
 }
 
-func (c *client) DownloadFile(path string, params QueryParams, header http.Header, downloader func(header *http.Header, body io.ReadCloser) error) error {
-	resp, err := c.performRequest("GET", path, params, header, nil, "")
+func (c *client) DownloadFile(path string, params QueryParams, header http.Header, downloader func(header *http.Header, body io.ReadCloser) error) (err error) {
+	ctx, span := c.startSpan("DownloadFile", attrHTTPMethod.String("GET"), attrHTTPTarget.String(path))
+	defer func() { endSpan(span, err) }()
+
+	resp, err := c.performRequest(ctx, "GET", path, params, header, nil, "")
 	if err != nil {
 		return err
 	}
@@ -243,8 +379,9 @@ func (c *client) DownloadFile(path string, params QueryParams, header http.Heade
 
 	// NOTE: As a fileResposne is an inline response and different with
 	// generic api.Response so that we can't simply parse the response
-	// directly unless http status code is not StatusOK
-	if resp.StatusCode != http.StatusOK {
+	// directly unless http status code is not StatusOK (or StatusPartialContent
+	// for ranged requests)
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
 		_, _, err := c.parseResponse(resp)
 		return err
 	}
@@ -252,58 +389,142 @@ func (c *client) DownloadFile(path string, params QueryParams, header http.Heade
 	return downloader(&resp.Header, resp.Body)
 }
 
-func (c *client) performRequest(method, path string, params QueryParams, header http.Header, body io.Reader, etag string) (*http.Response, error) {
+// Raw sends a request for an arbitrary API endpoint and returns the plain
+// HTTP response. The request goes through the same authentication, retry and
+// middleware handling as all other requests. Responses with an error status
+// are turned into an error, otherwise the caller has to close the body.
+func (c *client) Raw(ctx context.Context, method, path string, params QueryParams, body io.Reader) (resp *http.Response, err error) {
+	ctx, span := c.startSpanWithContext(ctx, "Raw", attrHTTPMethod.String(method), attrHTTPTarget.String(path))
+	defer func() { endSpan(span, err) }()
+
+	resp, err = c.performRequest(ctx, method, path, params, nil, body, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+		_, _, err := c.parseResponse(resp)
+		if err == nil {
+			err = errors.New(http.StatusText(resp.StatusCode))
+		}
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *client) performRequest(ctx context.Context, method, path string, params QueryParams, header http.Header, body io.Reader, etag string) (*http.Response, error) {
+	if c.readOnly && !isSafeMethod(method) {
+		return nil, errs.ErrReadOnlyClient
+	}
+
 	u := c.serviceURL.ResolveReference(
 		&url.URL{
 			Path: path,
 		},
 	)
 
-	r, err := http.NewRequest(method, u.String(), body)
+	header, err := c.withIdempotencyKey(method, header)
 	if err != nil {
 		return nil, err
 	}
 
+	ctx, cancel := withDefaultTimeout(ctx, c.requestTimeout)
+	r, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
 	v := r.URL.Query()
 	for key, value := range params {
 		v.Add(key, value)
 	}
 	r.URL.RawQuery = v.Encode()
 
-	if len(c.httpUserAgent) > 0 {
-		r.Header.Set("User-Agent", c.httpUserAgent)
+	c.setExtraHeaders(ctx, r.Header)
+
+	// Headers given for the request replace the ones configured for the client
+	for k, v := range header {
+		r.Header[http.CanonicalHeaderKey(k)] = append([]string{}, v...)
 	}
 
-	if header != nil {
-		for k, v := range header {
-			for _, s := range v {
-				r.Header.Add(k, s)
-			}
-		}
+	// Only modify the resource if it did not change since it was retrieved
+	if len(etag) > 0 {
+		r.Header.Set("If-Match", etag)
+	}
+
+	if resp, ok, err := c.dryRunResponse(r); ok {
+		cancel()
+		return resp, err
 	}
 
-	return c.Doer.Do(r)
+	injectTraceContext(ctx, r.Header)
+	decompress := c.setAcceptEncoding(r.Header)
+
+	resp, err := c.do(ctx, r, path)
+	if err == nil && decompress {
+		decompressResponse(resp)
+	}
+	releaseOnClose(resp, cancel)
+	traceResponse(ctx, resp)
+	return resp, err
 }
 
 // Internal functions
+
+// isSafeMethod returns true if the HTTP method does not modify any state
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+// StatusError is returned when AMS answered a request with an error. Its
+// message is the error reported by AMS.
+type StatusError struct {
+	// StatusCode is the HTTP status code of the response
+	StatusCode int
+	Message    string
+}
+
+// Error returns the error reported by AMS
+func (e *StatusError) Error() string {
+	return e.Message
+}
+
 func (c *client) parseResponse(resp *http.Response) (*api.Response, string, error) {
 	// Get the ETag
 	etag := resp.Header.Get("ETag")
 
-	decoder := json.NewDecoder(resp.Body)
+	if resp.StatusCode == http.StatusPreconditionFailed {
+		what := "resource"
+		if resp.Request != nil {
+			what = resp.Request.URL.Path
+		}
+		return nil, "", errs.NewErrPreconditionFailed(what)
+	}
+
 	response := api.Response{}
-	err := decoder.Decode(&response)
+	data, err := io.ReadAll(resp.Body)
+	if err == nil {
+		err = c.Codec().Unmarshal(data, &response)
+	}
 
 	// Not all API calls return a proper api.Response, in those cases we just print the status text
 	if err != nil {
-		return nil, "", errors.New(http.StatusText(resp.StatusCode))
+		return nil, "", &StatusError{StatusCode: resp.StatusCode, Message: http.StatusText(resp.StatusCode)}
 	}
 
 	if response.Type == api.ResponseTypeError {
 		if response.Error != "" {
-			return nil, "", errors.New(response.Error)
+			return nil, "", &StatusError{StatusCode: resp.StatusCode, Message: response.Error}
 		}
-		return nil, "", errors.New(http.StatusText(resp.StatusCode))
+		return nil, "", &StatusError{StatusCode: resp.StatusCode, Message: http.StatusText(resp.StatusCode)}
 	}
 
 	return &response, etag, nil
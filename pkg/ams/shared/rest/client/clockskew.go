@@ -0,0 +1,154 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+)
+
+// ClockSkew describes the difference between the clock of the AMS service and
+// the local clock as measured from the Date header of its responses. As the
+// header has a resolution of one second, the skew is only accurate to about
+// half a second plus half the request latency.
+type ClockSkew struct {
+	// Skew is positive if the clock of the AMS service is ahead of the local one
+	Skew time.Duration
+	// MeasuredAt is the local time of the last measurement. Zero if no
+	// response with a Date header was received yet.
+	MeasuredAt time.Time
+	// Samples is the number of measurements taken
+	Samples uint64
+}
+
+// ClockSkewCheck configures how the client reacts when the clock of the AMS
+// service differs from the local one
+type ClockSkewCheck struct {
+	// Threshold is the absolute skew above which the check triggers
+	Threshold time.Duration
+	// Fail makes requests fail with a ClockSkewError while the skew exceeds
+	// the threshold. Otherwise only a warning is emitted.
+	Fail bool
+	// OnSkew is called once whenever the skew starts exceeding the threshold.
+	// If not set a warning is written to the standard logger.
+	OnSkew func(skew ClockSkew)
+}
+
+// ClockSkewError is returned for requests when the measured clock skew exceeds
+// the configured threshold and the check is configured to fail
+type ClockSkewError struct {
+	Skew      time.Duration
+	Threshold time.Duration
+}
+
+// Error returns the error string
+func (e *ClockSkewError) Error() string {
+	return fmt.Sprintf("clock of the AMS service differs by %s from the local clock (threshold %s)", e.Skew, e.Threshold)
+}
+
+type skewTracker struct {
+	lock     sync.Mutex
+	check    *ClockSkewCheck
+	skew     ClockSkew
+	exceeded bool
+}
+
+func newSkewTracker() *skewTracker {
+	return &skewTracker{}
+}
+
+// record measures the skew from the given response of a request which was
+// started at the given time and returns an error if the check is configured
+// to fail and the threshold is exceeded
+func (s *skewTracker) record(start time.Time, resp *http.Response) error {
+	if resp == nil {
+		return nil
+	}
+	date, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return nil
+	}
+
+	// The server generated the header somewhere between sending the request
+	// and receiving the response and truncated it to full seconds
+	end := time.Now()
+	local := start.Add(end.Sub(start) / 2)
+	skew := date.Add(500 * time.Millisecond).Sub(local)
+
+	s.lock.Lock()
+	s.skew.Skew = skew
+	s.skew.MeasuredAt = end
+	s.skew.Samples++
+	current := s.skew
+	check := s.check
+
+	if check == nil || check.Threshold <= 0 {
+		s.lock.Unlock()
+		return nil
+	}
+	exceeded := skew > check.Threshold || skew < -check.Threshold
+	notify := exceeded && !s.exceeded
+	s.exceeded = exceeded
+	s.lock.Unlock()
+
+	if notify {
+		if check.OnSkew != nil {
+			check.OnSkew(current)
+		} else if !check.Fail {
+			log.Printf("WARNING: clock of the AMS service differs by %s from the local clock", skew.Round(time.Millisecond))
+		}
+	}
+
+	if exceeded && check.Fail {
+		return &ClockSkewError{Skew: skew, Threshold: check.Threshold}
+	}
+	return nil
+}
+
+func (s *skewTracker) clockSkew() ClockSkew {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.skew
+}
+
+// ClockSkew returns the clock skew between the AMS service and the local
+// clock as measured through the responses of the requests the client sent
+func (c *client) ClockSkew() ClockSkew {
+	return c.skew.clockSkew()
+}
+
+// WithClockSkewCheck enables checking the clock skew between the AMS service
+// and the local clock with every response. A skew breaks token based
+// authentication and makes correlating logs of both sides difficult.
+func WithClockSkewCheck(check ClockSkewCheck) Option {
+	return func(c *client) error {
+		if check.Threshold <= 0 {
+			return errs.NewInvalidArgument("threshold")
+		}
+		c.skew.lock.Lock()
+		c.skew.check = &check
+		c.skew.lock.Unlock()
+		return nil
+	}
+}
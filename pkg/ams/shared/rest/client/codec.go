@@ -0,0 +1,59 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"encoding/json"
+)
+
+// Codec encodes request bodies and decodes the responses of the AMS API. It
+// allows plugging in a faster JSON implementation or canonicalizing
+// fields. Implementations have to produce and accept standard JSON and must
+// be safe for concurrent use.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+// CodecJSON is the codec based on encoding/json. This is the default.
+var CodecJSON Codec = jsonCodec{}
+
+// WithCodec sets the codec used to encode requests and decode responses
+func WithCodec(codec Codec) Option {
+	return func(c *client) error {
+		if codec == nil {
+			codec = CodecJSON
+		}
+		c.codec = codec
+		return nil
+	}
+}
+
+// Codec returns the codec used to encode requests and decode responses
+func (c *client) Codec() Codec {
+	if c.codec == nil {
+		return CodecJSON
+	}
+	return c.codec
+}
@@ -0,0 +1,118 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// acceptedEncodings is the value of the Accept-Encoding header sent when
+// response compression is enabled
+const acceptedEncodings = "gzip, deflate"
+
+// WithoutCompression disables response compression. By default the client
+// asks the server for gzip or deflate compressed responses and decompresses
+// them transparently.
+func WithoutCompression() Option {
+	return func(c *client) error {
+		c.disableCompression = true
+		return nil
+	}
+}
+
+// setAcceptEncoding announces the encodings the client decompresses and
+// returns whether the response has to be decompressed. Requests for which the
+// caller set the Accept-Encoding header itself are left untouched. Range
+// requests are never compressed as the offsets refer to the uncompressed
+// content.
+func (c *client) setAcceptEncoding(header http.Header) bool {
+	if len(header.Get("Accept-Encoding")) > 0 {
+		return false
+	}
+	if c.disableCompression || len(header.Get("Range")) > 0 {
+		// Prevents the transport from asking for gzip on its own
+		header.Set("Accept-Encoding", "identity")
+		return false
+	}
+	header.Set("Accept-Encoding", acceptedEncodings)
+	return true
+}
+
+// decompressResponse replaces the body of a compressed response with a
+// reader returning the decompressed content
+func decompressResponse(resp *http.Response) {
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	if encoding != "gzip" && encoding != "deflate" {
+		return
+	}
+
+	resp.Body = &decompressingReader{body: resp.Body, encoding: encoding}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+}
+
+// decompressingReader creates the decompressor on first read so responses
+// without a body, e.g. to HEAD requests, do not fail
+type decompressingReader struct {
+	body     io.ReadCloser
+	encoding string
+	r        io.Reader
+	err      error
+}
+
+func (d *decompressingReader) Read(p []byte) (int, error) {
+	if d.r == nil && d.err == nil {
+		d.r, d.err = d.decompressor()
+	}
+	if d.err != nil {
+		return 0, d.err
+	}
+	return d.r.Read(p)
+}
+
+func (d *decompressingReader) decompressor() (io.Reader, error) {
+	if d.encoding == "gzip" {
+		return gzip.NewReader(d.body)
+	}
+
+	// HTTP deflate is meant to be zlib wrapped, but some servers send raw
+	// deflate streams. A zlib stream always starts with the deflate
+	// compression method in the lower bits of its first byte.
+	br := bufio.NewReader(d.body)
+	header, err := br.Peek(2)
+	if err != nil {
+		return nil, err
+	}
+	if header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(br)
+	}
+	return flate.NewReader(br), nil
+}
+
+func (d *decompressingReader) Close() error {
+	return d.body.Close()
+}
@@ -0,0 +1,110 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"crypto/tls"
+	"sync"
+
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+)
+
+// ConnectionInfo describes the TLS parameters negotiated with the AMS service
+// for the most recent request
+type ConnectionInfo struct {
+	// ServerName is the name the certificate of the service was verified for
+	ServerName string
+	// Version of TLS, e.g. tls.VersionTLS13
+	Version uint16
+	// CipherSuite negotiated for the connection
+	CipherSuite uint16
+	// CurveID is the key exchange group negotiated for the connection. Zero if
+	// it is not known, which is the case on Go versions before 1.25.
+	CurveID tls.CurveID
+	// Hybrid reports whether CurveID is a hybrid post-quantum key exchange
+	Hybrid bool
+}
+
+type connectionInfoTracker struct {
+	lock sync.Mutex
+	info *ConnectionInfo
+}
+
+func (t *connectionInfoTracker) record(state *tls.ConnectionState) {
+	if state == nil {
+		return
+	}
+
+	curve := negotiatedCurve(state)
+	info := &ConnectionInfo{
+		ServerName:  state.ServerName,
+		Version:     state.Version,
+		CipherSuite: state.CipherSuite,
+		CurveID:     curve,
+		Hybrid:      isHybridCurve(curve),
+	}
+
+	t.lock.Lock()
+	t.info = info
+	t.lock.Unlock()
+}
+
+// ConnectionInfo returns the TLS parameters negotiated with the AMS service
+// for the most recent request. Returns nil if no request was sent over TLS yet.
+func (c *client) ConnectionInfo() *ConnectionInfo {
+	c.connInfo.lock.Lock()
+	defer c.connInfo.lock.Unlock()
+	if c.connInfo.info == nil {
+		return nil
+	}
+	info := *c.connInfo.info
+	return &info
+}
+
+// WithHybridKeyExchange prefers a hybrid key exchange combining X25519 with a
+// post-quantum KEM for TLS connections to the AMS service. As hybrid key
+// exchanges require TLS 1.3, older versions are disabled. Fails with
+// ErrNotSupported if the client was built with a Go version without support
+// for hybrid key exchanges.
+func WithHybridKeyExchange() Option {
+	return func(c *client) error {
+		if len(hybridCurves) == 0 {
+			return errs.NewErrNotSupported("hybrid key exchange with this Go version")
+		}
+
+		t := c.HTTPTransport()
+		if t.TLSClientConfig == nil {
+			return errs.NewErrNotSupported("hybrid key exchange on connections without TLS")
+		}
+
+		t.TLSClientConfig.MinVersion = tls.VersionTLS13
+		t.TLSClientConfig.CurvePreferences = append(append([]tls.CurveID{}, hybridCurves...),
+			tls.X25519, tls.CurveP256, tls.CurveP384)
+		return nil
+	}
+}
+
+func isHybridCurve(curve tls.CurveID) bool {
+	for _, h := range hybridCurves {
+		if h == curve {
+			return true
+		}
+	}
+	return false
+}
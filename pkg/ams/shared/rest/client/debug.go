@@ -0,0 +1,288 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultDebugMaxBodySize is the default number of bytes logged per body
+	DefaultDebugMaxBodySize = 4 * 1024
+
+	redactedValue = "[REDACTED]"
+)
+
+// sensitiveNames lists the parts of header, query parameter and JSON field
+// names whose values are redacted in debug logs. Names are compared in lower
+// case with dashes and underscores removed.
+var sensitiveNames = []string{
+	"authorization",
+	"certificate",
+	"cookie",
+	"password",
+	"privatekey",
+	"secret",
+	"token",
+	"userdata",
+}
+
+// DebugOptions configures the logging of requests and responses
+type DebugOptions struct {
+	// Logf is called with every log entry. If not set the standard logger
+	// is used.
+	Logf func(format string, v ...interface{})
+	// Headers enables logging of the request and response headers
+	Headers bool
+	// Bodies enables logging of the request and response bodies. Only JSON
+	// bodies are logged, other content is summarized by its type and size.
+	Bodies bool
+	// MaxBodySize limits the number of bytes logged per body. Defaults to
+	// DefaultDebugMaxBodySize.
+	MaxBodySize int
+}
+
+// WithDebug logs the method, path, status and duration of every request
+// through the given options. Certificates, tokens, passwords and userdata are
+// redacted from the logged headers and bodies. Logging bodies disables
+// response compression so responses can be logged as received.
+func WithDebug(opts DebugOptions) Option {
+	return func(c *client) error {
+		if opts.Bodies {
+			c.disableCompression = true
+		}
+		c.Use(DebugMiddleware(opts))
+		return nil
+	}
+}
+
+// DebugMiddleware returns a middleware which logs requests and responses as
+// described by WithDebug
+func DebugMiddleware(opts DebugOptions) Middleware {
+	logf := opts.Logf
+	if logf == nil {
+		logf = log.Printf
+	}
+	maxBodySize := opts.MaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = DefaultDebugMaxBodySize
+	}
+
+	return func(next Doer) Doer {
+		return DoerFunc(func(r *http.Request) (*http.Response, error) {
+			target := redactedTarget(r.URL)
+			logf("--> %s %s", r.Method, target)
+			if opts.Headers {
+				logf("--> %s", formatHeader(r.Header))
+			}
+			if opts.Bodies && r.Body != nil && r.Body != http.NoBody {
+				logf("--> %s", requestBody(r, maxBodySize))
+			}
+
+			start := time.Now()
+			resp, err := next.Do(r)
+			elapsed := time.Since(start).Round(time.Millisecond)
+			if err != nil {
+				logf("<-- %s %s failed after %s: %v", r.Method, target, elapsed, err)
+				return resp, err
+			}
+
+			logf("<-- %s %s %s (%s)", resp.Status, r.Method, target, elapsed)
+			if opts.Headers {
+				logf("<-- %s", formatHeader(resp.Header))
+			}
+			if opts.Bodies && resp.Body != nil {
+				logf("<-- %s", responseBody(resp, maxBodySize))
+			}
+			return resp, nil
+		})
+	}
+}
+
+// isSensitive returns true if values of the given name must not be logged
+func isSensitive(name string) bool {
+	name = strings.ToLower(name)
+	name = strings.NewReplacer("-", "", "_", "").Replace(name)
+	for _, s := range sensitiveNames {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactedTarget returns the path and query of the given URL with the values
+// of sensitive query parameters redacted
+func redactedTarget(u *url.URL) string {
+	query := u.Query()
+	if len(query) == 0 {
+		return u.Path
+	}
+	for k := range query {
+		if isSensitive(k) {
+			query[k] = []string{redactedValue}
+		}
+	}
+	return fmt.Sprintf("%s?%s", u.Path, query.Encode())
+}
+
+// formatHeader returns the given header as a single line with the values of
+// sensitive headers redacted
+func formatHeader(header http.Header) string {
+	names := make([]string, 0, len(header))
+	for k := range header {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, k := range names {
+		value := strings.Join(header[k], ", ")
+		if isSensitive(k) {
+			value = redactedValue
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s", k, value))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// requestBody returns the loggable representation of the request body. Only
+// bodies which can be read again without consuming the original (see
+// http.Request.GetBody) are logged so uploads are never buffered.
+func requestBody(r *http.Request, maxBodySize int) string {
+	if r.GetBody == nil {
+		return describeBody(r.Header, r.ContentLength)
+	}
+	body, err := r.GetBody()
+	if err != nil {
+		return describeBody(r.Header, r.ContentLength)
+	}
+	defer body.Close()
+
+	data, truncated, err := readPrefix(body, maxBodySize)
+	if err != nil {
+		return describeBody(r.Header, r.ContentLength)
+	}
+	return formatBody(r.Header, data, truncated, r.ContentLength)
+}
+
+// responseBody returns the loggable representation of the response body and
+// leaves the body unchanged for the caller
+func responseBody(resp *http.Response, maxBodySize int) string {
+	if encoding := resp.Header.Get("Content-Encoding"); len(encoding) > 0 && encoding != "identity" {
+		return describeBody(resp.Header, resp.ContentLength)
+	}
+	if !isJSON(resp.Header) {
+		return describeBody(resp.Header, resp.ContentLength)
+	}
+
+	data, truncated, err := readPrefix(resp.Body, maxBodySize)
+	resp.Body = &prefixedBody{
+		Reader: io.MultiReader(bytes.NewReader(data), resp.Body),
+		Closer: resp.Body,
+	}
+	if err != nil {
+		return describeBody(resp.Header, resp.ContentLength)
+	}
+	return formatBody(resp.Header, data, truncated, resp.ContentLength)
+}
+
+// prefixedBody restores the part of a body which was already read for logging
+type prefixedBody struct {
+	io.Reader
+	io.Closer
+}
+
+// readPrefix reads up to max bytes and reports whether more data followed
+func readPrefix(r io.Reader, max int) ([]byte, bool, error) {
+	data, err := io.ReadAll(io.LimitReader(r, int64(max)+1))
+	if err != nil {
+		return data, false, err
+	}
+	if len(data) > max {
+		return data, true, nil
+	}
+	return data, false, nil
+}
+
+func isJSON(header http.Header) bool {
+	contentType := header.Get("Content-Type")
+	return len(contentType) == 0 || strings.Contains(contentType, "json")
+}
+
+func describeBody(header http.Header, size int64) string {
+	contentType := header.Get("Content-Type")
+	if len(contentType) == 0 {
+		contentType = "unknown content"
+	}
+	if size < 0 {
+		return fmt.Sprintf("<body of %s not logged>", contentType)
+	}
+	return fmt.Sprintf("<body of %d bytes of %s not logged>", size, contentType)
+}
+
+// formatBody returns the given JSON body with all sensitive fields redacted.
+// Truncated bodies cannot be parsed and so are not logged.
+func formatBody(header http.Header, data []byte, truncated bool, size int64) string {
+	if len(data) == 0 {
+		return "<empty body>"
+	}
+	if !isJSON(header) || truncated {
+		return describeBody(header, size)
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return describeBody(header, size)
+	}
+	redacted, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return describeBody(header, size)
+	}
+	return string(redacted)
+}
+
+// redactValue replaces the values of all sensitive fields in the given
+// decoded JSON value
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, field := range v {
+			if isSensitive(k) {
+				v[k] = redactedValue
+				continue
+			}
+			v[k] = redactValue(field)
+		}
+	case []interface{}:
+		for n := range v {
+			v[n] = redactValue(v[n])
+		}
+	}
+	return value
+}
@@ -0,0 +1,256 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/api"
+)
+
+const (
+	// DryRunHeader marks a single request as dry run. The header is removed
+	// before the request would be sent, so it never reaches AMS.
+	DryRunHeader = "X-AMS-Dry-Run"
+
+	dryRunOperationPrefix = "dry-run-"
+)
+
+// DryRunRequest describes a mutating request which was not sent because of
+// dry-run mode
+type DryRunRequest struct {
+	Method string
+	Path   string
+	Query  url.Values
+	// Header holds the request headers with sensitive values redacted
+	Header http.Header
+	// Body is the request body with sensitive fields redacted. Non JSON
+	// bodies are summarized by their type and size.
+	Body string
+}
+
+// DryRunOptions configures the dry-run mode of a client
+type DryRunOptions struct {
+	// Logf is called for every request which is not sent. If not set the
+	// standard logger is used.
+	Logf func(format string, v ...interface{})
+	// OnRequest is called for every request which is not sent, e.g. to
+	// collect the change plan of a CI pipeline. Optional.
+	OnRequest func(r DryRunRequest)
+	// MaxBodySize limits the number of bytes of a body which are validated
+	// and logged. Defaults to DefaultDebugMaxBodySize.
+	MaxBodySize int
+}
+
+var dryRunOperations int64
+
+// WithDryRun makes the client not send any mutating request. AMS has no
+// server side dry run, so the requests are validated locally instead: the
+// client performs its usual argument checks and JSON bodies have to be well
+// formed. Each request is then logged. Requests expecting an operation are
+// answered with one which already succeeded, so callers waiting for it
+// continue as usual, all others with an empty synchronous response. Requests
+// relying on the result of the operation, e.g. to attach to the websockets of
+// an exec session, cannot be dry run.
+//
+// Single requests can be dry run by setting the DryRunHeader on them.
+func WithDryRun(opts DryRunOptions) Option {
+	return func(c *client) error {
+		c.dryRun = &opts
+		return nil
+	}
+}
+
+// dryRunResponse returns a response for the given request if it must not be
+// sent
+func (c *client) dryRunResponse(r *http.Request) (*http.Response, bool, error) {
+	perRequest := len(r.Header.Get(DryRunHeader)) > 0
+	r.Header.Del(DryRunHeader)
+
+	if id, ok := dryRunOperationID(r.URL.Path); ok {
+		// Operations created in dry-run mode only exist on the client side
+		resp, err := dryRunReply(r, api.ResponseTypeSync, dryRunOperation(id, r))
+		return resp, true, err
+	}
+	if isSafeMethod(r.Method) || (c.dryRun == nil && !perRequest) {
+		return nil, false, nil
+	}
+
+	opts := DryRunOptions{}
+	if c.dryRun != nil {
+		opts = *c.dryRun
+	}
+	logf := opts.Logf
+	if logf == nil {
+		logf = log.Printf
+	}
+	maxBodySize := opts.MaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = DefaultDebugMaxBodySize
+	}
+
+	body := "<empty body>"
+	if r.Body != nil && r.Body != http.NoBody {
+		data, truncated, err := readPrefix(r.Body, maxBodySize)
+		size := r.ContentLength
+		if err == nil && truncated {
+			var rest int64
+			rest, err = io.Copy(io.Discard, r.Body)
+			size = int64(len(data)) + rest
+		}
+		r.Body.Close()
+		if err != nil {
+			return nil, true, err
+		}
+		if isJSON(r.Header) && !truncated && len(data) > 0 && !json.Valid(data) {
+			return nil, true, errs.NewErrInvalidFormat("request body")
+		}
+		body = formatBody(r.Header, data, truncated, size)
+	}
+
+	logf("dry run: %s %s %s", r.Method, redactedTarget(r.URL), body)
+	if opts.OnRequest != nil {
+		header := http.Header{}
+		for name, values := range r.Header {
+			if isSensitive(name) {
+				header.Set(name, redactedValue)
+				continue
+			}
+			header[name] = append([]string{}, values...)
+		}
+		query := url.Values{}
+		for name, values := range r.URL.Query() {
+			if isSensitive(name) {
+				query.Set(name, redactedValue)
+				continue
+			}
+			query[name] = values
+		}
+		opts.OnRequest(DryRunRequest{
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Query:  query,
+			Header: header,
+			Body:   body,
+		})
+	}
+
+	if !expectsOperation(r.Context()) {
+		resp, err := dryRunReply(r, api.ResponseTypeSync, nil)
+		return resp, true, err
+	}
+	id := fmt.Sprintf("%s%d", dryRunOperationPrefix, atomic.AddInt64(&dryRunOperations, 1))
+	resp, err := dryRunReply(r, api.ResponseTypeAsync, dryRunOperation(id, r))
+	return resp, true, err
+}
+
+// expectOperationKey marks the context of requests answered with an operation
+type expectOperationKey struct{}
+
+// withExpectedOperation marks requests made with the returned context as
+// answered with an operation
+func withExpectedOperation(ctx context.Context) context.Context {
+	return context.WithValue(ctx, expectOperationKey{}, true)
+}
+
+func expectsOperation(ctx context.Context) bool {
+	expected, _ := ctx.Value(expectOperationKey{}).(bool)
+	return expected
+}
+
+// isDryRun returns true if a request with the given method and headers is not
+// sent
+func (c *client) isDryRun(method string, header http.Header) bool {
+	return !isSafeMethod(method) && (c.dryRun != nil || len(header.Get(DryRunHeader)) > 0)
+}
+
+// dryRunOperationID returns the ID of the dry-run operation the given path
+// refers to
+func dryRunOperationID(path string) (string, bool) {
+	prefix := APIPath("operations") + "/"
+	if !strings.HasPrefix(path, prefix) {
+		return "", false
+	}
+	id := strings.SplitN(strings.TrimPrefix(path, prefix), "/", 2)[0]
+	return id, strings.HasPrefix(id, dryRunOperationPrefix)
+}
+
+func dryRunOperation(id string, r *http.Request) api.Operation {
+	now := time.Now()
+	return api.Operation{
+		ID:          id,
+		Class:       "task",
+		Description: fmt.Sprintf("Dry run of %s %s", r.Method, r.URL.Path),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		Status:      api.Success.String(),
+		StatusCode:  api.Success,
+		Resources:   map[string][]string{},
+		Metadata:    map[string]interface{}{"dry_run": true},
+	}
+}
+
+// dryRunReply returns a response of the given type with the given metadata.
+// For async responses the metadata has to be the operation.
+func dryRunReply(r *http.Request, responseType api.ResponseType, metadata interface{}) (*http.Response, error) {
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+	response := api.Response{
+		Type:       responseType,
+		Status:     api.Success.String(),
+		StatusCode: int(api.Success),
+		Metadata:   data,
+	}
+	status := http.StatusOK
+	if op, ok := metadata.(api.Operation); ok && responseType == api.ResponseTypeAsync {
+		response.Status = api.OperationCreated.String()
+		response.StatusCode = int(api.OperationCreated)
+		response.Operation = APIPath("operations", op.ID)
+		status = http.StatusAccepted
+	}
+
+	data, err = json.Marshal(response)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(data)),
+		ContentLength: int64(len(data)),
+		Request:       r,
+	}, nil
+}
@@ -0,0 +1,192 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+)
+
+const (
+	defaultEndpointCooldown = 30 * time.Second
+)
+
+type endpoint struct {
+	url            *url.URL
+	unhealthyUntil time.Time
+}
+
+// endpointPool tracks the health of the endpoints of a multi-endpoint client.
+// Requests stick to the endpoint which last worked.
+type endpointPool struct {
+	cooldown time.Duration
+
+	lock      sync.Mutex
+	endpoints []*endpoint
+	current   int
+}
+
+// WithFailoverEndpoints adds further endpoints of the same AMS cluster to the
+// endpoint the client was created with. When an endpoint cannot be connected
+// to, the request is transparently sent to the next endpoint. Idempotent
+// requests are also sent to the next endpoint on any other transport error or
+// a server error response; other requests may already have been processed
+// and are not repeated. Failing endpoints are skipped for the given cooldown,
+// which defaults to 30 seconds. Requests whose body cannot be replayed are not
+// failed over once sent.
+func WithFailoverEndpoints(cooldown time.Duration, endpoints ...string) Option {
+	return func(c *client) error {
+		if c.HTTPTransport().Dial != nil {
+			return errs.NewErrNotSupported("failover endpoints on unix socket connections")
+		}
+		if len(endpoints) == 0 {
+			return errs.NewInvalidArgument("endpoints")
+		}
+		if cooldown <= 0 {
+			cooldown = defaultEndpointCooldown
+		}
+
+		pool := &endpointPool{
+			cooldown:  cooldown,
+			endpoints: []*endpoint{{url: c.serviceURL}},
+		}
+		for _, e := range endpoints {
+			u, err := url.Parse(e)
+			if err != nil {
+				return err
+			}
+			if u.Scheme != c.serviceURL.Scheme || len(u.Host) == 0 {
+				return errs.NewInvalidArgument(fmt.Sprintf("endpoint %s", e))
+			}
+			pool.endpoints = append(pool.endpoints, &endpoint{url: u})
+		}
+		c.endpoints = pool
+		return nil
+	}
+}
+
+// get returns the endpoint to use for the next request. If all endpoints are
+// unhealthy the one which recovers first is returned.
+func (p *endpointPool) get() *url.URL {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	now := time.Now()
+	for n := 0; n < len(p.endpoints); n++ {
+		idx := (p.current + n) % len(p.endpoints)
+		if !now.Before(p.endpoints[idx].unhealthyUntil) {
+			p.current = idx
+			return p.endpoints[idx].url
+		}
+	}
+
+	next := p.current
+	for idx, e := range p.endpoints {
+		if e.unhealthyUntil.Before(p.endpoints[next].unhealthyUntil) {
+			next = idx
+		}
+	}
+	p.current = next
+	return p.endpoints[next].url
+}
+
+// markUnhealthy excludes the endpoint with the given host for the cooldown
+func (p *endpointPool) markUnhealthy(host string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for _, e := range p.endpoints {
+		if e.url.Host == host {
+			e.unhealthyUntil = time.Now().Add(p.cooldown)
+		}
+	}
+}
+
+func (p *endpointPool) size() int {
+	return len(p.endpoints)
+}
+
+// prepare points the request to the current endpoint
+func (p *endpointPool) prepare(r *http.Request) {
+	u := p.get()
+	r.URL.Scheme = u.Scheme
+	r.URL.Host = u.Host
+	r.Host = u.Host
+}
+
+// shouldFailover returns true if the request has to be sent to another endpoint
+func (p *endpointPool) shouldFailover(r *http.Request, resp *http.Response, err error) bool {
+	if r.Body != nil && r.Body != http.NoBody && r.GetBody == nil {
+		return false
+	}
+	if err != nil {
+		if r.Context().Err() != nil {
+			return false
+		}
+		// A request which may already have been processed must not be sent
+		// a second time unless it can safely be repeated
+		return isIdempotentMethod(r.Method) || isDialError(err)
+	}
+	return resp.StatusCode >= http.StatusInternalServerError && isIdempotentMethod(r.Method)
+}
+
+// isDialError returns true if the error occurred while establishing the
+// connection, so the request never reached the server
+func isDialError(err error) bool {
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// websocketURL replaces the host of the websocket URL with the current endpoint
+func (p *endpointPool) websocketURL(wsURL string) string {
+	u, err := url.Parse(wsURL)
+	if err != nil {
+		return wsURL
+	}
+	u.Host = p.get().Host
+	return u.String()
+}
+
+func websocketHost(wsURL string) string {
+	u, err := url.Parse(wsURL)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
+
+func isIdempotentMethod(method string) bool {
+	switch strings.ToUpper(method) {
+	case http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return isSafeMethod(method)
+	}
+}
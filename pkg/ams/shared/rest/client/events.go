@@ -19,9 +19,10 @@
 package client
 
 import (
-	"encoding/json"
+	"context"
 
 	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared"
+	"github.com/gorilla/websocket"
 )
 
 // Event handling functions
@@ -45,8 +46,7 @@ func (c *client) GetEvents() (*EventListener, error) {
 	}
 
 	// Setup a new connection with the server
-	resource := APIPath("events")
-	conn, err := c.dialWebsocket(c.composeWebsocketPath(resource))
+	conn, err := c.dialEvents()
 	if err != nil {
 		return nil, err
 	}
@@ -91,7 +91,7 @@ func (c *client) GetEvents() (*EventListener, error) {
 
 			// Attempt to unpack the message
 			message := make(map[string]interface{})
-			err = json.Unmarshal(data, &message)
+			err = c.Codec().Unmarshal(data, &message)
 			if err != nil {
 				continue
 			}
@@ -124,3 +124,19 @@ func (c *client) GetEvents() (*EventListener, error) {
 
 	return &listener, nil
 }
+
+// eventConn is the connection the events are read from
+type eventConn interface {
+	ReadMessage() (int, []byte, error)
+	Close() error
+}
+
+func (c *client) dialEvents() (eventConn, error) {
+	url := c.composeWebsocketPath(APIPath("events"))
+	if c.eventReconnect == nil {
+		return c.dialWebsocket(context.Background(), url)
+	}
+	return NewReconnectingWebsocket(context.Background(), func(ctx context.Context) (*websocket.Conn, error) {
+		return c.dialWebsocket(ctx, url)
+	}, *c.eventReconnect)
+}
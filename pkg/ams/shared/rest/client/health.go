@@ -0,0 +1,189 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+)
+
+const (
+	// DefaultHealthHalfLife is the default time after which the weight of an
+	// observed request on the health score has decayed by half
+	DefaultHealthHalfLife = 1 * time.Minute
+
+	// The long term latency average decays this many times slower than the
+	// short term one and serves as the baseline for the latency trend
+	healthBaselineFactor = 10
+)
+
+// Health describes the health of the AMS endpoint as observed by the client
+type Health struct {
+	// Score summarizes the health of the endpoint between 0 (unhealthy) and
+	// 1 (healthy). It is based on the success rate and the latency trend.
+	Score float64
+	// SuccessRate is the exponentially decayed rate of successful requests
+	// between 0 and 1
+	SuccessRate float64
+	// Latency is the exponentially decayed average latency of recent requests
+	Latency time.Duration
+	// LatencyTrend is the ratio between the recent and the long term latency.
+	// Values above 1 indicate that requests are getting slower.
+	LatencyTrend float64
+	// Requests is the total number of requests observed
+	Requests uint64
+	// Failures is the total number of failed requests observed
+	Failures uint64
+	// LastSuccess is the time of the last successful request
+	LastSuccess time.Time
+	// LastFailure is the time of the last failed request
+	LastFailure time.Time
+	// LastError is the error of the last failed request
+	LastError error
+}
+
+type healthTracker struct {
+	lock     sync.Mutex
+	halfLife time.Duration
+
+	lastSample      time.Time
+	successRate     float64
+	latency         float64
+	latencyBaseline float64
+
+	requests    uint64
+	failures    uint64
+	lastSuccess time.Time
+	lastFailure time.Time
+	lastError   error
+}
+
+func newHealthTracker(halfLife time.Duration) *healthTracker {
+	return &healthTracker{
+		halfLife:    halfLife,
+		successRate: 1,
+	}
+}
+
+// decay returns the weight a new sample gets when the previous one was seen
+// the given duration ago and values decay with the given half life
+func decay(elapsed, halfLife time.Duration) float64 {
+	if halfLife <= 0 {
+		return 1
+	}
+	return 1 - math.Exp2(-float64(elapsed)/float64(halfLife))
+}
+
+func (h *healthTracker) record(start time.Time, resp *http.Response, err error) {
+	now := time.Now()
+	latency := float64(now.Sub(start))
+
+	failed := err != nil
+	if !failed && resp != nil && resp.StatusCode >= http.StatusInternalServerError {
+		failed = true
+		err = errors.New(http.StatusText(resp.StatusCode))
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.requests++
+	if h.requests == 1 {
+		h.latency = latency
+		h.latencyBaseline = latency
+		if failed {
+			h.successRate = 0
+		}
+	} else {
+		elapsed := now.Sub(h.lastSample)
+		// A minimal weight ensures bursts of requests still move the averages
+		w := math.Max(decay(elapsed, h.halfLife), 0.05)
+		wb := math.Max(decay(elapsed, h.halfLife*healthBaselineFactor), 0.005)
+
+		success := 1.0
+		if failed {
+			success = 0
+		}
+		h.successRate += w * (success - h.successRate)
+		h.latency += w * (latency - h.latency)
+		h.latencyBaseline += wb * (latency - h.latencyBaseline)
+	}
+	h.lastSample = now
+
+	if failed {
+		h.failures++
+		h.lastFailure = now
+		h.lastError = err
+	} else {
+		h.lastSuccess = now
+	}
+}
+
+func (h *healthTracker) health() Health {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	trend := 1.0
+	if h.latencyBaseline > 0 {
+		trend = h.latency / h.latencyBaseline
+	}
+
+	// Getting slower reduces the score, getting faster does not improve it
+	// beyond the success rate
+	score := h.successRate
+	if trend > 1 {
+		score /= trend
+	}
+
+	return Health{
+		Score:        score,
+		SuccessRate:  h.successRate,
+		Latency:      time.Duration(h.latency),
+		LatencyTrend: trend,
+		Requests:     h.requests,
+		Failures:     h.failures,
+		LastSuccess:  h.lastSuccess,
+		LastFailure:  h.lastFailure,
+		LastError:    h.lastError,
+	}
+}
+
+// Health returns the current health of the AMS endpoint as observed by the
+// client through the requests it sent
+func (c *client) Health() Health {
+	return c.health.health()
+}
+
+// WithHealthHalfLife sets the time after which the weight of an observed
+// request on the health score has decayed by half. Smaller values make the
+// score react faster to changes.
+func WithHealthHalfLife(halfLife time.Duration) Option {
+	return func(c *client) error {
+		if halfLife <= 0 {
+			return errs.NewInvalidArgument("halfLife")
+		}
+		c.health = newHealthTracker(halfLife)
+		return nil
+	}
+}
@@ -0,0 +1,84 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// IdempotencyKeyHeader is the header carrying the idempotency key of a request
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+var attrIdempotencyKey = attribute.Key("ams.idempotency_key")
+
+// WithIdempotencyKeys makes the client send an idempotency key with every
+// POST, PATCH and DELETE request. Unless the caller already set the
+// IdempotencyKeyHeader for a call, a random UUID is generated for it. The key
+// stays the same when the request is retried or sent to another endpoint, so
+// the server can detect duplicates of a request it already processed.
+func WithIdempotencyKeys() Option {
+	return func(c *client) error {
+		c.idempotencyKeys = true
+		return nil
+	}
+}
+
+// IdempotencyKey returns a header with the given idempotency key set. It can
+// be passed to a call to reuse the key of a previous attempt.
+func IdempotencyKey(key string) http.Header {
+	header := http.Header{}
+	header.Set(IdempotencyKeyHeader, key)
+	return header
+}
+
+// NewIdempotencyKey returns a new random (version 4) UUID
+func NewIdempotencyKey() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// withIdempotencyKey returns the given header with an idempotency key added if
+// the client sends idempotency keys for the method and none was set yet. The
+// passed header is not modified.
+func (c *client) withIdempotencyKey(method string, header http.Header) (http.Header, error) {
+	if !c.idempotencyKeys || isSafeMethod(method) || method == http.MethodPut || len(header.Get(IdempotencyKeyHeader)) > 0 {
+		return header, nil
+	}
+
+	key, err := NewIdempotencyKey()
+	if err != nil {
+		return nil, err
+	}
+
+	h := header.Clone()
+	if h == nil {
+		h = http.Header{}
+	}
+	h.Set(IdempotencyKeyHeader, key)
+	return h, nil
+}
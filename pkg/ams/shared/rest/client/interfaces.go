@@ -63,13 +63,26 @@ type Client interface {
 	HTTPTransport() *http.Transport
 
 	SetTransportTimeout(timeout time.Duration)
+	TransportTimeout() time.Duration
 
 	QueryStruct(method, path string, params QueryParams, header http.Header, body io.Reader, ETag string, target interface{}) (etag string, err error)
+	QueryStructWithContext(ctx context.Context, method, path string, params QueryParams, header http.Header, body io.Reader, ETag string, target interface{}) (etag string, err error)
 	QueryOperation(method, path string, params QueryParams, header http.Header, body io.Reader, ETag string) (operation Operation, etag string, err error)
 	CallAPI(method, path string, params QueryParams, header http.Header, body io.Reader, ETag string) (response *api.Response, etag string, err error)
 	DownloadFile(path string, params QueryParams, header http.Header, downloader func(header *http.Header, body io.ReadCloser) error) error
+	Raw(ctx context.Context, method, path string, params QueryParams, body io.Reader) (resp *http.Response, err error)
 
 	Websocket(resource string) (conn *websocket.Conn, err error)
+	WebsocketWithContext(ctx context.Context, resource string) (conn *websocket.Conn, err error)
+	OpenStreams() []StreamInfo
+	CloseIdleStreams(olderThan time.Duration) int
+
+	Health() Health
+	ClockSkew() ClockSkew
+	ConnectionInfo() *ConnectionInfo
+
+	Use(middlewares ...Middleware)
+	Codec() Codec
 
 	// Event handling functions
 	GetEvents() (listener *EventListener, err error)
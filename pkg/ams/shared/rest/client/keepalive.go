@@ -0,0 +1,111 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"net"
+	"time"
+
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+	"golang.org/x/net/http2"
+)
+
+// KeepAlive describes how TCP keepalive probes are sent on idle connections to
+// the AMS service. Deployments behind NATs which drop idle connections early
+// should use values below the NAT timeout so that dead connections are
+// detected instead of requests hanging until the kernel gives up.
+type KeepAlive struct {
+	// Idle is the time a connection has to be idle before the first probe is
+	// sent
+	Idle time.Duration
+	// Interval is the time between two probes. Defaults to Idle.
+	Interval time.Duration
+	// Count is the number of unanswered probes after which the connection is
+	// considered dead. Defaults to the value of the operating system.
+	Count int
+}
+
+// WithKeepAlive configures TCP keepalive for all connections the client opens,
+// including websockets. Interval and Count are only supported on Linux.
+func WithKeepAlive(keepAlive KeepAlive) Option {
+	return func(c *client) error {
+		if keepAlive.Idle <= 0 {
+			return errs.NewInvalidArgument("idle")
+		}
+		if keepAlive.Interval < 0 {
+			return errs.NewInvalidArgument("interval")
+		}
+		if keepAlive.Count < 0 {
+			return errs.NewInvalidArgument("count")
+		}
+
+		t := c.HTTPTransport()
+		if t.Dial != nil {
+			return errs.NewErrNotSupported("keepalive on unix socket connections")
+		}
+
+		dialer, err := newKeepAliveDialer(keepAlive)
+		if err != nil {
+			return err
+		}
+		c.dialer = dialer
+		t.DialContext = c.dialContext
+		return nil
+	}
+}
+
+// WithHTTP2Ping enables HTTP/2 for TLS connections to the AMS service and sends
+// a ping frame when no frame was received on a connection for the given
+// interval. If the ping is not answered within the given timeout the
+// connection is closed and pending requests fail instead of hanging.
+func WithHTTP2Ping(interval, timeout time.Duration) Option {
+	return func(c *client) error {
+		if interval <= 0 {
+			return errs.NewInvalidArgument("interval")
+		}
+		if timeout < 0 {
+			return errs.NewInvalidArgument("timeout")
+		}
+
+		t := c.HTTPTransport()
+		if t.Dial != nil || c.serviceURL.Scheme != "https" {
+			return errs.NewErrNotSupported("HTTP/2 on connections without TLS")
+		}
+
+		if c.http2 == nil {
+			t2, err := http2.ConfigureTransports(t)
+			if err != nil {
+				return err
+			}
+			c.http2 = t2
+		}
+		c.http2.ReadIdleTimeout = interval
+		c.http2.PingTimeout = timeout
+		return nil
+	}
+}
+
+// netDialer returns the dialer used for connections which are not
+// established by the HTTP transport
+func (c *client) netDialer() *net.Dialer {
+	if c.dialer != nil {
+		return c.dialer
+	}
+	return &net.Dialer{}
+}
@@ -0,0 +1,69 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"net"
+	"syscall"
+	"time"
+)
+
+// newKeepAliveDialer returns a dialer which configures the keepalive options
+// on the socket directly as the standard library only supports setting a
+// single period for both idle time and interval
+func newKeepAliveDialer(keepAlive KeepAlive) (*net.Dialer, error) {
+	interval := keepAlive.Interval
+	if interval == 0 {
+		interval = keepAlive.Idle
+	}
+
+	return &net.Dialer{
+		// Disable the keepalive handling of the standard library as it would
+		// overwrite the interval configured below
+		KeepAlive: -1,
+		Control: func(network, address string, rc syscall.RawConn) error {
+			var sockErr error
+			err := rc.Control(func(fd uintptr) {
+				opts := [][3]int{
+					{syscall.SOL_SOCKET, syscall.SO_KEEPALIVE, 1},
+					{syscall.IPPROTO_TCP, syscall.TCP_KEEPIDLE, seconds(keepAlive.Idle)},
+					{syscall.IPPROTO_TCP, syscall.TCP_KEEPINTVL, seconds(interval)},
+				}
+				if keepAlive.Count > 0 {
+					opts = append(opts, [3]int{syscall.IPPROTO_TCP, syscall.TCP_KEEPCNT, keepAlive.Count})
+				}
+				for _, opt := range opts {
+					if sockErr = syscall.SetsockoptInt(int(fd), opt[0], opt[1], opt[2]); sockErr != nil {
+						return
+					}
+				}
+			})
+			if err != nil {
+				return err
+			}
+			return sockErr
+		},
+	}, nil
+}
+
+// seconds rounds the given duration up to full seconds as that is the
+// resolution of the socket options
+func seconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
@@ -0,0 +1,36 @@
+//go:build !linux
+
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"net"
+
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+)
+
+// newKeepAliveDialer returns a dialer with the given keepalive options. Only
+// the idle time can be configured on this platform.
+func newKeepAliveDialer(keepAlive KeepAlive) (*net.Dialer, error) {
+	if (keepAlive.Interval > 0 && keepAlive.Interval != keepAlive.Idle) || keepAlive.Count > 0 {
+		return nil, errs.NewErrNotSupported("keepalive interval and count on this platform")
+	}
+	return &net.Dialer{KeepAlive: keepAlive.Idle}, nil
+}
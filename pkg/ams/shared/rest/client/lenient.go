@@ -0,0 +1,109 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// DecodeError describes a single element of a list response which could not
+// be decoded
+type DecodeError struct {
+	// Index of the element within the list returned by AMS
+	Index int
+	// Raw is the JSON encoded element
+	Raw json.RawMessage
+	// Err is the error decoding the element failed with
+	Err error
+}
+
+// DecodeReport lists the elements which were skipped when decoding a list
+// response in lenient mode
+type DecodeReport struct {
+	Method string
+	Path   string
+	Errors []DecodeError
+}
+
+// String returns a summary of the report
+func (r *DecodeReport) String() string {
+	return fmt.Sprintf("%s %s: skipped %d undecodable elements", r.Method, r.Path, len(r.Errors))
+}
+
+// WithLenientDecoding makes the client skip elements of list responses which
+// cannot be decoded instead of failing the whole request. The valid elements
+// are returned and the given function is called with a report of the skipped
+// elements. Responses which are no lists or cannot be split into their
+// elements still fail as before.
+func WithLenientDecoding(onReport func(report *DecodeReport)) Option {
+	return func(c *client) error {
+		if onReport == nil {
+			return fmt.Errorf("no report function given")
+		}
+		c.onDecodeReport = onReport
+		return nil
+	}
+}
+
+// decodeMetadata decodes the metadata of a response into the target. In
+// lenient mode undecodable elements of a list are skipped and reported.
+func (c *client) decodeMetadata(method, path string, metadata json.RawMessage, target interface{}) error {
+	err := c.Codec().Unmarshal(metadata, target)
+	if err == nil || c.onDecodeReport == nil {
+		return err
+	}
+
+	v := reflect.ValueOf(target)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return err
+		}
+		if v.Kind() == reflect.Ptr && v.Elem().Kind() == reflect.Slice {
+			break
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Ptr {
+		return err
+	}
+
+	elements := []json.RawMessage{}
+	if c.Codec().Unmarshal(metadata, &elements) != nil {
+		return err
+	}
+
+	slice := v.Elem()
+	elemType := slice.Type().Elem()
+	result := reflect.MakeSlice(slice.Type(), 0, len(elements))
+	report := &DecodeReport{Method: method, Path: path}
+	for n, raw := range elements {
+		elem := reflect.New(elemType)
+		if err := c.Codec().Unmarshal(raw, elem.Interface()); err != nil {
+			report.Errors = append(report.Errors, DecodeError{Index: n, Raw: raw, Err: err})
+			continue
+		}
+		result = reflect.Append(result, elem.Elem())
+	}
+	slice.Set(result)
+
+	c.onDecodeReport(report)
+	return nil
+}
@@ -0,0 +1,65 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/metrics"
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+)
+
+// WithMetrics makes the client record metrics about the requests it sends to
+// AMS in the given registry. A registry can be shared between multiple clients.
+func WithMetrics(registry *metrics.Registry) Option {
+	return func(c *client) error {
+		if registry == nil {
+			return errs.NewInvalidArgument("registry")
+		}
+		c.metrics = metrics.NewCollectors(registry)
+		return nil
+	}
+}
+
+// metricsResource returns the top level resource of an API path, e.g.
+// "instances" for "/1.0/instances/<id>", to keep the cardinality of the
+// metric labels low
+func metricsResource(path string) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) > 1 {
+		return parts[1]
+	}
+	return "root"
+}
+
+func (c *client) recordMetrics(method, path string, start time.Time, resp *http.Response, err error) {
+	if c.metrics == nil {
+		return
+	}
+	code := "error"
+	if err == nil && resp != nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	resource := metricsResource(path)
+	c.metrics.Requests.Inc(method, resource, code)
+	c.metrics.RequestDuration.Observe(time.Since(start).Seconds(), method, resource)
+}
@@ -0,0 +1,64 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"net/http"
+)
+
+// DoerFunc allows using an ordinary function as Doer
+type DoerFunc func(*http.Request) (*http.Response, error)
+
+// Do calls f(r)
+func (f DoerFunc) Do(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// Middleware wraps the Doer sending the requests of the client. It allows
+// inspecting and modifying requests and responses, e.g. to inject
+// authentication headers or for audit logging.
+type Middleware func(next Doer) Doer
+
+// Use adds the given middlewares to the chain every request of the client
+// passes before it is sent. Middlewares see requests in the order they were
+// added, the first one added sees a request first. Websocket connections do
+// not pass the chain, they can be intercepted with WithWebsocketDialFunc. Use
+// must not be called while the client is in use.
+func (c *client) Use(middlewares ...Middleware) {
+	for _, mw := range middlewares {
+		if mw != nil {
+			c.middlewares = append(c.middlewares, mw)
+		}
+	}
+
+	var doer Doer = c.http
+	for n := len(c.middlewares) - 1; n >= 0; n-- {
+		doer = c.middlewares[n](doer)
+	}
+	c.Doer = doer
+}
+
+// WithMiddleware adds the given middlewares to the chain every request of
+// the client passes before it is sent
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(c *client) error {
+		c.Use(middlewares...)
+		return nil
+	}
+}
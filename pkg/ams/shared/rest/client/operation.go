@@ -24,17 +24,46 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/metrics"
 	"github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/api"
 )
 
+// OperationError is returned when waiting for an operation which failed. It
+// carries the details the AMS service reported for the failure.
+type OperationError struct {
+	// ID of the operation
+	ID string
+	// Description of the operation
+	Description string
+	// StatusCode of the operation when it failed
+	StatusCode api.StatusCode
+	// Message is the error the operation failed with
+	Message string
+	// Metadata the service attached to the operation, e.g. details about
+	// why an instance could not be scheduled
+	Metadata map[string]interface{}
+	// Resources affected by the operation
+	Resources map[string][]string
+	// ServerAddress is the address of the server the operation ran on
+	ServerAddress string
+}
+
+// Error returns the error string
+func (e *OperationError) Error() string {
+	return e.Message
+}
+
 // Operation wrapper type for operations response allowing certain additional logic
 // like blocking current thread until operations completes or cancel it.
 type operation struct {
 	api.Operation
 
 	c            *operations
+	metrics      *metrics.Collectors
 	listener     *EventListener
+	waitTimeout  time.Duration
 	handlerReady bool
 	handlerLock  sync.Mutex
 
@@ -114,12 +143,32 @@ func (op *operation) Refresh() error {
 	return nil
 }
 
-// Wait lets you wait until the operation reaches a final state
+// Wait lets you wait until the operation reaches a final state. If the
+// operation failed an *OperationError with the details of the failure is
+// returned. When the context is done the operation is cancelled.
 func (op *operation) Wait(ctx context.Context) error {
+	ctx, cancel := withDefaultTimeout(ctx, op.waitTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := op.wait(ctx)
+	if op.metrics != nil {
+		result := "success"
+		if ctx.Err() != nil {
+			result = "cancelled"
+		} else if err != nil {
+			result = "failure"
+		}
+		op.metrics.OperationWait.Observe(time.Since(start).Seconds(), result)
+	}
+	return err
+}
+
+func (op *operation) wait(ctx context.Context) error {
 	// Check if not done already
 	if op.StatusCode.IsFinal() {
 		if op.Err != "" {
-			return fmt.Errorf(op.Err)
+			return op.failure()
 		}
 		return nil
 	}
@@ -148,11 +197,24 @@ func (op *operation) Wait(ctx context.Context) error {
 
 	// We're done, parse the result
 	if op.Err != "" {
-		return fmt.Errorf(op.Err)
+		return op.failure()
 	}
 	return nil
 }
 
+// failure returns the error of a failed operation
+func (op *operation) failure() error {
+	return &OperationError{
+		ID:            op.ID,
+		Description:   op.Description,
+		StatusCode:    op.StatusCode,
+		Message:       op.Err,
+		Metadata:      op.Metadata,
+		Resources:     op.Resources,
+		ServerAddress: op.ServerAddress,
+	}
+}
+
 func (op *operation) setupListener() error {
 	// Make sure we're not racing with ourselves
 	op.handlerLock.Lock()
@@ -263,7 +325,7 @@ func (op *operation) setupListener() error {
 		close(chReady)
 
 		if op.Err != "" {
-			return fmt.Errorf(op.Err)
+			return op.failure()
 		}
 
 		return nil
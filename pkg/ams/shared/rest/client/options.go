@@ -0,0 +1,115 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+)
+
+// Option allows customizing the behaviour of a REST client when it is created
+type Option func(c *client) error
+
+// WithTransportTimeout overwrites the default timeout the client waits for a
+// reply of the server
+func WithTransportTimeout(timeout time.Duration) Option {
+	return func(c *client) error {
+		if timeout < 0 {
+			return errs.NewInvalidArgument("timeout")
+		}
+		c.transportTimeout = timeout
+		c.http.Timeout = timeout
+		return nil
+	}
+}
+
+// WithReadOnly makes the client refuse all requests which can modify the state
+// of the AMS service. Such requests fail with ErrReadOnlyClient before anything
+// is sent to the service.
+func WithReadOnly() Option {
+	return func(c *client) error {
+		c.readOnly = true
+		return nil
+	}
+}
+
+// WithUserAgent sets the User-Agent header of all requests and websocket
+// handshakes
+func WithUserAgent(userAgent string) Option {
+	return func(c *client) error {
+		c.httpUserAgent = userAgent
+		return nil
+	}
+}
+
+// WithHeaders adds the given headers to all requests and websocket handshakes.
+// Headers given for a single request take precedence.
+func WithHeaders(header http.Header) Option {
+	return func(c *client) error {
+		for k, v := range header {
+			c.extraHeader[http.CanonicalHeaderKey(k)] = append([]string{}, v...)
+		}
+		return nil
+	}
+}
+
+// HeaderFunc sets headers on a request based on the context the request was
+// made with, e.g. to propagate the ID of an incoming request
+type HeaderFunc func(ctx context.Context, header http.Header)
+
+// WithHeaderFunc calls the given function for all requests and websocket
+// handshakes to add headers. Requests made without a context and websocket
+// handshakes pass a background context.
+func WithHeaderFunc(f HeaderFunc) Option {
+	return func(c *client) error {
+		if f == nil {
+			return errs.NewInvalidArgument("f")
+		}
+		c.headerFuncs = append(c.headerFuncs, f)
+		return nil
+	}
+}
+
+// setExtraHeaders adds the headers configured through options
+func (c *client) setExtraHeaders(ctx context.Context, header http.Header) {
+	if len(c.httpUserAgent) > 0 {
+		header.Set("User-Agent", c.httpUserAgent)
+	}
+	for k, v := range c.extraHeader {
+		header[k] = append([]string{}, v...)
+	}
+	for _, f := range c.headerFuncs {
+		f(ctx, header)
+	}
+}
+
+func (c *client) applyOptions(opts []Option) error {
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if err := opt(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,288 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+)
+
+const (
+	// DefaultRetryAfterMaxWait is the default upper limit for the time the
+	// client waits before it retries a request rejected with 429
+	DefaultRetryAfterMaxWait = 1 * time.Minute
+)
+
+// RateLimit describes the budgets of requests the client is allowed to send
+// to AMS. Read-only requests (GET, HEAD, OPTIONS) and mutating requests use
+// separate budgets.
+type RateLimit struct {
+	// ReadRate is the number of read-only requests per second. Zero disables
+	// the limit for read-only requests.
+	ReadRate float64
+	// ReadBurst is the number of read-only requests which can be sent at once.
+	// Defaults to 1.
+	ReadBurst int
+	// WriteRate is the number of mutating requests per second. Zero disables
+	// the limit for mutating requests.
+	WriteRate float64
+	// WriteBurst is the number of mutating requests which can be sent at once.
+	// Defaults to 1.
+	WriteBurst int
+}
+
+// tokenBucket implements a token bucket rate limiter
+type tokenBucket struct {
+	lock   sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if rate <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// wait blocks until a token is available or the context is done
+func (b *tokenBucket) wait(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+
+	b.lock.Lock()
+	now := time.Now()
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	// Reserve the token even if it is not available yet so that waiting
+	// requests are served in order
+	b.tokens--
+	delay := time.Duration(0)
+	if b.tokens < 0 {
+		delay = time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	b.lock.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+
+	t := time.NewTimer(delay)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		// Give the reserved token back
+		b.lock.Lock()
+		b.tokens++
+		b.lock.Unlock()
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+type rateLimiter struct {
+	read  *tokenBucket
+	write *tokenBucket
+}
+
+func (l *rateLimiter) wait(ctx context.Context, method string) error {
+	if l == nil {
+		return nil
+	}
+	if isSafeMethod(method) {
+		return l.read.wait(ctx)
+	}
+	return l.write.wait(ctx)
+}
+
+// WithRateLimit limits the rate of requests the client sends to AMS. Requests
+// exceeding the budget are delayed until they can be sent.
+func WithRateLimit(limit RateLimit) Option {
+	return func(c *client) error {
+		if limit.ReadRate < 0 {
+			return errs.NewInvalidArgument("read rate")
+		}
+		if limit.WriteRate < 0 {
+			return errs.NewInvalidArgument("write rate")
+		}
+		c.limiter = &rateLimiter{
+			read:  newTokenBucket(limit.ReadRate, limit.ReadBurst),
+			write: newTokenBucket(limit.WriteRate, limit.WriteBurst),
+		}
+		return nil
+	}
+}
+
+// WithRetryAfter makes the client retry requests which AMS rejected with 429
+// Too Many Requests after the delay given in the Retry-After header. Requests
+// are retried at most maxAttempts times and the client waits at most maxWait
+// before each retry. If maxWait is zero DefaultRetryAfterMaxWait is used.
+// Requests with a body which cannot be replayed are never retried.
+func WithRetryAfter(maxAttempts int, maxWait time.Duration) Option {
+	return func(c *client) error {
+		if maxAttempts < 0 {
+			return errs.NewInvalidArgument("maxAttempts")
+		}
+		if maxWait < 0 {
+			return errs.NewInvalidArgument("maxWait")
+		}
+		if maxWait == 0 {
+			maxWait = DefaultRetryAfterMaxWait
+		}
+		c.retryAfterAttempts = maxAttempts
+		c.retryAfterMaxWait = maxWait
+		return nil
+	}
+}
+
+// parseRetryAfter returns the delay described by the value of a Retry-After
+// header, which is either a number of seconds or a HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if len(value) == 0 {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	t, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if d := t.Sub(now); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
+
+// retryDelay returns how long to wait before the given request is retried
+// after it was rejected with the given response. It returns false if the
+// request must not be retried.
+func (c *client) retryDelay(r *http.Request, resp *http.Response, attempt int) (time.Duration, bool) {
+	if resp == nil || resp.StatusCode != http.StatusTooManyRequests || attempt >= c.retryAfterAttempts {
+		return 0, false
+	}
+	if r.Body != nil && r.Body != http.NoBody && r.GetBody == nil {
+		return 0, false
+	}
+	delay, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	if !ok {
+		return 0, false
+	}
+	if delay > c.retryAfterMaxWait {
+		delay = c.retryAfterMaxWait
+	}
+	return delay, true
+}
+
+// do sends the request through the middleware chain while respecting the
+// rate limit and retrying it if AMS asked to do so
+func (c *client) do(ctx context.Context, r *http.Request, path string) (*http.Response, error) {
+	failovers := 0
+	for attempt := 0; ; attempt++ {
+		if err := c.limiter.wait(ctx, r.Method); err != nil {
+			return nil, err
+		}
+		if err := c.setAuthorization(r.Header); err != nil {
+			return nil, err
+		}
+		if c.endpoints != nil {
+			c.endpoints.prepare(r)
+		}
+
+		start := time.Now()
+		resp, err := c.Doer.Do(r)
+		c.health.record(start, resp, err)
+		c.recordMetrics(r.Method, path, start, resp, err)
+		if c.endpoints != nil && failovers < c.endpoints.size()-1 && c.endpoints.shouldFailover(r, resp, err) {
+			c.endpoints.markUnhealthy(r.URL.Host)
+			if resp != nil {
+				resp.Body.Close()
+			}
+			if err := rewindBody(r); err != nil {
+				return nil, err
+			}
+			failovers++
+			attempt--
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		c.connInfo.record(resp.TLS)
+		if err := c.skew.record(start, resp); err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+
+		delay, retry := c.retryDelay(r, resp, attempt)
+		if !retry {
+			return resp, nil
+		}
+		resp.Body.Close()
+
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+
+		if err := rewindBody(r); err != nil {
+			return nil, err
+		}
+		if c.metrics != nil {
+			c.metrics.Retries.Inc(r.Method, metricsResource(path))
+		}
+	}
+}
+
+// rewindBody resets the body of the request so it can be sent again
+func rewindBody(r *http.Request) error {
+	if r.GetBody == nil {
+		return nil
+	}
+	body, err := r.GetBody()
+	if err != nil {
+		return err
+	}
+	r.Body = body
+	return nil
+}
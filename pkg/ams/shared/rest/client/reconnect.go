@@ -0,0 +1,201 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// ReconnectPolicy controls how a ReconnectingWebsocket reconnects after the
+// connection was lost
+type ReconnectPolicy struct {
+	// InitialBackoff is the delay before the first reconnect attempt. It is
+	// doubled for each further attempt. Defaults to one second.
+	InitialBackoff time.Duration
+	// MaxBackoff limits the delay between two reconnect attempts. Defaults to
+	// 30 seconds.
+	MaxBackoff time.Duration
+	// MaxAttempts limits the number of consecutive failed reconnect
+	// attempts. Zero means no limit.
+	MaxAttempts int
+}
+
+// DefaultReconnectPolicy reconnects without limit with a delay growing from one
+// second up to 30 seconds
+var DefaultReconnectPolicy = ReconnectPolicy{
+	InitialBackoff: time.Second,
+	MaxBackoff:     30 * time.Second,
+}
+
+// Backoff returns the delay before the given reconnect attempt, starting at 1
+func (p ReconnectPolicy) Backoff(attempt int) time.Duration {
+	backoff := p.InitialBackoff
+	if backoff <= 0 {
+		backoff = DefaultReconnectPolicy.InitialBackoff
+	}
+	max := p.MaxBackoff
+	if max <= 0 {
+		max = DefaultReconnectPolicy.MaxBackoff
+	}
+	for n := 1; n < attempt && backoff < max; n++ {
+		backoff *= 2
+	}
+	if backoff > max {
+		backoff = max
+	}
+	return backoff
+}
+
+// Exhausted returns true if no further reconnect attempt should be made after
+// the given number of consecutive failed attempts
+func (p ReconnectPolicy) Exhausted(attempts int) bool {
+	return p.MaxAttempts > 0 && attempts >= p.MaxAttempts
+}
+
+// WebsocketDialer establishes a websocket connection. It is called again for
+// every reconnect, so it can resume a stream at the position it was
+// interrupted at where the API supports it.
+type WebsocketDialer func(ctx context.Context) (*websocket.Conn, error)
+
+// ReconnectingWebsocket wraps a websocket connection which is
+// re-established transparently when it is lost
+type ReconnectingWebsocket struct {
+	dial   WebsocketDialer
+	policy ReconnectPolicy
+
+	// OnReconnect is called after the connection was re-established
+	OnReconnect func(attempts int)
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	lock sync.Mutex
+	conn *websocket.Conn
+}
+
+// NewReconnectingWebsocket establishes a connection with the given dialer and
+// returns it wrapped so that it reconnects according to the given policy. The
+// initial connection is not retried, so errors like missing permissions
+// surface immediately.
+func NewReconnectingWebsocket(ctx context.Context, dial WebsocketDialer, policy ReconnectPolicy) (*ReconnectingWebsocket, error) {
+	if dial == nil {
+		return nil, fmt.Errorf("A valid dialer must be provided")
+	}
+	conn, err := dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	return &ReconnectingWebsocket{
+		dial:   dial,
+		policy: policy,
+		ctx:    ctx,
+		cancel: cancel,
+		conn:   conn,
+	}, nil
+}
+
+// ReadMessage reads the next message from the connection. If the connection
+// was lost, it is re-established first. A normal closure by the server is
+// returned as is and not reconnected.
+func (r *ReconnectingWebsocket) ReadMessage() (int, []byte, error) {
+	for {
+		r.lock.Lock()
+		conn := r.conn
+		r.lock.Unlock()
+		if conn == nil {
+			return 0, nil, r.ctx.Err()
+		}
+
+		mt, data, err := conn.ReadMessage()
+		if err == nil {
+			return mt, data, nil
+		}
+		if websocket.IsCloseError(err, websocket.CloseNormalClosure) || r.ctx.Err() != nil {
+			return 0, nil, err
+		}
+		if rerr := r.reconnect(); rerr != nil {
+			return 0, nil, fmt.Errorf("%v (reconnect failed: %v)", err, rerr)
+		}
+	}
+}
+
+func (r *ReconnectingWebsocket) reconnect() error {
+	r.lock.Lock()
+	if r.conn != nil {
+		r.conn.Close()
+	}
+	r.lock.Unlock()
+
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-r.ctx.Done():
+			return r.ctx.Err()
+		case <-time.After(r.policy.Backoff(attempt)):
+		}
+
+		conn, err := r.dial(r.ctx)
+		if err == nil {
+			r.lock.Lock()
+			if r.ctx.Err() != nil {
+				r.lock.Unlock()
+				conn.Close()
+				return r.ctx.Err()
+			}
+			r.conn = conn
+			r.lock.Unlock()
+			if r.OnReconnect != nil {
+				r.OnReconnect(attempt)
+			}
+			return nil
+		}
+		if r.policy.Exhausted(attempt) {
+			return err
+		}
+	}
+}
+
+// Close closes the connection and stops any reconnect attempt
+func (r *ReconnectingWebsocket) Close() error {
+	r.cancel()
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.conn == nil {
+		return nil
+	}
+	err := r.conn.Close()
+	r.conn = nil
+	return err
+}
+
+// WithEventReconnect makes the event listeners returned by GetEvents survive
+// transient disconnects: the event stream is re-established according to the
+// given policy. AMS has no way to replay events, so events sent while the
+// connection was down are lost.
+func WithEventReconnect(policy ReconnectPolicy) Option {
+	return func(c *client) error {
+		c.eventReconnect = &policy
+		return nil
+	}
+}
@@ -0,0 +1,130 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+)
+
+// Requirements describe the capabilities a client requires from the AMS service
+type Requirements struct {
+	// MinAPIVersion is the minimum API version, e.g. "1.0"
+	MinAPIVersion string
+	// Extensions lists the API extensions which must be supported
+	Extensions []string
+}
+
+// WithRequirements makes the client verify when it is created that the AMS
+// service provides the given capabilities. If it does not, creating the client
+// fails with an ErrIncompatibleServer error listing what is missing.
+func WithRequirements(req Requirements) Option {
+	return func(c *client) error {
+		if len(req.MinAPIVersion) > 0 {
+			if _, err := parseAPIVersion(req.MinAPIVersion); err != nil {
+				return errs.NewInvalidArgument("min api version")
+			}
+		}
+		c.requirements = &req
+		return nil
+	}
+}
+
+// checkRequirements verifies that the service meets the requirements of the client
+func (c *client) checkRequirements() error {
+	if c.requirements == nil {
+		return nil
+	}
+
+	status := struct {
+		APIVersion    string   `json:"api_version"`
+		APIExtensions []string `json:"api_extensions"`
+	}{}
+	if _, err := c.QueryStruct("GET", APIPath(""), nil, nil, nil, "", &status); err != nil {
+		return err
+	}
+
+	requiredVersion := ""
+	if len(c.requirements.MinAPIVersion) > 0 {
+		older, err := apiVersionOlder(status.APIVersion, c.requirements.MinAPIVersion)
+		if err != nil {
+			return err
+		}
+		if older {
+			requiredVersion = c.requirements.MinAPIVersion
+		}
+	}
+
+	supported := map[string]bool{}
+	for _, ext := range status.APIExtensions {
+		supported[ext] = true
+	}
+	missing := []string{}
+	for _, ext := range c.requirements.Extensions {
+		if !supported[ext] {
+			missing = append(missing, ext)
+		}
+	}
+
+	if len(requiredVersion) > 0 || len(missing) > 0 {
+		return errs.NewErrIncompatibleServer(status.APIVersion, requiredVersion, missing)
+	}
+	return nil
+}
+
+// apiVersionOlder returns true if version a is older than version b
+func apiVersionOlder(a, b string) (bool, error) {
+	va, err := parseAPIVersion(a)
+	if err != nil {
+		return false, fmt.Errorf("invalid API version %q of the service", a)
+	}
+	vb, err := parseAPIVersion(b)
+	if err != nil {
+		return false, err
+	}
+	for n := 0; n < len(va) || n < len(vb); n++ {
+		pa, pb := 0, 0
+		if n < len(va) {
+			pa = va[n]
+		}
+		if n < len(vb) {
+			pb = vb[n]
+		}
+		if pa != pb {
+			return pa < pb, nil
+		}
+	}
+	return false, nil
+}
+
+func parseAPIVersion(version string) ([]int, error) {
+	parts := strings.Split(version, ".")
+	numbers := make([]int, 0, len(parts))
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid API version %q", version)
+		}
+		numbers = append(numbers, n)
+	}
+	return numbers, nil
+}
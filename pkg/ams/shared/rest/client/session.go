@@ -0,0 +1,99 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"crypto/tls"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// SessionTokenHeader carries the session token the AMS service hands out
+	// on websocket handshakes and which the client presents on later ones
+	SessionTokenHeader = "X-AMS-Session-Token"
+	// SessionExpiresHeader carries the validity of a session token in seconds
+	SessionExpiresHeader = "X-AMS-Session-Expires"
+
+	// Size of the cache for resumable TLS sessions
+	defaultTLSSessionCacheSize = 64
+	// Tokens are renewed this long before they expire to account for the
+	// round trip of the handshake
+	sessionTokenExpiryMargin = 5 * time.Second
+)
+
+// sessionCache holds the session token negotiated with the AMS service
+type sessionCache struct {
+	lock    sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// get returns the cached token if it is still valid
+func (s *sessionCache) get() string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if len(s.token) == 0 || time.Now().Add(sessionTokenExpiryMargin).After(s.expires) {
+		s.token = ""
+		return ""
+	}
+	return s.token
+}
+
+// update stores the token handed out with the given handshake response
+func (s *sessionCache) update(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+	token := resp.Header.Get(SessionTokenHeader)
+	seconds, err := strconv.Atoi(resp.Header.Get(SessionExpiresHeader))
+	if len(token) == 0 || err != nil || seconds <= 0 {
+		return
+	}
+
+	s.lock.Lock()
+	s.token = token
+	s.expires = time.Now().Add(time.Duration(seconds) * time.Second)
+	s.lock.Unlock()
+}
+
+func (s *sessionCache) reset() {
+	s.lock.Lock()
+	s.token = ""
+	s.lock.Unlock()
+}
+
+// WithWebsocketSessions reduces the setup latency of websockets for workloads
+// opening many of them, e.g. for exec. TLS sessions are resumed instead of
+// doing a full handshake for every connection and, if the AMS service hands
+// out a short-lived session token during a websocket handshake, the token is
+// presented on subsequent handshakes so the service can skip the full
+// authentication. An expired or rejected token is discarded transparently.
+func WithWebsocketSessions() Option {
+	return func(c *client) error {
+		t := c.HTTPTransport()
+		if t.TLSClientConfig != nil && t.TLSClientConfig.ClientSessionCache == nil {
+			t.TLSClientConfig.ClientSessionCache = tls.NewLRUClientSessionCache(defaultTLSSessionCacheSize)
+		}
+		c.sessions = &sessionCache{}
+		return nil
+	}
+}
@@ -0,0 +1,85 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+	"github.com/anbox-cloud/ams-sdk/pkg/network"
+)
+
+const (
+	// DefaultSRVService is the service name used to discover AMS through
+	// SRV records
+	DefaultSRVService = "ams"
+)
+
+// WithSRVDiscovery makes the client discover the AMS endpoints through the
+// _<service>._tcp SRV records of the host of the service URL instead of
+// connecting to the host directly. The records are resolved for every new
+// connection and the targets are tried by priority and weight until a
+// connection is established. TLS verification still uses the host of the
+// service URL. An empty service defaults to DefaultSRVService.
+func WithSRVDiscovery(service string) Option {
+	return func(c *client) error {
+		t := c.HTTPTransport()
+		if t.Dial != nil {
+			return errs.NewErrNotSupported("SRV discovery on unix socket connections")
+		}
+		if len(service) == 0 {
+			service = DefaultSRVService
+		}
+		c.srvService = service
+		t.DialContext = c.dialContext
+		return nil
+	}
+}
+
+// dialContext establishes connections of the HTTP transport and websockets
+// which are not using a unix socket
+func (c *client) dialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	if len(c.srvService) == 0 {
+		return c.netDialer().DialContext(ctx, network, addr)
+	}
+	return c.dialSRV(ctx, network)
+}
+
+func (c *client) dialSRV(ctx context.Context, netw string) (net.Conn, error) {
+	domain := c.serviceURL.Hostname()
+	targets, err := network.LookupSRVTargets(ctx, nil, c.srvService, "tcp", domain)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for _, target := range targets {
+		conn, err := c.netDialer().DialContext(ctx, netw, target)
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, fmt.Errorf("failed to connect to any endpoint of _%s._tcp.%s: %v", c.srvService, domain, lastErr)
+}
@@ -0,0 +1,203 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/metrics"
+)
+
+// StreamInfo describes a websocket stream opened by the client
+type StreamInfo struct {
+	// ID identifies the stream within the client
+	ID uint64
+	// URL the stream is connected to. The query is removed as it can contain secrets.
+	URL string
+	// OpenedAt is the time the stream was opened
+	OpenedAt time.Time
+	// LastActivity is the time data was last sent or received on the stream
+	LastActivity time.Time
+	// BytesSent is the number of bytes sent on the stream
+	BytesSent uint64
+	// BytesReceived is the number of bytes received on the stream
+	BytesReceived uint64
+	// Events is true for the stream the client uses internally to receive events
+	Events bool
+}
+
+// trackedConn records the activity on a connection underlying a websocket
+type trackedConn struct {
+	net.Conn
+
+	registry *streamRegistry
+	metrics  *metrics.Collectors
+	closed   int32
+
+	id           uint64
+	url          string
+	events       bool
+	openedAt     time.Time
+	lastActivity int64
+	sent         uint64
+	received     uint64
+}
+
+func (c *trackedConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	c.record(n, &c.received, "received")
+	return n, err
+}
+
+func (c *trackedConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	c.record(n, &c.sent, "sent")
+	return n, err
+}
+
+func (c *trackedConn) Close() error {
+	if atomic.CompareAndSwapInt32(&c.closed, 0, 1) {
+		c.registry.remove(c.id)
+	}
+	return c.Conn.Close()
+}
+
+func (c *trackedConn) record(n int, counter *uint64, direction string) {
+	if n <= 0 {
+		return
+	}
+	atomic.AddUint64(counter, uint64(n))
+	atomic.StoreInt64(&c.lastActivity, time.Now().UnixNano())
+	if c.metrics != nil {
+		c.metrics.WebsocketBytes.Add(float64(n), direction)
+	}
+}
+
+func (c *trackedConn) info() StreamInfo {
+	return StreamInfo{
+		ID:            c.id,
+		URL:           c.url,
+		OpenedAt:      c.openedAt,
+		LastActivity:  time.Unix(0, atomic.LoadInt64(&c.lastActivity)),
+		BytesSent:     atomic.LoadUint64(&c.sent),
+		BytesReceived: atomic.LoadUint64(&c.received),
+		Events:        c.events,
+	}
+}
+
+// streamRegistry keeps track of all live websocket streams of a client
+type streamRegistry struct {
+	lock    sync.Mutex
+	nextID  uint64
+	streams map[uint64]*trackedConn
+}
+
+func newStreamRegistry() *streamRegistry {
+	return &streamRegistry{streams: map[uint64]*trackedConn{}}
+}
+
+func (r *streamRegistry) add(conn *trackedConn) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.nextID++
+	conn.id = r.nextID
+	r.streams[conn.id] = conn
+}
+
+func (r *streamRegistry) remove(id uint64) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.streams, id)
+}
+
+func (r *streamRegistry) list() []*trackedConn {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	conns := make([]*trackedConn, 0, len(r.streams))
+	for _, conn := range r.streams {
+		conns = append(conns, conn)
+	}
+	sort.Slice(conns, func(i, j int) bool { return conns[i].id < conns[j].id })
+	return conns
+}
+
+// trackedDial wraps the given dial function so that the connections it
+// establishes for the websocket with the given URL are registered with the
+// client until they are closed
+func (c *client) trackedDial(url string, dial func(network, addr string) (net.Conn, error)) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		var conn net.Conn
+		var err error
+		if dial != nil {
+			conn, err = dial(network, addr)
+		} else {
+			conn, err = c.dialContext(ctx, network, addr)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		now := time.Now()
+		redacted := redactURL(url)
+		tracked := &trackedConn{
+			Conn:         conn,
+			registry:     c.streams,
+			metrics:      c.metrics,
+			url:          redacted,
+			events:       strings.HasSuffix(redacted, APIPath("events")),
+			openedAt:     now,
+			lastActivity: now.UnixNano(),
+		}
+		c.streams.add(tracked)
+		return tracked, nil
+	}
+}
+
+// OpenStreams returns all websocket streams the client currently has open
+func (c *client) OpenStreams() []StreamInfo {
+	conns := c.streams.list()
+	infos := make([]StreamInfo, 0, len(conns))
+	for _, conn := range conns {
+		infos = append(infos, conn.info())
+	}
+	return infos
+}
+
+// CloseIdleStreams closes all websocket streams which had no activity for
+// longer than the given duration and returns the number of closed streams.
+// The stream used internally to receive events is never closed.
+func (c *client) CloseIdleStreams(olderThan time.Duration) int {
+	closed := 0
+	deadline := time.Now().Add(-olderThan)
+	for _, conn := range c.streams.list() {
+		if conn.events || conn.info().LastActivity.After(deadline) {
+			continue
+		}
+		if err := conn.Close(); err == nil {
+			closed++
+		}
+	}
+	return closed
+}
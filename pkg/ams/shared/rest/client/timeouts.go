@@ -0,0 +1,100 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+)
+
+// WithRequestTimeout limits the time a single request can take, including
+// reading the response body. Requests made with a context which already has
+// a deadline use the deadline of the context instead. The transport timeout
+// still applies in addition. A timeout of 0 disables the limit.
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(c *client) error {
+		if timeout < 0 {
+			return errs.NewInvalidArgument("timeout")
+		}
+		c.requestTimeout = timeout
+		return nil
+	}
+}
+
+// WithOperationWaitTimeout limits the time waiting for an operation to finish
+// can take. When the timeout expires the operation is cancelled. Waiting with
+// a context which already has a deadline uses the deadline of the context
+// instead. A timeout of 0 disables the limit.
+func WithOperationWaitTimeout(timeout time.Duration) Option {
+	return func(c *client) error {
+		if timeout < 0 {
+			return errs.NewInvalidArgument("timeout")
+		}
+		c.operationWaitTimeout = timeout
+		return nil
+	}
+}
+
+// WithWebsocketHandshakeTimeout overwrites the default time the client waits
+// for the websocket handshake to complete. A timeout of 0 disables the limit.
+func WithWebsocketHandshakeTimeout(timeout time.Duration) Option {
+	return func(c *client) error {
+		if timeout < 0 {
+			return errs.NewInvalidArgument("timeout")
+		}
+		c.websocketHandshakeTimeout = timeout
+		return nil
+	}
+}
+
+// withDefaultTimeout returns a context limited by the given timeout unless
+// the context already has a deadline or no timeout is given
+func withDefaultTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// cancelOnClose releases the context of a request once its response body
+// was closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// releaseOnClose makes the cancel function of the request context run when
+// the response body is closed, or right away if there is no response
+func releaseOnClose(resp *http.Response, cancel context.CancelFunc) {
+	if resp == nil || resp.Body == nil {
+		cancel()
+		return
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+}
@@ -0,0 +1,56 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"fmt"
+	"net/http"
+
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+	"golang.org/x/oauth2"
+)
+
+// WithTokenSource authenticates all requests and websocket handshakes with a
+// bearer token from the given source, e.g. for AMS deployments behind an
+// identity aware proxy. Tokens are reused until they expire and then
+// refreshed through the source. Client certificates are still presented if
+// configured.
+func WithTokenSource(ts oauth2.TokenSource) Option {
+	return func(c *client) error {
+		if ts == nil {
+			return errs.NewInvalidArgument("token source")
+		}
+		c.tokens = oauth2.ReuseTokenSource(nil, ts)
+		return nil
+	}
+}
+
+// setAuthorization sets the authorization header with the current token if a
+// token source is configured
+func (c *client) setAuthorization(header http.Header) error {
+	if c.tokens == nil {
+		return nil
+	}
+	token, err := c.tokens.Token()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve token: %v", err)
+	}
+	header.Set("Authorization", fmt.Sprintf("%s %s", token.Type(), token.AccessToken))
+	return nil
+}
@@ -0,0 +1,111 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName = "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/client"
+)
+
+// Attribute keys used for the spans created by the client
+const (
+	attrHTTPMethod     = attribute.Key("http.method")
+	attrHTTPTarget     = attribute.Key("http.target")
+	attrHTTPStatusCode = attribute.Key("http.status_code")
+	attrOperationID    = attribute.Key("ams.operation.id")
+	attrWebsocketURL   = attribute.Key("ams.websocket.url")
+)
+
+// WithTracerProvider enables OpenTelemetry tracing of all requests and
+// websocket connections of the client with the given tracer provider. If no
+// provider is given the global one is used. The trace context is propagated to
+// AMS with the global text map propagator.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *client) error {
+		if provider == nil {
+			provider = otel.GetTracerProvider()
+		}
+		c.tracer = provider.Tracer(tracerName)
+		return nil
+	}
+}
+
+// startSpan starts a new client span. If tracing is not enabled a
+// non-recording span is returned.
+func (c *client) startSpan(name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return c.startSpanWithContext(context.Background(), name, attrs...)
+}
+
+// startSpanWithContext starts a new span as child of the span in the given context
+func (c *client) startSpanWithContext(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if c.tracer == nil {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+	return c.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...))
+}
+
+// endSpan ends the given span and records the error if one is given
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// traceResponse records the status of the response on the span of the given
+// context
+func traceResponse(ctx context.Context, resp *http.Response) {
+	if resp == nil {
+		return
+	}
+	trace.SpanFromContext(ctx).SetAttributes(attrHTTPStatusCode.Int(resp.StatusCode))
+}
+
+// injectTraceContext adds the headers required to propagate the trace
+// context of the given context to the server
+func injectTraceContext(ctx context.Context, header http.Header) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return
+	}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// redactURL removes the query of a URL which can contain secrets
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	u.RawQuery = ""
+	return u.String()
+}
@@ -19,15 +19,43 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
 	"github.com/gorilla/websocket"
 )
 
 func (c *client) Websocket(resource string) (*websocket.Conn, error) {
-	return c.dialWebsocket(c.composeWebsocketPath(resource))
+	return c.WebsocketWithContext(context.Background(), resource)
+}
+
+// WebsocketWithContext establishes a websocket connection to the given
+// resource. The handshake is aborted when the context is done.
+func (c *client) WebsocketWithContext(ctx context.Context, resource string) (*websocket.Conn, error) {
+	return c.dialWebsocket(ctx, c.composeWebsocketPath(resource))
+}
+
+// WebsocketDialFunc performs the handshake of a websocket connection of the
+// client. It receives the dialer the client configured for the connection and
+// the headers it would send. The function can use the dialer or establish the
+// connection in any other way, e.g. to record or replay its messages.
+type WebsocketDialFunc func(ctx context.Context, dialer *websocket.Dialer, url string, headers http.Header) (*websocket.Conn, *http.Response, error)
+
+// WithWebsocketDialFunc sets the function used to establish all websocket
+// connections of the client. Unlike requests, websocket connections do not
+// pass the middleware chain, so this is the hook to intercept them.
+func WithWebsocketDialFunc(dial WebsocketDialFunc) Option {
+	return func(c *client) error {
+		if dial == nil {
+			return errs.NewInvalidArgument("dial")
+		}
+		c.websocketDial = dial
+		return nil
+	}
 }
 
 // composeWebsocketPath returns websocket url related with rest client one
@@ -42,34 +70,83 @@ func (c *client) composeWebsocketPath(path string) string {
 	return fmt.Sprintf("%s://%s%s", scheme, host, path)
 }
 
-func (c *client) dialWebsocket(url string) (*websocket.Conn, error) {
-
-	switch c.Doer.(type) {
-	case *http.Client:
-	default:
+func (c *client) dialWebsocket(ctx context.Context, url string) (conn *websocket.Conn, err error) {
+	if c.http == nil {
 		return nil, errors.New("Client is not a valid http one")
 	}
 
-	t := c.Doer.(*http.Client).Transport.(*http.Transport)
+	ctx, span := c.startSpanWithContext(ctx, "Websocket", attrWebsocketURL.String(redactURL(url)))
+	defer func() { endSpan(span, err) }()
+
+	t := c.HTTPTransport()
+
+	// Websockets are always established over HTTP/1.1 so the protocols
+	// negotiated for HTTP/2 must not be offered
+	tlsConfig := t.TLSClientConfig
+	if tlsConfig != nil && c.http2 != nil {
+		tlsConfig = tlsConfig.Clone()
+		tlsConfig.NextProtos = nil
+	}
 
 	// Setup a new websocket dialer based on it
 	dialer := websocket.Dialer{
-		NetDial:         t.Dial,
-		TLSClientConfig: t.TLSClientConfig,
-		Proxy:           t.Proxy,
+		NetDialContext:   c.trackedDial(url, t.Dial),
+		TLSClientConfig:  tlsConfig,
+		Proxy:            t.Proxy,
+		HandshakeTimeout: c.websocketHandshakeTimeout,
 	}
 
-	// Set the user agent
+	// Set the user agent and additional headers
 	headers := http.Header{}
-	if c.httpUserAgent != "" {
-		headers.Set("User-Agent", c.httpUserAgent)
+	c.setExtraHeaders(ctx, headers)
+	injectTraceContext(ctx, headers)
+	if err := c.setAuthorization(headers); err != nil {
+		return nil, err
+	}
+
+	token := ""
+	if c.sessions != nil {
+		token = c.sessions.get()
+	}
+	if len(token) > 0 {
+		headers.Set(SessionTokenHeader, token)
 	}
 
 	// Establish the connection
-	conn, _, err := dialer.Dial(url, headers)
+	start := time.Now()
+	if c.endpoints != nil {
+		url = c.endpoints.websocketURL(url)
+	}
+	conn, resp, err := c.dialWebsocketConn(ctx, &dialer, url, headers)
+	for failovers := 0; c.endpoints != nil && err != nil && resp == nil && failovers < c.endpoints.size()-1; failovers++ {
+		// The endpoint could not be reached, try the next one
+		c.endpoints.markUnhealthy(websocketHost(url))
+		url = c.endpoints.websocketURL(url)
+		conn, resp, err = c.dialWebsocketConn(ctx, &dialer, url, headers)
+	}
+	if err != nil && len(token) > 0 && resp != nil && resp.StatusCode == http.StatusUnauthorized {
+		// The token expired or was revoked, authenticate again without it
+		c.sessions.reset()
+		headers.Del(SessionTokenHeader)
+		conn, resp, err = c.dialWebsocketConn(ctx, &dialer, url, headers)
+	}
+	c.health.record(start, resp, err)
+	traceResponse(ctx, resp)
 	if err != nil {
 		return nil, err
 	}
+	if c.sessions != nil {
+		c.sessions.update(resp)
+	}
 
 	return conn, err
 }
+
+// dialWebsocketConn performs the websocket handshake, either through the dial
+// function configured for the client or the given dialer
+func (c *client) dialWebsocketConn(ctx context.Context, dialer *websocket.Dialer, url string, headers http.Header) (*websocket.Conn, *http.Response, error) {
+	if c.websocketDial != nil {
+		return c.websocketDial(ctx, dialer, url, headers)
+	}
+	return dialer.DialContext(ctx, url, headers)
+}
@@ -0,0 +1,62 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package telemetry
+
+import (
+	"encoding/json"
+	"expvar"
+	"net/http"
+
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+)
+
+// Handler returns a HTTP handler serving a snapshot of the statistics of the
+// given recorder as JSON. The statistics are reset after the dump if the
+// request has the "reset" query parameter set to "1" or "true".
+func Handler(r *Recorder) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		snapshot := r.Snapshot()
+		if reset := req.URL.Query().Get("reset"); reset == "1" || reset == "true" {
+			r.Reset()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(snapshot); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// Publish makes the statistics of the recorder available under the given
+// name through expvar, which serves them on /debug/vars of the default mux.
+// expvar does not allow replacing a published variable, so publishing under
+// a name which is already taken fails.
+func (r *Recorder) Publish(name string) error {
+	if len(name) == 0 {
+		return errs.NewInvalidArgument("name")
+	}
+	if expvar.Get(name) != nil {
+		return errs.NewErrAlreadyExists("expvar " + name)
+	}
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return r.Snapshot()
+	}))
+	return nil
+}
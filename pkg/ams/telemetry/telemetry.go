@@ -0,0 +1,171 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package telemetry aggregates statistics about the calls a client sends to
+// AMS. The statistics are kept in memory and never leave the process; they
+// can be dumped as JSON or published through expvar to find hot paths of an
+// embedding service without a metrics infrastructure. Recording is opt-in by
+// adding the middleware of a Recorder to a client.
+package telemetry
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	restclient "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/client"
+)
+
+// CallStats summarizes all calls of a single kind
+type CallStats struct {
+	// Method is the HTTP method of the call
+	Method string `json:"method" yaml:"method"`
+	// Path is the API path with identifiers replaced by "*", e.g.
+	// "/1.0/instances/*/logs"
+	Path string `json:"path" yaml:"path"`
+	// Count is the number of calls
+	Count int64 `json:"count" yaml:"count"`
+	// Errors is the number of calls which failed or returned an error status
+	Errors int64 `json:"errors" yaml:"errors"`
+	// TotalDuration is the time spent in all calls, encoded in nanoseconds
+	TotalDuration time.Duration `json:"total_duration" yaml:"total_duration"`
+	// MaxDuration is the duration of the slowest call
+	MaxDuration time.Duration `json:"max_duration" yaml:"max_duration"`
+	// BytesSent is the sum of the request body sizes known in advance
+	BytesSent int64 `json:"bytes_sent" yaml:"bytes_sent"`
+	// BytesReceived is the sum of the response body sizes announced by AMS
+	BytesReceived int64 `json:"bytes_received" yaml:"bytes_received"`
+	// LastCall is the time the last call was sent
+	LastCall time.Time `json:"last_call" yaml:"last_call"`
+}
+
+// AverageDuration returns the mean duration of the calls
+func (s CallStats) AverageDuration() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.TotalDuration / time.Duration(s.Count)
+}
+
+// Snapshot is a point in time copy of the statistics of a recorder
+type Snapshot struct {
+	// Since is the time the recorder was created or last reset
+	Since time.Time `json:"since" yaml:"since"`
+	// Calls lists the statistics per kind of call, the kind with the most
+	// time spent first
+	Calls []CallStats `json:"calls" yaml:"calls"`
+}
+
+// Recorder aggregates the statistics of the calls passing its middleware. A
+// recorder can be shared between multiple clients.
+type Recorder struct {
+	lock  sync.Mutex
+	since time.Time
+	calls map[string]*CallStats
+}
+
+// NewRecorder returns a new and empty recorder
+func NewRecorder() *Recorder {
+	return &Recorder{since: time.Now(), calls: map[string]*CallStats{}}
+}
+
+// Middleware returns the middleware recording the calls of a client. It is
+// added to a client with its Use method or the WithMiddleware option.
+func (r *Recorder) Middleware() restclient.Middleware {
+	return func(next restclient.Doer) restclient.Doer {
+		return restclient.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.Do(req)
+			r.record(req, resp, err, start)
+			return resp, err
+		})
+	}
+}
+
+func (r *Recorder) record(req *http.Request, resp *http.Response, err error, start time.Time) {
+	duration := time.Since(start)
+	path := normalizePath(req.URL.Path)
+	key := req.Method + " " + path
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	s, ok := r.calls[key]
+	if !ok {
+		s = &CallStats{Method: req.Method, Path: path}
+		r.calls[key] = s
+	}
+	s.Count++
+	s.TotalDuration += duration
+	if duration > s.MaxDuration {
+		s.MaxDuration = duration
+	}
+	s.LastCall = start
+	if req.ContentLength > 0 {
+		s.BytesSent += req.ContentLength
+	}
+	if err != nil || resp == nil || resp.StatusCode >= 400 {
+		s.Errors++
+	}
+	if resp != nil && resp.ContentLength > 0 {
+		s.BytesReceived += resp.ContentLength
+	}
+}
+
+// Snapshot returns a copy of the current statistics
+func (r *Recorder) Snapshot() Snapshot {
+	r.lock.Lock()
+	s := Snapshot{Since: r.since, Calls: make([]CallStats, 0, len(r.calls))}
+	for _, call := range r.calls {
+		s.Calls = append(s.Calls, *call)
+	}
+	r.lock.Unlock()
+
+	sort.Slice(s.Calls, func(i, j int) bool {
+		a, b := s.Calls[i], s.Calls[j]
+		if a.TotalDuration != b.TotalDuration {
+			return a.TotalDuration > b.TotalDuration
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Method < b.Method
+	})
+	return s
+}
+
+// Reset drops all recorded statistics
+func (r *Recorder) Reset() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.since = time.Now()
+	r.calls = map[string]*CallStats{}
+}
+
+// normalizePath replaces the identifiers of an API path with "*". AMS paths
+// alternate between collections and identifiers after the version, e.g.
+// "/1.0/instances/<id>/logs/<name>".
+func normalizePath(path string) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	for n := 2; n < len(parts); n += 2 {
+		parts[n] = "*"
+	}
+	return "/" + strings.Join(parts, "/")
+}
@@ -0,0 +1,195 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package userdata
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ValueType describes the type of a userdata value
+type ValueType string
+
+const (
+	// TypeString is a JSON string
+	TypeString ValueType = "string"
+	// TypeNumber is a JSON number
+	TypeNumber ValueType = "number"
+	// TypeBoolean is a JSON boolean
+	TypeBoolean ValueType = "boolean"
+	// TypeObject is a JSON object
+	TypeObject ValueType = "object"
+	// TypeBinary is a string holding base64 encoded data, see SetBinary
+	TypeBinary ValueType = "binary"
+)
+
+// KeySpec describes a single key of a schema
+type KeySpec struct {
+	Type ValueType
+	// Required fails validation if the key is missing
+	Required bool
+	// MaxLength limits the length of string values or, for TypeBinary, of
+	// the decoded data. Zero means no limit.
+	MaxLength int
+	// Description documents the key in the generated JSON schema
+	Description string
+}
+
+// Schema describes the keys the userdata of an application consists of
+type Schema struct {
+	Keys map[string]KeySpec
+	// AllowUnknown accepts keys which are not part of the schema
+	AllowUnknown bool
+}
+
+// ValidationError lists all problems found when validating userdata against
+// a schema
+type ValidationError struct {
+	Problems []string
+}
+
+// Error returns the error string
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid userdata: %s", strings.Join(e.Problems, "; "))
+}
+
+// Validate checks the userdata against the schema. All problems found are
+// returned together as a *ValidationError.
+func (s *Schema) Validate(u *Userdata) error {
+	problems := []string{}
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	names := make([]string, 0, len(s.Keys))
+	for name := range s.Keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		spec := s.Keys[name]
+		raw, ok := u.values[name]
+		if !ok {
+			if spec.Required {
+				add("missing required key %s", name)
+			}
+			continue
+		}
+		if err := spec.check(raw); err != nil {
+			add("%s: %v", name, err)
+		}
+	}
+
+	if !s.AllowUnknown {
+		for _, key := range u.Keys() {
+			if _, ok := s.Keys[key]; !ok {
+				add("unknown key %s", key)
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+func (k KeySpec) check(raw json.RawMessage) error {
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return err
+	}
+
+	switch k.Type {
+	case TypeString, TypeBinary:
+		str, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected a string")
+		}
+		length := len(str)
+		if k.Type == TypeBinary {
+			data, err := base64.StdEncoding.DecodeString(str)
+			if err != nil {
+				return fmt.Errorf("expected base64 encoded data")
+			}
+			length = len(data)
+		}
+		if k.MaxLength > 0 && length > k.MaxLength {
+			return fmt.Errorf("exceeds %d bytes", k.MaxLength)
+		}
+	case TypeNumber:
+		if _, ok := value.(float64); !ok {
+			return fmt.Errorf("expected a number")
+		}
+	case TypeBoolean:
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("expected a boolean")
+		}
+	case TypeObject:
+		if _, ok := value.(map[string]interface{}); !ok {
+			return fmt.Errorf("expected an object")
+		}
+	default:
+		return fmt.Errorf("unknown type %q in schema", k.Type)
+	}
+	return nil
+}
+
+// JSONSchema returns the schema as JSON schema document, e.g. to validate
+// userdata with other tools or to document it
+func (s *Schema) JSONSchema() ([]byte, error) {
+	properties := map[string]interface{}{}
+	required := []string{}
+	for name, spec := range s.Keys {
+		property := map[string]interface{}{}
+		switch spec.Type {
+		case TypeBinary:
+			property["type"] = "string"
+			property["contentEncoding"] = "base64"
+		default:
+			property["type"] = string(spec.Type)
+		}
+		if spec.MaxLength > 0 && spec.Type == TypeString {
+			property["maxLength"] = spec.MaxLength
+		}
+		if len(spec.Description) > 0 {
+			property["description"] = spec.Description
+		}
+		properties[name] = property
+		if spec.Required {
+			required = append(required, name)
+		}
+	}
+	sort.Strings(required)
+
+	doc := map[string]interface{}{
+		"$schema":              "http://json-schema.org/draft-07/schema#",
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": s.AllowUnknown,
+	}
+	if len(required) > 0 {
+		doc["required"] = required
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
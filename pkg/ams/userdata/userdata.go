@@ -0,0 +1,158 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package userdata builds and parses the userdata passed to containers and
+// instances at launch. Userdata is stored as a JSON object of key/value pairs
+// which the application reads during its bootstrap.
+package userdata
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/anbox-cloud/ams-sdk/pkg/ams/constants"
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+)
+
+// Userdata is a set of key/value pairs passed to a container at launch
+type Userdata struct {
+	values map[string]json.RawMessage
+}
+
+// New returns new and empty userdata
+func New() *Userdata {
+	return &Userdata{values: map[string]json.RawMessage{}}
+}
+
+// Parse reads userdata previously created by Encode. Userdata which is not a
+// JSON object is rejected.
+func Parse(data string) (*Userdata, error) {
+	u := New()
+	if len(strings.TrimSpace(data)) == 0 {
+		return u, nil
+	}
+	if err := json.Unmarshal([]byte(data), &u.values); err != nil || u.values == nil {
+		return nil, errs.NewErrMalformed("userdata")
+	}
+	return u, nil
+}
+
+func validKey(key string) bool {
+	return len(key) > 0 && !strings.ContainsAny(key, "\n\r\t")
+}
+
+// Set stores the JSON encoding of value under the given key
+func (u *Userdata) Set(key string, value interface{}) error {
+	if !validKey(key) {
+		return errs.NewInvalidArgument("key")
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode value of %s: %w", key, err)
+	}
+	u.values[key] = b
+	return nil
+}
+
+// SetString stores a string under the given key
+func (u *Userdata) SetString(key, value string) error {
+	return u.Set(key, value)
+}
+
+// SetBinary stores binary data base64 encoded under the given key
+func (u *Userdata) SetBinary(key string, data []byte) error {
+	return u.Set(key, base64.StdEncoding.EncodeToString(data))
+}
+
+// Delete removes the given key
+func (u *Userdata) Delete(key string) {
+	delete(u.values, key)
+}
+
+// Keys returns all keys in alphabetical order
+func (u *Userdata) Keys() []string {
+	keys := make([]string, 0, len(u.values))
+	for key := range u.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Get decodes the value stored under the given key into target. Returns an
+// ErrNotFound if the key does not exist.
+func (u *Userdata) Get(key string, target interface{}) error {
+	raw, ok := u.values[key]
+	if !ok {
+		return errs.NewErrNotFound(fmt.Sprintf("userdata key %s", key))
+	}
+	if err := json.Unmarshal(raw, target); err != nil {
+		return fmt.Errorf("failed to decode value of %s: %w", key, err)
+	}
+	return nil
+}
+
+// GetString returns the string stored under the given key
+func (u *Userdata) GetString(key string) (string, error) {
+	var value string
+	err := u.Get(key, &value)
+	return value, err
+}
+
+// GetBinary returns the binary data stored base64 encoded under the given key
+func (u *Userdata) GetBinary(key string) ([]byte, error) {
+	value, err := u.GetString(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("value of %s is not base64 encoded: %w", key, err)
+	}
+	return data, nil
+}
+
+// Encode returns the userdata as passed to AMS. Keys are sorted so the same
+// userdata always encodes the same. Fails with an ErrInvalidLength if the
+// result exceeds constants.MaxUserdataSize.
+func (u *Userdata) Encode() (string, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for n, key := range u.Keys() {
+		if n > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return "", err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(u.values[key])
+	}
+	buf.WriteByte('}')
+
+	if buf.Len() > constants.MaxUserdataSize {
+		return "", errs.NewErrInvalidLength(fmt.Sprintf("userdata (%d bytes, at most %d allowed)", buf.Len(), constants.MaxUserdataSize))
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,273 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package vcr records the interactions of a client with AMS to fixture files
+// and replays them later. Tests can record a cassette once against a real AMS
+// and then run against it deterministically without a cluster. Requests as
+// well as websocket connections (events, exec sessions) are covered. Sensitive
+// values are redacted before anything is written to a cassette.
+package vcr
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// CassetteVersion is the version of the cassette format written by this
+// package
+const CassetteVersion = 1
+
+// Redacted replaces sensitive values in a cassette
+const Redacted = "[REDACTED]"
+
+// sensitiveNames lists the parts of header, query parameter and JSON field
+// names whose values are never written to a cassette. Names are compared in
+// lower case with dashes and underscores removed.
+var sensitiveNames = []string{
+	"authorization",
+	"certificate",
+	"cookie",
+	"password",
+	"privatekey",
+	"secret",
+	"token",
+	"userdata",
+}
+
+// Request describes a recorded request or websocket handshake
+type Request struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Query  url.Values  `json:"query,omitempty"`
+	Header http.Header `json:"header,omitempty"`
+	Body   *Body       `json:"body,omitempty"`
+}
+
+// Response describes a recorded response or websocket handshake response
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       *Body       `json:"body,omitempty"`
+}
+
+// Body holds a recorded request or response body. Text bodies are stored as
+// they are, other bodies base64 encoded.
+type Body struct {
+	Text   string `json:"text,omitempty"`
+	Binary []byte `json:"binary,omitempty"`
+	// Omitted is set for a request body which was too large to be recorded
+	Omitted bool `json:"omitted,omitempty"`
+}
+
+// newBody returns the body for the given data
+func newBody(data []byte) *Body {
+	if len(data) == 0 {
+		return nil
+	}
+	if utf8.Valid(data) {
+		return &Body{Text: string(data)}
+	}
+	return &Body{Binary: data}
+}
+
+// Bytes returns the recorded data of the body
+func (b *Body) Bytes() []byte {
+	if b == nil {
+		return nil
+	}
+	if b.Binary != nil {
+		return b.Binary
+	}
+	return []byte(b.Text)
+}
+
+// MessageDirection describes whether a websocket message was sent by the
+// client or received from AMS
+type MessageDirection string
+
+const (
+	// MessageSent marks a message the client sent
+	MessageSent MessageDirection = "sent"
+	// MessageReceived marks a message the client received
+	MessageReceived MessageDirection = "received"
+)
+
+// MessageType describes the type of a websocket message
+type MessageType string
+
+const (
+	// MessageText is a text message
+	MessageText MessageType = "text"
+	// MessageBinary is a binary message
+	MessageBinary MessageType = "binary"
+	// MessageClose marks the end of the connection. A close without code
+	// records a connection which ended without close handshake.
+	MessageClose MessageType = "close"
+)
+
+// Message is a single recorded websocket message
+type Message struct {
+	Direction MessageDirection `json:"direction"`
+	Type      MessageType      `json:"type"`
+	// Offset is the time the message was seen after the connection was
+	// established
+	Offset time.Duration `json:"offset"`
+	Body   *Body         `json:"body,omitempty"`
+	// Code is the close code of a close message
+	Code int `json:"code,omitempty"`
+}
+
+// Interaction is a single recorded request with its response. For websocket
+// connections the response holds the handshake response and Messages all
+// messages exchanged over the connection.
+type Interaction struct {
+	Websocket bool      `json:"websocket,omitempty"`
+	Request   Request   `json:"request"`
+	Response  Response  `json:"response"`
+	Messages  []Message `json:"messages,omitempty"`
+}
+
+// Cassette holds the recorded interactions in the order they completed.
+// Websocket connections are ordered by the time they were established.
+type Cassette struct {
+	Version      int            `json:"version"`
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// NewCassette returns an empty cassette
+func NewCassette() *Cassette {
+	return &Cassette{Version: CassetteVersion, Interactions: []*Interaction{}}
+}
+
+// LoadCassette reads the cassette from the file at the given path
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &Cassette{}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("invalid cassette %s: %v", path, err)
+	}
+	if c.Version > CassetteVersion {
+		return nil, fmt.Errorf("cassette %s has unsupported version %d", path, c.Version)
+	}
+	return c, nil
+}
+
+// Save writes the cassette to the file at the given path. Missing parent
+// directories are created.
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// isSensitive returns true if values of the header, query parameter or JSON
+// field with the given name must not be recorded
+func isSensitive(name string) bool {
+	name = strings.ToLower(name)
+	name = strings.NewReplacer("-", "", "_", "").Replace(name)
+	for _, s := range sensitiveNames {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// sanitizeHeader returns a copy of the given headers with sensitive values
+// redacted
+func sanitizeHeader(header http.Header) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+	sanitized := http.Header{}
+	for k, values := range header {
+		if isSensitive(k) {
+			sanitized[k] = []string{Redacted}
+			continue
+		}
+		sanitized[k] = append([]string{}, values...)
+	}
+	return sanitized
+}
+
+// sanitizeQuery returns a copy of the given query parameters with sensitive
+// values redacted
+func sanitizeQuery(query url.Values) url.Values {
+	if len(query) == 0 {
+		return nil
+	}
+	sanitized := url.Values{}
+	for k, values := range query {
+		if isSensitive(k) {
+			sanitized[k] = []string{Redacted}
+			continue
+		}
+		sanitized[k] = append([]string{}, values...)
+	}
+	return sanitized
+}
+
+// sanitizeBody redacts sensitive fields of a JSON body. Other bodies are
+// returned as they are.
+func sanitizeBody(data []byte) []byte {
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if len(data) == 0 || decoder.Decode(&value) != nil || decoder.More() {
+		return data
+	}
+	sanitized, err := json.Marshal(sanitizeValue(value))
+	if err != nil {
+		return data
+	}
+	return sanitized
+}
+
+func sanitizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, field := range v {
+			if isSensitive(k) {
+				v[k] = Redacted
+				continue
+			}
+			v[k] = sanitizeValue(field)
+		}
+	case []interface{}:
+		for n := range v {
+			v[n] = sanitizeValue(v[n])
+		}
+	}
+	return value
+}
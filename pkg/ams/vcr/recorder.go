@@ -0,0 +1,410 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vcr
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	errs "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/errors"
+	restclient "github.com/anbox-cloud/ams-sdk/pkg/ams/shared/rest/client"
+)
+
+const (
+	// DefaultMaxBodySize is the default size up to which request bodies are
+	// recorded
+	DefaultMaxBodySize = 1 << 20
+	// DefaultMaxMessageDelay is the default limit for the time waited between
+	// two replayed websocket messages
+	DefaultMaxMessageDelay = time.Second
+)
+
+// Mode describes whether a recorder records or replays interactions
+type Mode string
+
+const (
+	// ModeAuto replays the cassette if it exists and records it otherwise
+	ModeAuto Mode = "auto"
+	// ModeRecord sends all requests to AMS and records them, replacing an
+	// existing cassette
+	ModeRecord Mode = "record"
+	// ModeReplay answers all requests from the cassette. Nothing is sent to AMS.
+	ModeReplay Mode = "replay"
+)
+
+// Options configures a recorder
+type Options struct {
+	// Mode defines whether interactions are recorded or replayed. Defaults to
+	// ModeAuto.
+	Mode Mode
+	// Sanitize is called for every interaction before the cassette is
+	// written, after the sensitive headers, query parameters and JSON fields
+	// known to the package were redacted. It allows redacting further values.
+	Sanitize func(i *Interaction)
+	// Match decides whether a recorded interaction answers the given request
+	// during replay. The request is sanitized the same way recorded requests
+	// are. By default the method, path and query parameters have to be equal.
+	Match func(r *Request, recorded *Interaction) bool
+	// AllowRepeats lets a request be answered by an interaction which was
+	// already replayed once no unused interaction matches. This is required
+	// for polling loops whose number of iterations depends on timing.
+	AllowRepeats bool
+	// MaxBodySize limits the size of recorded request bodies. Larger bodies,
+	// e.g. package uploads, are marked as omitted. Defaults to
+	// DefaultMaxBodySize.
+	MaxBodySize int64
+	// MaxMessageDelay limits the time waited between two replayed websocket
+	// messages. Messages are replayed with the delays they were recorded with
+	// so that handlers have a chance to be registered in time. Defaults to
+	// DefaultMaxMessageDelay, a negative value replays messages without delay.
+	MaxMessageDelay time.Duration
+}
+
+// Recorder records the interactions of a client with AMS to a cassette or
+// replays them from it. It is hooked into a client through the options
+// returned by ClientOptions.
+type Recorder struct {
+	path string
+	mode Mode
+	opts Options
+
+	lock     sync.Mutex
+	cassette *Cassette
+	used     []bool
+	closers  []io.Closer
+
+	wg       sync.WaitGroup
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// New returns a recorder for the cassette at the given path. In replay mode
+// the cassette is loaded immediately.
+func New(path string, opts *Options) (*Recorder, error) {
+	if len(path) == 0 {
+		return nil, errs.NewInvalidArgument("path")
+	}
+
+	r := &Recorder{path: path, stop: make(chan struct{})}
+	if opts != nil {
+		r.opts = *opts
+	}
+	if r.opts.MaxBodySize <= 0 {
+		r.opts.MaxBodySize = DefaultMaxBodySize
+	}
+	if r.opts.MaxMessageDelay == 0 {
+		r.opts.MaxMessageDelay = DefaultMaxMessageDelay
+	}
+
+	r.mode = r.opts.Mode
+	switch r.mode {
+	case "", ModeAuto:
+		r.mode = ModeRecord
+		if _, err := os.Stat(path); err == nil {
+			r.mode = ModeReplay
+		}
+	case ModeRecord, ModeReplay:
+	default:
+		return nil, errs.NewInvalidArgument("mode")
+	}
+
+	if r.mode == ModeReplay {
+		cassette, err := LoadCassette(path)
+		if err != nil {
+			return nil, err
+		}
+		r.cassette = cassette
+		r.used = make([]bool, len(cassette.Interactions))
+	} else {
+		r.cassette = NewCassette()
+	}
+
+	return r, nil
+}
+
+// Mode returns whether the recorder records or replays. It never returns
+// ModeAuto.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// ClientOptions returns the options which hook the recorder into a client
+func (r *Recorder) ClientOptions() []restclient.Option {
+	return []restclient.Option{
+		restclient.WithMiddleware(r.Middleware()),
+		restclient.WithWebsocketDialFunc(r.DialWebsocket),
+	}
+}
+
+// Middleware returns the middleware recording or replaying requests. It
+// should be the last middleware of the client so that it sees requests the
+// way they are sent to AMS.
+func (r *Recorder) Middleware() restclient.Middleware {
+	return func(next restclient.Doer) restclient.Doer {
+		return restclient.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			if r.mode == ModeReplay {
+				return r.replay(req)
+			}
+			return r.record(next, req)
+		})
+	}
+}
+
+// Cassette returns the cassette of the recorder. In record mode it holds the
+// interactions recorded so far and must not be modified before Stop returns.
+func (r *Recorder) Cassette() *Cassette {
+	return r.cassette
+}
+
+// Unused returns the recorded interactions which were not replayed. A non
+// empty result at the end of a test means the code under test sent fewer
+// requests than it did while recording.
+func (r *Recorder) Unused() []*Interaction {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	unused := []*Interaction{}
+	for n, used := range r.used {
+		if !used {
+			unused = append(unused, r.cassette.Interactions[n])
+		}
+	}
+	return unused
+}
+
+// Stop closes all websocket connections which are still open and, in record
+// mode, writes the cassette. Interactions happening after Stop are not
+// recorded.
+func (r *Recorder) Stop() error {
+	first := false
+	r.stopOnce.Do(func() {
+		first = true
+		close(r.stop)
+	})
+	if !first {
+		return nil
+	}
+
+	r.lock.Lock()
+	closers := r.closers
+	r.closers = nil
+	r.lock.Unlock()
+	for _, c := range closers {
+		c.Close()
+	}
+	r.wg.Wait()
+
+	if r.mode != ModeRecord {
+		return nil
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.opts.Sanitize != nil {
+		for _, i := range r.cassette.Interactions {
+			r.opts.Sanitize(i)
+		}
+	}
+	return r.cassette.Save(r.path)
+}
+
+// stopped returns true once Stop was called
+func (r *Recorder) stopped() bool {
+	select {
+	case <-r.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// add appends the given interaction to the cassette
+func (r *Recorder) add(i *Interaction) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if !r.stopped() {
+		r.cassette.Interactions = append(r.cassette.Interactions, i)
+	}
+}
+
+// record sends the request to AMS and records it with its response
+func (r *Recorder) record(next restclient.Doer, req *http.Request) (*http.Response, error) {
+	// Compressed responses could not be sanitized, so ask for plain ones.
+	// The client only decompresses responses which announce an encoding.
+	req.Header.Set("Accept-Encoding", "identity")
+
+	recorded, err := r.newRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := next.Do(req)
+	if err != nil {
+		// Transport errors cannot be replayed
+		return nil, err
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+
+	r.add(&Interaction{
+		Request:  *recorded,
+		Response: newResponse(resp, data),
+	})
+	return resp, nil
+}
+
+// replay answers the request with the next matching recorded interaction
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	recorded, err := r.newRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if req.Body != nil {
+		// Drain the body so that progress reporting of uploads completes
+		io.Copy(io.Discard, req.Body)
+		req.Body.Close()
+	}
+
+	i, err := r.next(recorded, false)
+	if err != nil {
+		return nil, err
+	}
+	return i.Response.httpResponse(req), nil
+}
+
+// next returns the first matching interaction which was not replayed yet
+func (r *Recorder) next(req *Request, websocket bool) (*Interaction, error) {
+	match := r.opts.Match
+	if match == nil {
+		match = matchRequest
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var repeat *Interaction
+	for n, i := range r.cassette.Interactions {
+		if i.Websocket != websocket || !match(req, i) {
+			continue
+		}
+		if !r.used[n] {
+			r.used[n] = true
+			return i, nil
+		}
+		repeat = i
+	}
+	if repeat != nil && r.opts.AllowRepeats {
+		return repeat, nil
+	}
+
+	target := req.Path
+	if len(req.Query) > 0 {
+		target = fmt.Sprintf("%s?%s", target, req.Query.Encode())
+	}
+	return nil, errs.NewErrNotFound(fmt.Sprintf("recorded interaction for %s %s", req.Method, target))
+}
+
+// matchRequest matches requests by method, path and query parameters
+func matchRequest(req *Request, recorded *Interaction) bool {
+	return req.Method == recorded.Request.Method &&
+		req.Path == recorded.Request.Path &&
+		req.Query.Encode() == recorded.Request.Query.Encode()
+}
+
+// newRequest returns the sanitized record of the given request. The body is
+// read up to the maximum recorded size and restored afterwards.
+func (r *Recorder) newRequest(req *http.Request) (*Request, error) {
+	recorded := sanitizedRequest(req.Method, req.URL, req.Header)
+	if req.Body == nil || req.Body == http.NoBody {
+		return recorded, nil
+	}
+
+	data, err := io.ReadAll(io.LimitReader(req.Body, r.opts.MaxBodySize+1))
+	if err != nil {
+		return nil, err
+	}
+	req.Body = readCloser{
+		Reader: io.MultiReader(bytes.NewReader(data), req.Body),
+		Closer: req.Body,
+	}
+	if int64(len(data)) > r.opts.MaxBodySize {
+		recorded.Body = &Body{Omitted: true}
+	} else {
+		recorded.Body = newBody(sanitizeBody(data))
+	}
+	return recorded, nil
+}
+
+// sanitizedRequest returns the record of a request without body
+func sanitizedRequest(method string, u *url.URL, header http.Header) *Request {
+	return &Request{
+		Method: method,
+		Path:   u.Path,
+		Query:  sanitizeQuery(u.Query()),
+		Header: sanitizeHeader(header),
+	}
+}
+
+// newResponse returns the sanitized record of the given response
+func newResponse(resp *http.Response, data []byte) Response {
+	header := sanitizeHeader(resp.Header)
+	// The sanitized body can differ in size
+	delete(header, "Content-Length")
+	return Response{
+		StatusCode: resp.StatusCode,
+		Header:     header,
+		Body:       newBody(sanitizeBody(data)),
+	}
+}
+
+// httpResponse returns the recorded response as answer to the given request
+func (resp *Response) httpResponse(req *http.Request) *http.Response {
+	data := resp.Body.Bytes()
+	header := http.Header{}
+	for k, values := range resp.Header {
+		header[k] = append([]string{}, values...)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode)),
+		StatusCode:    resp.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(data)),
+		ContentLength: int64(len(data)),
+		Request:       req,
+	}
+}
+
+// readCloser combines a reader with the closer of the original body
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,315 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vcr
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// closeTimeout limits the time spent on the close handshake of a connection
+const closeTimeout = time.Second
+
+// DialWebsocket establishes websocket connections for the client. In record
+// mode the connection to AMS is established with the dialer of the client
+// and all messages are recorded while they are relayed. In replay mode the
+// recorded messages are played back over a local connection.
+func (r *Recorder) DialWebsocket(ctx context.Context, dialer *websocket.Dialer, rawURL string, headers http.Header) (*websocket.Conn, *http.Response, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	req := sanitizedRequest(http.MethodGet, u, headers)
+
+	if r.mode == ModeReplay {
+		return r.replayWebsocket(ctx, req)
+	}
+	return r.recordWebsocket(ctx, dialer, rawURL, headers, req)
+}
+
+// recordWebsocket connects to AMS and relays and records all messages
+func (r *Recorder) recordWebsocket(ctx context.Context, dialer *websocket.Dialer, rawURL string, headers http.Header, req *Request) (*websocket.Conn, *http.Response, error) {
+	upstream, resp, err := dialer.DialContext(ctx, rawURL, headers)
+	if err != nil {
+		if resp != nil {
+			// A rejected handshake can be replayed
+			data, _ := io.ReadAll(resp.Body)
+			resp.Body = io.NopCloser(bytes.NewReader(data))
+			r.add(&Interaction{Websocket: true, Request: *req, Response: newResponse(resp, data)})
+		}
+		return nil, resp, err
+	}
+
+	local, remote, err := localWebsocket(ctx)
+	if err != nil {
+		upstream.Close()
+		return nil, nil, err
+	}
+
+	i := &Interaction{Websocket: true, Request: *req, Response: newResponse(resp, nil)}
+	r.add(i)
+	if !r.track(upstream, remote) {
+		local.Close()
+		return nil, nil, errors.New("recorder was stopped")
+	}
+
+	start := time.Now()
+	r.wg.Add(2)
+	go r.relay(i, start, upstream, remote, MessageReceived)
+	go r.relay(i, start, remote, upstream, MessageSent)
+
+	return local, resp, nil
+}
+
+// track registers the given connections to be closed by Stop. It returns
+// false and closes them if the recorder was already stopped.
+func (r *Recorder) track(conns ...*websocket.Conn) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.stopped() {
+		for _, c := range conns {
+			c.Close()
+		}
+		return false
+	}
+	for _, c := range conns {
+		r.closers = append(r.closers, c)
+	}
+	return true
+}
+
+// relay forwards all messages read from one connection to the other and
+// records them with the given direction
+func (r *Recorder) relay(i *Interaction, start time.Time, from, to *websocket.Conn, direction MessageDirection) {
+	defer r.wg.Done()
+	defer to.Close()
+	defer from.Close()
+
+	for {
+		messageType, data, err := from.ReadMessage()
+		if err != nil {
+			msg := Message{Direction: direction, Type: MessageClose, Offset: time.Since(start)}
+			var closeErr *websocket.CloseError
+			if errors.As(err, &closeErr) && closeErr.Code != websocket.CloseAbnormalClosure {
+				msg.Code = closeErr.Code
+				msg.Body = newBody([]byte(closeErr.Text))
+				to.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(closeErr.Code, closeErr.Text),
+					time.Now().Add(closeTimeout))
+			}
+			r.addMessage(i, msg)
+			return
+		}
+
+		msg := Message{Direction: direction, Type: MessageBinary, Offset: time.Since(start), Body: newBody(data)}
+		if messageType == websocket.TextMessage {
+			msg.Type = MessageText
+			msg.Body = newBody(sanitizeBody(data))
+		}
+		r.addMessage(i, msg)
+
+		if err := to.WriteMessage(messageType, data); err != nil {
+			return
+		}
+	}
+}
+
+// addMessage appends the message to the given interaction. Only the first
+// close message is kept, the other side of a relay ends as a consequence.
+func (r *Recorder) addMessage(i *Interaction, msg Message) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.stopped() && msg.Type != MessageClose {
+		return
+	}
+	if n := len(i.Messages); n > 0 && i.Messages[n-1].Type == MessageClose {
+		return
+	}
+	i.Messages = append(i.Messages, msg)
+}
+
+// replayWebsocket plays back the recorded messages of the next matching
+// websocket connection
+func (r *Recorder) replayWebsocket(ctx context.Context, req *Request) (*websocket.Conn, *http.Response, error) {
+	i, err := r.next(req, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp := i.Response.httpResponse(nil)
+	if i.Response.StatusCode != http.StatusSwitchingProtocols {
+		return nil, resp, websocket.ErrBadHandshake
+	}
+
+	local, remote, err := localWebsocket(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !r.track(remote) {
+		local.Close()
+		return nil, nil, errors.New("recorder was stopped")
+	}
+
+	r.wg.Add(1)
+	go r.play(i, remote)
+
+	return local, resp, nil
+}
+
+// play sends the received messages of the given interaction over the
+// connection. For every sent message it waits until the client sent one, so
+// that responses do not arrive before the requests they answer.
+func (r *Recorder) play(i *Interaction, conn *websocket.Conn) {
+	defer r.wg.Done()
+	defer conn.Close()
+
+	received := make(chan struct{})
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+			select {
+			case received <- struct{}{}:
+			case <-r.stop:
+				return
+			}
+		}
+	}()
+
+	// waitClosed discards further messages until the client closed the
+	// connection
+	waitClosed := func(timeout <-chan time.Time) {
+		for {
+			select {
+			case <-received:
+			case <-closed:
+				return
+			case <-timeout:
+				return
+			case <-r.stop:
+				return
+			}
+		}
+	}
+
+	last := time.Duration(0)
+	for _, msg := range i.Messages {
+		delay := msg.Offset - last
+		last = msg.Offset
+
+		if msg.Direction == MessageSent {
+			if msg.Type == MessageClose {
+				waitClosed(nil)
+				return
+			}
+			select {
+			case <-received:
+			case <-closed:
+				return
+			case <-r.stop:
+				return
+			}
+			continue
+		}
+
+		if r.opts.MaxMessageDelay < 0 {
+			delay = 0
+		} else if delay > r.opts.MaxMessageDelay {
+			delay = r.opts.MaxMessageDelay
+		}
+		if delay > 0 {
+			select {
+			case <-time.After(delay):
+			case <-closed:
+				return
+			case <-r.stop:
+				return
+			}
+		}
+
+		switch msg.Type {
+		case MessageClose:
+			if msg.Code != 0 {
+				conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(msg.Code, string(msg.Body.Bytes())),
+					time.Now().Add(closeTimeout))
+				waitClosed(time.After(closeTimeout))
+			}
+			return
+		case MessageBinary:
+			if err := conn.WriteMessage(websocket.BinaryMessage, msg.Body.Bytes()); err != nil {
+				return
+			}
+		default:
+			if err := conn.WriteMessage(websocket.TextMessage, msg.Body.Bytes()); err != nil {
+				return
+			}
+		}
+	}
+
+	// All messages were played, the client decides when the connection ends
+	waitClosed(nil)
+}
+
+// localWebsocket returns both ends of a websocket connection over the
+// loopback interface
+func localWebsocket(ctx context.Context) (*websocket.Conn, *websocket.Conn, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer listener.Close()
+
+	conns := make(chan *websocket.Conn, 1)
+	upgrader := websocket.Upgrader{}
+	server := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			conn, err := upgrader.Upgrade(w, req, nil)
+			if err != nil {
+				return
+			}
+			conns <- conn
+		}),
+	}
+	go server.Serve(listener)
+
+	dialer := &websocket.Dialer{}
+	local, _, err := dialer.DialContext(ctx, "ws://"+listener.Addr().String()+"/", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	select {
+	case remote := <-conns:
+		return local, remote, nil
+	case <-ctx.Done():
+		local.Close()
+		return nil, nil, ctx.Err()
+	}
+}
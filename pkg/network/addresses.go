@@ -0,0 +1,155 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package network
+
+import (
+	"fmt"
+	"net"
+	"path/filepath"
+)
+
+// AddressOptions controls which addresses ListAddresses returns. Loopback
+// addresses are never returned.
+type AddressOptions struct {
+	// IncludeIPv6 includes IPv6 addresses. By default only IPv4 addresses
+	// are returned.
+	IncludeIPv6 bool
+	// IncludeLinkLocal includes link-local unicast addresses
+	IncludeLinkLocal bool
+	// Interfaces limits the result to the interfaces matching one of the
+	// given names. Shell patterns like "eth*" are supported.
+	Interfaces []string
+	// ExcludeInterfaces drops the addresses of the interfaces matching one of
+	// the given names. Shell patterns like "veth*" are supported.
+	ExcludeInterfaces []string
+	// ExcludeDownInterfaces drops the addresses of interfaces which are not up
+	ExcludeDownInterfaces bool
+	// Networks limits the result to addresses within one of the given
+	// networks in CIDR notation, e.g. "10.0.0.0/8"
+	Networks []string
+}
+
+// Address describes a network address of the host
+type Address struct {
+	// Interface is the name of the interface the address is assigned to
+	Interface string
+	// IP is the address itself
+	IP net.IP
+	// Network is the network the address is part of
+	Network *net.IPNet
+}
+
+// ListAddresses returns the network addresses the host has which match the
+// given options. A nil options value behaves like ListAvailableAddresses.
+func ListAddresses(opts *AddressOptions) ([]Address, error) {
+	if opts == nil {
+		opts = &AddressOptions{}
+	}
+
+	networks := []*net.IPNet{}
+	for _, cidr := range opts.Networks {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid network %q: %w", cidr, err)
+		}
+		networks = append(networks, network)
+	}
+
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	ret := []Address{}
+	for _, iface := range ifaces {
+		if len(opts.Interfaces) > 0 && !matchInterface(iface.Name, opts.Interfaces) {
+			continue
+		}
+		if matchInterface(iface.Name, opts.ExcludeInterfaces) {
+			continue
+		}
+		if opts.ExcludeDownInterfaces && iface.Flags&net.FlagUp == 0 {
+			continue
+		}
+
+		addrs, err := iface.Addrs()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, addr := range addrs {
+			ipnet, ok := addr.(*net.IPNet)
+			if !ok || !opts.includes(ipnet.IP, networks) {
+				continue
+			}
+			ret = append(ret, Address{
+				Interface: iface.Name,
+				IP:        ipnet.IP,
+				Network:   &net.IPNet{IP: ipnet.IP.Mask(ipnet.Mask), Mask: ipnet.Mask},
+			})
+		}
+	}
+
+	return ret, nil
+}
+
+// ListAvailableAddressesWithOptions returns the network addresses the host
+// has which match the given options
+func ListAvailableAddressesWithOptions(opts *AddressOptions) ([]string, error) {
+	addrs, err := ListAddresses(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		ret = append(ret, addr.IP.String())
+	}
+	return ret, nil
+}
+
+func (o *AddressOptions) includes(ip net.IP, networks []*net.IPNet) bool {
+	if ip.IsLoopback() {
+		return false
+	}
+	if ip.IsLinkLocalUnicast() && !o.IncludeLinkLocal {
+		return false
+	}
+	if ip.To4() == nil && !o.IncludeIPv6 {
+		return false
+	}
+	if len(networks) == 0 {
+		return true
+	}
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchInterface(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, name); ok || pattern == name {
+			return true
+		}
+	}
+	return false
+}
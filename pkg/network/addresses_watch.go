@@ -0,0 +1,167 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package network
+
+import (
+	"context"
+	"time"
+)
+
+var (
+	// addressPollInterval is the interval in which addresses are compared on
+	// platforms without change notifications
+	addressPollInterval = 5 * time.Second
+	// addressSettleTime collects bursts of change notifications, e.g. when
+	// an interface goes down with several addresses, into a single update
+	addressSettleTime = 200 * time.Millisecond
+)
+
+// AddressEventType describes the kind of an address change
+type AddressEventType string
+
+const (
+	// AddressAdded is emitted for an address which became available
+	AddressAdded AddressEventType = "added"
+	// AddressRemoved is emitted for an address which is not available anymore
+	AddressRemoved AddressEventType = "removed"
+)
+
+// AddressEvent describes a change of the network addresses of the host
+type AddressEvent struct {
+	Type    AddressEventType
+	Address Address
+}
+
+// WatchAddresses emits an event whenever an address matching the given
+// options is added to or removed from the host, e.g. because an interface
+// went up or down or a DHCP lease changed. Addresses present when watching
+// starts are not emitted, use ListAddresses to retrieve them. On Linux
+// changes are detected through netlink, other platforms compare the
+// addresses periodically. The returned channel is closed when the context is
+// done.
+func WatchAddresses(ctx context.Context, opts *AddressOptions) (<-chan AddressEvent, error) {
+	current, err := ListAddresses(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := addressChanges(ctx)
+	if err != nil {
+		changes = pollAddressChanges(ctx)
+	}
+
+	ch := make(chan AddressEvent)
+	go func() {
+		defer close(ch)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-changes:
+				if !ok {
+					// Notifications broke down, continue by polling
+					changes = pollAddressChanges(ctx)
+					continue
+				}
+			}
+
+			settle := time.NewTimer(addressSettleTime)
+		drain:
+			for {
+				select {
+				case _, ok := <-changes:
+					if !ok {
+						// A closed channel would make this loop spin until
+						// the timer fires
+						changes = pollAddressChanges(ctx)
+					}
+				case <-settle.C:
+					break drain
+				case <-ctx.Done():
+					settle.Stop()
+					return
+				}
+			}
+
+			next, err := ListAddresses(opts)
+			if err != nil {
+				continue
+			}
+			for _, event := range diffAddresses(current, next) {
+				select {
+				case ch <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+			current = next
+		}
+	}()
+
+	return ch, nil
+}
+
+func pollAddressChanges(ctx context.Context) <-chan struct{} {
+	ch := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(addressPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				select {
+				case ch <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+func addressKey(addr Address) string {
+	return addr.Interface + "/" + addr.IP.String() + "/" + addr.Network.String()
+}
+
+func diffAddresses(old, new []Address) []AddressEvent {
+	oldKeys := map[string]bool{}
+	for _, addr := range old {
+		oldKeys[addressKey(addr)] = true
+	}
+	newKeys := map[string]bool{}
+	for _, addr := range new {
+		newKeys[addressKey(addr)] = true
+	}
+
+	events := []AddressEvent{}
+	for _, addr := range old {
+		if !newKeys[addressKey(addr)] {
+			events = append(events, AddressEvent{Type: AddressRemoved, Address: addr})
+		}
+	}
+	for _, addr := range new {
+		if !oldKeys[addressKey(addr)] {
+			events = append(events, AddressEvent{Type: AddressAdded, Address: addr})
+		}
+	}
+	return events
+}
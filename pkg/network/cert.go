@@ -69,7 +69,7 @@ func GetRemoteCertificateWithTLSConfig(address string, tlsConfig *tls.Config) (*
 	tlsConfig.InsecureSkipVerify = true
 	tr := &http.Transport{
 		TLSClientConfig:   tlsConfig,
-		Dial:              RFC3493Dialer,
+		DialContext:       DefaultDialer.DialContext,
 		DisableKeepAlives: true,
 		IdleConnTimeout:   30 * time.Second,
 		MaxIdleConns:      1,
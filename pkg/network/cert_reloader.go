@@ -0,0 +1,169 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package network
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	defaultCertCheckInterval = 10 * time.Second
+)
+
+// CertLoader loads a client certificate
+type CertLoader func() (*tls.Certificate, error)
+
+// CertReloader provides the client certificate for TLS handshakes and reloads
+// it when it changes. Existing connections are not affected by a reload, the
+// new certificate is used for all following handshakes.
+type CertReloader struct {
+	// OnError is called when a certificate could not be reloaded. The
+	// previously loaded certificate stays in use. Optional.
+	OnError func(err error)
+
+	load     CertLoader
+	changed  func() (bool, error)
+	interval time.Duration
+
+	lock      sync.Mutex
+	cert      *tls.Certificate
+	lastCheck time.Time
+}
+
+// NewCertReloader returns a reloader for the client certificate and key at
+// the given paths. The files are checked for modifications at most once per
+// interval when a handshake requires the certificate. An interval of zero
+// defaults to 10 seconds.
+func NewCertReloader(certFile, keyFile string, interval time.Duration) (*CertReloader, error) {
+	var certMod, keyMod time.Time
+	changed := func() (bool, error) {
+		certInfo, err := os.Stat(certFile)
+		if err != nil {
+			return false, err
+		}
+		keyInfo, err := os.Stat(keyFile)
+		if err != nil {
+			return false, err
+		}
+		if certInfo.ModTime().Equal(certMod) && keyInfo.ModTime().Equal(keyMod) {
+			return false, nil
+		}
+		certMod, keyMod = certInfo.ModTime(), keyInfo.ModTime()
+		return true, nil
+	}
+	load := func() (*tls.Certificate, error) {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		return &cert, nil
+	}
+	return newCertReloader(load, changed, interval)
+}
+
+// NewCertReloaderWithLoader returns a reloader which calls the given loader at
+// most once per interval to fetch the current certificate. An interval of zero
+// defaults to 10 seconds.
+func NewCertReloaderWithLoader(load CertLoader, interval time.Duration) (*CertReloader, error) {
+	if load == nil {
+		return nil, fmt.Errorf("no certificate loader given")
+	}
+	changed := func() (bool, error) { return true, nil }
+	return newCertReloader(load, changed, interval)
+}
+
+func newCertReloader(load CertLoader, changed func() (bool, error), interval time.Duration) (*CertReloader, error) {
+	if interval <= 0 {
+		interval = defaultCertCheckInterval
+	}
+	r := &CertReloader{load: load, changed: changed, interval: interval}
+	if _, err := changed(); err != nil {
+		return nil, err
+	}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload loads the certificate immediately
+func (r *CertReloader) Reload() error {
+	cert, err := r.load()
+	if err != nil {
+		return err
+	}
+	if cert == nil {
+		return fmt.Errorf("no certificate loaded")
+	}
+
+	r.lock.Lock()
+	r.cert = cert
+	r.lastCheck = time.Now()
+	r.lock.Unlock()
+	return nil
+}
+
+// Certificate returns the currently loaded certificate, reloading it first if
+// the check interval passed and the certificate changed
+func (r *CertReloader) Certificate() *tls.Certificate {
+	r.lock.Lock()
+	due := time.Since(r.lastCheck) >= r.interval
+	if due {
+		// Only one handshake performs the check, all others continue to
+		// use the current certificate
+		r.lastCheck = time.Now()
+	}
+	cert := r.cert
+	r.lock.Unlock()
+
+	if !due {
+		return cert
+	}
+
+	changed, err := r.changed()
+	if err == nil && changed {
+		err = r.Reload()
+	}
+	if err != nil {
+		if r.OnError != nil {
+			r.OnError(err)
+		}
+		return cert
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.cert
+}
+
+// GetClientCertificate implements the tls.Config.GetClientCertificate callback
+func (r *CertReloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return r.Certificate(), nil
+}
+
+// Apply configures the given TLS config to use the certificate of the reloader
+// for all client handshakes
+func (r *CertReloader) Apply(tlsConfig *tls.Config) {
+	tlsConfig.Certificates = nil
+	tlsConfig.GetClientCertificate = r.GetClientCertificate
+}
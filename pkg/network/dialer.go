@@ -0,0 +1,203 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package network
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+const (
+	defaultDialTimeout   = 10 * time.Second
+	defaultFallbackDelay = 300 * time.Millisecond
+)
+
+// Dialer connects to hosts which resolve to multiple addresses. IPv6 and IPv4
+// addresses are dialed in parallel as described by RFC 6555 ("Happy
+// Eyeballs"): the addresses of the family which is returned first by the
+// resolver are tried one after another and, if no connection is established
+// within the fallback delay, the addresses of the other family are tried
+// concurrently. The first established connection is used.
+type Dialer struct {
+	// Timeout limits a single connection attempt. Defaults to 10 seconds.
+	Timeout time.Duration
+	// FallbackDelay is the time to wait before the addresses of the other
+	// family are tried. Defaults to 300 milliseconds. A negative value
+	// disables parallel dialing and all addresses are tried serially.
+	FallbackDelay time.Duration
+	// KeepAlive is the keep-alive period of established connections. Zero
+	// uses the default of net.Dialer.
+	KeepAlive time.Duration
+	// Resolver is used to resolve host names. Defaults to net.DefaultResolver.
+	Resolver *net.Resolver
+	// LookupHost resolves host names instead of the Resolver if set
+	LookupHost LookupFunc
+	// Cache keeps resolved addresses if set
+	Cache *DNSCache
+}
+
+// DefaultDialer is the dialer used by RFC3493Dialer
+var DefaultDialer = &Dialer{}
+
+// Dial connects to the address on the named network
+func (d *Dialer) Dial(network, address string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, address)
+}
+
+// DialContext connects to the address on the named network using the given context
+func (d *Dialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs, err := d.lookupHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	primary, fallback := splitAddressFamilies(addrs)
+	delay := d.FallbackDelay
+	if delay == 0 {
+		delay = defaultFallbackDelay
+	}
+
+	var conn net.Conn
+	if delay < 0 || len(fallback) == 0 {
+		conn, err = d.dialSerial(ctx, network, address, port, append(primary, fallback...))
+	} else {
+		conn, err = d.dialParallel(ctx, network, address, port, primary, fallback, delay)
+	}
+	if err != nil && d.Cache != nil && ctx.Err() == nil {
+		// The cached addresses may be outdated
+		d.Cache.Remove(host)
+	}
+	return conn, err
+}
+
+type dialResult struct {
+	conn net.Conn
+	err  error
+}
+
+func (d *Dialer) dialParallel(ctx context.Context, network, address, port string, primary, fallback []string, delay time.Duration) (net.Conn, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan dialResult, 2)
+	dial := func(addrs []string) {
+		go func() {
+			conn, err := d.dialSerial(ctx, network, address, port, addrs)
+			results <- dialResult{conn: conn, err: err}
+		}()
+	}
+
+	dial(primary)
+	pending := 1
+	fallbackStarted := false
+	startFallback := func() {
+		if !fallbackStarted {
+			fallbackStarted = true
+			pending++
+			dial(fallback)
+		}
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	var firstErr error
+	for {
+		select {
+		case <-timer.C:
+			startFallback()
+		case result := <-results:
+			pending--
+			if result.err == nil {
+				// Close the connection of the other family in case
+				// both succeed
+				for ; pending > 0; pending-- {
+					go func() {
+						if other := <-results; other.conn != nil {
+							other.conn.Close()
+						}
+					}()
+				}
+				return result.conn, nil
+			}
+			if firstErr == nil {
+				firstErr = result.err
+			}
+			startFallback()
+			if pending == 0 {
+				return nil, firstErr
+			}
+		}
+	}
+}
+
+func (d *Dialer) dialSerial(ctx context.Context, network, address, port string, addrs []string) (net.Conn, error) {
+	timeout := d.Timeout
+	if timeout <= 0 {
+		timeout = defaultDialTimeout
+	}
+	dialer := net.Dialer{Timeout: timeout, KeepAlive: d.KeepAlive}
+
+	var lastErr error
+	for _, a := range addrs {
+		c, err := dialer.DialContext(ctx, network, net.JoinHostPort(a, port))
+		if err == nil {
+			return c, nil
+		}
+		lastErr = err
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	if lastErr == nil {
+		return nil, fmt.Errorf("Unable to connect to: %s", address)
+	}
+	return nil, fmt.Errorf("Unable to connect to: %s: %v", address, lastErr)
+}
+
+// splitAddressFamilies splits the addresses into those of the family of the
+// first address and the remaining ones
+func splitAddressFamilies(addrs []string) ([]string, []string) {
+	primary := []string{}
+	fallback := []string{}
+	if len(addrs) == 0 {
+		return primary, fallback
+	}
+
+	isIPv4 := func(addr string) bool {
+		ip := net.ParseIP(addr)
+		return ip != nil && ip.To4() != nil
+	}
+	primaryIsIPv4 := isIPv4(addrs[0])
+	for _, addr := range addrs {
+		if isIPv4(addr) == primaryIsIPv4 {
+			primary = append(primary, addr)
+		} else {
+			fallback = append(fallback, addr)
+		}
+	}
+	return primary, fallback
+}
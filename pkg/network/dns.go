@@ -0,0 +1,132 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package network
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+)
+
+// LookupFunc resolves a host name into its addresses
+type LookupFunc func(ctx context.Context, host string) ([]string, error)
+
+// DNSCache caches the addresses host names resolve to for a fixed time, as
+// the resolver of the standard library does not expose the TTL of records.
+// Concurrent lookups of the same host name share a single query.
+type DNSCache struct {
+	ttl time.Duration
+
+	lock     sync.Mutex
+	entries  map[string]dnsCacheEntry
+	inflight map[string]*dnsLookup
+}
+
+type dnsCacheEntry struct {
+	addrs   []string
+	expires time.Time
+}
+
+type dnsLookup struct {
+	done  chan struct{}
+	addrs []string
+	err   error
+}
+
+// NewDNSCache returns a cache which keeps resolved addresses for the given time
+func NewDNSCache(ttl time.Duration) *DNSCache {
+	return &DNSCache{
+		ttl:      ttl,
+		entries:  map[string]dnsCacheEntry{},
+		inflight: map[string]*dnsLookup{},
+	}
+}
+
+// Lookup returns the cached addresses of the host or resolves them with the
+// given function if they are not cached or expired. Failed lookups are not
+// cached.
+func (c *DNSCache) Lookup(ctx context.Context, host string, lookup LookupFunc) ([]string, error) {
+	c.lock.Lock()
+	if entry, ok := c.entries[host]; ok && time.Now().Before(entry.expires) {
+		c.lock.Unlock()
+		return entry.addrs, nil
+	}
+	if l, ok := c.inflight[host]; ok {
+		c.lock.Unlock()
+		select {
+		case <-l.done:
+			return l.addrs, l.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	l := &dnsLookup{done: make(chan struct{})}
+	c.inflight[host] = l
+	c.lock.Unlock()
+
+	l.addrs, l.err = lookup(ctx, host)
+
+	c.lock.Lock()
+	delete(c.inflight, host)
+	if l.err == nil {
+		c.entries[host] = dnsCacheEntry{addrs: l.addrs, expires: time.Now().Add(c.ttl)}
+	}
+	c.lock.Unlock()
+	close(l.done)
+
+	return l.addrs, l.err
+}
+
+// Remove drops the cached addresses of the host, e.g. after connecting to all
+// of them failed
+func (c *DNSCache) Remove(host string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.entries, host)
+}
+
+// Flush drops all cached addresses
+func (c *DNSCache) Flush() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.entries = map[string]dnsCacheEntry{}
+}
+
+// lookupHost resolves the host with the lookup function, resolver and cache
+// of the dialer
+func (d *Dialer) lookupHost(ctx context.Context, host string) ([]string, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []string{host}, nil
+	}
+
+	lookup := d.LookupHost
+	if lookup == nil {
+		resolver := d.Resolver
+		if resolver == nil {
+			resolver = net.DefaultResolver
+		}
+		lookup = resolver.LookupHost
+	}
+
+	if d.Cache == nil {
+		return lookup(ctx, host)
+	}
+	return d.Cache.Lookup(ctx, host, lookup)
+}
@@ -0,0 +1,286 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package network
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Channel IDs used when the streams of an exec session share a single
+// websocket connection
+const (
+	MuxChannelStdin   uint8 = 0
+	MuxChannelStdout  uint8 = 1
+	MuxChannelStderr  uint8 = 2
+	MuxChannelControl uint8 = 3
+)
+
+// Every binary message of a multiplexed connection starts with a header made
+// of the channel ID and the frame type, followed by the payload
+const (
+	muxHeaderSize = 2
+
+	muxFrameData byte = 0
+	muxFrameEOF  byte = 1
+
+	// Number of received messages queued per stream before reading from the
+	// connection pauses
+	muxStreamQueueSize = 16
+)
+
+// Mux multiplexes several streams over a single websocket connection. Each
+// stream is identified by a channel ID which both sides have to agree on, e.g.
+// MuxChannelStdin for the stdin of an exec session.
+//
+// Received messages are dispatched to the streams by a single goroutine, so a
+// stream which is not read from eventually blocks all other streams.
+type Mux struct {
+	conn *websocket.Conn
+
+	writeLock sync.Mutex
+
+	lock    sync.Mutex
+	streams map[uint8]*MuxStream
+	err     error
+
+	closing   chan struct{}
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// NewMux returns a new multiplexer for the given connection and starts
+// dispatching received messages. The connection must not be used directly
+// afterwards.
+func NewMux(conn *websocket.Conn) *Mux {
+	m := &Mux{
+		conn:    conn,
+		streams: map[uint8]*MuxStream{},
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go m.readLoop()
+	return m
+}
+
+// Stream returns the stream with the given channel ID. Data the peer sent on
+// the channel before the stream was requested is not lost.
+func (m *Mux) Stream(id uint8) *MuxStream {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.stream(id)
+}
+
+func (m *Mux) stream(id uint8) *MuxStream {
+	s, ok := m.streams[id]
+	if !ok {
+		s = &MuxStream{
+			mux:      m,
+			id:       id,
+			incoming: make(chan []byte, muxStreamQueueSize),
+		}
+		m.streams[id] = s
+		if m.err != nil {
+			s.closeIncoming(false)
+		}
+	}
+	return s
+}
+
+// Done returns a channel which is closed once the connection ended
+func (m *Mux) Done() <-chan struct{} {
+	return m.done
+}
+
+// Err returns the error the connection ended with. A normal close by either
+// side is reported as nil.
+func (m *Mux) Err() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if closeErr, ok := m.err.(*websocket.CloseError); ok && closeErr.Code == websocket.CloseNormalClosure {
+		return nil
+	}
+	return m.err
+}
+
+// Close performs the close handshake with the peer and closes the underlying
+// connection. Streams which were not closed by the peer fail on read then.
+func (m *Mux) Close() error {
+	m.closeOnce.Do(func() {
+		close(m.closing)
+		forwardClose(m.conn, nil)
+		select {
+		case <-m.done:
+		case <-time.After(DefaultCloseTimeout):
+		}
+		m.conn.Close()
+	})
+	return nil
+}
+
+func (m *Mux) readLoop() {
+	for {
+		mt, data, err := m.conn.ReadMessage()
+		if err != nil {
+			m.fail(err)
+			return
+		}
+		if mt != websocket.BinaryMessage {
+			continue
+		}
+		if len(data) < muxHeaderSize {
+			m.fail(fmt.Errorf("received multiplexed message without header"))
+			return
+		}
+
+		m.lock.Lock()
+		s := m.stream(data[0])
+		m.lock.Unlock()
+
+		switch data[1] {
+		case muxFrameData:
+			if s.inClosed {
+				// The peer already closed the stream
+				continue
+			}
+			select {
+			case s.incoming <- data[muxHeaderSize:]:
+			case <-m.closing:
+			}
+		case muxFrameEOF:
+			s.closeIncoming(true)
+		default:
+			m.fail(fmt.Errorf("received multiplexed message with unknown type %d", data[1]))
+			return
+		}
+	}
+}
+
+func (m *Mux) fail(err error) {
+	m.lock.Lock()
+	m.err = err
+	for _, s := range m.streams {
+		s.closeIncoming(false)
+	}
+	m.lock.Unlock()
+
+	// Without any reader left the connection cannot be used anymore
+	m.conn.Close()
+	close(m.done)
+}
+
+func (m *Mux) write(id uint8, frameType byte, data []byte) error {
+	m.writeLock.Lock()
+	defer m.writeLock.Unlock()
+
+	msg := make([]byte, muxHeaderSize+len(data))
+	msg[0] = id
+	msg[1] = frameType
+	copy(msg[muxHeaderSize:], data)
+	return m.conn.WriteMessage(websocket.BinaryMessage, msg)
+}
+
+// MuxStream is a single stream of a multiplexed connection
+type MuxStream struct {
+	mux *Mux
+	id  uint8
+
+	incoming  chan []byte
+	pending   []byte
+	inOnce    sync.Once
+	inClosed  bool
+	remoteEOF bool
+
+	writeLock   sync.Mutex
+	writeClosed bool
+}
+
+func (s *MuxStream) closeIncoming(eof bool) {
+	s.inOnce.Do(func() {
+		s.remoteEOF = eof
+		s.inClosed = true
+		close(s.incoming)
+	})
+}
+
+// ID returns the channel ID of the stream
+func (s *MuxStream) ID() uint8 {
+	return s.id
+}
+
+// Read reads data the peer sent on the stream. It returns io.EOF once the peer
+// closed the stream and all data was read.
+func (s *MuxStream) Read(p []byte) (int, error) {
+	if len(s.pending) == 0 {
+		data, ok := <-s.incoming
+		if !ok {
+			if s.remoteEOF {
+				return 0, io.EOF
+			}
+			if err := s.mux.Err(); err != nil {
+				return 0, err
+			}
+			return 0, io.ErrUnexpectedEOF
+		}
+		s.pending = data
+	}
+
+	n := copy(p, s.pending)
+	s.pending = s.pending[n:]
+	return n, nil
+}
+
+// Write sends data on the stream. Data larger than DefaultStreamBufferSize is
+// split into several messages.
+func (s *MuxStream) Write(p []byte) (int, error) {
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+	if s.writeClosed {
+		return 0, io.ErrClosedPipe
+	}
+
+	written := 0
+	for written < len(p) {
+		n := len(p) - written
+		if n > DefaultStreamBufferSize {
+			n = DefaultStreamBufferSize
+		}
+		if err := s.mux.write(s.id, muxFrameData, p[written:written+n]); err != nil {
+			return written, err
+		}
+		written += n
+	}
+	return written, nil
+}
+
+// Close signals the peer that no more data is sent on the stream. Data can
+// still be read from the stream until the peer closes it as well.
+func (s *MuxStream) Close() error {
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+	if s.writeClosed {
+		return nil
+	}
+	s.writeClosed = true
+	return s.mux.write(s.id, muxFrameEOF, nil)
+}
@@ -28,30 +28,15 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/gorilla/websocket"
 )
 
-// RFC3493Dialer dialer
+// RFC3493Dialer dials the address with the DefaultDialer
 func RFC3493Dialer(network, address string) (net.Conn, error) {
-	host, port, err := net.SplitHostPort(address)
-	if err != nil {
-		return nil, err
-	}
-
-	addrs, err := net.LookupHost(host)
-	if err != nil {
-		return nil, err
-	}
-	for _, a := range addrs {
-		c, err := net.DialTimeout(network, net.JoinHostPort(a, port), 10*time.Second)
-		if err != nil {
-			continue
-		}
-		return c, err
-	}
-	return nil, fmt.Errorf("Unable to connect to: " + address)
+	return DefaultDialer.Dial(network, address)
 }
 
 // InitTLSConfig returns a tls.Config populated with TLS1.3
@@ -87,14 +72,41 @@ func finalizeTLSConfig(tlsConfig *tls.Config, tlsRemoteCert *x509.Certificate) {
 
 // GetTLSConfig returns a tls 1.2 config
 func GetTLSConfig(tlsClientCertFile, tlsClientKeyFile, tlsClientCAFile string, tlsRemoteCert *x509.Certificate) (*tls.Config, error) {
-	tlsConfig := InitTLSConfig()
-	return getTLSConfig(tlsClientCertFile, tlsClientKeyFile, tlsClientCAFile, tlsRemoteCert, tlsConfig)
+	opts := &TLSConfigOptions{
+		ClientCertFile: tlsClientCertFile,
+		ClientKeyFile:  tlsClientKeyFile,
+	}
+	if tlsClientCAFile != "" {
+		opts.CAFiles = []string{tlsClientCAFile}
+	}
+	return GetTLSConfigWithOptions(opts, tlsRemoteCert)
 }
 
-func getTLSConfig(tlsClientCertFile, tlsClientKeyFile, tlsClientCAFile string, tlsRemoteCert *x509.Certificate, tlsConfig *tls.Config) (*tls.Config, error) {
+// TLSConfigOptions describe the certificates used by a TLS configuration
+type TLSConfigOptions struct {
+	// ClientCertFile and ClientKeyFile are the paths to the client certificate
+	// and key used for client authentication. Optional.
+	ClientCertFile string
+	ClientKeyFile  string
+	// CAFiles lists files or directories with PEM encoded CA certificates to
+	// trust. For directories all files ending in .crt or .pem are loaded.
+	CAFiles []string
+	// UseSystemCAs adds the CA certificates of CAFiles to the system pool
+	// instead of trusting only them
+	UseSystemCAs bool
+}
+
+// GetTLSConfigWithOptions returns a TLS config with the certificates described
+// by the given options
+func GetTLSConfigWithOptions(opts *TLSConfigOptions, tlsRemoteCert *x509.Certificate) (*tls.Config, error) {
+	if opts == nil {
+		opts = &TLSConfigOptions{}
+	}
+	tlsConfig := InitTLSConfig()
+
 	// Client authentication
-	if tlsClientCertFile != "" && tlsClientKeyFile != "" {
-		cert, err := tls.LoadX509KeyPair(tlsClientCertFile, tlsClientKeyFile)
+	if opts.ClientCertFile != "" && opts.ClientKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.ClientCertFile, opts.ClientKeyFile)
 		if err != nil {
 			return nil, err
 		}
@@ -102,14 +114,21 @@ func getTLSConfig(tlsClientCertFile, tlsClientKeyFile, tlsClientCAFile string, t
 		tlsConfig.Certificates = []tls.Certificate{cert}
 	}
 
-	if tlsClientCAFile != "" {
-		caCertificates, err := os.ReadFile(tlsClientCAFile)
-		if err != nil {
-			return nil, err
+	if len(opts.CAFiles) > 0 || opts.UseSystemCAs {
+		caPool := x509.NewCertPool()
+		if opts.UseSystemCAs {
+			systemPool, err := x509.SystemCertPool()
+			if err != nil {
+				return nil, fmt.Errorf("failed to load system CA certificates: %v", err)
+			}
+			caPool = systemPool
 		}
 
-		caPool := x509.NewCertPool()
-		caPool.AppendCertsFromPEM(caCertificates)
+		for _, caFile := range opts.CAFiles {
+			if err := appendCAFile(caPool, caFile); err != nil {
+				return nil, err
+			}
+		}
 
 		tlsConfig.RootCAs = caPool
 	}
@@ -118,25 +137,48 @@ func getTLSConfig(tlsClientCertFile, tlsClientKeyFile, tlsClientCAFile string, t
 	return tlsConfig, nil
 }
 
-// ListAvailableAddresses returns a list of IPv4 network addresses the host has.
-// It ignores the loopback device
-func ListAvailableAddresses() ([]string, error) {
-	ret := []string{}
-
-	addrs, err := net.InterfaceAddrs()
+// appendCAFile adds the CA certificates of the given file or all certificate
+// files of the given directory to the pool
+func appendCAFile(caPool *x509.CertPool, caFile string) error {
+	info, err := os.Stat(caFile)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	for _, addr := range addrs {
-		if ipnet, ok := addr.(*net.IPNet); ok && !(ipnet.IP.IsLoopback() || ipnet.IP.IsLinkLocalUnicast()) {
-			if ipnet.IP.To4() != nil {
-				ret = append(ret, ipnet.IP.String())
+	files := []string{caFile}
+	if info.IsDir() {
+		files = []string{}
+		entries, err := os.ReadDir(caFile)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			ext := filepath.Ext(entry.Name())
+			if entry.IsDir() || (ext != ".crt" && ext != ".pem") {
+				continue
 			}
+			files = append(files, filepath.Join(caFile, entry.Name()))
 		}
 	}
 
-	return ret, nil
+	for _, f := range files {
+		caCertificates, err := os.ReadFile(f)
+		if err != nil {
+			return err
+		}
+		if !caPool.AppendCertsFromPEM(caCertificates) && !info.IsDir() {
+			return fmt.Errorf("no CA certificates found in %s", f)
+		}
+	}
+	return nil
+}
+
+// ListAvailableAddresses returns a list of IPv4 network addresses the host has.
+// It ignores the loopback device and link-local addresses. See
+// ListAvailableAddressesWithOptions to include IPv6 addresses or filter the
+// result.
+func ListAvailableAddresses() ([]string, error) {
+	return ListAvailableAddressesWithOptions(nil)
 }
 
 // GetLocalIP returns the first non loopback address of the system we're running on
@@ -156,13 +198,108 @@ func GetLocalIP() string {
 	return ""
 }
 
+// DefaultCloseTimeout is the time to wait for the peer to answer a close
+// message before a websocket connection is closed anyway
+const DefaultCloseTimeout = 5 * time.Second
+
+// CloseGracefully performs the websocket close handshake: it sends a close
+// message with the given code and reason, waits until the peer answered with
+// its own close message or the timeout expired and closes the connection
+// afterwards. Data messages received in the meantime are discarded. It must
+// not be called while another goroutine reads from the connection.
+func CloseGracefully(conn *websocket.Conn, code int, reason string, timeout time.Duration) error {
+	defer conn.Close()
+
+	if timeout <= 0 {
+		timeout = DefaultCloseTimeout
+	}
+	deadline := time.Now().Add(timeout)
+
+	closeMsg := websocket.FormatCloseMessage(code, reason)
+	err := conn.WriteControl(websocket.CloseMessage, closeMsg, deadline)
+	if err != nil && err != websocket.ErrCloseSent {
+		return err
+	}
+
+	conn.SetReadDeadline(deadline)
+	for {
+		_, _, err := conn.NextReader()
+		if err == nil {
+			continue
+		}
+		if _, ok := err.(*websocket.CloseError); ok {
+			return nil
+		}
+		return err
+	}
+}
+
+// forwardClose sends a close message to conn mirroring the close error
+// received from its peer
+func forwardClose(conn *websocket.Conn, err error) {
+	code, reason := websocket.CloseNormalClosure, ""
+	if closeErr, ok := err.(*websocket.CloseError); ok && closeErr.Code != websocket.CloseNoStatusReceived {
+		code, reason = closeErr.Code, closeErr.Text
+	}
+	closeMsg := websocket.FormatCloseMessage(code, reason)
+	conn.WriteControl(websocket.CloseMessage, closeMsg, time.Now().Add(DefaultCloseTimeout))
+}
+
+// DefaultStreamBufferSize is the size of the buffer used to copy data between
+// a websocket and a reader or writer
+const DefaultStreamBufferSize = 128 * 1024
+
+// StreamOptions controls how data is copied between a websocket and a reader
+// or writer
+type StreamOptions struct {
+	// BufferSize is the size of the buffer data is copied through. When
+	// sending, it is also the maximum size of a single message. Defaults to
+	// DefaultStreamBufferSize.
+	BufferSize int
+	// MaxMessageSize limits the size of a single received message in bytes.
+	// The connection is closed if a larger message arrives and the receiving
+	// side fails with an *ErrMessageTooLarge. Zero means no limit.
+	MaxMessageSize int64
+	// FragmentSize splits sent data into messages of at most this many bytes,
+	// so a peer with a message size limit can receive it. Defaults to the
+	// buffer size.
+	FragmentSize int
+}
+
+// ErrMessageTooLarge is returned when a peer sent a websocket message larger
+// than the configured limit
+type ErrMessageTooLarge struct {
+	Limit int64
+}
+
+// Error returns the error string
+func (e *ErrMessageTooLarge) Error() string {
+	return fmt.Sprintf("websocket message exceeds the limit of %d bytes", e.Limit)
+}
+
+func (o StreamOptions) bufferSize() int {
+	if o.BufferSize <= 0 {
+		return DefaultStreamBufferSize
+	}
+	return o.BufferSize
+}
+
 // WebsocketSendStream manages the send stream of the websocket
 func WebsocketSendStream(conn *websocket.Conn, r io.Reader, bufferSize int) chan bool {
 	return WebsocketSendStreamWithContext(context.Background(), conn, r, bufferSize)
 }
 
 // WebsocketSendStreamWithContext manages the send stream of the websocket
+// until the reader is drained or the context is done
 func WebsocketSendStreamWithContext(ctx context.Context, conn *websocket.Conn, r io.Reader, bufferSize int) chan bool {
+	return WebsocketSendStreamWithOptions(ctx, conn, r, StreamOptions{BufferSize: bufferSize})
+}
+
+// WebsocketSendStreamWithOptions sends everything read from r as binary
+// messages over the websocket until the reader is drained or the context is
+// done. Reading is at most one buffer ahead of sending, so a slow receiver
+// slows down reading instead of data piling up in memory.
+func WebsocketSendStreamWithOptions(ctx context.Context, conn *websocket.Conn, r io.Reader, opts StreamOptions) chan bool {
 	ch := make(chan bool)
 
 	if r == nil {
@@ -170,97 +307,218 @@ func WebsocketSendStreamWithContext(ctx context.Context, conn *websocket.Conn, r
 		return ch
 	}
 
-	go func(ctx context.Context, conn *websocket.Conn, r io.Reader) {
-		in := ReaderToChannel(r, bufferSize)
-		active := true
-		for active {
+	// Two buffers are passed back and forth between the reader and the
+	// sender so the next chunk can be read while the current one is sent
+	free := make(chan []byte, 2)
+	for n := 0; n < cap(free); n++ {
+		free <- make([]byte, opts.bufferSize())
+	}
+	chunks := make(chan []byte)
+	// Closed once the sender stopped so the reader does not block forever
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(chunks)
+		for {
+			var buf []byte
 			select {
-			case buf, ok := <-in:
-				if !ok {
+			case buf = <-free:
+			case <-stopped:
+				return
+			case <-ctx.Done():
+				return
+			}
 
-					active = false
-					break
+			nr, err := r.Read(buf)
+			if nr > 0 {
+				select {
+				case chunks <- buf[:nr]:
+				case <-stopped:
+					return
+				case <-ctx.Done():
+					return
 				}
+			} else {
+				free <- buf
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
 
-				w, err := conn.NextWriter(websocket.BinaryMessage)
-				if err != nil {
-					log.Printf("Got error getting next writer %s", err)
+	go func() {
+		active := true
+		for active {
+			select {
+			case buf, ok := <-chunks:
+				if !ok {
 					active = false
 					break
 				}
 
-				_, err = w.Write(buf)
-				w.Close()
+				err := writeFragmented(conn, buf, opts.FragmentSize)
+				free <- buf[:cap(buf)]
 				if err != nil {
 					log.Printf("Got err writing %s", err)
+					active = false
 				}
 			case <-ctx.Done():
 				active = false
 			}
 		}
+		close(stopped)
 		ch <- true
-	}(ctx, conn, r)
+	}()
 
 	return ch
 }
 
 // WebsocketRecvStream manages the recv stream of the socket
 func WebsocketRecvStream(w io.Writer, conn *websocket.Conn) chan bool {
+	return WebsocketRecvStreamWithOptions(w, conn, StreamOptions{})
+}
+
+// WebsocketRecvStreamWithContext manages the recv stream of the socket until
+// the connection is closed or the context is done
+func WebsocketRecvStreamWithContext(ctx context.Context, w io.Writer, conn *websocket.Conn) chan bool {
 	ch := make(chan bool)
+	go func() {
+		if err := WebsocketReceiveWithContext(ctx, w, conn, StreamOptions{}); err != nil {
+			log.Printf("Error receiving stream %s", err)
+		}
+		ch <- true
+	}()
+	return ch
+}
 
-	go func(w io.Writer, conn *websocket.Conn) {
-		for {
-			mt, r, err := conn.NextReader()
-			if err != nil {
-				switch err.(type) {
-				case *websocket.CloseError:
-				default:
-				}
-				break
-			}
+// WebsocketRecvStreamWithOptions writes the binary messages received over the
+// websocket to w until the connection is closed or a text message marks the
+// end of the stream. See WebsocketReceive for details.
+func WebsocketRecvStreamWithOptions(w io.Writer, conn *websocket.Conn, opts StreamOptions) chan bool {
+	ch := make(chan bool)
+	go func() {
+		if err := WebsocketReceive(w, conn, opts); err != nil {
+			log.Printf("Error receiving stream %s", err)
+		}
+		ch <- true
+	}()
+	return ch
+}
 
-			if mt == websocket.CloseMessage {
-				log.Printf("Got close message for reader")
-				break
-			}
+// WebsocketReceive writes the binary messages received over the websocket to
+// w until the connection is closed or a text message marks the end of the
+// stream. Messages are copied through a bounded buffer, so large messages are
+// not held in memory and a slow writer slows down reading. A message
+// exceeding opts.MaxMessageSize fails with an *ErrMessageTooLarge. The
+// connection being closed by the peer is not considered an error.
+func WebsocketReceive(w io.Writer, conn *websocket.Conn, opts StreamOptions) error {
+	return WebsocketReceiveWithContext(context.Background(), w, conn, opts)
+}
 
-			if mt == websocket.TextMessage {
-				log.Printf("got message barrier (type %d)", mt)
-				break
-			}
+// WebsocketReceiveWithContext behaves like WebsocketReceive but stops when
+// the context is done and returns the error of the context then. Pending reads
+// are interrupted through the read deadline of the connection, which cannot be
+// read from anymore afterwards.
+func WebsocketReceiveWithContext(ctx context.Context, w io.Writer, conn *websocket.Conn, opts StreamOptions) error {
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.SetReadDeadline(time.Now())
+		case <-done:
+		}
+	}()
 
-			buf, err := io.ReadAll(r)
-			if err != nil {
-				log.Printf("Got error writing to writer %s", err)
-				break
-			}
+	err := receive(w, conn, opts)
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
 
-			if w == nil {
-				continue
-			}
+func receive(w io.Writer, conn *websocket.Conn, opts StreamOptions) error {
+	if opts.MaxMessageSize > 0 {
+		conn.SetReadLimit(opts.MaxMessageSize)
+	}
+	if w == nil {
+		w = io.Discard
+	}
 
-			i, err := w.Write(buf)
-			if i != len(buf) {
-				log.Printf("Didn't write all of buf")
-				break
+	buf := make([]byte, opts.bufferSize())
+	for {
+		mt, r, err := conn.NextReader()
+		if err != nil {
+			if _, ok := err.(*websocket.CloseError); ok {
+				return nil
 			}
-			if err != nil {
-				log.Printf("Error writing buf %s", err)
-				break
+			if err == websocket.ErrReadLimit {
+				return &ErrMessageTooLarge{Limit: opts.MaxMessageSize}
 			}
+			return err
 		}
-		ch <- true
-	}(w, conn)
 
-	return ch
+		if mt == websocket.TextMessage {
+			// A text message marks the end of the stream
+			return nil
+		}
+
+		if _, err := io.CopyBuffer(w, &limitErrReader{r: r, limit: opts.MaxMessageSize}, buf); err != nil {
+			return err
+		}
+	}
+}
+
+// limitErrReader turns hitting the read limit of a connection into an
+// *ErrMessageTooLarge
+type limitErrReader struct {
+	r     io.Reader
+	limit int64
+}
+
+func (l *limitErrReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	if err == websocket.ErrReadLimit {
+		err = &ErrMessageTooLarge{Limit: l.limit}
+	}
+	return n, err
+}
+
+// writeFragmented sends data as binary messages of at most fragmentSize bytes
+func writeFragmented(conn *websocket.Conn, data []byte, fragmentSize int) error {
+	if fragmentSize <= 0 {
+		fragmentSize = len(data)
+	}
+	for len(data) > 0 {
+		n := fragmentSize
+		if n > len(data) {
+			n = len(data)
+		}
+		if err := conn.WriteMessage(websocket.BinaryMessage, data[:n]); err != nil {
+			return err
+		}
+		data = data[n:]
+	}
+	return nil
 }
 
 // WebsocketProxy proxies a websocket connection
 func WebsocketProxy(source *websocket.Conn, target *websocket.Conn) chan bool {
+	return WebsocketProxyWithContext(context.Background(), source, target)
+}
+
+// WebsocketProxyWithContext proxies a websocket connection until one of both
+// sides closes its connection or the context is done. A close message
+// received from one side is forwarded to the other one and both connections
+// are closed once the close handshake completed or DefaultCloseTimeout
+// expired.
+func WebsocketProxyWithContext(ctx context.Context, source *websocket.Conn, target *websocket.Conn) chan bool {
 	forward := func(in *websocket.Conn, out *websocket.Conn, ch chan bool) {
 		for {
 			mt, r, err := in.NextReader()
 			if err != nil {
+				forwardClose(out, err)
 				break
 			}
 
@@ -279,17 +537,38 @@ func WebsocketProxy(source *websocket.Conn, target *websocket.Conn) chan bool {
 		ch <- true
 	}
 
-	chSend := make(chan bool)
+	// Buffered so the direction finishing last does not block forever
+	chSend := make(chan bool, 1)
 	go forward(source, target, chSend)
 
-	chRecv := make(chan bool)
+	chRecv := make(chan bool, 1)
 	go forward(target, source, chRecv)
 
 	ch := make(chan bool)
 	go func() {
 		select {
 		case <-chSend:
+			chSend = nil
 		case <-chRecv:
+			chRecv = nil
+		case <-ctx.Done():
+		}
+
+		// Initiate the close handshake with both sides if not done yet and
+		// give them a chance to answer before the connections are closed
+		forwardClose(source, nil)
+		forwardClose(target, nil)
+		timeout := time.NewTimer(DefaultCloseTimeout)
+		defer timeout.Stop()
+		for chSend != nil || chRecv != nil {
+			select {
+			case <-chSend:
+				chSend = nil
+			case <-chRecv:
+				chRecv = nil
+			case <-timeout.C:
+				chSend, chRecv = nil, nil
+			}
 		}
 
 		source.Close()
@@ -330,7 +609,7 @@ func defaultReader(conn *websocket.Conn, r io.ReadCloser, readDone chan<- bool)
 		}
 	}
 	closeMsg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
-	conn.WriteMessage(websocket.CloseMessage, closeMsg)
+	conn.WriteControl(websocket.CloseMessage, closeMsg, time.Now().Add(DefaultCloseTimeout))
 	readDone <- true
 	r.Close()
 }
@@ -351,12 +630,15 @@ func ReaderToChannel(r io.Reader, bufferSize int) <-chan []byte {
 		for {
 			read := buf[offset : offset+readSize]
 			nr, err := r.Read(read)
+			offset += nr
 			if err != nil {
+				if offset > 0 {
+					ch <- buf[0:offset]
+				}
 				close(ch)
 				break
 			}
 
-			offset += nr
 			if offset > 0 && (offset+readSize >= bufferSize) {
 				ch <- buf[0:offset]
 				offset = 0
@@ -369,6 +651,9 @@ func ReaderToChannel(r io.Reader, bufferSize int) <-chan []byte {
 }
 
 func defaultWriter(conn *websocket.Conn, w io.WriteCloser, writeDone chan<- bool) {
+	// Messages are copied through a bounded buffer instead of being read
+	// into memory as a whole
+	buf := make([]byte, DefaultStreamBufferSize)
 	for {
 		mt, r, err := conn.NextReader()
 		if err != nil {
@@ -386,17 +671,7 @@ func defaultWriter(conn *websocket.Conn, w io.WriteCloser, writeDone chan<- bool
 			break
 		}
 
-		buf, err := io.ReadAll(r)
-		if err != nil {
-			log.Printf("Got error writing to writer %s", err)
-			break
-		}
-		i, err := w.Write(buf)
-		if i != len(buf) {
-			log.Printf("Didn't write all of buf")
-			break
-		}
-		if err != nil {
+		if _, err := io.CopyBuffer(w, r, buf); err != nil {
 			log.Printf("Error writing buf %s", err)
 			break
 		}
@@ -436,6 +711,69 @@ func WebsocketMirror(conn *websocket.Conn, w io.WriteCloser, r io.ReadCloser, Re
 	return readDone, writeDone
 }
 
+// WebsocketMirrorWithContext behaves like WebsocketMirror but additionally
+// stops mirroring when the context is done. A close message is sent to the
+// peer then and once it answered or DefaultCloseTimeout expired, pending reads
+// and writes on the websocket are interrupted through its deadlines. Both
+// returned channels are signalled, even if the reader or writer is still
+// blocked on r or w.
+func WebsocketMirrorWithContext(ctx context.Context, conn *websocket.Conn, w io.WriteCloser, r io.ReadCloser, Reader WebSocketMirrorReader, Writer WebSocketMirrorWriter) (chan bool, chan bool) {
+	readDone, writeDone := WebsocketMirror(conn, w, r, Reader, Writer)
+	return mirrorWithContext(ctx, conn, readDone, writeDone)
+}
+
+// WebsocketConsoleMirrorWithContext behaves like WebsocketConsoleMirror but
+// stops mirroring when the context is done, see WebsocketMirrorWithContext
+func WebsocketConsoleMirrorWithContext(ctx context.Context, conn *websocket.Conn, w io.WriteCloser, r io.ReadCloser) (chan bool, chan bool) {
+	readDone, writeDone := WebsocketConsoleMirror(conn, w, r)
+	return mirrorWithContext(ctx, conn, readDone, writeDone)
+}
+
+func mirrorWithContext(ctx context.Context, conn *websocket.Conn, innerReadDone, innerWriteDone chan bool) (chan bool, chan bool) {
+	if ctx.Done() == nil {
+		return innerReadDone, innerWriteDone
+	}
+
+	readDone := make(chan bool, 1)
+	writeDone := make(chan bool, 1)
+
+	go func() {
+		for innerReadDone != nil || innerWriteDone != nil {
+			select {
+			case <-innerReadDone:
+				innerReadDone = nil
+				readDone <- true
+			case <-innerWriteDone:
+				innerWriteDone = nil
+				writeDone <- true
+			case <-ctx.Done():
+				// Give the peer a chance to complete the close handshake
+				// before pending reads and writes are interrupted
+				forwardClose(conn, nil)
+				if innerWriteDone != nil {
+					select {
+					case <-innerWriteDone:
+						innerWriteDone = nil
+						writeDone <- true
+					case <-time.After(DefaultCloseTimeout):
+					}
+				}
+				conn.SetReadDeadline(time.Now())
+				conn.SetWriteDeadline(time.Now())
+				if innerReadDone != nil {
+					readDone <- true
+				}
+				if innerWriteDone != nil {
+					writeDone <- true
+				}
+				return
+			}
+		}
+	}()
+
+	return readDone, writeDone
+}
+
 // WebsocketConsoleMirror console mirror
 func WebsocketConsoleMirror(conn *websocket.Conn, w io.WriteCloser, r io.ReadCloser) (chan bool, chan bool) {
 	readDone := make(chan bool, 1)
@@ -468,7 +806,7 @@ func WebsocketConsoleMirror(conn *websocket.Conn, w io.WriteCloser, r io.ReadClo
 			}
 		}
 		closeMsg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
-		conn.WriteMessage(websocket.CloseMessage, closeMsg)
+		conn.WriteControl(websocket.CloseMessage, closeMsg, time.Now().Add(DefaultCloseTimeout))
 		readDone <- true
 		r.Close()
 	}(conn, r)
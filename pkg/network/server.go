@@ -0,0 +1,121 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package network
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// TrustStore holds the fingerprints of the client certificates a server
+// trusts. Fingerprints can be added and removed while the server is running.
+type TrustStore struct {
+	lock         sync.RWMutex
+	fingerprints map[string]struct{}
+}
+
+// NewTrustStore returns a trust store trusting the given certificate
+// fingerprints
+func NewTrustStore(fingerprints ...string) *TrustStore {
+	s := &TrustStore{fingerprints: map[string]struct{}{}}
+	for _, fingerprint := range fingerprints {
+		s.Add(fingerprint)
+	}
+	return s
+}
+
+// Add trusts the certificate with the given sha256 fingerprint
+func (s *TrustStore) Add(fingerprint string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.fingerprints[strings.ToLower(fingerprint)] = struct{}{}
+}
+
+// AddCertificate trusts the given certificate
+func (s *TrustStore) AddCertificate(cert *x509.Certificate) {
+	s.Add(CertFingerprint(cert))
+}
+
+// Remove stops trusting the certificate with the given fingerprint. New
+// connections with the certificate are refused, existing connections are not
+// affected.
+func (s *TrustStore) Remove(fingerprint string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.fingerprints, strings.ToLower(fingerprint))
+}
+
+// Contains returns true if the certificate with the given fingerprint is trusted
+func (s *TrustStore) Contains(fingerprint string) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	_, ok := s.fingerprints[strings.ToLower(fingerprint)]
+	return ok
+}
+
+// Fingerprints returns the sorted fingerprints of all trusted certificates
+func (s *TrustStore) Fingerprints() []string {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	fingerprints := make([]string, 0, len(s.fingerprints))
+	for fingerprint := range s.fingerprints {
+		fingerprints = append(fingerprints, fingerprint)
+	}
+	sort.Strings(fingerprints)
+	return fingerprints
+}
+
+// VerifyConnection implements the tls.Config.VerifyConnection callback. It is
+// called for resumed sessions too, so certificates removed from the store
+// cannot continue to connect through session resumption.
+func (s *TrustStore) VerifyConnection(state tls.ConnectionState) error {
+	if len(state.PeerCertificates) == 0 {
+		return fmt.Errorf("no client certificate provided")
+	}
+	fingerprint := CertFingerprint(state.PeerCertificates[0])
+	if !s.Contains(fingerprint) {
+		return fmt.Errorf("client certificate %s is not trusted", fingerprint)
+	}
+	return nil
+}
+
+// GetServerTLSConfig returns a TLS config for a server using the given
+// certificate and key which requires clients to authenticate with a
+// certificate trusted by the store. Client certificates don't need to be
+// signed by a CA, as with AMS they are trusted by their fingerprint.
+func GetServerTLSConfig(tlsCertFile, tlsKeyFile string, store *TrustStore) (*tls.Config, error) {
+	if store == nil {
+		return nil, fmt.Errorf("no trust store given")
+	}
+
+	cert, err := tls.LoadX509KeyPair(tlsCertFile, tlsKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := InitTLSConfig()
+	tlsConfig.Certificates = []tls.Certificate{cert}
+	tlsConfig.ClientAuth = tls.RequireAnyClientCert
+	tlsConfig.VerifyConnection = store.VerifyConnection
+	return tlsConfig, nil
+}
@@ -0,0 +1,57 @@
+// -*- Mode: Go; indent-tabs-mode: t -*-
+/*
+ * This file is part of AMS SDK
+ * Copyright 2021 Canonical Ltd.
+ *
+ * This program is free software: you can redistribute it and/or modify it under
+ * the terms of the Lesser GNU General Public License version 3, as published
+ * by the Free Software Foundation.
+ *
+ * This program is distributed in the hope that it will be useful, but WITHOUT
+ * ANY WARRANTY; without even the implied warranties of MERCHANTABILITY, SATISFACTORY
+ * QUALITY, or FITNESS FOR A PARTICULAR PURPOSE.  See the Lesser GNU General Public
+ * License for more details.
+ *
+ * You should have received a copy of the Lesser GNU General Public License along
+ * with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package network
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// LookupSRVTargets resolves the SRV records of the given service, protocol and
+// domain, e.g. _ams._tcp.example.com, and returns the addresses of the targets
+// in the order they should be tried: by priority and, within the same
+// priority, randomized according to their weight as described by RFC 2782.
+// If no resolver is given net.DefaultResolver is used.
+func LookupSRVTargets(ctx context.Context, resolver *net.Resolver, service, proto, domain string) ([]string, error) {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	_, records, err := resolver.LookupSRV(ctx, service, proto, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make([]string, 0, len(records))
+	for _, record := range records {
+		// A target of "." means the service is decidedly not available
+		target := strings.TrimSuffix(record.Target, ".")
+		if len(target) == 0 {
+			continue
+		}
+		targets = append(targets, net.JoinHostPort(target, strconv.Itoa(int(record.Port))))
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("service _%s._%s.%s is not available", service, proto, domain)
+	}
+	return targets, nil
+}